@@ -0,0 +1,89 @@
+// exitcodes.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Exit codes of jdocgen. They are part of its interface: scripts rely on them, so existing
+// codes must keep their meaning.
+const (
+	// exitOK is returned when the documentation was generated.
+	exitOK = 0
+	// exitFailure is returned when reading the sources or writing the documentation failed.
+	exitFailure = 1
+	// exitInvalid is returned when annotation errors, warnings in -strict mode, a
+	// -min-documented shortfall or -validate-output problems were reported.
+	exitInvalid = 2
+	// exitDrift is reserved for a check mode finding the documentation out of date.
+	exitDrift = 3
+	// exitUsage is returned for invalid flags, arguments or configuration.
+	exitUsage = 4
+	// exitPartial is returned with -keep-going when the documentation was generated with
+	// stubs for the commands that could not be parsed.
+	exitPartial = 5
+)
+
+// exitError is an error carrying the exit code it should end the process with.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode attaches an exit code to err. A nil err stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// usageErrorf returns an error ending the process with exitUsage.
+func usageErrorf(format string, args ...interface{}) error {
+	return withExitCode(exitUsage, fmt.Errorf(format, args...))
+}
+
+// exitCode returns the exit code for err. Errors without one are failures.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitFailure
+}
+
+// worseExitCode returns the most severe of two exit codes. A partial success is only more
+// severe than a success.
+func worseExitCode(a, b int) int {
+	if a == exitPartial && b != exitOK {
+		return b
+	}
+	if b == exitPartial && a != exitOK {
+		return a
+	}
+	return max(a, b)
+}
+
+// printExitCodes writes the exit code contract, shown by -help.
+func printExitCodes(w io.Writer) {
+	fmt.Fprintf(w, "\nExit codes:\n")
+	fmt.Fprintf(w, "  %d  documentation generated\n", exitOK)
+	fmt.Fprintf(w, "  %d  failure reading the sources or writing the documentation\n", exitFailure)
+	fmt.Fprintf(w, "  %d  annotation errors, warnings with -strict, -min-documented or -validate-output problems\n", exitInvalid)
+	fmt.Fprintf(w, "  %d  documentation out of date (reserved for check mode)\n", exitDrift)
+	fmt.Fprintf(w, "  %d  invalid flags, arguments or configuration\n", exitUsage)
+	fmt.Fprintf(w, "  %d  documentation generated with stubs for incomplete commands (-keep-going)\n", exitPartial)
+}
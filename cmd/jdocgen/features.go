@@ -0,0 +1,68 @@
+// features.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// listFlag collects repeated flags, each holding one or more comma-separated values.
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return fmt.Errorf("invalid value %q: empty item", value)
+		}
+		*f = append(*f, item)
+	}
+	return nil
+}
+
+// printFeatureReport writes how many commands the feature filters removed, by flag.
+func printFeatureReport(w io.Writer, prefix string, report parser.FeatureReport) {
+	if report.Total == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%sFiltered out %d commands by feature flags\n", prefix, report.Total)
+	for _, flag := range sortedKeys(report.Excluded) {
+		fmt.Fprintf(w, "%s  %s (excluded): %d commands\n", prefix, flag, report.Excluded[flag])
+	}
+	for _, flag := range sortedKeys(report.NotEnabled) {
+		fmt.Fprintf(w, "%s  %s (not enabled): %d commands\n", prefix, flag, report.NotEnabled[flag])
+	}
+}
+
+// printSuppressions writes how many diagnostics //jdocgen:ignore pragmas silenced, by class,
+// so suppressions stay visible.
+func printSuppressions(w io.Writer, prefix string, suppressed map[string]int) {
+	total := 0
+	for _, count := range suppressed {
+		total += count
+	}
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%sSuppressed %d diagnostics with //jdocgen:ignore\n", prefix, total)
+	for _, class := range sortedKeys(suppressed) {
+		fmt.Fprintf(w, "%s  %s: %d\n", prefix, class, suppressed[class])
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
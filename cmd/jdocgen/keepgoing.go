@@ -0,0 +1,30 @@
+// keepgoing.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// printIncomplete writes the commands documented as stubs with -keep-going, with the reason
+// each one is incomplete, and returns how many there are.
+func printIncomplete(w io.Writer, prefix string, apiFunctions []models.APIFunction) int {
+	var stubs []models.APIFunction
+	for _, apiFunc := range apiFunctions {
+		if apiFunc.Incomplete != "" {
+			stubs = append(stubs, apiFunc)
+		}
+	}
+	if len(stubs) == 0 {
+		return 0
+	}
+	sort.Slice(stubs, func(i, j int) bool { return stubs[i].Command < stubs[j].Command })
+	fmt.Fprintf(w, "%sDocumented %d incomplete commands as stubs\n", prefix, len(stubs))
+	for _, stub := range stubs {
+		fmt.Fprintf(w, "%s  %s (%s:%d): %s\n", prefix, stub.Command, stub.SourceFile, stub.SourceLine, stub.Incomplete)
+	}
+	return len(stubs)
+}
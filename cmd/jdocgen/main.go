@@ -2,40 +2,754 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 
+	"github.com/pablolagos/jdocgen/config"
 	"github.com/pablolagos/jdocgen/generator"
+	"github.com/pablolagos/jdocgen/lint"
 	"github.com/pablolagos/jdocgen/parser"
 )
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes jdocgen with the command-line arguments args and returns its exit code.
+// Failures are reported to stderr, see exitcodes.go for the codes.
+func run(args []string, stdout, stderr io.Writer) int {
+	err := runCommand(args, stdout, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+	return exitCode(err)
+}
+
+// runCommand runs the command selected by args.
+func runCommand(args []string, stdout, stderr io.Writer) error {
+	// "generate" is the default command and may be omitted
+	if len(args) > 0 && args[0] == "schema" {
+		return runSchema(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "preview" {
+		return runPreview(args[1:], os.Stdin, stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "generate" {
+		args = args[1:]
+	}
+	return runGenerate(args, stdout, stderr)
+}
+
+// runGenerate implements `jdocgen generate`.
+func runGenerate(args []string, stdout, stderr io.Writer) error {
+	return runProfile(args, "", stdout, stderr)
+}
+
+// stdoutPath is the -output path writing the documentation to stdout.
+const stdoutPath = "-"
+
+// runProfile runs `jdocgen generate` with args. profile names the configuration profile run
+// by -all-profiles, and is empty otherwise.
+func runProfile(args []string, profile string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("jdocgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jdocgen [generate] [flags]\n       jdocgen preview [-dir dir]\n       jdocgen schema [-format json]\n\nFlags:\n")
+		flags.PrintDefaults()
+		printExitCodes(stderr)
+	}
+
 	// Define command-line flags
-	outputPath := flag.String("output", "API_Documentation.md", "Path to the output Markdown file")
-	dirPath := flag.String("dir", ".", "Directory to parse for Go source files")
-	omitRFC := flag.Bool("omit-rfc", false, "Omit JSON-RPC 2.0 specification information from the documentation")
+	outputPath := flags.String("output", "API_Documentation.md", "Path to the output Markdown file (output directory when -variant or -split is used), - for standard output")
+	var dirPaths listFlag
+	flags.Var(&dirPaths, "dir", "Directory to parse for Go source files, repeatable to parse several trees as one project (default .)")
+	var exclude listFlag
+	flags.Var(&exclude, "exclude", "Leave out the files and directories matching this glob pattern, such as *_gen.go or **/mocks/** (repeatable, comma-separated)")
+	var terminalTypes terminalTypesFlag
+	flags.Var(&terminalTypes, "terminal-type", "Document a type as name=description instead of as a struct, such as decimal.Decimal=\"string (decimal number)\" (repeatable)")
+	omitRFC := flags.Bool("omit-rfc", false, "Omit JSON-RPC 2.0 specification information from the documentation")
+	configPath := flags.String("config", "", "Path to a JSON configuration file")
+	rfcTemplate := flags.String("rfc-template", "", "Path to a template file replacing the JSON-RPC 2.0 preamble")
+	htmlTemplate := flags.String("template", "", "Path to an html/template file replacing the built-in page of -format html")
+	idType := flags.String("id-type", "", "JSON type of request ids in examples: number or string (default number)")
+	strict := flags.Bool("strict", false, "Fail when warnings are reported")
+	verbose := flags.Bool("v", false, "Verbose output: also print informational diagnostics")
+	flattenParams := flags.Bool("flatten-params", false, "Replace struct-typed parameters by their fields in the Parameters table")
+	codeSamples := flags.String("code-samples", "", "Comma-separated code sample languages rendered per command: curl")
+	endpoint := flags.String("endpoint", "", "Server URL used in code samples (default: first @server)")
+	quickSummary := flags.Bool("quick-summary", false, "Render required parameters and the result type under each command description")
+	exampleStyle := flags.String("example-style", "", "Example style: json or jsonc, which comments every field (default json)")
+	exampleCommentLength := flags.Int("example-comment-length", 0, "Longest field comment in jsonc examples (default 80)")
+	exampleDepth := flags.Int("example-depth", 0, "Nesting levels of structs expanded in example responses (default 3)")
+	maxFields := flags.Int("max-fields", 0, "Maximum rows of struct tables, longer structs are listed complete in a types appendix (0 = no limit)")
+	format := flags.String("format", "markdown", "Output format: markdown, cheatsheet (a one-page command table), json (the document model), openrpc (an OpenRPC document), html (a standalone page) or exec:<renderer> to pipe the document model to an external renderer")
+	split := flags.Bool("split", false, "Write one file per command into the -output directory, with index.md and manifest.json")
+	appendixSplit := flags.String("appendix-split", "", "Spread the types appendix of -split output over several files: package, alpha or size")
+	appendixLines := flags.Int("appendix-lines", 0, "Approximate lines per types appendix file with -appendix-split size (default 2000)")
+	fileNameScheme := flags.String("filename-scheme", "", "File naming scheme used with -split: default or kebab")
+	emptyDescription := flags.String("empty-description", "", "Text rendered in table cells with an empty description (default \"—\")")
+	showExcludedFields := flags.Bool("show-excluded-fields", false, "Document the struct fields tagged json:\"-\" with an \"excluded from JSON\" marker instead of leaving them out")
+	noTOC := flags.Bool("no-toc", false, "Leave out the Table of Contents at the top of the Markdown documentation")
+	noHeader := flags.Bool("no-header", false, "Leave out the project information and the JSON-RPC preamble at the top of the Markdown documentation")
+	linkEmbedded := flags.Bool("link-embedded", false, "Document the fields of embedded structs in their own table, linked from an \"embeds\" row, instead of promoting them into the embedding struct")
+	structSource := flags.Bool("struct-source", false, "Render the Go definition of each documented struct in a collapsed code block")
+	collapsible := flags.Bool("collapsible", false, "Render the structs documented under each command in nested, collapsed <details> blocks")
+	typesAppendix := flags.Bool("types-appendix", false, "Document every struct once in a Types section at the end, linked from the commands, instead of under each command using it")
+	linkTemplate := flags.String("link-template", "", "URL of the line a command is defined at, with {repository}, {path} and {line} placeholders (default: the GitHub or GitLab pattern of @repository)")
+	showSource := flags.Bool("show-source", false, "Render the file and line each command is defined at when it cannot be linked")
+	omitEmptySections := flags.Bool("omit-empty-sections", false, "Leave out the sentences stating that a command takes no parameters or defines no errors")
+	standardErrorsText := flags.String("standard-errors-text", "", "Sentence rendered for commands without @Error annotations")
+	mergeGlobalErrors := flags.Bool("merge-global-errors", false, "List the @globalerror errors in the Errors section of every command instead of a Common Errors section")
+	requireErrors := flags.Bool("require-errors", false, "Report commands without @Error annotations")
+	minDocumented := flags.Int("min-documented", 0, "Fail when less than this percentage of parameter, result and field descriptions is documented (0 = no minimum)")
+	methodPattern := flags.String("method-pattern", "", "Regular expression command names and former names must match (default "+lint.DefaultMethodPattern+")")
+	caseInsensitiveMethods := flags.Bool("case-insensitive-methods", false, "Report command names differing only in case, for servers matching method names case-insensitively")
+	allowDuplicateCommands := flags.Bool("allow-duplicate-commands", false, "Document the first of the functions declaring the same command, with a warning, instead of failing")
+	noClobber := flags.Bool("no-clobber", false, "Refuse to overwrite existing output files that were not generated by jdocgen")
+	validateOutputFlag := flags.Bool("validate-output", false, "Check the structure of the generated Markdown (tables, links, code blocks, headings) and fail on problems")
+	summaryPath := flags.String("summary", "", "Write a JSON summary of the documentation health (commands, examples, errors, warnings) to this file")
+	badgePath := flags.String("badge", "", "Write an SVG badge such as \"API docs: 97%\" to this file")
+	badgeFormula := flags.String("badge-formula", "", "Formula of the badge percentage over commands, examples, errors, deprecated and warnings (default \""+generator.DefaultBadgeFormula+"\")")
+	var withFeatures, withoutFeatures listFlag
+	flags.Var(&withFeatures, "with-feature", "Document only commands whose @Feature flags are all enabled (repeatable, comma-separated)")
+	flags.Var(&withoutFeatures, "without-feature", "Leave out commands with this @Feature flag (repeatable, comma-separated)")
+	var only listFlag
+	flags.Var(&only, "only", "Generate only the commands matching this name or glob, such as user.* (repeatable, comma-separated)")
+	onlyQuiet := flags.Bool("only-quiet", false, "With -only, report only the diagnostics about the files of the selected commands")
+	var variants variantFlag
+	flags.Var(&variants, "variant", "Document a variant as name=dir, writing <output>/<name>.md (repeatable)")
+	audience := flags.String("audience", parser.AudiencePublic, "Audience of the documentation: public leaves out @Internal commands and @Hidden fields, internal documents them")
+	profileName := flags.String("profile", "", "Apply the options of this profile of the configuration file")
+	allProfiles := flags.Bool("all-profiles", false, "Generate every profile of the configuration file into its output path")
+	wrap := flags.Int("wrap", 0, "Wrap paragraphs, list items and block quotes of Markdown output at this column (0 = no wrapping)")
+	alignTables := flags.Bool("align-tables", false, "Pad the cells of Markdown tables so their pipes line up")
+	headingOffset := flags.Int("heading-offset", 0, "Move every Markdown heading down this many levels, up to level 6, to embed the output in a larger page")
+	keepGoing := flags.Bool("keep-going", false, "Document commands whose file or annotations could not be parsed as marked stubs, exiting with code 5")
+	watchFlag := flags.Bool("watch", false, "Regenerate the documentation each time a Go file under -dir changes, until interrupted")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return withExitCode(exitUsage, err)
+	}
+	if flags.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	setFlags := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
 
-	flag.Parse()
+	// Load configuration file, command-line flags take precedence
+	var cfg config.Config
+	if *configPath != "" {
+		var err error
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			return usageErrorf("Error loading configuration: %v", err)
+		}
+	}
+	if *allProfiles && *watchFlag {
+		return usageErrorf("-watch cannot be used with -all-profiles")
+	}
+	if *allProfiles && profile == "" {
+		return runAllProfiles(args, cfg, setFlags, *profileName != "" || len(variants) > 0, stdout, stderr)
+	}
+	if profile != "" {
+		*profileName = profile
+	}
+	if *profileName != "" {
+		p, exists := cfg.Profiles[*profileName]
+		if !exists {
+			return unknownProfileError(*profileName, cfg)
+		}
+		if err := applyProfile(flags, setFlags, *profileName, p); err != nil {
+			return usageErrorf("%v", err)
+		}
+	}
+	if len(dirPaths) == 0 {
+		dirPaths = listFlag{"."}
+	}
+	if !setFlags["exclude"] {
+		exclude = cfg.Exclude
+	}
+	if *rfcTemplate == "" {
+		*rfcTemplate = cfg.RFCTemplate
+	}
+	if *idType == "" {
+		*idType = cfg.IDType
+	}
+	if *exampleStyle == "" {
+		*exampleStyle = cfg.ExampleStyle
+	}
+	if *exampleCommentLength == 0 {
+		*exampleCommentLength = cfg.ExampleCommentLength
+	}
+	if *emptyDescription == "" {
+		*emptyDescription = cfg.EmptyDescription
+	}
+	if *minDocumented == 0 {
+		*minDocumented = cfg.MinDocumented
+	}
+	if *standardErrorsText == "" {
+		*standardErrorsText = cfg.StandardErrorsText
+	}
+	if !setFlags["omit-empty-sections"] && cfg.OmitEmptySections != nil {
+		*omitEmptySections = *cfg.OmitEmptySections
+	}
+	if !setFlags["merge-global-errors"] && cfg.MergeGlobalErrors != nil {
+		*mergeGlobalErrors = *cfg.MergeGlobalErrors
+	}
+	if !setFlags["require-errors"] && cfg.RequireErrors != nil {
+		*requireErrors = *cfg.RequireErrors
+	}
+	if *methodPattern == "" {
+		*methodPattern = cfg.MethodPattern
+	}
+	if *methodPattern == "" {
+		*methodPattern = lint.DefaultMethodPattern
+	}
+	if !setFlags["case-insensitive-methods"] && cfg.CaseInsensitiveMethods != nil {
+		*caseInsensitiveMethods = *cfg.CaseInsensitiveMethods
+	}
+	if !setFlags["allow-duplicate-commands"] && cfg.AllowDuplicateCommands != nil {
+		*allowDuplicateCommands = *cfg.AllowDuplicateCommands
+	}
+	if *badgeFormula == "" {
+		*badgeFormula = cfg.BadgeFormula
+	}
+	// Terminal types of the flags add to or replace the ones of the configuration file
+	for name, description := range cfg.TerminalTypes {
+		if _, exists := terminalTypes[name]; !exists {
+			if err := terminalTypes.Set(name + "=" + description); err != nil {
+				return usageErrorf("Error loading configuration: %v", err)
+			}
+		}
+	}
+
+	opts := generator.Options{
+		IncludeRFC:           !*omitRFC,
+		IDType:               *idType,
+		FlattenParams:        *flattenParams,
+		Endpoint:             *endpoint,
+		QuickSummary:         *quickSummary,
+		MaxFields:            *maxFields,
+		ExampleStyle:         *exampleStyle,
+		ExampleCommentLength: *exampleCommentLength,
+		ExampleDepth:         *exampleDepth,
+		FileNameScheme:       *fileNameScheme,
+		AppendixSplit:        *appendixSplit,
+		AppendixLines:        *appendixLines,
+		EmptyDescription:     *emptyDescription,
+		NoClobber:            *noClobber,
+		NoTOC:                *noTOC,
+		NoHeader:             *noHeader,
+		OmitEmptySections:    *omitEmptySections,
+		MergeGlobalErrors:    *mergeGlobalErrors,
+		StructSource:         *structSource,
+		Collapsible:          *collapsible,
+		TypesAppendix:        *typesAppendix,
+		LinkTemplate:         *linkTemplate,
+		ShowSource:           *showSource,
+		LinkEmbedded:         *linkEmbedded,
+		StandardErrorsText:   *standardErrorsText,
+		Wrap:                 *wrap,
+		AlignTables:          *alignTables,
+		HeadingOffset:        *headingOffset,
+		TerminalTypes:        terminalTypes,
+	}
+	if *codeSamples != "" {
+		for _, sample := range strings.Split(*codeSamples, ",") {
+			opts.CodeSamples = append(opts.CodeSamples, strings.TrimSpace(sample))
+		}
+	}
+	if cfg.SupportsBatch != nil {
+		opts.SupportsBatch = *cfg.SupportsBatch
+	}
+	if cfg.SupportsNotifications != nil {
+		opts.SupportsNotifications = *cfg.SupportsNotifications
+	}
+	if *rfcTemplate != "" && opts.IncludeRFC {
+		content, err := os.ReadFile(*rfcTemplate)
+		if err != nil {
+			return fmt.Errorf("Error reading RFC template: %v", err)
+		}
+		opts.RFCTemplate = string(content)
+	}
+	if *htmlTemplate != "" {
+		if *format != "html" {
+			return usageErrorf("-template requires -format html")
+		}
+		content, err := os.ReadFile(*htmlTemplate)
+		if err != nil {
+			return fmt.Errorf("Error reading HTML template: %v", err)
+		}
+		opts.HTMLTemplate = string(content)
+	}
+
+	run := runOptions{
+		Strict:              *strict,
+		Verbose:             *verbose,
+		Split:               *split,
+		Format:              *format,
+		Features:            parser.FeatureFilter{With: withFeatures, Without: withoutFeatures},
+		PlaceholderPatterns: lint.DefaultPlaceholderPatterns,
+		MethodPattern:       *methodPattern,
+		CaseInsensitive:     *caseInsensitiveMethods,
+		RequireErrors:       *requireErrors,
+		Audience:            *audience,
+		Label:               profile,
+		Stdout:              stdout,
+		Stderr:              stderr,
+		MinDocumented:       *minDocumented,
+		ValidateOutput:      *validateOutputFlag,
+		Only:                only,
+		OnlyQuiet:           *onlyQuiet,
+		Summary:             *summaryPath,
+		Badge:               *badgePath,
+		BadgeFormula:        *badgeFormula,
+		KeepGoing:           *keepGoing,
+		ShowExcludedFields:  *showExcludedFields,
+		Parse:               parser.Options{Exclude: exclude, AllowDuplicateCommands: *allowDuplicateCommands},
+	}
+	opts.Partial = len(only) > 0
+	if cfg.PlaceholderPatterns != nil {
+		run.PlaceholderPatterns = cfg.PlaceholderPatterns
+	}
+
+	if *maxFields < 0 {
+		return usageErrorf("-max-fields must not be negative")
+	}
+	if *exampleDepth < 0 {
+		return usageErrorf("-example-depth must not be negative")
+	}
+	if *wrap < 0 {
+		return usageErrorf("-wrap must not be negative")
+	}
+	if *headingOffset < 0 {
+		return usageErrorf("-heading-offset must not be negative")
+	}
+	if *audience != parser.AudiencePublic && *audience != parser.AudienceInternal {
+		return usageErrorf("invalid audience %q: expected %s or %s", *audience, parser.AudiencePublic, parser.AudienceInternal)
+	}
+	if *format != "markdown" && *format != "cheatsheet" && *format != "json" && *format != "openrpc" && *format != "html" && (!strings.HasPrefix(*format, "exec:") || strings.TrimSpace(strings.TrimPrefix(*format, "exec:")) == "") {
+		return usageErrorf("invalid format %q: expected markdown, cheatsheet, json, openrpc, html or exec:<renderer>", *format)
+	}
+	if *format != "markdown" && (*split || *validateOutputFlag || len(variants) > 0) {
+		return usageErrorf("-format %s cannot be used with -split, -validate-output or -variant", *format)
+	}
+	if *outputPath == stdoutPath {
+		if *split || *validateOutputFlag || len(variants) > 0 {
+			return usageErrorf("-output %s cannot be used with -split, -validate-output or -variant", stdoutPath)
+		}
+		opts.Output = stdout
+	}
+	if *appendixSplit != "" && !*split {
+		return usageErrorf("-appendix-split requires -split")
+	}
+	if *typesAppendix && *split {
+		return usageErrorf("-types-appendix cannot be used with -split")
+	}
+	if *appendixLines < 0 {
+		return usageErrorf("-appendix-lines must not be negative")
+	}
+	if *minDocumented < 0 || *minDocumented > 100 {
+		return usageErrorf("-min-documented must be a percentage between 0 and 100")
+	}
+	if (*summaryPath != "" || *badgePath != "") && len(variants) > 0 {
+		return usageErrorf("-summary and -badge cannot be used with -variant")
+	}
+	if err := generator.ValidateBadgeFormula(*badgeFormula); err != nil {
+		return usageErrorf("%v", err)
+	}
+	if err := opts.Validate(); err != nil {
+		return withExitCode(exitUsage, err)
+	}
+	if err := run.Parse.Validate(); err != nil {
+		return withExitCode(exitUsage, err)
+	}
 
-	// Resolve absolute directory path
-	absDir, err := filepath.Abs(*dirPath)
+	if len(variants) == 0 {
+		if *split && !setFlags["output"] {
+			*outputPath = "docs"
+		}
+		if *watchFlag {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return watch(ctx, dirPaths, *outputPath, run.Parse, watchInterval, func() error {
+				return generate(dirPaths, *outputPath, opts, run)
+			}, stderr)
+		}
+		return generate(dirPaths, *outputPath, opts, run)
+	}
+	if *watchFlag {
+		return usageErrorf("-watch cannot be used with -variant")
+	}
+
+	// Every variant is parsed and generated independently, sharing only the flags
+	if setFlags["dir"] {
+		return usageErrorf("-dir and -variant cannot be used together")
+	}
+	outputDir := *outputPath
+	if !setFlags["output"] {
+		outputDir = "."
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("Error creating output directory: %v", err)
+	}
+
+	// The run ends with the most severe exit code of the failed variants
+	failed, code := 0, exitOK
+	for _, v := range variants {
+		variantOpts := opts
+		variantOpts.Variant = v.Name
+		variantRun := run
+		variantRun.Label = strings.TrimPrefix(run.Label+"/"+v.Name, "/")
+
+		outFile := filepath.Join(outputDir, v.Name+".md")
+		if run.Split {
+			outFile = filepath.Join(outputDir, v.Name)
+		}
+		if err := generate([]string{v.Dir}, outFile, variantOpts, variantRun); err != nil {
+			fmt.Fprintln(stderr, err)
+			failed++
+			code = worseExitCode(code, exitCode(err))
+		}
+	}
+	if failed > 0 {
+		return withExitCode(code, fmt.Errorf("%d of %d variants failed", failed, len(variants)))
+	}
+	return nil
+}
+
+// runAllProfiles implements -all-profiles, generating every profile of cfg into the output
+// path it configures, one after the other. The other flags apply to every profile. The run
+// ends with the most severe exit code of the failed profiles.
+func runAllProfiles(args []string, cfg config.Config, setFlags map[string]bool, selected bool, stdout, stderr io.Writer) error {
+	if selected {
+		return usageErrorf("-all-profiles cannot be used with -profile or -variant")
+	}
+	if setFlags["output"] {
+		return usageErrorf("-all-profiles cannot be used with -output, every profile sets its own")
+	}
+	names := profileNames(cfg)
+	if len(names) == 0 {
+		return usageErrorf("-all-profiles requires a configuration file with profiles")
+	}
+	for _, name := range names {
+		if _, exists := cfg.Profiles[name]["output"]; !exists {
+			return usageErrorf("profile %q has no output path, required by -all-profiles", name)
+		}
+	}
+
+	failed, code := 0, exitOK
+	for _, name := range names {
+		if err := runProfile(args, name, stdout, stderr); err != nil {
+			fmt.Fprintln(stderr, err)
+			failed++
+			code = worseExitCode(code, exitCode(err))
+		}
+	}
+	if failed > 0 {
+		return withExitCode(code, fmt.Errorf("%d of %d profiles failed", failed, len(names)))
+	}
+	return nil
+}
+
+// runOptions controls reporting for a single parse and generate run.
+type runOptions struct {
+	Strict  bool
+	Verbose bool
+	// Split writes one file per command into the output directory.
+	Split bool
+	// Format is "markdown", "cheatsheet", "json", "openrpc", "html" or "exec:" followed by the
+	// path of an external renderer.
+	Format string
+	// Features selects the documented commands by their @Feature flags.
+	Features parser.FeatureFilter
+	// Label prefixes every reported line, used to tell profiles and variants apart.
+	Label string
+	// Audience is parser.AudiencePublic or parser.AudienceInternal.
+	Audience string
+	// PlaceholderPatterns are reported when found in descriptions.
+	PlaceholderPatterns []string
+	// MethodPattern is the regular expression command names must match.
+	MethodPattern string
+	// CaseInsensitive reports command names differing only in case.
+	CaseInsensitive bool
+	// RequireErrors reports commands without @Error annotations.
+	RequireErrors bool
+	// MinDocumented is the lowest accepted percentage of documented descriptions, 0 for none.
+	MinDocumented int
+	// ValidateOutput checks the structure of the generated Markdown.
+	ValidateOutput bool
+	// Stdout and Stderr receive the progress and the diagnostics.
+	Stdout io.Writer
+	Stderr io.Writer
+	// Only lists the glob patterns of the generated commands, empty for all of them.
+	Only []string
+	// OnlyQuiet reports only the diagnostics about the files of the commands selected by Only.
+	OnlyQuiet bool
+	// Summary and Badge are the paths of the health summary and badge, empty for none.
+	Summary string
+	Badge   string
+	// BadgeFormula computes the badge percentage, empty for the default formula.
+	BadgeFormula string
+	// KeepGoing documents the commands that could not be parsed as stubs.
+	KeepGoing bool
+	// ShowExcludedFields documents the fields tagged json:"-" with an "excluded from JSON" marker.
+	ShowExcludedFields bool
+	// Parse selects the files read by the parser.
+	Parse parser.Options
+}
+
+// generate parses the directories of a project and writes its documentation to outFile.
+func generate(dirs []string, outFile string, opts generator.Options, run runOptions) error {
+	prefix := ""
+	if run.Label != "" {
+		prefix = "[" + run.Label + "] "
+	}
+
+	// Resolve absolute directory paths
+	absDirs, err := absolutePaths(dirs)
 	if err != nil {
-		log.Fatalf("Error resolving directory path: %v", err)
+		return usageErrorf("%sError resolving directory path: %v", prefix, err)
 	}
 
 	// Parse the project to collect API functions and all struct definitions
-	apiFunctions, structs, projectInfo, err := parser.ParseProject(absDir)
+	result, err := parser.ParseProjects(absDirs, run.Parse)
 	if err != nil {
-		log.Fatalf("Error parsing project: %v", err)
+		return fmt.Errorf("%sError parsing project: %v", prefix, err)
+	}
+
+	// Filter before linting so only documented commands are reported
+	featureReport := result.FilterFeatures(run.Features)
+	audienceReport, err := result.ApplyAudience(run.Audience)
+	if err != nil {
+		return usageErrorf("%s%v", prefix, err)
+	}
+	excludedFields := 0
+	if run.ShowExcludedFields {
+		excludedFields = result.ShowExcludedFields()
+	}
+
+	placeholders, err := lint.Placeholders(result.Functions, result.Structs, run.PlaceholderPatterns)
+	if err != nil {
+		return usageErrorf("%s%v", prefix, err)
+	}
+	result.Diagnostics = append(result.Diagnostics, placeholders...)
+	methodNames, err := lint.MethodNames(result.Functions, run.MethodPattern, run.CaseInsensitive)
+	if err != nil {
+		return usageErrorf("%s%v", prefix, err)
+	}
+	result.Diagnostics = append(result.Diagnostics, methodNames...)
+	if run.RequireErrors {
+		result.Diagnostics = append(result.Diagnostics, lint.RequireErrors(result.Functions, result.ProjectInfo.GlobalErrors)...)
+	}
+	result.Diagnostics = append(result.Diagnostics, lint.ErrorCatalog(result.Functions, result.ProjectInfo.ErrorCatalog)...)
+	documentation := lint.Documentation(result.Functions, result.Structs)
+	result.Diagnostics = append(result.Diagnostics, documentation.Diagnostics(run.MinDocumented)...)
+	result.ApplySuppressions()
+	if run.KeepGoing {
+		result.KeepGoing()
+	}
+
+	// Select commands after linting so problems with the other commands are still reported
+	if err := result.FilterCommands(run.Only); err != nil {
+		return usageErrorf("%s%v", prefix, err)
+	}
+	if len(run.Only) > 0 && run.OnlyQuiet {
+		result.Diagnostics = result.Diagnostics.For(result.Functions)
+	}
+
+	// Report diagnostics before generating so problems are not lost in the log output
+	for _, diag := range result.Diagnostics {
+		if diag.Severity == parser.SeverityInfo && !run.Verbose {
+			continue
+		}
+		fmt.Fprintf(run.Stderr, "%s%s\n", prefix, diag)
+	}
+	fmt.Fprintf(run.Stderr, "%sParsed %d files (%d skipped), found %d commands and %d structs\n",
+		prefix, result.Stats.FilesParsed, result.Stats.FilesSkipped, result.Stats.Commands, result.Stats.Structs)
+	fmt.Fprintf(run.Stderr, "%sDocumented %d of %d descriptions (%.1f%%)\n",
+		prefix, documentation.Total-documentation.Empty, documentation.Total, documentation.Percent())
+	printSuppressions(run.Stderr, prefix, result.Stats.Suppressed)
+	printFeatureReport(run.Stderr, prefix, featureReport)
+	printAudienceReport(run.Stderr, prefix, audienceReport)
+	if excludedFields > 0 {
+		fmt.Fprintf(run.Stderr, "%sDocumented %d fields excluded from JSON\n", prefix, excludedFields)
+	}
+	if len(run.Only) > 0 {
+		fmt.Fprintf(run.Stderr, "%sSelected %d commands with -only\n", prefix, len(result.Functions))
+	}
+	incomplete := printIncomplete(run.Stderr, prefix, result.Functions)
+
+	if errs := result.Diagnostics.Count(parser.SeverityError); errs > 0 {
+		return withExitCode(exitInvalid, fmt.Errorf("%s%d errors reported", prefix, errs))
+	}
+	if result.Diagnostics.Count(parser.SeverityWarning) > 0 && run.Strict {
+		return strictError(prefix, result.Diagnostics)
+	}
+
+	// The warnings of the generator, such as results without a struct definition, count like
+	// the warnings of the parser; those the parser reported already are not repeated
+	var warnings parser.Diagnostics
+	opts.Warn = func(warning generator.Warning) {
+		warnings = append(warnings, parser.Diagnostic{
+			Severity: parser.SeverityWarning,
+			File:     warning.File,
+			Line:     warning.Line,
+			Class:    warning.Class,
+			Message:  warning.Message,
+		})
 	}
 
 	// Generate Markdown documentation for API endpoints
-	err = generator.GenerateDocumentation(apiFunctions, structs, projectInfo, *outputPath, !*omitRFC)
+	switch {
+	case run.Format == "cheatsheet":
+		err = generator.GenerateCheatSheet(result.Functions, result.Structs, result.ProjectInfo, outFile, opts)
+	case run.Format == "json":
+		err = generator.GenerateJSON(result.Functions, result.Structs, result.ProjectInfo, outFile, opts)
+	case run.Format == "openrpc":
+		err = generator.GenerateOpenRPC(result.Functions, result.Structs, result.ProjectInfo, outFile, opts)
+	case run.Format == "html":
+		err = generator.GenerateHTML(result.Functions, result.Structs, result.ProjectInfo, outFile, opts)
+	case strings.HasPrefix(run.Format, "exec:"):
+		err = generator.GenerateExternal(result.Functions, result.Structs, result.ProjectInfo, outFile, strings.TrimPrefix(run.Format, "exec:"), run.Stderr, opts)
+	case run.Split:
+		err = generator.GenerateSplitDocumentation(result.Functions, result.Structs, result.ProjectInfo, outFile, opts)
+	default:
+		err = generator.GenerateDocumentation(result.Functions, result.Structs, result.ProjectInfo, outFile, opts)
+	}
+	var rendererErr *generator.RendererError
+	if errors.As(err, &rendererErr) && rendererErr.ExitCode > 0 {
+		// The exit code of an external renderer is passed on
+		return withExitCode(rendererErr.ExitCode, fmt.Errorf("%sError generating documentation: %v", prefix, err))
+	}
 	if err != nil {
-		log.Fatalf("Error generating documentation: %v", err)
+		return fmt.Errorf("%sError generating documentation: %v", prefix, err)
+	}
+	reported := len(result.Diagnostics)
+	result.Diagnostics = append(result.Diagnostics, unreported(result.Diagnostics, warnings)...)
+	result.ApplySuppressions()
+	for _, diag := range result.Diagnostics[reported:] {
+		if diag.Severity == parser.SeverityInfo && !run.Verbose {
+			continue
+		}
+		fmt.Fprintf(run.Stderr, "%s%s\n", prefix, diag)
+	}
+	if result.Diagnostics.Count(parser.SeverityWarning) > 0 && run.Strict {
+		return strictError(prefix, result.Diagnostics)
 	}
 
-	fmt.Printf("Documentation successfully generated at %s\n", *outputPath)
+	if run.ValidateOutput {
+		if err := validateOutput(run.Stderr, prefix, outFile, run.Split, opts.HeadingOffset); err != nil {
+			return err
+		}
+	}
+
+	if run.Summary != "" || run.Badge != "" {
+		doc := generator.NewDocument(result.Functions, result.Structs, result.ProjectInfo)
+		health := generator.NewHealth(doc, result.Diagnostics.Count(parser.SeverityWarning), opts)
+		if run.Summary != "" {
+			if err := generator.WriteHealth(run.Summary, health, opts); err != nil {
+				return fmt.Errorf("%sError writing summary: %v", prefix, err)
+			}
+			fmt.Fprintf(run.Stderr, "%sDocumentation summary written to %s\n", prefix, run.Summary)
+		}
+		if run.Badge != "" {
+			if err := generator.WriteBadge(run.Badge, health, run.BadgeFormula, opts); err != nil {
+				return fmt.Errorf("%sError writing badge: %v", prefix, err)
+			}
+			fmt.Fprintf(run.Stderr, "%sDocumentation badge written to %s\n", prefix, run.Badge)
+		}
+	}
+
+	if incomplete > 0 {
+		return withExitCode(exitPartial, fmt.Errorf("%sDocumentation generated at %s with %d incomplete commands", prefix, outFile, incomplete))
+	}
+	if opts.Output != nil {
+		// The documentation itself went to stdout
+		fmt.Fprintf(run.Stderr, "%sDocumentation successfully written to standard output\n", prefix)
+		return nil
+	}
+	fmt.Fprintf(run.Stdout, "%sDocumentation successfully generated at %s\n", prefix, outFile)
+	return nil
+}
+
+// absolutePaths returns the absolute form of each path.
+func absolutePaths(paths []string) ([]string, error) {
+	absPaths := make([]string, len(paths))
+	for i, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		absPaths[i] = absPath
+	}
+	return absPaths, nil
+}
+
+// unreported returns the diagnostics of generated that were not reported already, such as the
+// result types without a struct definition that the parser reported as undeclared.
+func unreported(reported parser.Diagnostics, generated parser.Diagnostics) parser.Diagnostics {
+	type location struct {
+		file  string
+		line  int
+		class string
+	}
+	seen := make(map[location]bool, len(reported))
+	for _, diag := range reported {
+		seen[location{diag.File, diag.Line, diag.Class}] = true
+	}
+	var diagnostics parser.Diagnostics
+	for _, diag := range generated {
+		key := location{diag.File, diag.Line, diag.Class}
+		if diag.Line > 0 && seen[key] {
+			continue
+		}
+		seen[key] = true
+		diagnostics = append(diagnostics, diag)
+	}
+	return diagnostics
+}
+
+// strictError returns the error failing a -strict run with warnings, listing the unresolved
+// types with their location so they can be fixed without searching the output.
+func strictError(prefix string, diagnostics parser.Diagnostics) error {
+	var message strings.Builder
+	fmt.Fprintf(&message, "%sStrict mode: %d warnings reported", prefix, diagnostics.Count(parser.SeverityWarning))
+	var unresolved []parser.Diagnostic
+	for _, diag := range diagnostics {
+		if diag.Class == parser.ClassUnresolvedType {
+			unresolved = append(unresolved, diag)
+		}
+	}
+	if len(unresolved) > 0 {
+		fmt.Fprintf(&message, ", including %d unresolved types:", len(unresolved))
+		for _, diag := range unresolved {
+			switch {
+			case diag.Line > 0:
+				fmt.Fprintf(&message, "\n%s  %s:%d: %s", prefix, diag.File, diag.Line, diag.Message)
+			case diag.File != "":
+				fmt.Fprintf(&message, "\n%s  %s: %s", prefix, diag.File, diag.Message)
+			default:
+				fmt.Fprintf(&message, "\n%s  %s", prefix, diag.Message)
+			}
+		}
+	}
+	return withExitCode(exitInvalid, errors.New(message.String()))
 }
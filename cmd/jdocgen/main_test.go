@@ -0,0 +1,392 @@
+// main_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixture returns the path of a parser test fixture.
+func fixture(name string) string {
+	return filepath.Join("..", "..", "parser", "testdata", name)
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	handWritten := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(handWritten, []byte("# Hand-written\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	brokenTemplate := filepath.Join(dir, "broken.tmpl")
+	if err := os.WriteFile(brokenTemplate, []byte("| A | B |\n|---|---|\n| only one |\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	brokenHTMLTemplate := filepath.Join(dir, "broken.html.tmpl")
+	if err := os.WriteFile(brokenHTMLTemplate, []byte("{{range .Commands}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"success", []string{"-dir", fixture("features"), "-output", out("ok.md")}, exitOK},
+		{"generate command", []string{"generate", "-dir", fixture("features"), "-output", out("generate.md")}, exitOK},
+		{"schema", []string{"schema"}, exitOK},
+		{"validated heading offset", []string{"-dir", fixture("features"), "-heading-offset", "2", "-validate-output", "-output", out("offset.md")}, exitOK},
+		{"validated split heading offset", []string{"-dir", fixture("features"), "-split", "-heading-offset", "2", "-validate-output", "-output", out("offset")}, exitOK},
+
+		{"missing source directory", []string{"-dir", out("missing"), "-output", out("missing.md")}, exitFailure},
+		{"unwritable output", []string{"-dir", fixture("features"), "-output", out("missing/dir/out.md")}, exitFailure},
+		{"missing rfc template", []string{"-dir", fixture("features"), "-rfc-template", out("missing.tmpl"), "-output", out("tmpl.md")}, exitFailure},
+		{"no clobber", []string{"-dir", fixture("features"), "-no-clobber", "-output", handWritten}, exitFailure},
+		{"partial split without manifest", []string{"-dir", fixture("features"), "-split", "-only", "ping", "-output", out("partial")}, exitFailure},
+
+		{"annotation errors", []string{"-dir", fixture("ids"), "-output", out("ids.md")}, exitInvalid},
+		{"strict warnings", []string{"-dir", fixture("typos"), "-strict", "-output", out("typos.md")}, exitInvalid},
+		{"strict unresolved types", []string{"-dir", fixture("unresolved"), "-strict", "-output", out("unresolved.md")}, exitInvalid},
+		{"unresolved types", []string{"-dir", fixture("unresolved"), "-output", out("unresolved.md")}, exitOK},
+		{"require errors", []string{"-dir", fixture("features"), "-require-errors", "-strict", "-output", out("errors.md")}, exitInvalid},
+		{"min documented", []string{"-dir", fixture("methods"), "-min-documented", "100", "-output", out("methods.md")}, exitInvalid},
+		{"invalid output", []string{"-dir", fixture("features"), "-rfc-template", brokenTemplate, "-validate-output", "-output", out("invalid.md")}, exitInvalid},
+		{"failed variant", []string{"-variant", "v1=" + fixture("features"), "-variant", "v2=" + fixture("ids"), "-output", out("variants")}, exitInvalid},
+
+		{"unknown flag", []string{"-no-such-flag"}, exitUsage},
+		{"unexpected argument", []string{"-dir", fixture("features"), "extra"}, exitUsage},
+		{"negative max fields", []string{"-max-fields", "-1"}, exitUsage},
+		{"min documented range", []string{"-min-documented", "101"}, exitUsage},
+		{"invalid id type", []string{"-id-type", "uuid"}, exitUsage},
+		{"terminal type without description", []string{"-terminal-type", "decimal.Decimal"}, exitUsage},
+		{"unqualified terminal type", []string{"-terminal-type", "Decimal=string"}, exitUsage},
+		{"missing config", []string{"-config", out("missing.json")}, exitUsage},
+		{"dir with variant", []string{"-dir", ".", "-variant", "v1=."}, exitUsage},
+		{"no command matches only", []string{"-dir", fixture("features"), "-only", "nothing.*", "-output", out("only.md")}, exitUsage},
+		{"appendix split without split", []string{"-dir", fixture("features"), "-appendix-split", "package", "-output", out("appendix.md")}, exitUsage},
+		{"invalid appendix split", []string{"-dir", fixture("features"), "-split", "-appendix-split", "size2", "-output", out("appendix")}, exitUsage},
+		{"invalid method pattern", []string{"-dir", fixture("features"), "-method-pattern", "[", "-output", out("pattern.md")}, exitUsage},
+		{"unknown format", []string{"-dir", fixture("features"), "-format", "pdf", "-output", out("format.md")}, exitUsage},
+		{"template without html format", []string{"-dir", fixture("features"), "-template", out("page.tmpl"), "-output", out("page.md")}, exitUsage},
+		{"missing html template", []string{"-dir", fixture("features"), "-format", "html", "-template", out("missing.tmpl"), "-output", out("page.html")}, exitFailure},
+		{"broken html template", []string{"-dir", fixture("features"), "-format", "html", "-template", brokenHTMLTemplate, "-output", out("broken.html")}, exitFailure},
+		{"json format with split", []string{"-dir", fixture("features"), "-format", "json", "-split", "-output", out("format")}, exitUsage},
+		{"cheatsheet format with split", []string{"-dir", fixture("features"), "-format", "cheatsheet", "-split", "-output", out("cheatsheet")}, exitUsage},
+		{"invalid badge formula", []string{"-dir", fixture("features"), "-badge", out("badge.svg"), "-badge-formula", "examples +", "-output", out("badge.md")}, exitUsage},
+		{"badge with variant", []string{"-variant", "v1=" + fixture("features"), "-badge", out("badge.svg"), "-output", out("badges")}, exitUsage},
+		{"unknown schema format", []string{"schema", "-format", "yaml"}, exitUsage},
+		{"invalid audience", []string{"-dir", fixture("audience"), "-audience", "partners", "-output", out("audience.md")}, exitUsage},
+		{"profile without config", []string{"-dir", fixture("audience"), "-profile", "public", "-output", out("profile.md")}, exitUsage},
+		{"all profiles without config", []string{"-dir", fixture("audience"), "-all-profiles"}, exitUsage},
+		{"watch with variant", []string{"-watch", "-variant", "v1=" + fixture("features"), "-output", out("watch")}, exitUsage},
+		{"watch with all profiles", []string{"-dir", fixture("audience"), "-watch", "-all-profiles"}, exitUsage},
+		{"stdout with split", []string{"-dir", fixture("features"), "-split", "-output", "-"}, exitUsage},
+		{"stdout with validate output", []string{"-dir", fixture("features"), "-validate-output", "-output", "-"}, exitUsage},
+
+		{"broken file", []string{"-dir", fixture("broken"), "-output", out("broken.md")}, exitOK},
+		{"invalid exclude pattern", []string{"-dir", fixture("exclude"), "-exclude", "[mocks", "-output", out("exclude.md")}, exitUsage},
+		{"exclude", []string{"-dir", fixture("exclude"), "-exclude", "*_gen.go", "-exclude", "**/mocks/**,third_party/", "-output", out("exclude.md")}, exitOK},
+		{"several dirs", []string{"-dir", fixture("roots/rpc"), "-dir", fixture("roots/types"), "-output", out("roots.md")}, exitOK},
+		{"comma-separated dirs", []string{"-dir", fixture("roots/rpc") + "," + fixture("roots/types"), "-output", out("roots-list.md")}, exitOK},
+		{"conflicting dirs", []string{"-dir", fixture("roots/rpc"), "-dir", fixture("roots/types"), "-dir", fixture("roots/conflict"), "-output", out("conflict.md")}, exitFailure},
+		{"keep going", []string{"-dir", fixture("broken"), "-keep-going", "-output", out("keep-going.md")}, exitPartial},
+		{"keep going without failures", []string{"-dir", fixture("features"), "-keep-going", "-output", out("complete.md")}, exitOK},
+		{"partial and failed variants", []string{"-keep-going", "-variant", "v1=" + fixture("broken"), "-variant", "v2=" + fixture("ids"), "-output", out("partial-variants")}, exitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("run(%v) = %d, want %d\n%s", tt.args, code, tt.code, stderr.String())
+			}
+		})
+	}
+}
+
+func TestHelpListsExitCodes(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-help"}, &stdout, &stderr); code != exitOK {
+		t.Errorf("run(-help) = %d, want %d", code, exitOK)
+	}
+	for _, want := range []string{"Exit codes:", "  2  annotation errors", "  4  invalid flags"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("help output does not contain %q:\n%s", want, stderr.String())
+		}
+	}
+}
+
+func TestStdoutOutput(t *testing.T) {
+	// The generator does not log, the success message is printed by run only
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	for _, format := range []string{"markdown", "json", "openrpc", "html", "cheatsheet"} {
+		t.Run(format, func(t *testing.T) {
+			logs.Reset()
+			var stdout, stderr bytes.Buffer
+			args := []string{"-dir", fixture("features"), "-format", format, "-output", "-"}
+			if code := run(args, &stdout, &stderr); code != exitOK {
+				t.Fatalf("run(%v) = %d, want %d\n%s", args, code, exitOK, stderr.String())
+			}
+			if stdout.Len() == 0 || strings.Contains(stdout.String(), "successfully") {
+				t.Errorf("Expected only the document on stdout, got:\n%s", stdout.String())
+			}
+			if !strings.Contains(stderr.String(), "Documentation successfully written to standard output") {
+				t.Errorf("Expected the success message on stderr, got:\n%s", stderr.String())
+			}
+			if _, err := os.Stat("-"); !os.IsNotExist(err) {
+				t.Errorf("Expected no file named -, got %v", err)
+			}
+			if logs.Len() > 0 {
+				t.Errorf("Expected nothing logged, got:\n%s", logs.String())
+			}
+		})
+	}
+}
+
+func TestStrictListsUnresolvedTypes(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "api.md")
+	file := filepath.Join(fixture("unresolved"), "api.go")
+	file, _ = filepath.Abs(file)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("unresolved"), "-strict", "-output", out}, &stdout, &stderr); code != exitInvalid {
+		t.Fatalf("run returned %d, want %d:\n%s", code, exitInvalid, stderr.String())
+	}
+	want := "Strict mode: 2 warnings reported, including 2 unresolved types:\n" +
+		"  " + file + ":24: type 'rpc.AuditLog' of the result of command 'account.Audit' is not declared in the parsed packages\n" +
+		"  " + file + ":25: type 'rpc.AuditEntry' of an additional struct of command 'account.Audit' is not declared in the parsed packages\n"
+	if !strings.Contains(stderr.String(), want) {
+		t.Errorf("Expected the error to list the unresolved types:\n%s\ngot:\n%s", want, stderr.String())
+	}
+
+	// Structs the generator cannot find are warnings, and the ones the parser reported are
+	// not repeated
+	stderr.Reset()
+	if code := run([]string{"-dir", fixture("unresolved"), "-output", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run returned %d, want %d:\n%s", code, exitOK, stderr.String())
+	}
+	if want := file + ":43: warning: struct 'users.Owner' of the result of command 'account.Owner' not found, its fields are not documented [unresolved-type]"; !strings.Contains(stderr.String(), want) {
+		t.Errorf("Expected %q, got:\n%s", want, stderr.String())
+	}
+	// Named types such as Status are documented by their underlying type
+	if strings.Contains(stderr.String(), ":31: ") {
+		t.Errorf("Expected the Status result of account.State not to be reported, got:\n%s", stderr.String())
+	}
+	// Well-known types such as time.Time have no fields to document
+	if strings.Contains(stderr.String(), ":37: ") {
+		t.Errorf("Expected the time.Time result of account.Since not to be reported, got:\n%s", stderr.String())
+	}
+	if strings.Count(stderr.String(), ":24: ") != 1 {
+		t.Errorf("Expected the result type of account.Audit to be reported once, got:\n%s", stderr.String())
+	}
+}
+
+func TestTerminalTypes(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "api.md")
+	configPath := filepath.Join(dir, "jdocgen.json")
+	config := `{"terminalTypes": {"users.Owner": "string (owner name)", "rpc.Status": "string (account status)"}}`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-dir", fixture("unresolved"), "-config", configPath, "-terminal-type", "users.Owner=string (owner email)", "-v", "-output", out}
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run returned %d, want %d:\n%s", code, exitOK, stderr.String())
+	}
+	// Terminal types have no struct to report as missing
+	if strings.Contains(stderr.String(), ":31: ") {
+		t.Errorf("Expected the Status result of account.State not to be reported, got:\n%s", stderr.String())
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| result | string (account status) | The status |\n",
+		"| result | string (RFC 3339 timestamp) | The creation time |\n",
+		// The flag wins over the configuration file
+		"| result | string (owner email) | The owner |\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected the documentation to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestKeepGoing(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "api.md")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("broken"), "-keep-going", "-output", out}, &stdout, &stderr); code != exitPartial {
+		t.Fatalf("run returned %d, want %d:\n%s", code, exitPartial, stderr.String())
+	}
+	for _, want := range []string{
+		"Documented 2 incomplete commands as stubs\n",
+		"broken.go:4): its file failed to parse at line 6: missing ',' in parameter list\n",
+		"with 2 incomplete commands",
+	} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, stderr.String())
+		}
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "## user.List\n\n> **⚠ documentation incomplete:** its file failed to parse at line 6") {
+		t.Errorf("Expected a stub for user.List, got:\n%s", content)
+	}
+	if !strings.Contains(string(content), "## user.Get\n") {
+		t.Errorf("Expected user.Get to be documented, got:\n%s", content)
+	}
+}
+
+func TestSummaryAndBadge(t *testing.T) {
+	dir := t.TempDir()
+	summary, badge := filepath.Join(dir, "summary.json"), filepath.Join(dir, "badge.svg")
+	args := []string{"-dir", fixture("features"), "-output", filepath.Join(dir, "api.md"), "-summary", summary, "-badge", badge, "-badge-formula", "errors", "-no-clobber"}
+
+	// Running twice checks that -no-clobber accepts the files written the first time
+	for i := 0; i < 2; i++ {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != exitOK {
+			t.Fatalf("run(%v) = %d, want %d\n%s", args, code, exitOK, stderr.String())
+		}
+	}
+
+	content, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+	var health map[string]any
+	if err := json.Unmarshal(content, &health); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"schemaVersion", "commands", "withExamplesPercent", "withErrorsPercent", "deprecated", "warnings"} {
+		if _, ok := health[key]; !ok {
+			t.Errorf("Expected %q in the summary, got:\n%s", key, content)
+		}
+	}
+
+	svg, err := os.ReadFile(badge)
+	if err != nil {
+		t.Fatalf("Failed to read badge: %v", err)
+	}
+	if want := fmt.Sprintf("API docs: %.0f%%", health["withErrorsPercent"]); !strings.Contains(string(svg), want) {
+		t.Errorf("Expected the badge to show %q, got:\n%s", want, svg)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	out := func(name string) string { return filepath.Join(dir, name) }
+	writeConfig := func(name, content string) string {
+		if err := os.WriteFile(out(name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return out(name)
+	}
+	configPath := writeConfig("jdocgen.json", `{
+		"omitEmptySections": false,
+		"profiles": {
+			"public": {"output": "`+out("public.md")+`", "omit-rfc": true},
+			"internal": {"output": "`+out("internal.md")+`", "audience": "internal", "omit-empty-sections": true}
+		}
+	}`)
+	read := func(name string) string {
+		content, err := os.ReadFile(out(name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		return string(content)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("audience"), "-config", configPath, "-all-profiles"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run(-all-profiles) = %d, want %d\n%s", code, exitOK, stderr.String())
+	}
+	public, internal := read("public.md"), read("internal.md")
+	if strings.Contains(public, "admin.Reindex") || strings.Contains(public, "password_hash") || strings.Contains(public, "JSON-RPC 2.0") {
+		t.Errorf("Expected the public profile to leave out internal commands, hidden fields and the preamble, got:\n%s", public)
+	}
+	if !strings.Contains(internal, "admin.Reindex") || !strings.Contains(internal, "password_hash") || !strings.Contains(internal, "JSON-RPC 2.0") {
+		t.Errorf("Expected the internal profile to document everything, got:\n%s", internal)
+	}
+	// The profile wins over the top-level configuration
+	if strings.Contains(internal, "This method takes no parameters.") {
+		t.Errorf("Expected the internal profile to omit empty sections, got:\n%s", internal)
+	}
+	// Every profile reports separately
+	for _, want := range []string{
+		"[internal] Documented 1 @Hidden fields for the internal audience",
+		"[public] Left out 1 @Internal commands for the public audience",
+	} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, stderr.String())
+		}
+	}
+
+	// Flags win over the profile
+	stdout.Reset()
+	stderr.Reset()
+	args := []string{"-dir", fixture("audience"), "-config", configPath, "-profile", "public", "-audience", "internal", "-output", out("override.md")}
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run(%v) = %d, want %d\n%s", args, code, exitOK, stderr.String())
+	}
+	if override := read("override.md"); !strings.Contains(override, "admin.Reindex") || strings.Contains(override, "JSON-RPC 2.0") {
+		t.Errorf("Expected -audience to override the profile and omit-rfc to apply, got:\n%s", override)
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+	}{
+		{"unknown profile", configPath, []string{"-profile", "partners"}},
+		{"all profiles with profile", configPath, []string{"-all-profiles", "-profile", "public"}},
+		{"all profiles with output", configPath, []string{"-all-profiles", "-output", out("all.md")}},
+		{"unknown profile option", writeConfig("unknown.json", `{"profiles": {"public": {"no-such-flag": true}}}`), []string{"-profile", "public"}},
+		{"profile selecting a profile", writeConfig("nested.json", `{"profiles": {"public": {"profile": "internal"}}}`), []string{"-profile", "public"}},
+		{"invalid profile value", writeConfig("value.json", `{"profiles": {"public": {"max-fields": {"a": 1}}}}`), []string{"-profile", "public"}},
+		{"profile without output", writeConfig("output.json", `{"profiles": {"public": {"omit-rfc": true}}}`), []string{"-all-profiles"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-dir", fixture("audience"), "-config", tt.config}, tt.args...)
+			var stdout, stderr bytes.Buffer
+			if code := run(args, &stdout, &stderr); code != exitUsage {
+				t.Errorf("run(%v) = %d, want %d\n%s", args, code, exitUsage, stderr.String())
+			}
+		})
+	}
+}
+
+func TestStrictGeneratorWarnings(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "api.md")
+	file, _ := filepath.Abs(filepath.Join(fixture("generatorunresolved"), "api.go"))
+
+	// users.Owner is in a package outside -dir, so only the generator finds it missing
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("generatorunresolved"), "-strict", "-output", out}, &stdout, &stderr); code != exitInvalid {
+		t.Fatalf("run returned %d, want %d:\n%s", code, exitInvalid, stderr.String())
+	}
+	want := "Strict mode: 1 warnings reported, including 1 unresolved types:\n" +
+		"  " + file + ":10: struct 'users.Owner' of the result of command 'account.Owner' not found, its fields are not documented\n"
+	if !strings.Contains(stderr.String(), want) {
+		t.Errorf("Expected the error to list the unresolved type:\n%s\ngot:\n%s", want, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "successfully") {
+		t.Errorf("Expected no success message, got:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,472 @@
+// preview.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pablolagos/jdocgen/generator"
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// runPreview implements `jdocgen preview`, which browses the documentation of a project in the
+// terminal without writing any file. When stdin or stdout is not a terminal, it prints the
+// cheat sheet instead.
+func runPreview(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("preview", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var dirPaths listFlag
+	flags.Var(&dirPaths, "dir", "Directory to parse for Go source files, repeatable (default .)")
+	var exclude listFlag
+	flags.Var(&exclude, "exclude", "Leave out the files and directories matching this glob pattern (repeatable, comma-separated)")
+	audience := flags.String("audience", parser.AudiencePublic, "Audience of the documentation: public or internal")
+	allowDuplicateCommands := flags.Bool("allow-duplicate-commands", false, "Preview the first of the functions declaring the same command instead of failing")
+	var withFeatures, withoutFeatures listFlag
+	flags.Var(&withFeatures, "with-feature", "Preview only commands whose @Feature flags are all enabled (repeatable, comma-separated)")
+	flags.Var(&withoutFeatures, "without-feature", "Leave out commands with this @Feature flag (repeatable, comma-separated)")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return withExitCode(exitUsage, err)
+	}
+	if flags.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", flags.Arg(0))
+	}
+	if *audience != parser.AudiencePublic && *audience != parser.AudienceInternal {
+		return usageErrorf("invalid audience %q: expected %s or %s", *audience, parser.AudiencePublic, parser.AudienceInternal)
+	}
+
+	if len(dirPaths) == 0 {
+		dirPaths = listFlag{"."}
+	}
+	parseOpts := parser.Options{Exclude: exclude, AllowDuplicateCommands: *allowDuplicateCommands}
+	if err := parseOpts.Validate(); err != nil {
+		return withExitCode(exitUsage, err)
+	}
+
+	features := parser.FeatureFilter{With: withFeatures, Without: withoutFeatures}
+	load := func() (*parser.Result, error) {
+		return loadPreview(dirPaths, parseOpts, features, *audience)
+	}
+	result, err := load()
+	if err != nil {
+		return err
+	}
+
+	in, inFile := stdin.(*os.File)
+	out, outFile := stdout.(*os.File)
+	if inFile && outFile && isTerminal(in) && isTerminal(out) {
+		restore, err := rawTerminal(in)
+		if err == nil {
+			defer restore()
+			return browse(in, out, result, load)
+		}
+		fmt.Fprintf(stderr, "Interactive preview unavailable: %v\n", err)
+	}
+
+	// Without a terminal, print what would be browsed
+	for _, diag := range result.Diagnostics {
+		if diag.Severity != parser.SeverityInfo {
+			fmt.Fprintln(stderr, diag)
+		}
+	}
+	return generator.WriteCheatSheet(stdout, result.Functions, result.Structs, result.ProjectInfo, generator.Options{})
+}
+
+// loadPreview parses dirs and selects the commands and fields documented for the feature
+// filters and the audience, like generate does.
+func loadPreview(dirs []string, parseOpts parser.Options, features parser.FeatureFilter, audience string) (*parser.Result, error) {
+	absDirs, err := absolutePaths(dirs)
+	if err != nil {
+		return nil, usageErrorf("Error resolving directory path: %v", err)
+	}
+	result, err := parser.ParseProjects(absDirs, parseOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing project: %v", err)
+	}
+	result.FilterFeatures(features)
+	if _, err := result.ApplyAudience(audience); err != nil {
+		return nil, usageErrorf("%v", err)
+	}
+	result.ApplySuppressions()
+	return result, nil
+}
+
+// browse runs the interactive preview on the terminal in raw mode until the user quits,
+// parsing the project again with load when asked to.
+func browse(tty *os.File, out io.Writer, result *parser.Result, load func() (*parser.Result, error)) error {
+	fmt.Fprint(out, ansiEnterScreen)
+	defer fmt.Fprint(out, ansiLeaveScreen)
+
+	p := &preview{}
+	p.setResult(result)
+	input := make([]byte, 64)
+	for {
+		rows, cols := terminalSize(tty)
+		drawPreview(out, p.render(rows, cols), cols)
+
+		n, err := tty.Read(input)
+		if err != nil {
+			return err
+		}
+		for _, key := range parseKeys(input[:n]) {
+			switch p.handleKey(key, rows) {
+			case previewQuit:
+				return nil
+			case previewReload:
+				result, err := load()
+				if err != nil {
+					p.status = "Reload failed: " + err.Error()
+					continue
+				}
+				p.setResult(result)
+			}
+		}
+	}
+}
+
+// previewAction is what the preview loop does after a key.
+type previewAction int
+
+const (
+	previewContinue previewAction = iota
+	previewReload
+	previewQuit
+)
+
+// previewLine is a line of the preview screen with the escape sequence styling it.
+type previewLine struct {
+	Text  string
+	Style string
+}
+
+// preview is the state of the interactive preview: the commands, the filter typed by the
+// user and what is on screen.
+type preview struct {
+	doc       generator.Document
+	filter    string
+	filtering bool  // keys are typed into the filter
+	matches   []int // indexes in doc.Commands of the commands matching the filter
+	cursor    int   // index in matches of the selected command
+	top       int   // index in matches of the first command on screen
+	viewing   bool  // the selected command is shown instead of the list
+	scroll    int   // first line of the command on screen
+	status    string
+}
+
+// setResult shows the commands of a parsed project, keeping the selected command when it is
+// still documented.
+func (p *preview) setResult(result *parser.Result) {
+	selected := ""
+	if command, ok := p.selected(); ok {
+		selected = command.Command
+	}
+
+	p.doc = generator.NewDocument(result.Functions, result.Structs, result.ProjectInfo)
+	p.match()
+	for i, index := range p.matches {
+		if p.doc.Commands[index].Command == selected {
+			p.cursor = i
+			break
+		}
+	}
+	if command, ok := p.selected(); !ok || command.Command != selected {
+		p.viewing = false
+	}
+
+	errs, warnings := result.Diagnostics.Count(parser.SeverityError), result.Diagnostics.Count(parser.SeverityWarning)
+	p.status = fmt.Sprintf("%d commands, %d errors, %d warnings", len(p.doc.Commands), errs, warnings)
+}
+
+// match selects the commands matching the filter and moves the cursor to the first one.
+func (p *preview) match() {
+	p.matches = p.matches[:0]
+	for i, command := range p.doc.Commands {
+		if fuzzyMatch(p.filter, command.Command) {
+			p.matches = append(p.matches, i)
+		}
+	}
+	p.cursor, p.top = 0, 0
+}
+
+// selected returns the command under the cursor.
+func (p *preview) selected() (models.APIFunction, bool) {
+	if p.cursor >= len(p.matches) {
+		return models.APIFunction{}, false
+	}
+	return p.doc.Commands[p.matches[p.cursor]], true
+}
+
+// handleKey updates the preview for a key pressed on a screen of rows lines.
+func (p *preview) handleKey(key string, rows int) previewAction {
+	page := max(rows-3, 1)
+	printable := utf8.RuneCountInString(key) == 1 && unicode.IsPrint([]rune(key)[0])
+	switch {
+	case key == keyInterrupt:
+		return previewQuit
+	case key == keyReload:
+		return previewReload
+	case p.filtering:
+		switch {
+		case key == keyEnter || key == keyEscape:
+			p.filtering = false
+		case key == keyBackspace && p.filter != "":
+			_, size := utf8.DecodeLastRuneInString(p.filter)
+			p.filter = p.filter[:len(p.filter)-size]
+			p.match()
+		case printable:
+			p.filter += key
+			p.match()
+		}
+	case p.viewing:
+		switch key {
+		case keyUp, "k":
+			p.scroll--
+		case keyDown, "j":
+			p.scroll++
+		case keyPageUp:
+			p.scroll -= page
+		case keyPageDown, " ":
+			p.scroll += page
+		case keyEscape, keyBackspace, "q", "h":
+			p.viewing = false
+		case "r":
+			return previewReload
+		}
+	default:
+		switch key {
+		case keyUp, "k":
+			p.cursor--
+		case keyDown, "j":
+			p.cursor++
+		case keyPageUp:
+			p.cursor -= page
+		case keyPageDown, " ":
+			p.cursor += page
+		case keyEnter, "l":
+			_, p.viewing = p.selected()
+			p.scroll = 0
+		case "/":
+			p.filtering = true
+		case keyEscape:
+			p.filter = ""
+			p.match()
+		case "r":
+			return previewReload
+		case "q":
+			return previewQuit
+		}
+		p.cursor = max(min(p.cursor, len(p.matches)-1), 0)
+	}
+	return previewContinue
+}
+
+// render returns the lines of a screen of rows lines and cols columns.
+func (p *preview) render(rows, cols int) []previewLine {
+	if command, ok := p.selected(); ok && p.viewing {
+		return p.renderCommand(command, rows, cols)
+	}
+
+	lines := []previewLine{{Text: fmt.Sprintf("%s %s: %d of %d commands", p.doc.Project.Title, p.doc.Project.Version, len(p.matches), len(p.doc.Commands)), Style: ansiBold}}
+	switch {
+	case p.filtering:
+		lines = append(lines, previewLine{Text: "Filter: " + p.filter + "_"})
+	case p.filter != "":
+		lines = append(lines, previewLine{Text: "Filter: " + p.filter})
+	default:
+		lines = append(lines, previewLine{Text: "Type / to filter commands", Style: ansiDim})
+	}
+
+	height := max(rows-3, 1)
+	if p.cursor < p.top {
+		p.top = p.cursor
+	}
+	if p.cursor >= p.top+height {
+		p.top = p.cursor - height + 1
+	}
+	if len(p.matches) == 0 {
+		lines = append(lines, previewLine{Text: "No command matches the filter.", Style: ansiDim})
+	}
+	for i := p.top; i < len(p.matches) && i < p.top+height; i++ {
+		command := p.doc.Commands[p.matches[i]]
+		line := previewLine{Text: "  " + command.Command + commandLabel(command)}
+		if i == p.cursor {
+			line = previewLine{Text: "> " + command.Command + commandLabel(command), Style: ansiReverse}
+		}
+		lines = append(lines, line)
+	}
+	return withFooter(lines, rows, p.status, "enter view, / filter, r reload, q quit")
+}
+
+// renderCommand returns the screen showing a command, scrolled to p.scroll.
+func (p *preview) renderCommand(command models.APIFunction, rows, cols int) []previewLine {
+	content := commandLines(command, cols)
+	height := max(rows-2, 1)
+	p.scroll = max(min(p.scroll, len(content)-height), 0)
+
+	lines := []previewLine{{Text: command.Command + commandLabel(command), Style: ansiBold}}
+	lines = append(lines, content[p.scroll:min(p.scroll+height, len(content))]...)
+	return withFooter(lines, rows, p.status, "esc back, r reload, ctrl-c quit")
+}
+
+// withFooter pads lines to rows-1 lines and adds the status, or the help when there is no
+// status, on the last line.
+func withFooter(lines []previewLine, rows int, status, help string) []previewLine {
+	for len(lines) < rows-1 {
+		lines = append(lines, previewLine{})
+	}
+	if status != "" {
+		help = status + " | " + help
+	}
+	return append(lines, previewLine{Text: help, Style: ansiDim})
+}
+
+// commandLabel returns the markers of a command shown after its name.
+func commandLabel(command models.APIFunction) string {
+	var label string
+	if command.Deprecated {
+		label += " (deprecated)"
+	}
+	if command.Subscription != nil {
+		label += " (subscription)"
+	}
+	return label
+}
+
+// commandLines returns the description, parameters, results and errors of a command as lines
+// of at most width columns, except for table rows, which are cut when drawn.
+func commandLines(command models.APIFunction, width int) []previewLine {
+	var lines []previewLine
+	if command.Deprecated {
+		text := "Deprecated."
+		if command.DeprecationReason != "" {
+			text = "Deprecated: " + command.DeprecationReason
+		}
+		for _, line := range wrapPreview(text, width) {
+			lines = append(lines, previewLine{Text: line, Style: ansiYellow})
+		}
+	}
+	for _, paragraph := range strings.Split(command.Description, "\n") {
+		for _, line := range wrapPreview(paragraph, width) {
+			lines = append(lines, previewLine{Text: line})
+		}
+	}
+	if command.Subscription != nil {
+		lines = append(lines, previewLine{Text: "Pushes " + command.Subscription.Method + " notifications."})
+	}
+
+	section := func(title string, empty string, rows [][]string) {
+		lines = append(lines, previewLine{}, previewLine{Text: title, Style: ansiBold})
+		if len(rows) == 0 {
+			lines = append(lines, previewLine{Text: "  " + empty, Style: ansiDim})
+			return
+		}
+		var buf bytes.Buffer
+		table := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		for _, row := range rows {
+			fmt.Fprintf(table, "  %s\n", strings.Join(row, "\t"))
+		}
+		table.Flush()
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			lines = append(lines, previewLine{Text: strings.TrimRight(line, " ")})
+		}
+	}
+
+	var params [][]string
+	for _, param := range command.Parameters {
+		required := "optional"
+		if param.Required {
+			required = "required"
+		}
+		description := oneLine(param.Description)
+		if param.Default != nil && param.Default.Value != "" {
+			description = strings.TrimSpace(description + " (default " + param.Default.Value + ")")
+		}
+		params = append(params, []string{param.Name, param.Type, required, description})
+	}
+	section("Parameters", "No parameters.", params)
+
+	var results [][]string
+	for _, result := range command.Results {
+		results = append(results, []string{result.Name, result.Type, oneLine(result.Description)})
+	}
+	section("Results", "No results.", results)
+
+	var errs [][]string
+	for _, apiErr := range command.Errors {
+		row := []string{fmt.Sprint(apiErr.Code), oneLine(apiErr.Description)}
+		if apiErr.Name != "" {
+			row = []string{fmt.Sprint(apiErr.Code), apiErr.Name, oneLine(apiErr.Description)}
+		}
+		errs = append(errs, row)
+	}
+	section("Errors", "No method-specific errors.", errs)
+	return lines
+}
+
+// oneLine joins the lines of a description, for table rows.
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// wrapPreview wraps text at width columns on spaces. Words longer than width are cut when drawn.
+func wrapPreview(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
+
+// drawPreview clears the terminal and draws lines, cut at cols columns. Lines end with "\r\n"
+// since the terminal is in raw mode.
+func drawPreview(w io.Writer, lines []previewLine, cols int) {
+	var screen strings.Builder
+	screen.WriteString(ansiClear)
+	for i, line := range lines {
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+		text := line.Text
+		if utf8.RuneCountInString(text) > cols {
+			text = string([]rune(text)[:max(cols-1, 0)]) + "…"
+		}
+		if line.Style != "" {
+			text = line.Style + text + ansiReset
+		}
+		screen.WriteString(text)
+	}
+	io.WriteString(w, screen.String())
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in name in the same order,
+// ignoring case, so "ugp" matches "users.GetProfile".
+func fuzzyMatch(pattern, name string) bool {
+	name = strings.ToLower(name)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(name, r)
+		if i < 0 {
+			return false
+		}
+		name = name[i+utf8.RuneLen(r):]
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+// preview_test.go
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"q", []string{"q"}},
+		{"\x1b[A\x1b[B", []string{keyUp, keyDown}},
+		{"\x1bOB", []string{keyDown}},
+		{"\x1b[6~\x1b[5~", []string{keyPageDown, keyPageUp}},
+		{"\x1b", []string{keyEscape}},
+		{"\r\x7f", []string{keyEnter, keyBackspace}},
+		{"\x03\x12", []string{keyInterrupt, keyReload}},
+		{"ué", []string{"u", "é"}},
+		{"\x1b[1;5C", nil},
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseKeys(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"", "users.GetProfile", true},
+		{"ugp", "users.GetProfile", true},
+		{"GETPROF", "users.GetProfile", true},
+		{"pgu", "users.GetProfile", false},
+		{"usersx", "users.GetProfile", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPreviewKeys(t *testing.T) {
+	result := &parser.Result{
+		Functions: []models.APIFunction{
+			{Command: "users.List", Description: "List users."},
+			{
+				Command:     "users.GetProfile",
+				Description: "Get the profile of a user.",
+				Parameters: []models.APIParameter{
+					{Name: "id", Type: "int", Description: "User id.", Required: true},
+					{Name: "fields", Type: "[]string", Description: "Fields to return.", Default: &models.ParamDefault{Value: "nil"}},
+				},
+				Results:           []models.APIReturn{{Name: "result", Type: "Profile", Description: "The profile."}},
+				Errors:            []models.APIError{{Code: 404, Description: "User not found."}},
+				Deprecated:        true,
+				DeprecationReason: "Use users.Get.",
+			},
+			{Command: "ping", Description: "Check the server."},
+		},
+		Structs:     map[models.StructKey]models.StructDefinition{},
+		ProjectInfo: models.ProjectInfo{Title: "Users API", Version: "1.0.0"},
+	}
+	p := &preview{}
+	p.setResult(result)
+	press := func(keys ...string) {
+		for _, key := range keys {
+			if action := p.handleKey(key, 24); action != previewContinue {
+				t.Fatalf("Expected key %q to continue, got action %d", key, action)
+			}
+		}
+	}
+	screen := func() string {
+		var text []string
+		for _, line := range p.render(24, 80) {
+			text = append(text, line.Text)
+		}
+		return strings.Join(text, "\n")
+	}
+
+	// Commands are listed sorted, and typing after / filters them
+	if got := screen(); !strings.Contains(got, "> ping\n  users.GetProfile (deprecated)\n  users.List") {
+		t.Errorf("Expected the sorted commands, got:\n%s", got)
+	}
+	press("/", "u", "g", "p", keyEnter)
+	if got := screen(); !strings.Contains(got, "1 of 3 commands") || !strings.Contains(got, "> users.GetProfile") || strings.Contains(got, "users.List") {
+		t.Errorf("Expected only users.GetProfile to match, got:\n%s", got)
+	}
+
+	// Enter shows the command, Escape goes back to the list
+	press(keyEnter)
+	got := screen()
+	for _, want := range []string{"Deprecated: Use users.Get.", "Parameters", "id      int       required  User id.", "fields  []string  optional  Fields to return. (default nil)", "404  User not found."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the command screen to contain %q, got:\n%s", want, got)
+		}
+	}
+	press(keyEscape, keyEscape)
+	if got := screen(); !strings.Contains(got, "3 of 3 commands") {
+		t.Errorf("Expected Escape to clear the filter, got:\n%s", got)
+	}
+
+	if action := p.handleKey("r", 24); action != previewReload {
+		t.Errorf("Expected r to reload, got action %d", action)
+	}
+	if action := p.handleKey("q", 24); action != previewQuit {
+		t.Errorf("Expected q to quit, got action %d", action)
+	}
+
+	// Reloading keeps the command on screen while it exists
+	press(keyDown, keyEnter)
+	p.setResult(result)
+	if !p.viewing || !strings.Contains(screen(), "users.GetProfile (deprecated)") {
+		t.Errorf("Expected users.GetProfile to stay on screen after a reload, got:\n%s", screen())
+	}
+	result.Functions = []models.APIFunction{{Command: "ping", Description: "Check the server."}}
+	p.setResult(result)
+	if p.viewing {
+		t.Errorf("Expected the list after users.GetProfile was removed, got:\n%s", screen())
+	}
+}
+
+func TestPreviewWithoutTerminal(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"preview", "-dir", fixture("features"), "-without-feature", "beta"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run returned %d:\n%s", code, stderr.String())
+	}
+	// The cheat sheet is printed instead
+	if got := stdout.String(); !strings.Contains(got, "| `payments.Pay` |") || strings.Contains(got, "beta.Preview") {
+		t.Errorf("Expected the cheat sheet without beta.Preview, got:\n%s", got)
+	}
+
+	if code := run([]string{"preview", "-audience", "everyone"}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("Expected exit code %d for an invalid audience, got %d", exitUsage, code)
+	}
+}
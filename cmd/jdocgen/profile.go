@@ -0,0 +1,99 @@
+// profile.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/config"
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// profileFlags are the flags a profile cannot set, since they select the profile itself.
+var profileFlags = map[string]bool{"config": true, "profile": true, "all-profiles": true}
+
+// applyProfile sets the flags of a configuration profile that were not given on the command
+// line, and marks them as set so the top-level configuration does not override them: flags
+// win over the profile, which wins over the rest of the configuration file.
+func applyProfile(flags *flag.FlagSet, setFlags map[string]bool, name string, profile config.Profile) error {
+	keys := make([]string, 0, len(profile))
+	for key := range profile {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if profileFlags[key] || flags.Lookup(key) == nil {
+			return fmt.Errorf("profile %q: unknown option %q", name, key)
+		}
+		if setFlags[key] {
+			continue
+		}
+		values, err := profileValues(profile[key])
+		if err != nil {
+			return fmt.Errorf("profile %q: option %q: %v", name, key, err)
+		}
+		for _, value := range values {
+			if err := flags.Set(key, value); err != nil {
+				return fmt.Errorf("profile %q: option %q: %v", name, key, err)
+			}
+		}
+		setFlags[key] = true
+	}
+	return nil
+}
+
+// profileValues returns the flag values of a profile option decoded from JSON.
+func profileValues(value any) ([]string, error) {
+	switch value := value.(type) {
+	case string:
+		return []string{value}, nil
+	case bool:
+		return []string{strconv.FormatBool(value)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(value, 'f', -1, 64)}, nil
+	case []any:
+		var values []string
+		for _, item := range value {
+			itemValues, err := profileValues(item)
+			if err != nil || len(itemValues) != 1 {
+				return nil, fmt.Errorf("expected a list of strings, numbers or booleans")
+			}
+			values = append(values, itemValues...)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("expected a string, number, boolean or list")
+}
+
+// profileNames returns the names of the profiles of a configuration, sorted.
+func profileNames(cfg config.Config) []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unknownProfileError reports a -profile naming no profile of the configuration.
+func unknownProfileError(name string, cfg config.Config) error {
+	if len(cfg.Profiles) == 0 {
+		return usageErrorf("unknown profile %q: the configuration defines no profiles", name)
+	}
+	return usageErrorf("unknown profile %q: expected one of %s", name, strings.Join(profileNames(cfg), ", "))
+}
+
+// printAudienceReport writes what the audience removed from or added to the documentation.
+func printAudienceReport(w io.Writer, prefix string, report parser.AudienceReport) {
+	if report.InternalCommands > 0 {
+		fmt.Fprintf(w, "%sLeft out %d @Internal commands for the public audience\n", prefix, report.InternalCommands)
+	}
+	if report.HiddenFields > 0 {
+		fmt.Fprintf(w, "%sDocumented %d @Hidden fields for the internal audience\n", prefix, report.HiddenFields)
+	}
+}
@@ -0,0 +1,135 @@
+// renderer_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/generator"
+)
+
+func TestJSONFormat(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "api.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("features"), "-format", "json", "-output", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run returned %d:\n%s", code, stderr.String())
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc generator.Document
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("Output is not a document: %v", err)
+	}
+	if doc.SchemaVersion != generator.DocumentSchemaVersion || len(doc.Commands) == 0 {
+		t.Errorf("Expected schema version %d and commands, got version %d and %d commands", generator.DocumentSchemaVersion, doc.SchemaVersion, len(doc.Commands))
+	}
+}
+
+func TestCheatSheetFormat(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "cheatsheet.md")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("features"), "-format", "cheatsheet", "-without-feature", "beta", "-output", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run returned %d:\n%s", code, stderr.String())
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Filters apply to the cheat sheet like to the full documentation
+	for _, command := range []string{"`ping`", "`payments.Pay`", "`payments.Refund`"} {
+		if !strings.Contains(string(content), "| "+command+" |") {
+			t.Errorf("Expected the cheat sheet to list %s, got:\n%s", command, content)
+		}
+	}
+	if strings.Contains(string(content), "beta.Preview") {
+		t.Errorf("Expected beta.Preview to be left out, got:\n%s", content)
+	}
+}
+
+func TestExternalRenderer(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available to build the example renderer")
+	}
+	dir := t.TempDir()
+	renderer := filepath.Join(dir, "renderer")
+	if runtime.GOOS == "windows" {
+		renderer += ".exe"
+	}
+	build := exec.Command("go", "build", "-o", renderer, "../../examples/renderer")
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build the example renderer: %v\n%s", err, output)
+	}
+
+	out := filepath.Join(dir, "api.txt")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("features"), "-format", "exec:" + renderer, "-output", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run returned %d:\n%s", code, stderr.String())
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "Features Fixture API 1.0.0\n\n") || !strings.Contains(string(content), "\nping: ") {
+		t.Errorf("Unexpected renderer output:\n%s", content)
+	}
+}
+
+func TestExternalRendererPathWithSpaces(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the renderer is a shell script")
+	}
+	dir := filepath.Join(t.TempDir(), "my renderers")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	renderer := filepath.Join(dir, "copy renderer.sh")
+	if err := os.WriteFile(renderer, []byte("#!/bin/sh\ncat\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The whole value is the path, not an executable followed by arguments
+	out := filepath.Join(dir, "api.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("features"), "-format", "exec:" + renderer, "-output", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run returned %d:\n%s", code, stderr.String())
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc generator.Document
+	if err := json.Unmarshal(content, &doc); err != nil || len(doc.Commands) == 0 {
+		t.Errorf("Expected the document passed through, got %v:\n%s", err, content)
+	}
+}
+
+func TestExternalRendererFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the failing renderer is a shell script")
+	}
+	dir := t.TempDir()
+	renderer := filepath.Join(dir, "fail.sh")
+	if err := os.WriteFile(renderer, []byte("#!/bin/sh\ncat > /dev/null\necho 'unsupported model' >&2\nexit 5\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "api.txt")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", fixture("features"), "-format", "exec:" + renderer, "-output", out}, &stdout, &stderr); code != 5 {
+		t.Errorf("Expected the exit code of the renderer, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "unsupported model") {
+		t.Errorf("Expected the stderr of the renderer, got:\n%s", stderr.String())
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("Expected no output after a failed renderer, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+// schema.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// annotationSchema is the document written by `jdocgen schema`.
+type annotationSchema struct {
+	Version     string              `json:"version"`
+	Scopes      []parser.Scope      `json:"scopes"`
+	Annotations []parser.Annotation `json:"annotations"`
+}
+
+// runSchema implements `jdocgen schema`, which describes the annotation grammar for editor tooling.
+func runSchema(args []string, w io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "json", "Output format: json")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return withExitCode(exitUsage, err)
+	}
+
+	if *format != "json" {
+		return usageErrorf("unsupported schema format %q: expected \"json\"", *format)
+	}
+
+	schema := annotationSchema{
+		Version:     parser.GrammarVersion,
+		Scopes:      []parser.Scope{parser.ScopeProject, parser.ScopeFunction, parser.ScopeStruct, parser.ScopeField},
+		Annotations: parser.Annotations,
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schema)
+}
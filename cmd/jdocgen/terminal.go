@@ -0,0 +1,136 @@
+// terminal.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+// Escape sequences written to the terminal by the preview.
+const (
+	ansiReset       = "\x1b[0m"
+	ansiBold        = "\x1b[1m"
+	ansiDim         = "\x1b[2m"
+	ansiReverse     = "\x1b[7m"
+	ansiYellow      = "\x1b[33m"
+	ansiClear       = "\x1b[H\x1b[2J"
+	ansiEnterScreen = "\x1b[?1049h\x1b[?25l"
+	ansiLeaveScreen = "\x1b[?25h\x1b[?1049l"
+)
+
+// Keys decoded by parseKeys. Printable characters are returned as themselves.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdown"
+	keyEnter     = "enter"
+	keyBackspace = "backspace"
+	keyEscape    = "esc"
+	keyInterrupt = "ctrl-c"
+	keyReload    = "ctrl-r"
+)
+
+// escapeKeys maps the escape sequences sent by terminals to keys.
+var escapeKeys = map[string]string{
+	"\x1b[A":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOA":  keyUp,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+}
+
+// isTerminal reports whether f is a terminal rather than a file or a pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// rawTerminal switches the terminal of tty to raw mode with stty, so keys are read as they
+// are typed and not echoed, and returns the function restoring the previous mode.
+func rawTerminal(tty *os.File) (func(), error) {
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(tty, strings.TrimSpace(saved))
+	}, nil
+}
+
+// terminalSize returns the rows and columns of the terminal of tty, or 24 by 80 when they
+// cannot be read.
+func terminalSize(tty *os.File) (int, int) {
+	var rows, cols int
+	out, err := stty(tty, "size")
+	if err == nil {
+		if _, err := fmt.Sscan(out, &rows, &cols); err == nil && rows > 0 && cols > 0 {
+			return rows, cols
+		}
+	}
+	return 24, 80
+}
+
+// stty runs stty on the terminal of tty and returns its output.
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %v", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// parseKeys decodes the bytes read from a terminal in raw mode into keys. Unknown escape
+// sequences are dropped.
+func parseKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		switch c := input[0]; {
+		case c == 0x1b:
+			n := 1
+			if len(input) > 1 && (input[1] == '[' || input[1] == 'O') {
+				n = 2
+				for n < len(input) && (input[n] < 0x40 || input[n] > 0x7e) {
+					n++
+				}
+				n = min(n+1, len(input))
+			}
+			if n == 1 {
+				keys = append(keys, keyEscape)
+			} else if key, known := escapeKeys[string(input[:n])]; known {
+				keys = append(keys, key)
+			}
+			input = input[n:]
+			continue
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyEnter)
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, keyBackspace)
+		case c == 0x03:
+			keys = append(keys, keyInterrupt)
+		case c == 0x12:
+			keys = append(keys, keyReload)
+		case c == 0x0e:
+			keys = append(keys, keyDown)
+		case c == 0x10:
+			keys = append(keys, keyUp)
+		case c < 0x20:
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, string(r))
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
@@ -0,0 +1,38 @@
+// terminaltypes.go
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// terminalTypesFlag collects repeated -terminal-type name=description flags, mapping qualified
+// type names to the description they are documented by.
+type terminalTypesFlag map[string]string
+
+func (f *terminalTypesFlag) String() string {
+	var values []string
+	for name, description := range *f {
+		values = append(values, name+"="+description)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
+
+func (f *terminalTypesFlag) Set(value string) error {
+	name, description, ok := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	if !ok || name == "" || description == "" {
+		return fmt.Errorf("invalid terminal type %q: expected name=description", value)
+	}
+	if pkg, typeName, qualified := strings.Cut(name, "."); !qualified || pkg == "" || typeName == "" {
+		return fmt.Errorf("invalid terminal type %q: the name must be qualified by its package, such as decimal.Decimal", value)
+	}
+	if *f == nil {
+		*f = make(terminalTypesFlag)
+	}
+	(*f)[name] = description
+	return nil
+}
@@ -0,0 +1,53 @@
+// validate.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pablolagos/jdocgen/parser"
+	"github.com/pablolagos/jdocgen/validate"
+)
+
+// validateOutput checks the structure of the generated Markdown, the single file outFile or
+// every Markdown file of the directory outFile in split mode, and reports the problems to w.
+// headingOffset is the -heading-offset the documentation was generated with.
+func validateOutput(w io.Writer, prefix string, outFile string, split bool, headingOffset int) error {
+	files := make(map[string]string)
+	dir := ""
+	if split {
+		dir = outFile
+		paths, err := filepath.Glob(filepath.Join(outFile, "*.md"))
+		if err != nil {
+			return fmt.Errorf("%sError listing generated files: %v", prefix, err)
+		}
+		for _, path := range paths {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("%sError reading generated file: %v", prefix, err)
+			}
+			// Split files link to each other by base name
+			files[filepath.Base(path)] = string(content)
+		}
+	} else {
+		content, err := os.ReadFile(outFile)
+		if err != nil {
+			return fmt.Errorf("%sError reading generated file: %v", prefix, err)
+		}
+		files[outFile] = string(content)
+	}
+
+	problems := validate.Documents(files, headingOffset)
+	for _, problem := range problems {
+		if dir != "" {
+			problem.File = filepath.Join(dir, problem.File)
+		}
+		fmt.Fprintf(w, "%s%s\n", prefix, problem)
+	}
+	if errs := problems.Count(parser.SeverityError); errs > 0 {
+		return withExitCode(exitInvalid, fmt.Errorf("%sOutput validation: %d problems found in the generated documentation", prefix, errs))
+	}
+	return nil
+}
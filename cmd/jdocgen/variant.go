@@ -0,0 +1,43 @@
+// variant.go
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// variant is a named source tree documented into its own output file.
+type variant struct {
+	Name string
+	Dir  string
+}
+
+// variantFlag collects repeated -variant name=dir flags.
+type variantFlag []variant
+
+func (f *variantFlag) String() string {
+	var values []string
+	for _, v := range *f {
+		values = append(values, v.Name+"="+v.Dir)
+	}
+	return strings.Join(values, ",")
+}
+
+func (f *variantFlag) Set(value string) error {
+	name, dir, ok := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	dir = strings.TrimSpace(dir)
+	if !ok || name == "" || dir == "" {
+		return fmt.Errorf("invalid variant %q: expected name=dir", value)
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid variant name %q: must not contain path separators", name)
+	}
+	for _, v := range *f {
+		if v.Name == name {
+			return fmt.Errorf("duplicate variant name %q", name)
+		}
+	}
+	*f = append(*f, variant{Name: name, Dir: dir})
+	return nil
+}
@@ -0,0 +1,131 @@
+// watch.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// watchInterval is how often -watch looks for changed Go files. A change is only regenerated
+// once the files stayed the same for a whole interval, so saving several files at once or an
+// editor writing a file in steps regenerates once.
+const watchInterval = 500 * time.Millisecond
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchSources returns the stamps of the Go files under dirs that the parser reads, leaving
+// out vendor and hidden directories and the -exclude paths like the parser, and the paths
+// under ignore, such as the output, so writing the documentation does not trigger another run.
+func watchSources(dirs []string, ignore string, parse parser.Options) (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp)
+	ignore, _ = filepath.Abs(ignore)
+	for _, dir := range dirs {
+		if err := walkSources(dir, ignore, parse, stamps); err != nil {
+			return nil, err
+		}
+	}
+	return stamps, nil
+}
+
+// walkSources adds the stamps of the Go files under dir to stamps, see watchSources.
+func walkSources(dir string, ignore string, parse parser.Options, stamps map[string]fileStamp) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Files removed while walking are picked up by the next walk
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		relPath, _ := filepath.Rel(dir, path)
+		if abs, _ := filepath.Abs(path); abs == ignore || (path != dir && parse.Excludes(relPath, info.IsDir())) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && (info.Name() == "vendor" || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".go" && !strings.HasSuffix(path, "_test.go") {
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+}
+
+// sameStamps reports whether two walks of the sources found the same files.
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		if other, exists := b[path]; !exists || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
+
+// watch implements -watch: it runs regenerate, then runs it again each time the Go files
+// under dirs that parse reads change, until ctx is done. Every run ends with a timestamped line on w; failures
+// are reported without stopping the watch.
+func watch(ctx context.Context, dirs []string, ignore string, parse parser.Options, interval time.Duration, regenerate func() error, w io.Writer) error {
+	report := func() {
+		start := time.Now()
+		err := regenerate()
+		timestamp := time.Now().Format("15:04:05")
+		if err != nil {
+			fmt.Fprintf(w, "[%s] Regeneration failed: %v\n", timestamp, err)
+			return
+		}
+		fmt.Fprintf(w, "[%s] Documentation regenerated in %s\n", timestamp, time.Since(start).Round(time.Millisecond))
+	}
+
+	watched := strings.Join(dirs, ", ")
+	last, err := watchSources(dirs, ignore, parse)
+	if err != nil {
+		return fmt.Errorf("Error watching %s: %v", watched, err)
+	}
+	report()
+	fmt.Fprintf(w, "Watching %s for changes, press Ctrl-C to stop\n", watched)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	changed := false
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(w, "Stopped watching %s\n", watched)
+			return nil
+		case <-ticker.C:
+		}
+		current, err := watchSources(dirs, ignore, parse)
+		if err != nil {
+			fmt.Fprintf(w, "[%s] Error watching %s: %v\n", time.Now().Format("15:04:05"), watched, err)
+			continue
+		}
+		if !sameStamps(current, last) {
+			last, changed = current, true
+			continue
+		}
+		if changed {
+			changed = false
+			report()
+		}
+	}
+}
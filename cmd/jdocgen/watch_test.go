@@ -0,0 +1,112 @@
+// watch_test.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// syncBuffer is a bytes.Buffer safe for the watch goroutine and the test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("api.go", "package rpc\n")
+	outDir := filepath.Join(dir, "docs")
+
+	runs := make(chan int, 10)
+	count := 0
+	regenerate := func() error {
+		count++
+		runs <- count
+		if count == 2 {
+			return errors.New("parse failed")
+		}
+		return nil
+	}
+	waitRun := func(want int) {
+		t.Helper()
+		select {
+		case got := <-runs:
+			if got != want {
+				t.Fatalf("Expected run %d, got %d", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for run %d", want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var output syncBuffer
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, []string{dir}, outDir, parser.Options{Exclude: []string{"*_gen.go", "mocks/"}}, 10*time.Millisecond, regenerate, &output)
+	}()
+
+	waitRun(1)
+	// A failed run is reported and the watch goes on
+	write("api.go", "package rpc\n\nfunc A() {}\n")
+	waitRun(2)
+	write("nested/more.go", "package nested\n")
+	waitRun(3)
+
+	// Changes to the output, vendor, hidden directories, -exclude paths, tests and other
+	// files are ignored
+	write("docs/generated.go", "package docs\n")
+	write("vendor/lib/lib.go", "package lib\n")
+	write(".cache/cache.go", "package cache\n")
+	write("api_gen.go", "package rpc\n")
+	write("nested/mocks/mock.go", "package mocks\n")
+	write("api_test.go", "package rpc\n")
+	write("README.md", "# API\n")
+	select {
+	case got := <-runs:
+		t.Fatalf("Unexpected run %d after changes to ignored files", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watch returned error: %v", err)
+	}
+	got := output.String()
+	for _, want := range []string{"Documentation regenerated in", "Regeneration failed: parse failed", "Stopped watching"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in the output:\n%s", want, got)
+		}
+	}
+}
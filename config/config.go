@@ -0,0 +1,82 @@
+// config/config.go
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config holds settings loaded from a jdocgen configuration file.
+// Pointer fields distinguish "not set" from the zero value so that
+// command-line flags can take precedence over the file.
+type Config struct {
+	// RFCTemplate is the path to a text/template file replacing the JSON-RPC preamble.
+	RFCTemplate string `json:"rfcTemplate"`
+	// IDType is the JSON type of request ids used in examples ("number" or "string").
+	IDType string `json:"idType"`
+	// SupportsBatch toggles the batch requests paragraph in the preamble.
+	SupportsBatch *bool `json:"supportsBatch"`
+	// SupportsNotifications toggles the notifications paragraph in the preamble.
+	SupportsNotifications *bool `json:"supportsNotifications"`
+	// ExampleStyle is the style of examples ("json" or "jsonc").
+	ExampleStyle string `json:"exampleStyle"`
+	// ExampleCommentLength is the longest field comment in JSONC examples.
+	ExampleCommentLength int `json:"exampleCommentLength"`
+	// EmptyDescription is rendered in table cells with an empty description.
+	EmptyDescription string `json:"emptyDescription"`
+	// OmitEmptySections leaves out the sentences stating that a command has no parameters or errors.
+	OmitEmptySections *bool `json:"omitEmptySections"`
+	// StandardErrorsText is the sentence rendered for commands without errors.
+	StandardErrorsText string `json:"standardErrorsText"`
+	// MergeGlobalErrors lists the @globalerror errors in the Errors section of every command.
+	MergeGlobalErrors *bool `json:"mergeGlobalErrors"`
+	// RequireErrors reports commands without @Error annotations.
+	RequireErrors *bool `json:"requireErrors"`
+	// MinDocumented is the lowest accepted percentage of documented descriptions.
+	MinDocumented int `json:"minDocumented"`
+	// PlaceholderPatterns replaces the default placeholder patterns (TODO, FIXME, ...)
+	// reported in descriptions. An empty list disables the check.
+	PlaceholderPatterns []string `json:"placeholderPatterns"`
+	// MethodPattern is the regular expression command names and former names must match.
+	MethodPattern string `json:"methodPattern"`
+	// CaseInsensitiveMethods reports command names differing only in case.
+	CaseInsensitiveMethods *bool `json:"caseInsensitiveMethods"`
+	// AllowDuplicateCommands keeps the first of the functions declaring the same command.
+	AllowDuplicateCommands *bool `json:"allowDuplicateCommands"`
+	// Exclude holds glob patterns of the files and directories left out of the parse, used
+	// when -exclude is not given.
+	Exclude []string `json:"exclude"`
+	// TerminalTypes maps qualified type names, such as "decimal.Decimal", to the description
+	// they are documented by instead of as a struct. -terminal-type flags add to them.
+	TerminalTypes map[string]string `json:"terminalTypes"`
+	// BadgeFormula computes the percentage shown by the -badge badge.
+	BadgeFormula string `json:"badgeFormula"`
+	// Profiles are named sets of options selected with -profile, such as one for the public
+	// documentation and one for the internal documentation.
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Profile maps command-line flag names, without the leading dash, to their values: a string,
+// number or boolean, or a list of them for repeatable flags. For example
+// {"output": "docs/public.md", "audience": "public", "without-feature": ["beta"]}.
+type Profile map[string]any
+
+// Load reads a JSON configuration file. Unknown keys are rejected so typos do not go unnoticed.
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	file, err := os.Open(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to open config file: %v", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,58 @@
+// main.go
+//
+// Command renderer is an example external renderer for jdocgen. It reads the document model
+// from stdin and writes a plain-text list of the commands and their parameters to stdout:
+//
+//	jdocgen -format exec:./renderer -output API.txt
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// supportedSchemaVersion is the version of the document schema this renderer understands.
+const supportedSchemaVersion = 2
+
+// document holds the part of the document model used by this renderer.
+type document struct {
+	SchemaVersion int `json:"schemaVersion"`
+	Project       struct {
+		Title   string
+		Version string
+	} `json:"project"`
+	Commands []struct {
+		Command     string
+		Description string
+		Parameters  []struct {
+			Name     string
+			Type     string
+			Required bool
+		}
+	} `json:"commands"`
+}
+
+func main() {
+	var doc document
+	if err := json.NewDecoder(os.Stdin).Decode(&doc); err != nil {
+		fmt.Fprintf(os.Stderr, "renderer: failed to read the document: %v\n", err)
+		os.Exit(1)
+	}
+	if doc.SchemaVersion != supportedSchemaVersion {
+		fmt.Fprintf(os.Stderr, "renderer: unsupported schema version %d, expected %d\n", doc.SchemaVersion, supportedSchemaVersion)
+		os.Exit(2)
+	}
+
+	fmt.Printf("%s %s\n\n", doc.Project.Title, doc.Project.Version)
+	for _, command := range doc.Commands {
+		fmt.Printf("%s: %s\n", command.Command, command.Description)
+		for _, param := range command.Parameters {
+			optional := ""
+			if !param.Required {
+				optional = ", optional"
+			}
+			fmt.Printf("  %s (%s%s)\n", param.Name, param.Type, optional)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// generator/anchors.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// anchorRegistry tracks the headings written to a document and the anchors
+// GitHub assigns to them, so links can point at the right heading.
+type anchorRegistry struct {
+	counts map[string]int
+	// structs holds the anchor of the first heading documenting each struct.
+	structs map[models.StructKey]string
+	// commands holds the anchor of the heading of each command.
+	commands map[string]string
+	// offset is added to the level of every heading, so commands nest under tag sections.
+	offset int
+}
+
+func newAnchorRegistry() *anchorRegistry {
+	return &anchorRegistry{
+		counts:   make(map[string]int),
+		structs:  make(map[models.StructKey]string),
+		commands: make(map[string]string),
+	}
+}
+
+// peek returns the anchor the next heading with this text will receive.
+func (a *anchorRegistry) peek(text string) string {
+	slug := slugify(text)
+	if n := a.counts[slug]; n > 0 {
+		return fmt.Sprintf("%s-%d", slug, n)
+	}
+	return slug
+}
+
+// plan returns the anchors the headings of the structs in order will receive, skipping the
+// printed ones, so they can be linked before they are written. Structs without a heading
+// yet get the planned anchor as their first one.
+func (a *anchorRegistry) plan(order []models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, printed map[models.StructKey]bool) map[models.StructKey]string {
+	planned := make(map[string]int)
+	anchors := make(map[models.StructKey]string)
+	for _, key := range order {
+		if printed[key] {
+			continue
+		}
+		slug := slugify(structHeading(key, structDefinitions[key]))
+		anchor := slug
+		if n := a.counts[slug] + planned[slug]; n > 0 {
+			anchor = fmt.Sprintf("%s-%d", slug, n)
+		}
+		planned[slug]++
+		anchors[key] = anchor
+		if _, exists := a.structs[key]; !exists {
+			a.structs[key] = anchor
+		}
+	}
+	return anchors
+}
+
+// register records a heading and returns its anchor.
+func (a *anchorRegistry) register(text string) string {
+	anchor := a.peek(text)
+	a.counts[slugify(text)]++
+	return anchor
+}
+
+// registerHeadings registers the ATX headings of Markdown written without the registry, such
+// as the output of a template, so later headings with the same text get numbered anchors.
+// Lines of fenced code blocks are skipped.
+func (a *anchorRegistry) registerHeadings(content string) {
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case headingPattern.MatchString(line):
+			a.register(strings.TrimLeft(line, "#"))
+		}
+	}
+}
+
+// heading writes a Markdown heading of the given level and registers its anchor.
+func (a *anchorRegistry) heading(w io.Writer, level int, text string) string {
+	fmt.Fprintf(w, "%s %s\n\n", strings.Repeat("#", level+a.offset), text)
+	return a.register(text)
+}
+
+// structHeading writes the heading of a struct definition and records its anchor.
+func (a *anchorRegistry) structHeading(w io.Writer, level int, key models.StructKey, structDef models.StructDefinition) string {
+	anchor := a.heading(w, level, structHeading(key, structDef))
+	if _, exists := a.structs[key]; !exists {
+		a.structs[key] = anchor
+	}
+	return anchor
+}
+
+// writeFormerNames writes hidden anchors carrying the slugs of a command's former names,
+// so links made before a rename still land on it, and lists the former names.
+func writeFormerNames(w io.Writer, formerNames []string) {
+	if len(formerNames) == 0 {
+		return
+	}
+	names := make([]string, len(formerNames))
+	for i, former := range formerNames {
+		fmt.Fprintf(w, "<a id=\"%s\"></a>\n", slugify(former))
+		names[i] = "`" + former + "`"
+	}
+	fmt.Fprintf(w, "\nPreviously known as: %s.\n\n", strings.Join(names, ", "))
+}
+
+// slugify converts heading text to an anchor the way GitHub does: lower-case,
+// spaces become hyphens and punctuation other than '-' and '_' is removed.
+// For example, "stats.GetAllMetrics" returns "statsgetallmetrics"
+func slugify(text string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			slug.WriteRune(r)
+		case r == ' ':
+			slug.WriteRune('-')
+		}
+	}
+	return slug.String()
+}
@@ -0,0 +1,93 @@
+// generator/appendix.go
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// appendixHeading is the title of the section holding the complete definitions of truncated structs.
+const appendixHeading = "Types Appendix"
+
+// typeAppendix limits the number of rows of struct tables and collects the truncated
+// structs, whose complete definitions are written in the types appendix.
+type typeAppendix struct {
+	maxFields int
+	// file holds the appendix in split mode, it is empty when the appendix is in the same document.
+	file string
+	// files assigns structs to other files than file when the appendix is split across files.
+	files map[models.StructKey]string
+	keys  map[models.StructKey]bool
+}
+
+func newTypeAppendix(maxFields int, file string) *typeAppendix {
+	return &typeAppendix{
+		maxFields: maxFields,
+		file:      file,
+		keys:      make(map[models.StructKey]bool),
+	}
+}
+
+// visibleFields returns the fields shown in the table of structDef. Fields beyond the limit
+// are left out in declaration order, and the struct is recorded for the appendix.
+func (a *typeAppendix) visibleFields(key models.StructKey, structDef models.StructDefinition) []models.StructField {
+	if a == nil || a.maxFields <= 0 || structDef.NoTruncate || len(structDef.Fields) <= a.maxFields {
+		return structDef.Fields
+	}
+	a.keys[key] = true
+	return structDef.Fields[:a.maxFields]
+}
+
+// fileOf returns the file holding the complete definition of a struct.
+func (a *typeAppendix) fileOf(key models.StructKey) string {
+	if file, exists := a.files[key]; exists {
+		return file
+	}
+	return a.file
+}
+
+// link returns the link target of the complete definition of a struct in the appendix.
+func (a *typeAppendix) link(key models.StructKey, structDef models.StructDefinition) string {
+	return a.fileOf(key) + "#" + slugify(appendixEntry(key, structDef))
+}
+
+// fileKeys returns the truncated structs by appendix file, each sorted by package and name.
+func (a *typeAppendix) fileKeys() map[string][]models.StructKey {
+	keys := make(map[string][]models.StructKey)
+	for key := range a.keys {
+		keys[a.fileOf(key)] = append(keys[a.fileOf(key)], key)
+	}
+	for _, fileKeys := range keys {
+		sortStructKeys(fileKeys)
+	}
+	return keys
+}
+
+// appendixEntry returns the heading text of a struct in the appendix. It differs from the
+// inline heading so both can be linked.
+func appendixEntry(key models.StructKey, structDef models.StructDefinition) string {
+	return structHeading(key, structDef) + " (complete)"
+}
+
+// writeTypeAppendix writes the complete definitions of the truncated structs in keys, sorted
+// by name, each with the commands using it linked through commandLinks. Nothing is written
+// when no struct was truncated.
+func writeTypeAppendix(writer io.Writer, keys []models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, usage map[models.StructKey][]StructUse, commandLinks map[string]string, anchors *anchorRegistry, opts Options) {
+	if len(keys) == 0 {
+		return
+	}
+
+	anchors.heading(writer, 2, appendixHeading)
+	for _, key := range keys {
+		structDef := structDefinitions[key]
+		anchors.heading(writer, 3, appendixEntry(key, structDef))
+		writeUsedBy(writer, usage[key], commandLinks)
+		if structDef.Description != "" {
+			fmt.Fprintf(writer, "%s\n\n", structDef.Description)
+		}
+		fields := linkEmbedded(documentedFields(key, structDef, structDefinitions, opts), key.Package, structDefinitions, anchors)
+		writeFieldTable(writer, fields, 0, "", structLinker(key.Package, nil, structDefinitions, anchors), opts)
+	}
+}
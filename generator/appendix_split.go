@@ -0,0 +1,143 @@
+// generator/appendix_split.go
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// Supported values for Options.AppendixSplit.
+const (
+	AppendixSplitPackage = "package"
+	AppendixSplitAlpha   = "alpha"
+	AppendixSplitSize    = "size"
+)
+
+// defaultAppendixLines is the approximate number of lines of an appendix file with the size strategy.
+const defaultAppendixLines = 2000
+
+// alphaRanges are the ranges of first letters of struct names grouped by the alpha strategy.
+var alphaRanges = []struct{ from, to rune }{{'a', 'f'}, {'g', 'm'}, {'n', 's'}, {'t', 'z'}}
+
+// validateAppendixSplit checks that the appendix split strategy is supported.
+func validateAppendixSplit(strategy string) error {
+	switch strategy {
+	case "", AppendixSplitPackage, AppendixSplitAlpha, AppendixSplitSize:
+		return nil
+	}
+	return fmt.Errorf("invalid appendix split %q: expected %q, %q or %q", strategy, AppendixSplitPackage, AppendixSplitAlpha, AppendixSplitSize)
+}
+
+// appendixCandidates returns the structs documented by the commands or the result envelope
+// that are truncated and so listed in the appendix, sorted by package and name.
+func appendixCandidates(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, envelope models.ResultEnvelope, opts Options) []models.StructKey {
+	documented := make(map[models.StructKey]bool)
+	for key := range collectStructUsage(apiFunctions, structDefinitions, opts) {
+		documented[key] = true
+	}
+	var roots []models.StructKey
+	for _, member := range envelope.Members {
+		if key, found := resolveStructType(member.TypeRef); found {
+			roots = append(roots, key)
+		}
+	}
+	for _, key := range collectStructGraph(roots, structDefinitions, opts).order {
+		documented[key] = true
+	}
+
+	probe := newTypeAppendix(opts.MaxFields, "")
+	var keys []models.StructKey
+	for key := range documented {
+		structDef, exists := structDefinitions[key]
+		if table := tableStruct(key, structDef, structDefinitions, opts); exists && len(probe.visibleFields(key, table)) < len(table.Fields) {
+			keys = append(keys, key)
+		}
+	}
+	sortStructKeys(keys)
+	return keys
+}
+
+// assignAppendixFiles returns the appendix file of each struct in keys, which are sorted by
+// package and name, for a split strategy. The assignment only depends on the structs, so it
+// is the same on every run.
+func assignAppendixFiles(keys []models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, strategy string, maxLines int) map[models.StructKey]string {
+	files := make(map[models.StructKey]string)
+	switch strategy {
+	case AppendixSplitPackage:
+		for _, key := range keys {
+			files[key] = "types-" + SanitizeFileName(key.Package) + ".md"
+		}
+	case AppendixSplitAlpha:
+		for _, key := range keys {
+			files[key] = alphaAppendixFile(key.Name)
+		}
+	case AppendixSplitSize:
+		if maxLines <= 0 {
+			maxLines = defaultAppendixLines
+		}
+		// Files are filled in package order, so a package only spans files when it must
+		number, lines := 1, appendixHeaderLines
+		for _, key := range keys {
+			entry := appendixEntryLines(structDefinitions[key])
+			if lines > appendixHeaderLines && lines+entry > maxLines {
+				number, lines = number+1, appendixHeaderLines
+			}
+			files[key] = fmt.Sprintf("types-%d.md", number)
+			lines += entry
+		}
+	}
+	return files
+}
+
+// appendixFileNames returns the distinct files of an appendix assignment, sorted.
+func appendixFileNames(files map[models.StructKey]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, file := range files {
+		if !seen[file] {
+			seen[file] = true
+			names = append(names, file)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// alphaAppendixFile returns the file of the alpha strategy for a struct name, such as
+// "types-a-f.md" for "Address".
+func alphaAppendixFile(name string) string {
+	first := unicode.ToLower([]rune(name)[0])
+	for _, r := range alphaRanges {
+		if first >= r.from && first <= r.to {
+			return fmt.Sprintf("types-%c-%c.md", r.from, r.to)
+		}
+	}
+	return "types-other.md"
+}
+
+// appendixHeaderLines are the lines of an appendix file before its first entry: the marker
+// and the heading.
+const appendixHeaderLines = 4
+
+// appendixEntryLines estimates the lines of the appendix entry of a struct: heading, "Used by"
+// line, description and fields table, each followed by a blank line.
+func appendixEntryLines(structDef models.StructDefinition) int {
+	lines := 2 + 2 + len(structDef.Fields) + 3
+	if structDef.Description != "" {
+		lines += 2
+	}
+	return lines
+}
+
+// sortStructKeys sorts struct keys by package and name.
+func sortStructKeys(keys []models.StructKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Package != keys[j].Package {
+			return keys[i].Package < keys[j].Package
+		}
+		return keys[i].Name < keys[j].Name
+	})
+}
@@ -0,0 +1,125 @@
+// generator/appendix_split_test.go
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// fieldsOf returns n string fields.
+func fieldsOf(n int) []models.StructField {
+	fields := make([]models.StructField, n)
+	for i := range fields {
+		fields[i] = models.StructField{Name: "F", Type: "string"}
+	}
+	return fields
+}
+
+func TestAssignAppendixFiles(t *testing.T) {
+	keys := []models.StructKey{
+		{Package: "billing", Name: "Invoice"},
+		{Package: "rpc", Name: "Address"},
+		{Package: "rpc", Name: "Zone"},
+		{Package: "rpc", Name: "_Internal"},
+	}
+	structs := make(map[models.StructKey]models.StructDefinition)
+	for _, key := range keys {
+		structs[key] = models.StructDefinition{Name: key.Name, Fields: fieldsOf(2)}
+	}
+
+	tests := []struct {
+		strategy string
+		want     []string
+	}{
+		{AppendixSplitPackage, []string{"types-billing.md", "types-rpc.md", "types-rpc.md", "types-rpc.md"}},
+		{AppendixSplitAlpha, []string{"types-g-m.md", "types-a-f.md", "types-t-z.md", "types-other.md"}},
+	}
+	for _, tt := range tests {
+		files := assignAppendixFiles(keys, structs, tt.strategy, 0)
+		for i, key := range keys {
+			if files[key] != tt.want[i] {
+				t.Errorf("%s: expected %s in %s, got %s", tt.strategy, key.Name, tt.want[i], files[key])
+			}
+		}
+	}
+}
+
+func TestAssignAppendixFilesBySize(t *testing.T) {
+	// Each entry takes 4 + 10 + 3 = 17 lines, so two entries fit in 40 lines with the header
+	var keys []models.StructKey
+	structs := make(map[models.StructKey]models.StructDefinition)
+	for _, name := range []string{"A", "B", "C"} {
+		key := models.StructKey{Package: "big", Name: name}
+		keys = append(keys, key)
+		structs[key] = models.StructDefinition{Name: name, Fields: fieldsOf(10)}
+	}
+	huge := models.StructKey{Package: "huge", Name: "H"}
+	keys = append(keys, huge)
+	structs[huge] = models.StructDefinition{Name: "H", Fields: fieldsOf(100)}
+	small := models.StructKey{Package: "small", Name: "S"}
+	keys = append(keys, small)
+	structs[small] = models.StructDefinition{Name: "S", Fields: fieldsOf(1)}
+
+	files := assignAppendixFiles(keys, structs, AppendixSplitSize, 40)
+	// The package spanning the limit continues in the next file, and an entry larger than
+	// the limit gets a file of its own
+	want := []string{"types-1.md", "types-1.md", "types-2.md", "types-3.md", "types-4.md"}
+	for i, key := range keys {
+		if files[key] != want[i] {
+			t.Errorf("Expected %s in %s, got %s", key.Name, want[i], files[key])
+		}
+	}
+	if again := assignAppendixFiles(keys, structs, AppendixSplitSize, 40); !reflect.DeepEqual(again, files) {
+		t.Errorf("Expected the same assignment on every run, got %v and %v", files, again)
+	}
+	if got := appendixFileNames(files); !reflect.DeepEqual(got, []string{"types-1.md", "types-2.md", "types-3.md", "types-4.md"}) {
+		t.Errorf("Unexpected file names %v", got)
+	}
+}
+
+func TestSplitAppendixLinks(t *testing.T) {
+	apiFunctions, structs := usageModel()
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+	opts := Options{MaxFields: 1, AppendixSplit: AppendixSplitAlpha}
+
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	files := readDir(t, outDir)
+	if _, exists := files[splitTypesFile]; exists {
+		t.Errorf("Expected no %s with a split appendix", splitTypesFile)
+	}
+	target := "types-a-f.md#" + slugify("rpc.Address (complete)")
+	if !strings.Contains(files["office.list.md"], "see [appendix]("+target+")") {
+		t.Errorf("Expected a link to %s, got:\n%s", target, files["office.list.md"])
+	}
+	if !strings.Contains(files["types-a-f.md"], "### rpc.Address (complete)\n\nUsed by: [office.List](office.list.md), ") {
+		t.Errorf("Expected rpc.Address in types-a-f.md, got:\n%s", files["types-a-f.md"])
+	}
+
+	manifest := readManifest(t, outDir)
+	if manifest.Types != "" || !reflect.DeepEqual(manifest.TypeFiles, []string{"types-a-f.md"}) {
+		t.Errorf("Expected only types-a-f.md, got %q and %v", manifest.Types, manifest.TypeFiles)
+	}
+	if manifest.Appendix["rpc.Address"] != target {
+		t.Errorf("Expected rpc.Address at %s, got %v", target, manifest.Appendix)
+	}
+
+	// Partial regeneration links to the files of the full run
+	if err := GenerateSplitDocumentation(apiFunctions[:1], structs, projectInfo, outDir, Options{MaxFields: 1, Partial: true}); err != nil {
+		t.Fatalf("Partial GenerateSplitDocumentation returned error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outDir, manifest.Commands[apiFunctions[0].Command]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "("+target+")") {
+		t.Errorf("Expected the partial run to link to %s, got:\n%s", target, content)
+	}
+}
@@ -0,0 +1,93 @@
+// generator/badge.go
+package generator
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// badgeLabel is the left-hand text of the documentation badge.
+const badgeLabel = "API docs"
+
+//go:embed templates/badge.svg.tmpl
+var badgeTemplate string
+
+// badgeData is the data passed to the badge template. Widths are estimated from the number
+// of characters, which is close enough for the short texts of a badge.
+type badgeData struct {
+	Label      string
+	Value      string
+	Color      string
+	Width      int
+	LabelWidth int
+	ValueWidth int
+	LabelX     int
+	ValueX     int
+}
+
+// newBadgeData lays out a badge showing percent.
+func newBadgeData(percent float64) badgeData {
+	data := badgeData{
+		Label: badgeLabel,
+		Value: fmt.Sprintf("%.0f%%", percent),
+		Color: badgeColor(percent),
+	}
+	data.LabelWidth = 7*len(data.Label) + 10
+	data.ValueWidth = 7*len(data.Value) + 10
+	data.Width = data.LabelWidth + data.ValueWidth
+	data.LabelX = data.LabelWidth / 2
+	data.ValueX = data.LabelWidth + data.ValueWidth/2
+	return data
+}
+
+// badgeColor returns the background color of the value of a badge: green from 90%, yellow
+// from 75%, orange from 50% and red below.
+func badgeColor(percent float64) string {
+	switch {
+	case percent >= 90:
+		return "#4c1"
+	case percent >= 75:
+		return "#dfb317"
+	case percent >= 50:
+		return "#fe7d37"
+	default:
+		return "#e05d44"
+	}
+}
+
+// writeBadge renders the SVG badge showing percent.
+func writeBadge(w io.Writer, percent float64) error {
+	tmpl, err := template.New("badge").Parse(badgeTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse badge template: %v", err)
+	}
+	if err := tmpl.Execute(w, newBadgeData(percent)); err != nil {
+		return fmt.Errorf("failed to render badge: %v", err)
+	}
+	return nil
+}
+
+// WriteBadge writes an SVG badge such as "API docs: 97%" to path, the percentage being the
+// badge formula evaluated over the health metrics.
+func WriteBadge(path string, health Health, formula string, opts Options) error {
+	percent, err := health.BadgePercent(formula)
+	if err != nil {
+		return err
+	}
+	output := &stagedFiles{noClobber: opts.NoClobber}
+	defer output.discard()
+	err = output.write(path, func(writer *bufio.Writer) error {
+		return writeBadge(writer, percent)
+	})
+	if err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+// generator/cells_test.go
+package generator
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/validate"
+)
+
+func TestTableCell(t *testing.T) {
+	tests := map[string]string{
+		"Plain text.":                   "Plain text.",
+		"Either `a|b` or c | d.":        "Either `a\\|b` or c \\| d.",
+		"First line.\nSecond line.":     "First line.<br>Second line.",
+		"Windows\r\nline breaks.\n":     "Windows<br>line breaks.",
+		"One of:\n  - *fast*\n  - slow": "One of:<br>- *fast*<br>- slow",
+		"See [docs](https://x.io/a).":   "See [docs](https://x.io/a).",
+	}
+	for text, want := range tests {
+		if got := tableCell(text); got != want {
+			t.Errorf("tableCell(%q) = %q, expected %q", text, got, want)
+		}
+	}
+}
+
+func TestMarkdownDescriptions(t *testing.T) {
+	const description = "Returns `a|b`, *emphasized*\n- one item\n- another | item"
+	key := models.StructKey{Package: "main", Name: "Pair"}
+	structs := map[models.StructKey]models.StructDefinition{
+		key: {Name: "Pair", Description: "A pair.", Fields: []models.StructField{
+			{Name: "Left", Type: "string", JSONName: "left", Description: description},
+		}},
+	}
+	apiFunctions := []models.APIFunction{{
+		Command:     "pairs.Get",
+		Description: "Gets a pair, see [pairs](https://example.com/pairs):\n\n- by `id`\n- by *name*",
+		PackageName: "main",
+		Parameters:  []models.APIParameter{{Name: "id", Type: "string", Description: description, Required: true}},
+		Results:     []models.APIReturn{{Name: "result", Type: "Pair", Description: description}},
+		Errors:      []models.APIError{{Code: 404, Description: description}},
+	}}
+	got := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, Options{})
+
+	// Command descriptions outside tables are Markdown as written, cells are escaped
+	assertGolden(t, "markdown_descriptions", got)
+	if diagnostics := validate.Markdown("API.md", got); len(diagnostics) > 0 {
+		t.Errorf("Expected valid Markdown, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,101 @@
+// generator/cheatsheet.go
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// GenerateCheatSheet writes a one-page summary of a project to outFile: a table with the
+// method, required parameters, result type and error codes of every command, split by @Tag
+// like the full documentation, and no descriptions or struct tables. Parameters are flattened like in the full documentation,
+// so both list the same required parameters.
+func GenerateCheatSheet(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outFile string, opts Options) error {
+	projectInfo, err := prepare(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
+	}
+
+	output := documentOutput(opts)
+	defer output.discard()
+	err = output.write(outFile, func(writer *bufio.Writer) error {
+		writeCheatSheet(writer, apiFunctions, structDefinitions, projectInfo, opts)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteCheatSheet writes the cheat sheet of a project to w, such as a terminal, instead of a
+// file.
+func WriteCheatSheet(w io.Writer, apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) error {
+	projectInfo, err := prepare(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
+	}
+	writeCheatSheet(w, apiFunctions, structDefinitions, projectInfo, opts)
+	return nil
+}
+
+// writeCheatSheet writes the cheat sheet, with commands sorted and grouped by @Tag like in the
+// full documentation: a table per tag section under its heading, or a single table in a
+// project without tags.
+func writeCheatSheet(w io.Writer, apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) {
+	fmt.Fprintf(w, "# %s: Cheat Sheet\n\n", projectInfo.Title)
+	fmt.Fprintf(w, "Version: %s\n\n", projectInfo.Version)
+
+	apiFunctions = sortCommands(apiFunctions)
+	for _, section := range groupByTag(apiFunctions) {
+		if section.name != "" {
+			fmt.Fprintf(w, "## %s\n\n", section.name)
+		}
+		writeCheatSheetTable(w, section.commands, structDefinitions, projectInfo, opts)
+	}
+}
+
+// writeCheatSheetTable writes the cheat sheet table of commands.
+func writeCheatSheetTable(w io.Writer, apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) {
+	fmt.Fprintf(w, "| Method | Required parameters | Result | Errors |\n")
+	fmt.Fprintf(w, "|--------|---------------------|--------|--------|\n")
+	for _, apiFunc := range apiFunctions {
+		method := "`" + apiFunc.Command + "`" + commandMarker(apiFunc)
+
+		parameters := apiFunc.Parameters
+		if opts.FlattenParams || apiFunc.FlattenParams {
+			parameters = flattenParameters(apiFunc, structDefinitions, opts)
+		}
+		var required []string
+		for _, param := range parameters {
+			if param.Required {
+				required = append(required, fmt.Sprintf("`%s: %s`", param.Name, param.Type))
+			}
+		}
+
+		var results []string
+		for _, result := range apiFunc.Results {
+			results = append(results, "`"+result.Type+"`")
+		}
+
+		var codes []string
+		for _, apiErr := range commandErrors(apiFunc, projectInfo, opts) {
+			codes = append(codes, strconv.Itoa(apiErr.Code))
+		}
+
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n", method,
+			cellDescription(strings.Join(required, ", "), opts),
+			cellDescription(strings.Join(results, ", "), opts),
+			cellDescription(strings.Join(codes, ", "), opts))
+	}
+	fmt.Fprintf(w, "\n")
+}
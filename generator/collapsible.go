@@ -0,0 +1,68 @@
+// generator/collapsible.go
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// collapsibleTree arranges the structs of graph left to print, skipping the printed ones, in
+// the nested <details> blocks of Options.Collapsible: each struct is nested in the block of
+// the first struct printed before it that refers to it, and structs without one are tops.
+// order lists the structs depth-first, the order their headings are written in.
+func collapsibleTree(graph structGraph, printed map[models.StructKey]bool) (tops []models.StructKey, children map[models.StructKey][]models.StructKey, order []models.StructKey) {
+	position := make(map[models.StructKey]int)
+	for i, key := range graph.order {
+		position[key] = i
+	}
+	children = make(map[models.StructKey][]models.StructKey)
+	for i, key := range graph.order {
+		if printed[key] {
+			continue
+		}
+		nested := false
+		for _, ref := range graph.references[key] {
+			// Referrers printed before their referee always come first in the graph order
+			if from, exists := position[ref.From]; exists && from < i && !printed[ref.From] {
+				children[ref.From] = append(children[ref.From], key)
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			tops = append(tops, key)
+		}
+	}
+
+	var visit func(key models.StructKey)
+	visit = func(key models.StructKey) {
+		order = append(order, key)
+		for _, child := range children[key] {
+			visit(child)
+		}
+	}
+	for _, top := range tops {
+		visit(top)
+	}
+	return tops, children, order
+}
+
+// printCollapsibleStruct prints a struct definition in a <details> block summarized by its
+// name, followed in the block by the structs nested in it.
+func printCollapsibleStruct(writer io.Writer, key models.StructKey, children map[models.StructKey][]models.StructKey, graph structGraph, structDefinitions map[models.StructKey]models.StructDefinition, printed map[models.StructKey]bool, planned map[models.StructKey]string, anchors *anchorRegistry, appendix *typeAppendix, opts Options) {
+	printed[key] = true
+	structDef, exists := structDefinitions[key]
+	if !exists {
+		// Reported by printStructDefinition, there is nothing to collapse
+		printStructDefinition(writer, key, structDefinitions, graph.references[key], planned, anchors, appendix, opts)
+		return
+	}
+	fmt.Fprintf(writer, "<details>\n<summary>%s</summary>\n\n", structHeading(key, structDef))
+	printStructDefinition(writer, key, structDefinitions, graph.references[key], planned, anchors, appendix, opts)
+	for _, child := range children[key] {
+		printCollapsibleStruct(writer, child, children, graph, structDefinitions, printed, planned, anchors, appendix, opts)
+	}
+	fmt.Fprintf(writer, "</details>\n\n")
+}
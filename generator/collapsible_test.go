@@ -0,0 +1,49 @@
+// generator/collapsible_test.go
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/validate"
+)
+
+func TestCollapsibleStructs(t *testing.T) {
+	apiFunctions, structs, projectInfo := typesModel()
+	got := generateString(t, apiFunctions[:1], structs, projectInfo, Options{Collapsible: true})
+
+	// Each struct is nested in the block of the struct referring to it
+	want := "<details>\n<summary>rpc.Pagination[ReportItem]</summary>\n\n#### rpc.Pagination[ReportItem]\n\n" +
+		"A page of results.\n\n" +
+		"| Name | Type | Description | JSON Name | Required |\n|------|------|-------------|-----------|----------|\n" +
+		"| Items | [[]ReportItem](#rpcreportitem) | Items of the page. | items | Yes |\n" +
+		"| Next | string | Cursor of the next page. | next | Yes |\n\n" +
+		"<details>\n<summary>rpc.ReportItem</summary>\n\n#### rpc.ReportItem\n\n" +
+		"Referenced by: Items of rpc.Pagination[ReportItem].\n\nA report.\n\n" +
+		"| Name | Type | Description | JSON Name | Required |\n|------|------|-------------|-----------|----------|\n" +
+		"| Title | string | Title of the report. | title | Yes |\n" +
+		"| Owner | [Owner](#rpcowner) | Owner of the report. | owner | Yes |\n\n" +
+		"<details>\n<summary>rpc.Owner</summary>\n\n#### rpc.Owner\n\n" +
+		"Referenced by: Owner of rpc.ReportItem.\n\n" +
+		"| Name | Type | Description | JSON Name | Required |\n|------|------|-------------|-----------|----------|\n" +
+		"| Name | string | Name of the owner. | name | Yes |\n\n" +
+		"</details>\n\n</details>\n\n</details>\n\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Expected output to contain:\n%s\ngot:\n%s", want, got)
+	}
+	if diagnostics := validate.Markdown("API.md", got); len(diagnostics) > 0 {
+		t.Errorf("Expected valid Markdown, got %v", diagnostics)
+	}
+
+	// Apart from the blocks, the documentation is the same as without -collapsible
+	plain := generateString(t, apiFunctions[:1], structs, projectInfo, Options{})
+	if strings.Contains(plain, "<details>") {
+		t.Errorf("Expected no details blocks by default, got:\n%s", plain)
+	}
+	for _, summary := range []string{"rpc.Pagination[ReportItem]", "rpc.ReportItem", "rpc.Owner"} {
+		got = strings.Replace(got, "<details>\n<summary>"+summary+"</summary>\n\n", "", 1)
+	}
+	if got = strings.ReplaceAll(got, "</details>\n\n", ""); got != plain {
+		t.Errorf("Expected the same documentation without the details blocks, got:\n%s\nexpected:\n%s", got, plain)
+	}
+}
@@ -0,0 +1,55 @@
+// generator/defaults.go
+package generator
+
+import (
+	"strconv"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// defaultNote returns the sentence documenting the default of a parameter, such as
+// "Default: `50`." or "Default: `50` (`DefaultPageSize`)." when the value comes from a
+// constant, or "" when the parameter has no default.
+func defaultNote(param models.APIParameter) string {
+	if param.Default == nil || param.Default.Value == "" {
+		return ""
+	}
+	if param.Default.Constant != "" {
+		return "Default: `" + param.Default.Value + "` (`" + param.Default.Constant + "`)."
+	}
+	return "Default: `" + param.Default.Value + "`."
+}
+
+// paramDescription returns the description of a parameter followed by its allowed values and
+// its default, as notes.
+func paramDescription(param models.APIParameter) string {
+	description := param.Description
+	if note := enumNote(param.Enum); note != "" {
+		description = appendNote(description, note)
+	}
+	if note := defaultNote(param); note != "" {
+		description = appendNote(description, note)
+	}
+	return description
+}
+
+// defaultValue returns the JSON value of the Go literal of a default, reporting false when it
+// is not a string, number or boolean literal.
+func defaultValue(literal string) (interface{}, bool) {
+	if s, err := strconv.Unquote(literal); err == nil && literal[0] != '\'' {
+		return s, true
+	}
+	switch literal {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	if i, err := strconv.ParseInt(literal, 0, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
@@ -0,0 +1,44 @@
+// generator/defaults_test.go
+package generator
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParameterDefaults(t *testing.T) {
+	apiFunctions := []models.APIFunction{{
+		Command:     "items.List",
+		Description: "Lists items.",
+		Parameters: []models.APIParameter{
+			{Name: "page_size", Type: "int", Description: "Items per page.", Default: &models.ParamDefault{Value: "50", Constant: "DefaultPageSize"}},
+			{Name: "order", Type: "string", Default: &models.ParamDefault{Value: `"asc"`}},
+			{Name: "deleted", Type: "bool", Description: "Include deleted items.", Default: &models.ParamDefault{Value: "true"}},
+		},
+	}}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+	assertGolden(t, "parameter_defaults", generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{ExampleStyle: ExampleStyleJSONC}))
+}
+
+func TestDefaultValue(t *testing.T) {
+	tests := map[string]interface{}{
+		`"asc"`: "asc",
+		"`raw`": "raw",
+		"50":    int64(50),
+		"0x10":  int64(16),
+		"1_000": int64(1000),
+		"-0.5":  -0.5,
+		"true":  true,
+	}
+	for literal, want := range tests {
+		if got, ok := defaultValue(literal); !ok || got != want {
+			t.Errorf("defaultValue(%q) = %v, %v, want %v", literal, got, ok, want)
+		}
+	}
+	for _, literal := range []string{"", "'a'", "Default"} {
+		if got, ok := defaultValue(literal); ok {
+			t.Errorf("defaultValue(%q) = %v, want no value", literal, got)
+		}
+	}
+}
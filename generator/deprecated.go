@@ -0,0 +1,48 @@
+// generator/deprecated.go
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// deprecatedNotice is the notice of a command marked @Deprecated without a reason.
+const deprecatedNotice = "This method is deprecated and may be removed in a future version."
+
+// writeDeprecation writes the warning block of a command marked @Deprecated, with its reason
+// or a generic notice.
+func writeDeprecation(w io.Writer, apiFunc models.APIFunction) {
+	if !apiFunc.Deprecated {
+		return
+	}
+	reason := apiFunc.DeprecationReason
+	if reason == "" {
+		reason = deprecatedNotice
+	}
+	fmt.Fprintf(w, "> **Deprecated:** %s\n\n", reason)
+}
+
+// writeIncomplete writes the warning block of the stub of a command that could not be parsed,
+// documented with -keep-going.
+func writeIncomplete(w io.Writer, apiFunc models.APIFunction) {
+	fmt.Fprintf(w, "> **⚠ documentation incomplete:** %s\n>\n", apiFunc.Incomplete)
+	fmt.Fprintf(w, "> The parameters, results and errors of this method could not be read from its source.\n\n")
+}
+
+// commandMarker returns the markers written after a command in command listings, such as
+// " _(deprecated)_" and " _(subscription)_", or "" for a plain command.
+func commandMarker(apiFunc models.APIFunction) string {
+	marker := ""
+	if apiFunc.Incomplete != "" {
+		marker += " _(incomplete)_"
+	}
+	if apiFunc.Deprecated {
+		marker += " _(deprecated)_"
+	}
+	if apiFunc.Subscription != nil {
+		marker += " _(subscription)_"
+	}
+	return marker
+}
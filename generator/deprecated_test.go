@@ -0,0 +1,54 @@
+// generator/deprecated_test.go
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func deprecatedModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	apiFunctions := []models.APIFunction{
+		{Command: "users.GetProfile", Description: "Returns a profile.", Deprecated: true, DeprecationReason: "use users.GetProfileV2 instead", FormerNames: []string{"user.Profile"}},
+		{Command: "users.GetProfileV2", Description: "Returns a profile."},
+		{Command: "users.Legacy", Description: "Old endpoint.", Deprecated: true},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+	return apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo
+}
+
+func TestDeprecation(t *testing.T) {
+	apiFunctions, structs, projectInfo := deprecatedModel()
+	assertGolden(t, "deprecated", generateString(t, apiFunctions, structs, projectInfo, Options{}))
+}
+
+func TestDeprecationMarkers(t *testing.T) {
+	apiFunctions, structs, projectInfo := deprecatedModel()
+	outDir := filepath.Join(t.TempDir(), "docs")
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(outDir, splitIndexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	// The index links to files that are not written here, so it is checked without a golden
+	want := GeneratedMarker + "\n\n# Test API\n\nVersion: 1.0.0\n\n## Commands\n\n" +
+		"- [users.GetProfile](users.getprofile.md) _(deprecated)_\n" +
+		"- [users.GetProfileV2](users.getprofilev2.md)\n" +
+		"- [users.Legacy](users.legacy.md) _(deprecated)_\n"
+	if string(index) != want {
+		t.Errorf("Expected the index:\n%s\ngot:\n%s", want, index)
+	}
+
+	var sheet strings.Builder
+	writeCheatSheet(&sheet, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "deprecated_cheatsheet", sheet.String())
+
+	if health := NewHealth(NewDocument(apiFunctions, structs, projectInfo), 0, Options{}); health.Deprecated != 2 {
+		t.Errorf("Expected 2 deprecated commands in the health summary, got %d", health.Deprecated)
+	}
+}
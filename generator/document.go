@@ -0,0 +1,146 @@
+// generator/document.go
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// DocumentSchemaVersion is the version of the Document JSON schema. It is increased when
+// fields are renamed, removed or change meaning, not when fields are added.
+const DocumentSchemaVersion = 2
+
+// Document is the parsed project as written by -format json and streamed to external
+// renderers. Field names of the models are kept as they are in Go. Structs are keyed by
+// "package.Name", which encoding/json writes in sorted order.
+type Document struct {
+	SchemaVersion int                                `json:"schemaVersion"`
+	Project       models.ProjectInfo                 `json:"project"`
+	Commands      []models.APIFunction               `json:"commands"`
+	Structs       map[string]models.StructDefinition `json:"structs"`
+}
+
+// documentKey returns the key of a struct in Document.Structs.
+func documentKey(key models.StructKey) string {
+	return key.Package + "." + key.Name
+}
+
+// NewDocument returns the document of a project, with commands sorted by name. The sizes of
+// the commands include the project defaults.
+func NewDocument(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo) Document {
+	apiFunctions = sortCommands(apiFunctions)
+
+	doc := Document{
+		SchemaVersion: DocumentSchemaVersion,
+		Project:       projectInfo,
+		Commands:      make([]models.APIFunction, len(apiFunctions)),
+		Structs:       make(map[string]models.StructDefinition, len(structDefinitions)),
+	}
+	for i, apiFunc := range apiFunctions {
+		apiFunc.Sizes = apiFunc.Sizes.Or(projectInfo.Sizes)
+		doc.Commands[i] = apiFunc
+	}
+	for key, structDef := range structDefinitions {
+		doc.Structs[documentKey(key)] = structDef
+	}
+	return doc
+}
+
+// encodeDocument returns the indented JSON of the document of a project.
+func encodeDocument(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) ([]byte, error) {
+	projectInfo, err := prepare(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(NewDocument(apiFunctions, structDefinitions, projectInfo), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %v", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// GenerateJSON writes the document of a project as JSON to outFile. Like the other formats it
+// takes Options, so -only, the feature filters and -flatten-params apply to the document too.
+func GenerateJSON(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outFile string, opts Options) error {
+	content, err := encodeDocument(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
+	}
+
+	output := documentOutput(opts)
+	defer output.discard()
+	err = output.stage(outFile, func(writer *bufio.Writer) error {
+		_, err := writer.Write(content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RendererError is returned by GenerateExternal when the renderer fails. ExitCode is the
+// exit code of the renderer, or -1 when it was killed by a signal.
+type RendererError struct {
+	Renderer string
+	ExitCode int
+}
+
+func (e *RendererError) Error() string {
+	return fmt.Sprintf("renderer %s exited with code %d", e.Renderer, e.ExitCode)
+}
+
+// GenerateExternal runs renderer, the path of an executable, with the JSON document of the
+// project on its stdin, and writes its stdout to outFile. The whole value is the path, which
+// may contain spaces, so arguments are not supported. The stderr of the renderer goes to
+// stderr. The output is only written when the renderer succeeds.
+func GenerateExternal(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outFile string, renderer string, stderr io.Writer, opts Options) error {
+	renderer = strings.TrimSpace(renderer)
+	if renderer == "" {
+		return fmt.Errorf("missing external renderer")
+	}
+	content, err := encodeDocument(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(renderer)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &RendererError{Renderer: renderer, ExitCode: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run renderer %s: %v", renderer, err)
+	}
+
+	output := documentOutput(opts)
+	defer output.discard()
+	err = output.stage(outFile, func(writer *bufio.Writer) error {
+		_, err := stdout.WriteTo(writer)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
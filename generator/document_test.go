@@ -0,0 +1,64 @@
+// generator/document_test.go
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestDocumentStructsKeyed(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	structs[models.StructKey{Package: "billing", Name: "User"}] = models.StructDefinition{Name: "User", Description: "Billing account."}
+
+	content, err := encodeDocument(apiFunctions, structs, projectInfo, Options{})
+	if err != nil {
+		t.Fatalf("encodeDocument returned error: %v", err)
+	}
+	var doc struct {
+		SchemaVersion int                                `json:"schemaVersion"`
+		Structs       map[string]models.StructDefinition `json:"structs"`
+	}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+
+	if doc.SchemaVersion != DocumentSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", DocumentSchemaVersion, doc.SchemaVersion)
+	}
+	want := map[string]string{
+		"billing.User": "Billing account.",
+		"rpc.User":     "User account.",
+	}
+	got := make(map[string]string, len(doc.Structs))
+	for key, structDef := range doc.Structs {
+		got[key] = structDef.Description
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected structs %v, got %v", want, got)
+	}
+}
+
+func TestGenerationKeepsCommandOrder(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	want := []string{apiFunctions[0].Command, apiFunctions[1].Command}
+	assertOrder := func(name string) {
+		t.Helper()
+		for i, apiFunc := range apiFunctions {
+			if apiFunc.Command != want[i] {
+				t.Fatalf("Expected %s to leave command %d as %s, got %s", name, i, want[i], apiFunc.Command)
+			}
+		}
+	}
+
+	if doc := NewDocument(apiFunctions, structs, projectInfo); doc.Commands[0].Command != "stats.GetAllMetrics" {
+		t.Errorf("Expected the document commands to be sorted, got %s first", doc.Commands[0].Command)
+	}
+	assertOrder("NewDocument")
+	newHTMLData(apiFunctions, structs, projectInfo, Options{})
+	assertOrder("newHTMLData")
+	generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertOrder("WriteDocumentation")
+}
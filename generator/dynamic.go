@@ -0,0 +1,36 @@
+// generator/dynamic.go
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// dynamicKeysPhrase describes a map type whose keys are described by keys, one description
+// per nesting level, such as "Object with dynamic keys (host name) whose values are
+// `HostStats`". Levels without a description are "key". It returns "" when typ is not a map.
+func dynamicKeysPhrase(typ string, keys []string) string {
+	ref := utils.ParseType(typ).Deref()
+	if ref.Kind != models.TypeMap {
+		return ""
+	}
+
+	var phrase strings.Builder
+	phrase.WriteString("Object")
+	for level := 0; ref.Kind == models.TypeMap; level++ {
+		key := "key"
+		if level < len(keys) {
+			key = keys[level]
+		}
+		if level > 0 {
+			phrase.WriteString(" whose values are objects")
+		}
+		fmt.Fprintf(&phrase, " with dynamic keys (%s)", key)
+		ref = ref.Elem.Deref()
+	}
+	fmt.Fprintf(&phrase, " whose values are `%s`", ref.String())
+	return phrase.String()
+}
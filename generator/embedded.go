@@ -0,0 +1,132 @@
+// generator/embedded.go
+package generator
+
+import (
+	"fmt"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// maxEmbeddingDepth bounds the levels of embedded structs whose fields are promoted, so
+// embedding cycles end. Embedded structs below it are documented as a field.
+const maxEmbeddingDepth = 5
+
+// promotedField is a field of a struct or of one of its embedded structs.
+type promotedField struct {
+	field models.StructField
+	// depth is the number of embedded structs the field is promoted through.
+	depth int
+}
+
+// promotedFields returns the fields of the struct identified by key as encoding/json writes
+// them: the fields of embedded structs without a JSON name take the place of the embedded
+// field. A field hides the fields of the same JSON name embedded deeper, and fields of the
+// same JSON name at the same depth hide each other. The fields of embedded pointers are
+// optional, since they are absent when the pointer is nil.
+func promotedFields(key models.StructKey, structDef models.StructDefinition, structDefinitions map[models.StructKey]models.StructDefinition) []models.StructField {
+	if !hasEmbeddedStruct(key, structDef, structDefinitions) {
+		return structDef.Fields
+	}
+
+	var candidates []promotedField
+	embedding := map[models.StructKey]bool{key: true}
+	var walk func(key models.StructKey, fields []models.StructField, depth int, optional bool)
+	walk = func(key models.StructKey, fields []models.StructField, depth int, optional bool) {
+		for _, field := range fields {
+			embeddedKey, embedded := embeddedStruct(field, key.Package, structDefinitions)
+			if embedded && depth < maxEmbeddingDepth && !embedding[embeddedKey] {
+				embedding[embeddedKey] = true
+				walk(embeddedKey, structDefinitions[embeddedKey].Fields, depth+1, optional || isPointerField(field))
+				delete(embedding, embeddedKey)
+				continue
+			}
+			if optional {
+				field.Omitempty = true
+			}
+			candidates = append(candidates, promotedField{field: field, depth: depth})
+		}
+	}
+	walk(key, structDef.Fields, 0, false)
+
+	shallowest := make(map[string]int)
+	count := make(map[string]int)
+	for _, candidate := range candidates {
+		name := candidate.field.JSONName
+		if depth, seen := shallowest[name]; !seen || candidate.depth < depth {
+			shallowest[name] = candidate.depth
+			count[name] = 0
+		}
+		if candidate.depth == shallowest[name] {
+			count[name]++
+		}
+	}
+	var fields []models.StructField
+	for _, candidate := range candidates {
+		name := candidate.field.JSONName
+		if candidate.depth == shallowest[name] && count[name] == 1 {
+			fields = append(fields, candidate.field)
+		}
+	}
+	return fields
+}
+
+// hasEmbeddedStruct reports whether a struct embeds a documented struct.
+func hasEmbeddedStruct(key models.StructKey, structDef models.StructDefinition, structDefinitions map[models.StructKey]models.StructDefinition) bool {
+	for _, field := range structDef.Fields {
+		if _, embedded := embeddedStruct(field, key.Package, structDefinitions); embedded {
+			return true
+		}
+	}
+	return false
+}
+
+// embeddedStruct returns the struct embedded by a field of a struct of package pkg, directly
+// or through a pointer, when the field has no JSON name and its struct is documented.
+func embeddedStruct(field models.StructField, pkg string, structDefinitions map[models.StructKey]models.StructDefinition) (models.StructKey, bool) {
+	if !field.Embedded || field.Excluded {
+		return models.StructKey{}, false
+	}
+	key, found := resolveStructType(typeRefOf(field.TypeRef, field.Type, pkg, nil, structDefinitions))
+	if !found {
+		return models.StructKey{}, false
+	}
+	_, exists := structDefinitions[key]
+	return key, exists
+}
+
+// documentedFields returns the fields listed in the table of a struct: its promoted fields,
+// or its own fields when opts.LinkEmbedded documents embedded structs on their own.
+func documentedFields(key models.StructKey, structDef models.StructDefinition, structDefinitions map[models.StructKey]models.StructDefinition, opts Options) []models.StructField {
+	if opts.LinkEmbedded {
+		return structDef.Fields
+	}
+	return promotedFields(key, structDef, structDefinitions)
+}
+
+// tableStruct returns structDef with the fields of its table, see documentedFields.
+func tableStruct(key models.StructKey, structDef models.StructDefinition, structDefinitions map[models.StructKey]models.StructDefinition, opts Options) models.StructDefinition {
+	structDef.Fields = documentedFields(key, structDef, structDefinitions, opts)
+	return structDef
+}
+
+// linkEmbedded returns the fields of a struct of package pkg with the embedded structs left
+// among them, as documented with opts.LinkEmbedded, replaced by an "embeds" row linking to
+// the table of the embedded struct. An embedded field has no JSON name of its own.
+func linkEmbedded(fields []models.StructField, pkg string, structDefinitions map[models.StructKey]models.StructDefinition, anchors *anchorRegistry) []models.StructField {
+	linked := append([]models.StructField(nil), fields...)
+	for i, field := range linked {
+		embeddedKey, embedded := embeddedStruct(field, pkg, structDefinitions)
+		if !embedded {
+			continue
+		}
+		heading := structHeading(embeddedKey, structDefinitions[embeddedKey])
+		field.Type = "embeds " + heading
+		if anchor, exists := anchors.structs[embeddedKey]; exists {
+			field.Type = fmt.Sprintf("embeds [%s](#%s)", heading, anchor)
+		}
+		field.TypeRef = &models.TypeRef{Kind: models.TypeAny, Name: field.Type}
+		field.JSONName = defaultEmptyDescription
+		linked[i] = field
+	}
+	return linked
+}
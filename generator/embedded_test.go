@@ -0,0 +1,86 @@
+// generator/embedded_test.go
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// embeddedFixture returns a user embedding its metadata by value and its audit record by
+// pointer, shadowing a field of the metadata, and a command returning it.
+func embeddedFixture() ([]models.APIFunction, map[models.StructKey]models.StructDefinition) {
+	meta := models.StructKey{Package: "rpc", Name: "Meta"}
+	audit := models.StructKey{Package: "rpc", Name: "Audit"}
+	user := models.StructKey{Package: "rpc", Name: "UserResponse"}
+	structs := map[models.StructKey]models.StructDefinition{
+		meta: {Name: "Meta", Description: "Meta holds the metadata of a record.", Fields: []models.StructField{
+			{Name: "ID", Type: "int", JSONName: "id", Description: "Identifier of the record"},
+			{Name: "Created", Type: "string", JSONName: "created", Description: "Creation time of the record"},
+			{Name: "Owner", Type: "string", JSONName: "owner", Description: "Owner of the record"},
+		}},
+		audit: {Name: "Audit", Fields: []models.StructField{
+			{Name: "UpdatedBy", Type: "string", JSONName: "updated_by", Description: "User who last changed the record"},
+			// Conflicts with Meta at the same depth, so encoding/json drops both
+			{Name: "Owner", Type: "string", JSONName: "owner", Description: "Owner of the change"},
+		}},
+		user: {Name: "UserResponse", Fields: []models.StructField{
+			{Name: "Meta", Type: "Meta", JSONName: "Meta", Embedded: true},
+			{Name: "Audit", Type: "*Audit", JSONName: "Audit", Embedded: true},
+			{Name: "Name", Type: "string", JSONName: "name", Description: "Name of the user"},
+			{Name: "Created", Type: "int64", JSONName: "created", Description: "Creation time as a Unix timestamp"},
+		}},
+	}
+	for key, structDef := range structs {
+		for i, field := range structDef.Fields {
+			structs[key].Fields[i].TypeRef = utils.ResolveTypeRef(utils.ParseType(field.Type), "rpc", nil, structs)
+		}
+	}
+	apiFunctions := []models.APIFunction{{
+		Command:     "users.Get",
+		Description: "Returns a user.",
+		PackageName: "rpc",
+		Results:     []models.APIReturn{{Name: "result", Type: "UserResponse", Description: "the user"}},
+	}}
+	return apiFunctions, structs
+}
+
+func TestEmbeddedFieldsArePromoted(t *testing.T) {
+	apiFunctions, structs := embeddedFixture()
+	got := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, Options{})
+
+	// Fields of an embedded pointer are optional, as they are absent when it is nil. The field
+	// of the struct hides the one it embeds, conflicting fields at the same depth are not
+	// written, and embedded structs have no section of their own.
+	assertGolden(t, "embedded_promoted", got)
+}
+
+func TestEmbeddingCycleEnds(t *testing.T) {
+	node := models.StructKey{Package: "rpc", Name: "Node"}
+	link := models.StructKey{Package: "rpc", Name: "Link"}
+	structs := map[models.StructKey]models.StructDefinition{
+		node: {Name: "Node", Fields: []models.StructField{
+			{Name: "Link", Type: "*Link", JSONName: "Link", Embedded: true},
+			{Name: "Name", Type: "string", JSONName: "name"},
+		}},
+		link: {Name: "Link", Fields: []models.StructField{
+			{Name: "Node", Type: "*Node", JSONName: "Node", Embedded: true},
+			{Name: "Target", Type: "string", JSONName: "target"},
+		}},
+	}
+	var names []string
+	for _, field := range promotedFields(node, structs[node], structs) {
+		names = append(names, field.JSONName)
+	}
+	if got := strings.Join(names, ","); got != "Node,target,name" {
+		t.Errorf("Expected the fields Node,target,name, got %s", got)
+	}
+}
+
+func TestLinkEmbeddedStructs(t *testing.T) {
+	apiFunctions, structs := embeddedFixture()
+	got := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, Options{LinkEmbedded: true})
+	assertGolden(t, "embedded_linked", got)
+}
@@ -0,0 +1,56 @@
+// generator/enums.go
+package generator
+
+import (
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// enumNote returns the sentence listing the values of an enum, such as "Allowed values:
+// active (Can sign in), suspended, deleted.", or "" when it has none.
+func enumNote(enum *models.EnumDefinition) string {
+	if enum == nil || len(enum.Values) == 0 {
+		return ""
+	}
+	values := make([]string, len(enum.Values))
+	for i, value := range enum.Values {
+		values[i] = value.Value
+		if value.Description != "" {
+			values[i] += " (" + value.Description + ")"
+		}
+	}
+	return "Allowed values: " + strings.Join(values, ", ") + "."
+}
+
+// fieldDescription returns the description of a field followed by its allowed values, as a
+// note.
+func fieldDescription(field models.StructField) string {
+	if note := enumNote(field.Enum); note != "" {
+		return appendNote(field.Description, note)
+	}
+	return field.Description
+}
+
+// enumSchema adds the values of an enum to the schema of a string or a number of its kind.
+// Schemas of the slices holding the enum are left as they are.
+func enumSchema(schema jsonObject, enum *models.EnumDefinition) jsonObject {
+	if enum == nil || len(enum.Values) == 0 || len(schema) != 1 || schema[0].Key != "type" {
+		return schema
+	}
+	switch typ := schema[0].Value; {
+	case enum.Values[0].Kind == "string" && typ == "string":
+	case enum.Values[0].Kind == "number" && (typ == "integer" || typ == "number"):
+	default:
+		return schema
+	}
+	var values []interface{}
+	for _, value := range enum.Values {
+		if value.Kind == "string" {
+			values = append(values, value.Value)
+		} else if number, ok := defaultValue(value.Value); ok {
+			values = append(values, number)
+		}
+	}
+	return append(schema, jsonField{Key: "enum", Value: values})
+}
@@ -0,0 +1,86 @@
+// generator/envelope.go
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// jsonrpcVersion is the protocol version of every command without an @Envelope override.
+const jsonrpcVersion = "2.0"
+
+// envelopeVersion returns the JSON-RPC version of the example envelopes of apiFunc.
+func envelopeVersion(apiFunc models.APIFunction) string {
+	if apiFunc.Envelope.JSONRPC != "" {
+		return apiFunc.Envelope.JSONRPC
+	}
+	return jsonrpcVersion
+}
+
+// envelopeIDType returns the request id type of the examples of apiFunc.
+func envelopeIDType(apiFunc models.APIFunction, opts Options) string {
+	if apiFunc.Envelope.IDType != "" {
+		return apiFunc.Envelope.IDType
+	}
+	if opts.IDType != "" {
+		return opts.IDType
+	}
+	return IDTypeNumber
+}
+
+// requestEnvelope wraps params in the JSON-RPC request envelope of apiFunc. JSON-RPC 1.0
+// requests have no "jsonrpc" member.
+func requestEnvelope(apiFunc models.APIFunction, params jsonObject, opts Options) jsonObject {
+	var request jsonObject
+	if version := envelopeVersion(apiFunc); version != "1.0" {
+		request = append(request, jsonField{Key: "jsonrpc", Value: version})
+	}
+	request = append(request, jsonField{Key: "method", Value: apiFunc.Command})
+	if len(params) > 0 {
+		request = append(request, jsonField{Key: "params", Value: params})
+	}
+	return append(request, jsonField{Key: "id", Value: exampleID(apiFunc, opts)})
+}
+
+// responseEnvelope builds the JSON-RPC response of apiFunc returning result, wrapped in the
+// result envelope unless the command has @NoEnvelope.
+func responseEnvelope(apiFunc models.APIFunction, result interface{}, envelope models.ResultEnvelope, opts Options) jsonObject {
+	if len(envelope.Members) > 0 && !apiFunc.NoEnvelope {
+		wrapped := jsonObject{}
+		for _, member := range envelope.Members {
+			value := result
+			if member.Type != models.EnvelopeResult {
+				value = placeholderOf(member.TypeRef, member.Type)
+			}
+			wrapped = append(wrapped, jsonField{Key: member.Name, Value: value})
+		}
+		result = wrapped
+	}
+
+	version := envelopeVersion(apiFunc)
+	var response jsonObject
+	if version != "1.0" {
+		response = append(response, jsonField{Key: "jsonrpc", Value: version})
+	}
+	response = append(response, jsonField{Key: "result", Value: result})
+	if version == "1.0" {
+		response = append(response, jsonField{Key: "error", Value: nil})
+	}
+	return append(response, jsonField{Key: "id", Value: exampleID(apiFunc, opts)})
+}
+
+// writeEnvelopeNote writes a note under commands whose envelope differs from the project-wide one.
+func writeEnvelopeNote(w io.Writer, apiFunc models.APIFunction, opts Options) {
+	version, idType := envelopeVersion(apiFunc), envelopeIDType(apiFunc, opts)
+	if version == jsonrpcVersion && idType == envelopeIDType(models.APIFunction{}, opts) {
+		return
+	}
+
+	versionNote := "JSON-RPC " + version
+	if version == "1.0" {
+		versionNote += " (no `jsonrpc` member)"
+	}
+	fmt.Fprintf(w, "**Envelope:** %s, %s request ids.\n\n", versionNote, idType)
+}
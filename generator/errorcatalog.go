@@ -0,0 +1,85 @@
+// generator/errorcatalog.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// errorCatalogHeading is the heading of the section listing the @errorcatalog.
+const errorCatalogHeading = "Error Catalog"
+
+// errorAnchor returns the anchor of an error code in the error catalog, such as
+// "error--32004". It only depends on the code, so links to an error stay valid when it is
+// renamed or the commands around it change.
+func errorAnchor(code int) string {
+	return "error-" + strconv.Itoa(code)
+}
+
+// writeErrorTable writes the Errors table of a command. In projects with an @errorcatalog,
+// the table names each error and links the codes of the catalog to their entry.
+func writeErrorTable(writer io.Writer, apiErrors []models.APIError, catalog []models.CatalogError, opts Options) {
+	if len(catalog) == 0 {
+		fmt.Fprintf(writer, "| Code | Description |\n")
+		fmt.Fprintf(writer, "|------|-------------|\n")
+		for _, apiError := range apiErrors {
+			fmt.Fprintf(writer, "| %d | %s |\n", apiError.Code, tableCell(apiError.Description))
+		}
+		fmt.Fprintf(writer, "\n")
+		return
+	}
+
+	fmt.Fprintf(writer, "| Code | Name | Description |\n")
+	fmt.Fprintf(writer, "|------|------|-------------|\n")
+	for _, apiError := range apiErrors {
+		code, name := strconv.Itoa(apiError.Code), ""
+		if apiError.Name != "" {
+			code = fmt.Sprintf("[%d](%s#%s)", apiError.Code, opts.indexFile, errorAnchor(apiError.Code))
+			name = "`" + apiError.Name + "`"
+		}
+		fmt.Fprintf(writer, "| %s | %s | %s |\n", code, cellDescription(name, opts), tableCell(apiError.Description))
+	}
+	fmt.Fprintf(writer, "\n")
+}
+
+// writeErrorCatalog writes the Error Catalog section: every error of the @errorcatalog with an
+// anchor derived from its code, and links to the commands returning it, or "Every command"
+// for the global errors. commandLinks maps commands to their file and anchor. Nothing is
+// written without @errorcatalog.
+func writeErrorCatalog(writer io.Writer, catalog []models.CatalogError, globalErrors []models.APIError, apiFunctions []models.APIFunction, commandLinks map[string]string, anchors *anchorRegistry, opts Options) {
+	if len(catalog) == 0 {
+		return
+	}
+	global := make(map[int]bool, len(globalErrors))
+	for _, globalError := range globalErrors {
+		global[globalError.Code] = true
+	}
+
+	anchors.heading(writer, 2, errorCatalogHeading)
+	fmt.Fprintf(writer, "Errors shared by the commands. Each code has a single description, used by every command returning it.\n\n")
+	fmt.Fprintf(writer, "| Code | Name | Description | Returned by |\n")
+	fmt.Fprintf(writer, "|------|------|-------------|-------------|\n")
+	for _, entry := range catalog {
+		if global[entry.Code] {
+			fmt.Fprintf(writer, "| <a id=\"%s\"></a>%d | `%s` | %s | Every command |\n", errorAnchor(entry.Code), entry.Code, entry.Name,
+				cellDescription(entry.Description, opts))
+			continue
+		}
+		var commands []string
+		for _, apiFunc := range apiFunctions {
+			for _, apiError := range apiFunc.Errors {
+				if apiError.Code == entry.Code {
+					commands = append(commands, fmt.Sprintf("[%s](%s)", apiFunc.Command, commandLinks[apiFunc.Command]))
+					break
+				}
+			}
+		}
+		fmt.Fprintf(writer, "| <a id=\"%s\"></a>%d | `%s` | %s | %s |\n", errorAnchor(entry.Code), entry.Code, entry.Name,
+			cellDescription(entry.Description, opts), cellDescription(strings.Join(commands, ", "), opts))
+	}
+	fmt.Fprintf(writer, "\n")
+}
@@ -0,0 +1,286 @@
+// generator/examples.go
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// jsonField is a key and value of a jsonObject. Comment is only rendered in JSONC examples.
+type jsonField struct {
+	Key     string
+	Value   interface{}
+	Comment string
+}
+
+// jsonObject is a JSON object that keeps its keys in insertion order,
+// so examples list parameters and fields in declaration order.
+type jsonObject []jsonField
+
+// MarshalJSON encodes the object with its keys in order.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// placeholderValue returns the value used in examples for a parameter: its default when it has
+// one, or else "" for strings, 0 for numbers, false for booleans, [] for slices and {} for
+// anything else.
+func placeholderValue(param models.APIParameter) interface{} {
+	if param.Default != nil {
+		if value, ok := defaultValue(param.Default.Value); ok {
+			return value
+		}
+	}
+	return placeholderOf(param.TypeRef, param.Type)
+}
+
+// placeholderOf returns the placeholder value of a type, given parsed or as a string.
+func placeholderOf(ref *models.TypeRef, typ string) interface{} {
+	if ref == nil {
+		ref = utils.ParseType(typ)
+	}
+	ref = ref.Deref()
+	switch {
+	case ref.Kind == models.TypeSlice:
+		return []interface{}{}
+	case ref.Kind == models.TypeBasic && ref.Name == "string":
+		return ""
+	case ref.Kind == models.TypeBasic && ref.Name == "bool":
+		return false
+	case ref.Kind == models.TypeBasic:
+		return 0
+	default:
+		return jsonObject{}
+	}
+}
+
+// exampleID returns the request id used in the examples of apiFunc for its id type.
+func exampleID(apiFunc models.APIFunction, opts Options) interface{} {
+	if envelopeIDType(apiFunc, opts) == IDTypeString {
+		return "1"
+	}
+	return 1
+}
+
+// minimalRequest builds a JSON-RPC request for apiFunc containing only its required parameters
+// and the parameters they @Requires.
+func minimalRequest(apiFunc models.APIFunction, opts Options) jsonObject {
+	params := jsonObject{}
+	for _, param := range exampleParameters(apiFunc, false) {
+		params = append(params, jsonField{Key: param.Name, Value: placeholderValue(param)})
+	}
+	return requestEnvelope(apiFunc, params, opts)
+}
+
+// commentedRequest builds a JSON-RPC request for apiFunc containing every parameter allowed
+// by its @ConflictsWith rules, each commented with its description, type and whether it is
+// required.
+func commentedRequest(apiFunc models.APIFunction, opts Options) jsonObject {
+	params := jsonObject{}
+	for _, param := range exampleParameters(apiFunc, true) {
+		params = append(params, jsonField{
+			Key:     param.Name,
+			Value:   placeholderValue(param),
+			Comment: fieldComment(param.Description, param.Type, param.Required, opts.ExampleCommentLength),
+		})
+	}
+	return requestEnvelope(apiFunc, params, opts)
+}
+
+// defaultExampleDepth bounds the nesting levels of structs expanded in example responses when
+// Options.ExampleDepth is not set.
+const defaultExampleDepth = 3
+
+// formatExamples are the example values of strings with a format.
+var formatExamples = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+}
+
+// encodedExamples are the beginnings of base64 payloads of common media types, shortened with
+// "..." in the examples of results with a base64 @ContentType.
+var encodedExamples = map[string]string{
+	"application/pdf": "JVBERi0xLjcK",
+	"application/zip": "UEsDBBQAAAAI",
+	"image/png":       "iVBORw0KGgo",
+	"image/jpeg":      "/9j/4AAQSkZJRg",
+}
+
+// defaultEncodedExample is the beginning of the base64 payload of other media types.
+const defaultEncodedExample = "AAECAwQFBgcI"
+
+// encodedExample returns the shortened base64 placeholder of the first base64 content type of
+// a result, such as "JVBERi0xLjcK..." for a PDF. ok is false without one.
+func encodedExample(contentTypes []models.ContentType) (example string, ok bool) {
+	for _, contentType := range contentTypes {
+		if !strings.EqualFold(contentType.Encoding, "base64") {
+			continue
+		}
+		example, known := encodedExamples[strings.ToLower(contentType.MediaType)]
+		if !known {
+			example = defaultEncodedExample
+		}
+		return example + "...", true
+	}
+	return "", false
+}
+
+// exampleValue returns the example value of a type in a response. Structs list their fields
+// under their JSON names, expanded up to depth levels of nesting and empty below, slices hold
+// a single element and maps a single "key" entry. format is the format of the field holding
+// the value, such as "date-time", used for the example of strings and well-known types.
+// contentTypes are the @ContentType of a result, whose string or []byte value is a shortened
+// base64 payload when one of them is base64 encoded.
+func exampleValue(ref *models.TypeRef, format string, contentTypes []models.ContentType, structDefinitions map[models.StructKey]models.StructDefinition, depth int) interface{} {
+	ref = ref.Deref()
+	if ref == nil {
+		return nil
+	}
+	if value, ok := formatExamples[format]; ok && (ref.Kind == models.TypeNamed || ref.Kind == models.TypeBasic && ref.Name == "string") {
+		return value
+	}
+	if value, ok := encodedExample(contentTypes); ok && (ref.Kind == models.TypeBasic && ref.Name == "string" || isByteSlice(ref)) {
+		return value
+	}
+	switch ref.Kind {
+	case models.TypeSlice:
+		if isByteSlice(ref) {
+			return ""
+		}
+		return []interface{}{exampleValue(ref.Elem, format, nil, structDefinitions, depth)}
+	case models.TypeMap:
+		return jsonObject{{Key: "key", Value: exampleValue(ref.Elem, format, nil, structDefinitions, depth)}}
+	case models.TypeStruct:
+		structDef, exists := structDefinitions[ref.Struct]
+		fields := jsonObject{}
+		if !exists || depth <= 0 {
+			return fields
+		}
+		for _, field := range promotedFields(ref.Struct, structDef, structDefinitions) {
+			if field.Excluded {
+				continue
+			}
+			fieldRef := typeRefOf(field.TypeRef, field.Type, ref.Struct.Package, nil, structDefinitions)
+			fields = append(fields, jsonField{Key: field.JSONName, Value: exampleValue(fieldRef, field.Format, nil, structDefinitions, depth-1)})
+		}
+		return fields
+	}
+	return placeholderOf(ref, "")
+}
+
+// exampleResponse builds the response to the example request of apiFunc, its result built
+// from the type and content types of its first @Result, or null without one.
+func exampleResponse(apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, envelope models.ResultEnvelope, opts Options) jsonObject {
+	var result interface{}
+	if len(apiFunc.Results) > 0 {
+		depth := opts.ExampleDepth
+		if depth == 0 {
+			depth = defaultExampleDepth
+		}
+		result = exampleValue(resultTypeRef(apiFunc, apiFunc.Results[0], structDefinitions), "", apiFunc.Results[0].ContentTypes, structDefinitions, depth)
+	}
+	return responseEnvelope(apiFunc, result, envelope, opts)
+}
+
+// writeExamples writes the example request and response of apiFunc. The request sends every
+// parameter allowed by its @ConflictsWith rules. JSON examples list the optional parameters
+// under the block, since JSON has no comments to mark them; JSONC examples comment every field
+// instead. The request and response of a subscribe command are already in its Subscription
+// section, so only the JSONC style renders its request again. Hand-written examples
+// (@ExampleRequest, @ExampleResponse) are written verbatim in place of the synthesized ones.
+func writeExamples(w io.Writer, apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options, anchors *anchorRegistry) error {
+	if apiFunc.ExampleRequest != "" {
+		anchors.heading(w, 3, "Example Request:")
+		fmt.Fprintf(w, "```json\n%s\n```\n\n", apiFunc.ExampleRequest)
+	} else if err := writeExampleRequest(w, apiFunc, opts, anchors); err != nil {
+		return err
+	}
+	if apiFunc.ExampleResponse != "" {
+		anchors.heading(w, 3, "Example Response:")
+		fmt.Fprintf(w, "```json\n%s\n```\n\n", apiFunc.ExampleResponse)
+		return nil
+	}
+	if apiFunc.Subscription != nil {
+		return nil
+	}
+
+	body, err := json.MarshalIndent(exampleResponse(apiFunc, structDefinitions, projectInfo.ResultEnvelope, opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to build example response for %s: %v", apiFunc.Command, err)
+	}
+	anchors.heading(w, 3, "Example Response:")
+	fmt.Fprintf(w, "```json\n%s\n```\n\n", body)
+	return nil
+}
+
+// writeExampleRequest writes the example request of apiFunc, see writeExamples.
+func writeExampleRequest(w io.Writer, apiFunc models.APIFunction, opts Options, anchors *anchorRegistry) error {
+	if opts.ExampleStyle == ExampleStyleJSONC {
+		body, err := marshalJSONC(commentedRequest(apiFunc, opts))
+		if err != nil {
+			return fmt.Errorf("failed to build example request for %s: %v", apiFunc.Command, err)
+		}
+		anchors.heading(w, 3, "Example Request:")
+		fmt.Fprintf(w, "_Comments describe the fields and must be removed before sending the request._\n\n")
+		fmt.Fprintf(w, "```jsonc\n%s\n```\n\n", body)
+		return nil
+	}
+	if apiFunc.Subscription != nil {
+		return nil
+	}
+
+	params := jsonObject{}
+	var optional []string
+	for _, param := range exampleParameters(apiFunc, true) {
+		params = append(params, jsonField{Key: param.Name, Value: placeholderValue(param)})
+		if !param.Required {
+			optional = append(optional, "`"+param.Name+"`")
+		}
+	}
+	body, err := json.MarshalIndent(requestEnvelope(apiFunc, params, opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to build example request for %s: %v", apiFunc.Command, err)
+	}
+	anchors.heading(w, 3, "Example Request:")
+	fmt.Fprintf(w, "```json\n%s\n```\n\n", body)
+	if len(optional) > 0 {
+		fmt.Fprintf(w, "_Optional parameters, which may be left out: %s._\n\n", strings.Join(optional, ", "))
+	}
+	return nil
+}
+
+// isByteSlice reports whether ref is a []byte, which encoding/json writes as a base64 string.
+func isByteSlice(ref *models.TypeRef) bool {
+	if ref.Kind != models.TypeSlice {
+		return false
+	}
+	elem := ref.Elem.Deref()
+	return elem != nil && elem.Kind == models.TypeBasic && (elem.Name == "byte" || elem.Name == "uint8")
+}
@@ -0,0 +1,147 @@
+// generator/filenames.go
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FileNamer turns a command or struct name into a file name without extension.
+// The result is sanitized again by the FileNames policy, so a FileNamer only
+// needs to choose the naming scheme.
+type FileNamer func(name string) string
+
+// Supported values for Options.FileNameScheme.
+const (
+	FileNameSchemeDefault = "default"
+	FileNameSchemeKebab   = "kebab"
+)
+
+// maxFileNameLength is the longest file name generated, extension and collision suffix excluded.
+const maxFileNameLength = 100
+
+// windowsReservedNames cannot be used as file names on Windows, with or without extension.
+var windowsReservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true, "com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true, "lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// fileNamerFor returns the FileNamer for a scheme name.
+func fileNamerFor(scheme string) (FileNamer, error) {
+	switch scheme {
+	case "", FileNameSchemeDefault:
+		return nil, nil
+	case FileNameSchemeKebab:
+		return KebabFileName, nil
+	default:
+		return nil, fmt.Errorf("unknown file name scheme %q: expected %q or %q", scheme, FileNameSchemeDefault, FileNameSchemeKebab)
+	}
+}
+
+// KebabFileName names files in kebab-case, splitting camel case words.
+// For example, "admin/user.ResetPassword!" returns "admin-user-reset-password".
+func KebabFileName(name string) string {
+	var b strings.Builder
+	pendingDash := false
+	var prev rune
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+				pendingDash = true
+			}
+			if pendingDash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			pendingDash = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			pendingDash = true
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// SanitizeFileName applies the file name policy used for every generated file:
+//   - only ASCII letters, digits, '.', '_' and '-' are kept, any other run of characters becomes '_'
+//   - leading and trailing '.', '_' and '-' are removed
+//   - names are lower-cased, so case-insensitive filesystems cannot produce clashes
+//   - names are cut to 100 characters
+//   - Windows reserved device names (CON, NUL, COM1, ...) get a '_' appended before any extension
+//   - an empty result becomes "_"
+func SanitizeFileName(name string) string {
+	var b strings.Builder
+	replaced := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+			replaced = false
+		} else if !replaced {
+			b.WriteRune('_')
+			replaced = true
+		}
+	}
+
+	sanitized := strings.Trim(b.String(), "._-")
+	if len(sanitized) > maxFileNameLength {
+		sanitized = strings.TrimRight(sanitized[:maxFileNameLength], "._-")
+	}
+	if sanitized == "" {
+		return "_"
+	}
+	// Windows ignores the extension when matching reserved names, so "nul.md" is reserved too
+	if base, ext, _ := strings.Cut(sanitized, "."); windowsReservedNames[base] {
+		sanitized = base + "_"
+		if ext != "" {
+			sanitized += "." + ext
+		}
+	}
+	return sanitized
+}
+
+// fileNames assigns unique file names to a set of names.
+type fileNames struct {
+	namer FileNamer
+	used  map[string]bool
+	names map[string]string
+}
+
+// newFileNames creates a file name allocator. Reserved names are never handed out.
+func newFileNames(namer FileNamer, reserved ...string) *fileNames {
+	f := &fileNames{
+		namer: namer,
+		used:  make(map[string]bool),
+		names: make(map[string]string),
+	}
+	for _, name := range reserved {
+		f.used[strings.ToLower(name)] = true
+	}
+	return f
+}
+
+// assign returns the file name, with extension, for name. Names that sanitize to a file name
+// already handed out get a "-2", "-3", ... suffix in the order they are assigned, so callers
+// must assign names in a deterministic order.
+func (f *fileNames) assign(name string, ext string) string {
+	if fileName, exists := f.names[name]; exists {
+		return fileName
+	}
+
+	base := name
+	if f.namer != nil {
+		base = f.namer(name)
+	}
+	base = SanitizeFileName(base)
+
+	fileName := base + ext
+	for i := 2; f.used[strings.ToLower(fileName)]; i++ {
+		fileName = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+
+	f.used[strings.ToLower(fileName)] = true
+	f.names[name] = fileName
+	return fileName
+}
@@ -0,0 +1,241 @@
+// generator/filenames_test.go
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"user.Get", "user.get"},
+		{"admin/user Reset!", "admin_user_reset"},
+		{"..hidden..", "hidden"},
+		{"", "_"},
+		{"???", "_"},
+		{"CON", "con_"},
+		{"nul", "nul_"},
+		{"Nul.Status", "nul_.status"},
+		{"com1", "com1_"},
+		{"console", "console"},
+		{strings.Repeat("a", 150), strings.Repeat("a", 100)},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeFileName(tt.name); got != tt.want {
+			t.Errorf("SanitizeFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKebabFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"user.Get", "user-get"},
+		{"stats.GetAllMetrics", "stats-get-all-metrics"},
+		{"admin/user.ResetPassword!", "admin-user-reset-password"},
+		{"v2.Get", "v2-get"},
+	}
+
+	for _, tt := range tests {
+		if got := KebabFileName(tt.name); got != tt.want {
+			t.Errorf("KebabFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFileNameCollisions(t *testing.T) {
+	files := newFileNames(nil, splitIndexFile, splitManifestFile)
+
+	got := []string{
+		files.assign("user.Get", ".md"),
+		files.assign("User.Get", ".md"),
+		files.assign("user/Get", ".md"),
+		files.assign("user.get", ".md"),
+		files.assign("index", ".md"),
+		files.assign("CON", ".md"),
+		files.assign("con_", ".md"),
+		files.assign("user.Get", ".md"),
+	}
+	want := []string{
+		"user.get.md",
+		"user.get-2.md",
+		"user_get.md",
+		"user.get-3.md",
+		"index-2.md",
+		"con_.md",
+		"con_-2.md",
+		"user.get.md",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("assigned file names = %q, want %q", got, want)
+	}
+}
+
+func TestSplitDocumentation(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions = append(apiFunctions,
+		models.APIFunction{Command: "User.Get", Description: "Same name, different case.", PackageName: "rpc"},
+		models.APIFunction{Command: "NUL", Description: "Reserved on Windows.", PackageName: "rpc"},
+	)
+
+	outDir := t.TempDir()
+	opts := Options{IncludeRFC: false, FileNameScheme: FileNameSchemeKebab}
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outDir, splitManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+
+	want := map[string]string{
+		"NUL":                 "nul_.md",
+		"User.Get":            "user-get.md",
+		"stats.GetAllMetrics": "stats-get-all-metrics.md",
+		"user.Get":            "user-get-2.md",
+	}
+	if manifest.Index != splitIndexFile {
+		t.Errorf("manifest index = %q, want %q", manifest.Index, splitIndexFile)
+	}
+	if !reflect.DeepEqual(manifest.Commands, want) {
+		t.Errorf("manifest commands = %v, want %v", manifest.Commands, want)
+	}
+	wantStructs := map[string]string{"rpc.User": "user-get-2.md#rpcuser"}
+	if !reflect.DeepEqual(manifest.Structs, wantStructs) {
+		t.Errorf("manifest structs = %v, want %v", manifest.Structs, wantStructs)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outDir, splitIndexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	for command, file := range want {
+		if !strings.Contains(string(index), "- ["+command+"]("+file+")") {
+			t.Errorf("index does not link %s to %s", command, file)
+		}
+		page, err := os.ReadFile(filepath.Join(outDir, file))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if !strings.HasPrefix(string(page), GeneratedMarker+"\n\n## "+command+"\n") {
+			t.Errorf("%s does not document %s:\n%s", file, command, page)
+		}
+	}
+}
+
+func TestSplitDocumentationCustomNamer(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	opts := Options{
+		FileNameScheme: "unknown",
+		FileNamer:      func(name string) string { return "cmd " + name },
+	}
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "cmd_user.get.md")); err != nil {
+		t.Errorf("custom file name not used: %v", err)
+	}
+}
+
+func TestPartialSplitDocumentation(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	outDir := t.TempDir()
+	opts := Options{FileNameScheme: FileNameSchemeKebab}
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(outDir, splitIndexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	// Files of the other commands must be left alone
+	untouched := filepath.Join(outDir, "stats-get-all-metrics.md")
+	if err := os.WriteFile(untouched, []byte("unchanged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Generation sorts the commands in place
+	var getUser models.APIFunction
+	for _, apiFunc := range apiFunctions {
+		if apiFunc.Command == "user.Get" {
+			getUser = apiFunc
+		}
+	}
+	getUser.Description = "Get a user by its id."
+	selected := []models.APIFunction{getUser, {Command: "user.Delete", Description: "Delete a user.", PackageName: "rpc"}}
+	opts.Partial = true
+	var warnings []Warning
+	opts.Warn = func(warning Warning) { warnings = append(warnings, warning) }
+	if err := GenerateSplitDocumentation(selected, structs, projectInfo, outDir, opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	wantWarning := Warning{Message: "command 'user.Delete' is not in index.md, it is missing from the index until the next full run"}
+	if len(warnings) != 1 || warnings[0] != wantWarning {
+		t.Errorf("Expected the warning %+v, got %+v", wantWarning, warnings)
+	}
+
+	if page, _ := os.ReadFile(filepath.Join(outDir, "user-get.md")); !strings.Contains(string(page), "Get a user by its id.") {
+		t.Errorf("user-get.md was not regenerated:\n%s", page)
+	}
+	if page, _ := os.ReadFile(untouched); string(page) != "unchanged\n" {
+		t.Errorf("stats-get-all-metrics.md was rewritten:\n%s", page)
+	}
+	if got, _ := os.ReadFile(filepath.Join(outDir, splitIndexFile)); string(got) != string(index) {
+		t.Errorf("index.md was rewritten:\n%s", got)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outDir, splitManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	want := map[string]string{
+		"stats.GetAllMetrics": "stats-get-all-metrics.md",
+		"user.Delete":         "user-delete.md",
+		"user.Get":            "user-get.md",
+	}
+	if !reflect.DeepEqual(manifest.Commands, want) {
+		t.Errorf("manifest commands = %v, want %v", manifest.Commands, want)
+	}
+	wantStructs := map[string]string{"rpc.User": "user-get.md#rpcuser"}
+	if !reflect.DeepEqual(manifest.Structs, wantStructs) {
+		t.Errorf("manifest structs = %v, want %v", manifest.Structs, wantStructs)
+	}
+}
+
+func TestPartialSplitWithoutManifest(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, t.TempDir(), Options{Partial: true})
+	if err == nil || !strings.Contains(err.Error(), "manifest of a full run") {
+		t.Errorf("Expected a missing manifest error, got %v", err)
+	}
+}
+
+func TestUnknownFileNameScheme(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, t.TempDir(), Options{FileNameScheme: "snake"})
+	if err == nil || !strings.Contains(err.Error(), "unknown file name scheme") {
+		t.Errorf("expected unknown scheme error, got %v", err)
+	}
+}
@@ -3,9 +3,11 @@ package generator
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"slices"
 	"sort"
 	"strings"
 
@@ -13,73 +15,375 @@ import (
 	"github.com/pablolagos/jdocgen/utils"
 )
 
-func GenerateDocumentation(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outFile string, includeRFC bool) error {
-	file, err := os.Create(outFile)
+// defaultEmptyDescription is rendered for empty descriptions when Options.EmptyDescription is not set.
+const defaultEmptyDescription = "—"
+
+// excludedFieldLabel is rendered in the JSON Name column for fields tagged json:"-".
+const excludedFieldLabel = "_excluded from JSON_"
+
+// noParametersText is rendered in place of the Parameters section of a command without parameters.
+const noParametersText = "This method takes no parameters."
+
+// defaultStandardErrorsText is rendered in place of the Errors section of a command without
+// @Error annotations when Options.StandardErrorsText is not set.
+const defaultStandardErrorsText = "No method-specific errors are defined; only standard JSON-RPC errors may be returned."
+
+// Errors returned up front for models that cannot be documented. They are wrapped in an
+// error naming the missing piece, test them with errors.Is.
+var (
+	// ErrNilInput is returned when the struct definitions map is nil. A project without
+	// structs passes an empty map; a nil or empty command slice documents no commands.
+	ErrNilInput = errors.New("nil input")
+	// ErrNoProjectInfo is returned when the project information has no title or no version.
+	ErrNoProjectInfo = errors.New("missing project information")
+	// ErrInvalidCommand is returned for a command without a name.
+	ErrInvalidCommand = errors.New("invalid command")
+)
+
+// Supported values for Options.IDType.
+const (
+	IDTypeNumber = "number"
+	IDTypeString = "string"
+)
+
+// Options controls how the documentation is rendered.
+type Options struct {
+	// IncludeRFC renders the JSON-RPC 2.0 preamble.
+	IncludeRFC bool
+	// RFCTemplate overrides the text/template source of the preamble. Empty uses DefaultRFCTemplate.
+	RFCTemplate string
+	// HTMLTemplate overrides the html/template source of GenerateHTML. Empty uses DefaultHTMLTemplate.
+	HTMLTemplate string
+	// IDType is the JSON type of request ids shown in the examples ("number" or "string").
+	IDType string
+	// SupportsBatch renders the batch requests paragraph in the preamble.
+	SupportsBatch bool
+	// SupportsNotifications renders the notifications paragraph in the preamble.
+	SupportsNotifications bool
+	// FlattenParams replaces struct-typed parameters by their fields in the Parameters table
+	// for every command. Commands can opt in individually with @FlattenParams.
+	FlattenParams bool
+	// FlattenDepth bounds the nesting levels expanded when flattening. Zero uses a default of 3.
+	FlattenDepth int
+	// CodeSamples lists the code sample languages rendered for every command ("curl").
+	CodeSamples []string
+	// Endpoint is the server URL used in code samples. Empty uses the first @server.
+	Endpoint string
+	// QuickSummary renders one-line "Requires" and "Returns" summaries under each command description.
+	QuickSummary bool
+	// ExampleStyle selects how examples are rendered: "json" (default) or "jsonc", which
+	// comments every field with its description, type and whether it is required.
+	ExampleStyle string
+	// ExampleCommentLength is the longest field comment in JSONC examples. Zero uses a default of 80.
+	ExampleCommentLength int
+	// ExampleDepth bounds the nesting levels of structs expanded in example responses, so
+	// self-referencing structs end. Zero uses a default of 3.
+	ExampleDepth int
+	// MaxFields limits the rows of struct tables, 0 means no limit. Truncated structs are
+	// listed complete in a types appendix, unless annotated with @NoTruncate.
+	MaxFields int
+	// FileNameScheme selects how split mode names files: "default" or "kebab".
+	FileNameScheme string
+	// FileNamer overrides FileNameScheme with a custom naming function.
+	FileNamer FileNamer
+	// Warn, when set, receives the warnings found while generating, such as result types
+	// without a struct definition. They are dropped otherwise: the generator does not log.
+	Warn func(warning Warning)
+	// EmptyDescription is rendered in table cells whose description is empty, so a missing
+	// comment is visible. Empty uses a default of "—". The models keep the empty descriptions.
+	EmptyDescription string
+	// LinkEmbedded documents the fields of embedded structs in the table of the embedded struct,
+	// linked from an "embeds" row, instead of promoting them into the tables of the structs
+	// embedding them the way encoding/json promotes them into their objects.
+	LinkEmbedded bool
+	// LinkTemplate is the URL of the line a command is defined at, with {repository}, {path}
+	// and {line} replaced by the @repository URL, the path of the file in the repository and
+	// the line. Empty uses the pattern of GitHub or GitLab when @repository is hosted there.
+	LinkTemplate string
+	// ShowSource writes the file and line each command is defined at when there is no link
+	// to them.
+	ShowSource bool
+	// Collapsible prints each struct documented under a command in a <details> block
+	// summarized by its name, nested in the block of the struct referring to it, so large
+	// payloads are expanded on demand.
+	Collapsible bool
+	// TypesAppendix documents every struct once, in a Types section at the end of the
+	// document, and links to it from the commands instead of repeating the tables of the
+	// structs under each of them. It has no effect on split output.
+	TypesAppendix bool
+	// StructSource renders the Go definition of each documented struct, reconstructed from
+	// its model, in a collapsed code block after its fields table.
+	StructSource bool
+	// OmitEmptySections leaves out the Parameters and Errors sections of commands without
+	// parameters or errors. By default they are replaced by a sentence, so "none" can be
+	// told from "not documented".
+	OmitEmptySections bool
+	// Wrap is the column at which paragraphs, list items and block quotes of the Markdown
+	// output are wrapped, 0 for no wrapping. Code spans and links are never broken.
+	Wrap int
+	// AlignTables pads the cells of Markdown tables so their pipes line up, which keeps the
+	// diffs of small changes small.
+	AlignTables bool
+	// HeadingOffset moves every heading of the Markdown output down this many levels, so
+	// the documentation can be embedded under the headings of a larger page. Headings stop
+	// at level 6. Anchors do not depend on the level, so links to them keep working.
+	HeadingOffset int
+	// MergeGlobalErrors lists the @globalerror errors of the project in the Errors section of
+	// every command, after its own, instead of in a single Common Errors section. A command
+	// declaring the same code keeps its own description.
+	MergeGlobalErrors bool
+	// StandardErrorsText is the sentence rendered for commands without errors. Empty uses
+	// "No method-specific errors are defined; only standard JSON-RPC errors may be returned."
+	StandardErrorsText string
+	// AppendixSplit spreads the types appendix of split mode over several files: one per
+	// package ("package"), per range of first letters ("alpha"), or filled up to
+	// AppendixLines lines ("size"). Empty writes a single types.md.
+	AppendixSplit string
+	// AppendixLines is the approximate size of the files of the "size" appendix split. Zero
+	// uses a default of 2000.
+	AppendixLines int
+	// Partial regenerates only the given commands in split mode: their files are rewritten
+	// and their entries patched in the existing manifest.json, while index.md and types.md
+	// are left as they are. It has no effect on single-file output.
+	Partial bool
+	// NoClobber refuses to overwrite existing files that do not start with the
+	// GeneratedMarker, such as a hand-written file at the output path.
+	NoClobber bool
+	// Output, when set, receives the documentation of the single-file formats instead of their
+	// output file, such as os.Stdout to pipe it into another tool. The output file only names
+	// the output in log messages, and NoClobber has no effect.
+	Output io.Writer
+	// TerminalTypes maps qualified type names, such as "decimal.Decimal", to the description
+	// they are documented by, like the well-known time.Time and json.RawMessage. Terminal types
+	// are never documented as structs and their missing definitions are not reported.
+	TerminalTypes map[string]string
+	// NoTOC leaves out the Table of Contents linking every command at the top of the Markdown
+	// documentation.
+	NoTOC bool
+	// NoHeader leaves out the project information and the JSON-RPC preamble at the top of
+	// the Markdown documentation, for output included into another page. The project
+	// information is still used where the commands need it, such as source links.
+	NoHeader bool
+	// Variant names the documented variant (e.g. "v1"). It is appended to the title
+	// and available to the preamble template as {{.Variant}}.
+	Variant string
+
+	// indexFile is the file holding the project header in split mode, empty when the whole
+	// documentation is one file.
+	indexFile string
+}
+
+// GenerateDocumentation writes the Markdown documentation of a project to outFile, or to
+// opts.Output when it is set. The existing file is only replaced once the whole documentation
+// is written.
+func GenerateDocumentation(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outFile string, opts Options) error {
+	output := documentOutput(opts)
+	defer output.discard()
+
+	err := output.stage(outFile, func(writer *bufio.Writer) error {
+		return WriteDocumentation(writer, apiFunctions, structDefinitions, projectInfo, opts)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %v", err)
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	writer := bufio.NewWriter(file)
+	return nil
+}
 
-	// Write Project Info at the top
-	fmt.Fprintf(writer, "# %s\n\n", projectInfo.Title)
-	fmt.Fprintf(writer, "Version: %s\n\n", projectInfo.Version)
-	if projectInfo.Description != "" {
-		fmt.Fprintf(writer, "%s\n\n", projectInfo.Description)
+// WriteDocumentation writes the Markdown documentation of a project to w, for callers keeping
+// it in memory instead of a file. Nothing is written when the generation fails. opts.Output
+// and opts.NoClobber have no effect.
+func WriteDocumentation(w io.Writer, apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) error {
+	projectInfo, err := prepare(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
 	}
 
-	if projectInfo.Author != "" {
-		fmt.Fprintf(writer, "**Author:** %s\n\n", projectInfo.Author)
+	// The documentation is laid out and buffered whole, so a failure leaves w untouched
+	output := &stagedFiles{wrap: opts.Wrap, alignTables: opts.AlignTables, headingOffset: opts.HeadingOffset, stream: w}
+	err = output.write("", func(writer *bufio.Writer) error {
+		anchors := newAnchorRegistry()
+		if err := writeHeader(writer, apiFunctions, projectInfo, anchors, opts); err != nil {
+			return err
+		}
+
+		// Sort API functions for consistent order
+		apiFunctions = sortCommands(apiFunctions)
+
+		// The body is written first, so the table of contents can link to the anchors its
+		// command headings receive
+		withTOC := !opts.NoTOC && len(apiFunctions) > 0
+		if withTOC {
+			anchors.register(tocHeading)
+		}
+		var body bytes.Buffer
+		sections := groupByTag(apiFunctions)
+		appendix := newTypeAppendix(opts.MaxFields, "")
+		writeResultEnvelope(&body, projectInfo.ResultEnvelope, structDefinitions, anchors, appendix, opts)
+		globalErrorsAnchor := writeGlobalErrors(&body, projectInfo, anchors, opts)
+		// Separators only go between two sections, never before the first or after the last
+		for i := range sections {
+			section := &sections[i]
+			if i > 0 {
+				fmt.Fprintf(&body, "---\n\n")
+			}
+			if section.name != "" {
+				section.anchor = anchors.heading(&body, 2, section.name)
+				anchors.offset = 1
+			}
+			for j, apiFunc := range section.commands {
+				if j > 0 {
+					fmt.Fprintf(&body, "---\n\n")
+				}
+				if err := writeCommand(&body, apiFunc, structDefinitions, projectInfo, opts, anchors, appendix); err != nil {
+					return err
+				}
+				section.commandAnchors = append(section.commandAnchors, anchors.commands[apiFunc.Command])
+			}
+			anchors.offset = 0
+		}
+		if withTOC {
+			writeTOC(writer, sections, globalErrorsAnchor)
+		}
+		body.WriteTo(writer)
+		commandLinks := make(map[string]string)
+		for command, anchor := range anchors.commands {
+			commandLinks[command] = "#" + anchor
+		}
+		hasCatalog := len(projectInfo.ErrorCatalog) > 0
+		if len(apiFunctions) > 0 && hasCatalog {
+			fmt.Fprintf(writer, "---\n\n")
+		}
+		writeErrorCatalog(writer, projectInfo.ErrorCatalog, projectInfo.GlobalErrors, apiFunctions, commandLinks, anchors, opts)
+		usage := collectStructUsage(apiFunctions, structDefinitions, opts)
+		appendixKeys := appendix.fileKeys()[""]
+		if (len(apiFunctions) > 0 || hasCatalog) && len(appendixKeys) > 0 {
+			fmt.Fprintf(writer, "---\n\n")
+		}
+		writeTypeAppendix(writer, appendixKeys, structDefinitions, usage, commandLinks, anchors, opts)
+		if opts.TypesAppendix {
+			typeKeys := collectTypes(apiFunctions, structDefinitions, projectInfo.ResultEnvelope, opts)
+			if (len(apiFunctions) > 0 || hasCatalog || len(appendixKeys) > 0) && len(typeKeys) > 0 {
+				fmt.Fprintf(writer, "---\n\n")
+			}
+			writeTypes(writer, typeKeys, structDefinitions, usage, commandLinks, anchors, opts)
+		}
+		writeCopyright(writer, projectInfo)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	if projectInfo.License != "" {
-		fmt.Fprintf(writer, "**License:** %s\n\n", projectInfo.License)
+	return output.commit()
+}
+
+// Validate checks the option values, so callers can reject them before generating.
+func (opts Options) Validate() error {
+	if opts.IDType != "" && opts.IDType != IDTypeNumber && opts.IDType != IDTypeString {
+		return fmt.Errorf("invalid id type %q: expected %q or %q", opts.IDType, IDTypeNumber, IDTypeString)
 	}
-	if len(projectInfo.Tags) > 0 {
-		fmt.Fprintf(writer, "**Tags:** %s\n\n", strings.Join(projectInfo.Tags, ", "))
+	if opts.Wrap < 0 {
+		return fmt.Errorf("invalid wrap column %d: must not be negative", opts.Wrap)
+	}
+	if opts.HeadingOffset < 0 {
+		return fmt.Errorf("invalid heading offset %d: must not be negative", opts.HeadingOffset)
+	}
+	if opts.ExampleDepth < 0 {
+		return fmt.Errorf("invalid example depth %d: must not be negative", opts.ExampleDepth)
+	}
+	if err := validateCodeSamples(opts.CodeSamples); err != nil {
+		return err
+	}
+	if err := validateExampleStyle(opts.ExampleStyle); err != nil {
+		return err
+	}
+	if err := validateAppendixSplit(opts.AppendixSplit); err != nil {
+		return err
+	}
+	if opts.FileNamer == nil {
+		if _, err := fileNamerFor(opts.FileNameScheme); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepare validates the model and the options, and applies the options to the project
+// information.
+func prepare(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) (models.ProjectInfo, error) {
+	if err := validateModel(apiFunctions, structDefinitions, projectInfo); err != nil {
+		return projectInfo, err
+	}
+	if err := opts.Validate(); err != nil {
+		return projectInfo, err
 	}
+	if opts.Variant != "" {
+		projectInfo.Title = fmt.Sprintf("%s (%s)", projectInfo.Title, opts.Variant)
+	}
+	return projectInfo, nil
+}
+
+// validateModel checks that a model can be documented, before anything is written.
+func validateModel(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo) error {
+	if structDefinitions == nil {
+		return fmt.Errorf("%w: the struct definitions map is nil, pass an empty map for a project without structs", ErrNilInput)
+	}
+	var missing []string
+	if strings.TrimSpace(projectInfo.Title) == "" {
+		missing = append(missing, "title")
+	}
+	if strings.TrimSpace(projectInfo.Version) == "" {
+		missing = append(missing, "version")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: the project has no %s", ErrNoProjectInfo, strings.Join(missing, " and no "))
+	}
+	for i, apiFunc := range apiFunctions {
+		if strings.TrimSpace(apiFunc.Command) == "" {
+			return fmt.Errorf("%w: command %d (%s:%d) has no name", ErrInvalidCommand, i, apiFunc.SourceFile, apiFunc.SourceLine)
+		}
+	}
+	return nil
+}
 
-	if includeRFC {
-		fmt.Fprintf(writer, "## JSON-RPC 2.0 Specification\n\n")
-		fmt.Fprintf(writer, "This API adheres to the [JSON-RPC 2.0 specification](https://www.jsonrpc.org/specification).\n\n")
-		fmt.Fprintf(writer, "**Requests:**\n\n")
-		fmt.Fprintf(writer, "Clients must send a JSON object containing the following fields:\n")
-		fmt.Fprintf(writer, "- `jsonrpc`: Must be the string \"2.0\".\n")
-		fmt.Fprintf(writer, "- `method`: The name of the method to invoke.\n")
-		fmt.Fprintf(writer, "- `params`: (Optional) A structured value containing method parameters.\n")
-		fmt.Fprintf(writer, "- `id`: An identifier to correlate the request with the response.\n\n")
-
-		fmt.Fprintf(writer, "**Responses:**\n\n")
-		fmt.Fprintf(writer, "The server responds with a JSON object containing one of these fields:\n")
-		fmt.Fprintf(writer, "- `result`: The data returned by the method if successful.\n")
-		fmt.Fprintf(writer, "- `error`: An error object with code, message, and optional data.\n")
-		fmt.Fprintf(writer, "- `id`: Matches the request identifier.\n\n")
-
-		fmt.Fprintf(writer, "**Example Request:**\n\n")
-		fmt.Fprintf(writer, "```json\n")
-		fmt.Fprintf(writer, "{\n")
-		fmt.Fprintf(writer, "  \"jsonrpc\": \"2.0\",\n")
-		fmt.Fprintf(writer, "  \"method\": \"stats.GetAllMetrics\",\n")
-		fmt.Fprintf(writer, "  \"params\": { \"tz\": \"UTC\" },\n")
-		fmt.Fprintf(writer, "  \"id\": 1\n")
-		fmt.Fprintf(writer, "}\n")
-		fmt.Fprintf(writer, "```\n\n")
-
-		fmt.Fprintf(writer, "**Example Response:**\n\n")
-		fmt.Fprintf(writer, "```json\n")
-		fmt.Fprintf(writer, "{\n")
-		fmt.Fprintf(writer, "  \"jsonrpc\": \"2.0\",\n")
-		fmt.Fprintf(writer, "  \"result\": {\n")
-		fmt.Fprintf(writer, "    \"TotalScannedFiles\": [100, 200],\n")
-		fmt.Fprintf(writer, "    \"TotalInfectedFiles\": [5, 10]\n")
-		fmt.Fprintf(writer, "  },\n")
-		fmt.Fprintf(writer, "  \"id\": 1\n")
-		fmt.Fprintf(writer, "}\n")
-		fmt.Fprintf(writer, "```\n\n")
-	}
-
-	// Write Project Info at the top
-	fmt.Fprintf(writer, "# %s\n\n", projectInfo.Title)
+// sortCommands returns a copy of API functions sorted by command name, leaving the caller's
+// slice in its order. Functions declaring the same command are kept in source order, so the
+// output does not depend on the order they were parsed in.
+func sortCommands(apiFunctions []models.APIFunction) []models.APIFunction {
+	sorted := slices.Clone(apiFunctions)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Command != b.Command {
+			return a.Command < b.Command
+		}
+		if a.SourceFile != b.SourceFile {
+			return a.SourceFile < b.SourceFile
+		}
+		return a.SourceLine < b.SourceLine
+	})
+	return sorted
+}
+
+// writeHeader writes the project information, followed by the JSON-RPC preamble unless it
+// is omitted. Nothing is written with opts.NoHeader.
+func writeHeader(writer io.Writer, apiFunctions []models.APIFunction, projectInfo models.ProjectInfo, anchors *anchorRegistry, opts Options) error {
+	if opts.NoHeader {
+		return nil
+	}
+	writeProjectInfo(writer, projectInfo, anchors)
+	if !opts.IncludeRFC {
+		return nil
+	}
+	return writeRFCSection(writer, apiFunctions, projectInfo, anchors, opts)
+}
+
+// writeProjectInfo writes the title, version, description, author, contact, license, terms,
+// repository and tags of the project. Fields left empty get no line.
+func writeProjectInfo(writer io.Writer, projectInfo models.ProjectInfo, anchors *anchorRegistry) {
+	anchors.heading(writer, 1, projectInfo.Title)
 	fmt.Fprintf(writer, "Version: %s\n\n", projectInfo.Version)
 	if projectInfo.Description != "" {
 		fmt.Fprintf(writer, "%s\n\n", projectInfo.Description)
@@ -88,307 +392,540 @@ func GenerateDocumentation(apiFunctions []models.APIFunction, structDefinitions
 	if projectInfo.Author != "" {
 		fmt.Fprintf(writer, "**Author:** %s\n\n", projectInfo.Author)
 	}
+	if projectInfo.Contact != "" {
+		fmt.Fprintf(writer, "**Contact:** %s\n\n", projectInfo.Contact)
+	}
 	if projectInfo.License != "" {
 		fmt.Fprintf(writer, "**License:** %s\n\n", projectInfo.License)
 	}
+	if projectInfo.Terms != "" {
+		fmt.Fprintf(writer, "**Terms of Service:** %s\n\n", projectInfo.Terms)
+	}
+	if repository := projectInfo.Repository; strings.Contains(repository, "://") {
+		fmt.Fprintf(writer, "**Repository:** [%s](%s)\n\n", repository, repository)
+	} else if repository != "" {
+		fmt.Fprintf(writer, "**Repository:** %s\n\n", repository)
+	}
 	if len(projectInfo.Tags) > 0 {
 		fmt.Fprintf(writer, "**Tags:** %s\n\n", strings.Join(projectInfo.Tags, ", "))
 	}
+}
 
-	if includeRFC {
-		fmt.Fprintf(writer, "## JSON-RPC 2.0 Specification\n\n")
-		fmt.Fprintf(writer, "This API adheres to the [JSON-RPC 2.0 specification](https://www.jsonrpc.org/specification).\n\n")
+// writeCopyright writes the copyright notice of the project as the footer of a document,
+// after a rule.
+func writeCopyright(writer io.Writer, projectInfo models.ProjectInfo) {
+	if notice := copyrightNotice(projectInfo); notice != "" {
+		fmt.Fprintf(writer, "---\n\n%s\n", notice)
 	}
+}
 
-	// Sort API functions for consistent order
-	sort.Slice(apiFunctions, func(i, j int) bool {
-		return apiFunctions[i].Command < apiFunctions[j].Command
-	})
+// copyrightNotice returns the @copyright text of the project, with a "©" sign unless it
+// already starts with one or with "Copyright".
+func copyrightNotice(projectInfo models.ProjectInfo) string {
+	notice := projectInfo.Copyright
+	if notice == "" || strings.HasPrefix(notice, "©") || strings.HasPrefix(strings.ToLower(notice), "copyright") {
+		return notice
+	}
+	return "© " + notice
+}
+
+// writeCommand writes the documentation section of a single command.
+func writeCommand(writer io.Writer, apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options, anchors *anchorRegistry, appendix *typeAppendix) error {
+	// Write Command as a header
+	anchors.commands[apiFunc.Command] = anchors.heading(writer, 2, apiFunc.Command)
+	if apiFunc.Incomplete != "" {
+		writeIncomplete(writer, apiFunc)
+		return nil
+	}
+	writeDeprecation(writer, apiFunc)
+	writeFormerNames(writer, apiFunc.FormerNames)
+	writeCommandTags(writer, apiFunc.Tags)
 
-	// Iterate over each API function and write its documentation
-	for _, apiFunc := range apiFunctions {
-		log.Printf("Documenting API Command: %s", apiFunc.Command)
+	// Write Description
+	if apiFunc.Description != "" {
+		fmt.Fprintf(writer, "%s\n\n", apiFunc.Description)
+	}
+	writeEnvelopeNote(writer, apiFunc, opts)
+	writeSizeNote(writer, apiFunc, projectInfo)
+	writeSourceNote(writer, apiFunc, projectInfo, opts)
 
-		// Write Command as a header
-		fmt.Fprintf(writer, "## %s\n\n", apiFunc.Command)
+	parameters := apiFunc.Parameters
+	if opts.FlattenParams || apiFunc.FlattenParams {
+		parameters = flattenParameters(apiFunc, structDefinitions, opts)
+	}
+
+	if opts.QuickSummary {
+		writeQuickSummary(writer, apiFunc, parameters, structDefinitions, anchors)
+	}
 
-		// Write Description
-		if apiFunc.Description != "" {
-			fmt.Fprintf(writer, "%s\n\n", apiFunc.Description)
+	// Write Parameters section
+	if len(parameters) > 0 {
+		anchors.heading(writer, 3, "Parameters:")
+		fmt.Fprintf(writer, "| Name | Type | Description | Required |\n")
+		fmt.Fprintf(writer, "|------|------|-------------|----------|\n")
+		for _, param := range parameters {
+			description := cellDescription(paramDescription(param), opts)
+			fmt.Fprintf(writer, "| %s | %s | %s | %s |\n", param.Name, underlyingLabel(param.Type, param.TypeRef), description, paramRequirement(param))
 		}
+		fmt.Fprintf(writer, "\n")
+		writeParamRules(writer, apiFunc)
+	} else if !opts.OmitEmptySections {
+		fmt.Fprintf(writer, "%s\n\n", noParametersText)
+	}
 
-		// Write Parameters section
-		if len(apiFunc.Parameters) > 0 {
-			fmt.Fprintf(writer, "### Parameters:\n\n")
-			fmt.Fprintf(writer, "| Name | Type | Description | Required |\n")
-			fmt.Fprintf(writer, "|------|------|-------------|----------|\n")
-			for _, param := range apiFunc.Parameters {
-				required := "Yes"
-				if !param.Required {
-					required = "No"
-				}
-				description := strings.ReplaceAll(param.Description, "|", "\\|")
-				fmt.Fprintf(writer, "| %s | %s | %s | %s |\n", param.Name, param.Type, description, required)
+	// Structs are documented once per endpoint, even when both results and @Additional refer to them
+	printed := make(map[models.StructKey]bool)
+
+	// Write Results section
+	if len(apiFunc.Results) > 0 {
+		anchors.heading(writer, 3, "Results:")
+		writeResults(writer, apiFunc, structDefinitions, opts)
+		writeEnvelopeUse(writer, apiFunc, projectInfo.ResultEnvelope, opts)
+
+		// Inline struct documentation for each endpoint
+		var roots []models.StructKey
+		for _, result := range apiFunc.Results {
+			if _, terminal := utils.WellKnownType(resultTypeRef(apiFunc, result, structDefinitions).Held(), opts.TerminalTypes); terminal {
+				continue
+			}
+			if resolvedKey, found := findResultStruct(apiFunc, result, structDefinitions); found {
+				roots = append(roots, resolvedKey)
+			} else if held := resultTypeRef(apiFunc, result, structDefinitions).Held(); held != nil && held.Kind == models.TypeNamed {
+				opts.warn(Warning{
+					File:    apiFunc.SourceFile,
+					Line:    apiFunc.LineOr(result.SourceLine),
+					Class:   unresolvedTypeClass,
+					Message: fmt.Sprintf("struct '%s' of the %s of command '%s' not found, its fields are not documented", result.Type, result.Name, apiFunc.Command),
+				})
 			}
-			fmt.Fprintf(writer, "\n")
 		}
+		// Print the result struct and all referenced structs inline
+		printStructDefinitions(writer, roots, structDefinitions, printed, anchors, appendix, opts)
+	}
 
-		// Write Results section
-		if len(apiFunc.Results) > 0 {
-			fmt.Fprintf(writer, "### Results:\n\n")
-			fmt.Fprintf(writer, "| Name | Type | Description |\n")
-			fmt.Fprintf(writer, "|------|------|-------------|\n")
-			for _, result := range apiFunc.Results {
-				description := strings.ReplaceAll(result.Description, "|", "\\|")
-				fmt.Fprintf(writer, "| %s | %s | %s |\n", result.Name, result.Type, description)
-			}
-			fmt.Fprintf(writer, "\n")
-
-			// Inline struct documentation for each endpoint
-			visited := make(map[models.StructKey]bool) // Reset visited map for every endpoint
-			for _, result := range apiFunc.Results {
-				baseType, typeArgs := utils.ParseGenericType(result.Type)
-				if !utils.IsBasicType(baseType) {
-					concreteType := result.Type
-
-					// Find the struct in structDefinitions
-					var found bool
-					var resolvedKey models.StructKey
-					for key := range structDefinitions {
-						if key.Name == concreteType {
-							resolvedKey = key
-							found = true
-							break
-						}
-					}
-
-					if !found && len(typeArgs) == 0 {
-						// If not a generic instantiation, try to find the base type
-						for key := range structDefinitions {
-							if key.Name == baseType {
-								resolvedKey = key
-								found = true
-								break
-							}
-						}
-					}
-
-					if found {
-						// Print the struct and all referenced structs inline
-						printStructDefinitionInline(writer, resolvedKey, structDefinitions, visited)
-					} else {
-						log.Printf("Warning: Struct '%s' not found for result '%s'", concreteType, result.Name)
-					}
-				}
+	// Add Additional Structs section
+	if len(apiFunc.AdditionalStructs) > 0 {
+		anchors.heading(writer, 3, "Additional Structs:")
+		var roots []models.StructKey
+		for _, additional := range apiFunc.AdditionalStructs {
+			ref := utils.ResolveTypeRef(utils.ParseType(additional), apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions).Held()
+			_, terminal := utils.WellKnownType(ref, opts.TerminalTypes)
+			switch {
+			case terminal:
+			case ref.Kind == models.TypeStruct:
+				roots = append(roots, ref.Struct)
+			case ref.Kind == models.TypeNamed:
+				opts.warn(Warning{
+					File:    apiFunc.SourceFile,
+					Line:    apiFunc.AnnotationLine("@Additional " + additional),
+					Class:   unresolvedTypeClass,
+					Message: fmt.Sprintf("struct '%s' of @Additional of command '%s' not found, its fields are not documented", additional, apiFunc.Command),
+				})
 			}
 		}
+		printStructDefinitions(writer, roots, structDefinitions, printed, anchors, appendix, opts)
+	}
 
-		// Add Additional Structs section
-		if len(apiFunc.AdditionalStructs) > 0 {
-			fmt.Fprintf(writer, "### Additional Structs:\n\n")
-			visited := make(map[models.StructKey]bool) // Reset visited map for every endpoint
-			for _, additional := range apiFunc.AdditionalStructs {
-				baseType, typeArgs := utils.ParseGenericType(additional)
-				if utils.IsBasicType(baseType) {
-					continue
-				}
-				// Resolve to package and name
-				pkg, baseName := resolvePackageAndType(baseType, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
-				if baseName == "" {
-					log.Printf("Warning: Struct '%s' not found for @Additional annotation.", additional)
-					continue
-				}
+	if err := writeSubscription(writer, apiFunc, structDefinitions, projectInfo, printed, anchors, appendix, opts); err != nil {
+		return err
+	}
 
-				var concreteType string
-				if len(typeArgs) > 0 {
-					// Construct generic name
-					// For each arg, also resolve package and name if needed
-					resolvedArgs := []string{}
-					for _, arg := range typeArgs {
-						argPkg, argName := resolvePackageAndType(arg, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
-						if argName == "" {
-							argName = arg
-						}
-						if argPkg != "" && argPkg != apiFunc.PackageName {
-							resolvedArgs = append(resolvedArgs, fmt.Sprintf("%s.%s", argPkg, argName))
-						} else {
-							resolvedArgs = append(resolvedArgs, argName)
-						}
-					}
-					concreteType = fmt.Sprintf("%s[%s]", baseName, strings.Join(resolvedArgs, ", "))
-				} else {
-					concreteType = baseName
-				}
+	// Errors section
+	if apiErrors := commandErrors(apiFunc, projectInfo, opts); len(apiErrors) > 0 {
+		anchors.heading(writer, 3, "Errors:")
+		writeErrorTable(writer, apiErrors, projectInfo.ErrorCatalog, opts)
+		writeGlobalErrorsNote(writer, projectInfo, opts)
+	} else if !opts.OmitEmptySections {
+		fmt.Fprintf(writer, "%s\n\n", standardErrorsText(projectInfo, opts))
+	}
 
-				// Find struct definition
-				var found bool
-				var resolvedKey models.StructKey
-				// For generics or normal
-				// Generic or not, package is from base
-				// If generic, we just store in same package as base type
-				if len(typeArgs) > 0 {
-					resolvedKey = models.StructKey{
-						Package: pkg,
-						Name:    concreteType,
-					}
-					if _, exists := structDefinitions[resolvedKey]; !exists {
-						// Create concrete struct if needed (similar to parser logic)
-						// If it's generic and not created yet, you must mimic the parser logic or skip
-						// For simplicity, assume it's already created. If needed, replicate parser logic here.
-						// If not found, warn
-						log.Printf("Warning: Concrete struct '%s.%s' not found for @Additional", pkg, concreteType)
-						continue
-					}
-					found = true
-				} else {
-					// Non-generic
-					resolvedKey = models.StructKey{
-						Package: pkg,
-						Name:    concreteType,
-					}
-					if _, exists := structDefinitions[resolvedKey]; exists {
-						found = true
-					}
-				}
+	if err := writeExamples(writer, apiFunc, structDefinitions, projectInfo, opts, anchors); err != nil {
+		return err
+	}
+	if err := writeCodeSamples(writer, apiFunc, projectInfo, opts, anchors); err != nil {
+		return err
+	}
 
-				if found {
-					printStructDefinitionInline(writer, resolvedKey, structDefinitions, visited)
-				} else {
-					log.Printf("Warning: Struct '%s' not found for @Additional annotation.", additional)
-				}
-			}
+	return nil
+}
+
+// writeResults writes the results table. A result with @DynamicKeys is described in a
+// sentence instead, followed by the definition of its value struct.
+func writeResults(writer io.Writer, apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, opts Options) {
+	var rows []models.APIReturn
+	var dynamic []models.APIReturn
+	for _, result := range apiFunc.Results {
+		if len(result.DynamicKeys) > 0 && dynamicKeysPhrase(result.Type, result.DynamicKeys) != "" {
+			dynamic = append(dynamic, result)
+		} else {
+			rows = append(rows, result)
 		}
+	}
 
-		// Errors section
-		if len(apiFunc.Errors) > 0 {
-			fmt.Fprintf(writer, "### Errors:\n\n")
-			fmt.Fprintf(writer, "| Code | Description |\n")
-			fmt.Fprintf(writer, "|------|-------------|\n")
-			for _, apiError := range apiFunc.Errors {
-				fmt.Fprintf(writer, "| %d | %s |\n", apiError.Code, apiError.Description)
+	if len(rows) > 0 {
+		fmt.Fprintf(writer, "| Name | Type | Description |\n")
+		fmt.Fprintf(writer, "|------|------|-------------|\n")
+		for _, result := range rows {
+			description := cellDescription(result.Description, opts)
+			fmt.Fprintf(writer, "| %s | %s | %s |\n", result.Name, resultType(apiFunc, result, structDefinitions, opts), description)
+		}
+		fmt.Fprintf(writer, "\n")
+	}
+	for _, result := range dynamic {
+		if result.Description != "" {
+			fmt.Fprintf(writer, "%s\n\n", result.Description)
+		}
+		ending := "."
+		if _, found := findResultStruct(apiFunc, result, structDefinitions); found {
+			ending = ":"
+		}
+		fmt.Fprintf(writer, "%s%s\n\n", dynamicKeysPhrase(result.Type, result.DynamicKeys), ending)
+	}
+	for _, result := range apiFunc.Results {
+		writeContentTypes(writer, result.ContentTypes)
+	}
+}
+
+// resultType returns the type of a result for the results table. Terminal types are replaced
+// by their description, and slices, arrays and maps of a struct are noted as such, such as
+// "[]*Item (array of rpc.Item)" or "map[string]Item (object with values of type rpc.Item)".
+func resultType(apiFunc models.APIFunction, result models.APIReturn, structDefinitions map[models.StructKey]models.StructDefinition, opts Options) string {
+	ref := resultTypeRef(apiFunc, result, structDefinitions)
+	if description, terminal := utils.WellKnownType(ref, opts.TerminalTypes); terminal {
+		return description
+	}
+	if note := holdingNote(ref, opts); note != "" {
+		return fmt.Sprintf("%s (%s)", result.Type, note)
+	}
+	return underlyingLabel(result.Type, ref)
+}
+
+// underlyingLabel returns the label of a type typ holding types declared with another type,
+// such as "string (UserID)" for "UserID" or "[]int64 (IDList)" for "IDList". Other types,
+// and types holding a struct, are labelled as written.
+func underlyingLabel(typ string, ref *models.TypeRef) string {
+	if !holdsAlias(ref) || ref.Held().Kind == models.TypeStruct {
+		return typ
+	}
+	return fmt.Sprintf("%s (%s)", ref, typ)
+}
+
+// holdsAlias reports whether ref is or holds a type declared with another type.
+func holdsAlias(ref *models.TypeRef) bool {
+	return ref != nil && (ref.Alias != "" || holdsAlias(ref.Elem) || holdsAlias(ref.Key))
+}
+
+// holdingNote describes a slice or map holding a struct, such as "array of arrays of rpc.Item"
+// or "object with values of type array of rpc.Item", or returns "" for other types.
+func holdingNote(ref *models.TypeRef, opts Options) string {
+	ref = ref.Deref()
+	held := ref.Held()
+	if ref == nil || (ref.Kind != models.TypeSlice && ref.Kind != models.TypeMap) || held == nil || held.Kind != models.TypeStruct {
+		return ""
+	}
+	if _, terminal := utils.WellKnownType(held, opts.TerminalTypes); terminal {
+		return ""
+	}
+	var describe func(ref *models.TypeRef, plural bool) string
+	describe = func(ref *models.TypeRef, plural bool) string {
+		switch ref.Kind {
+		case models.TypePointer:
+			return describe(ref.Elem, plural)
+		case models.TypeSlice:
+			if plural {
+				return "arrays of " + describe(ref.Elem, true)
+			}
+			return "array of " + describe(ref.Elem, true)
+		case models.TypeMap:
+			object := "object"
+			if plural {
+				object = "objects"
 			}
-			fmt.Fprintf(writer, "\n")
+			// JSON object keys are strings, other key types are encoded as text
+			if key := ref.Key.String(); key != "string" {
+				object += " with " + key + " keys and"
+			} else {
+				object += " with"
+			}
+			return object + " values of type " + describe(ref.Elem, false)
 		}
+		return ref.Struct.Package + "." + ref.Struct.Name
+	}
+	return describe(ref, false)
+}
 
-		fmt.Fprintf(writer, "---\n\n")
+// writeContentTypes writes the @ContentType note of a result, as a list when several content
+// types are negotiated.
+func writeContentTypes(writer io.Writer, contentTypes []models.ContentType) {
+	switch len(contentTypes) {
+	case 0:
+		return
+	case 1:
+		fmt.Fprintf(writer, "**Content type:** %s.\n\n", contentTypeText(contentTypes[0]))
+		return
+	}
+	fmt.Fprintf(writer, "**Content types:** negotiated with the client, one of:\n\n")
+	for _, contentType := range contentTypes {
+		fmt.Fprintf(writer, "- %s\n", contentTypeText(contentType))
 	}
+	fmt.Fprintf(writer, "\n")
+}
 
-	if err := writer.Flush(); err != nil {
-		return fmt.Errorf("failed to write to output file: %v", err)
+// contentTypeText describes a content type, such as "`application/pdf`, base64 encoded".
+func contentTypeText(contentType models.ContentType) string {
+	if contentType.Encoding == "" {
+		return fmt.Sprintf("`%s`", contentType.MediaType)
 	}
+	return fmt.Sprintf("`%s`, %s encoded", contentType.MediaType, contentType.Encoding)
+}
 
-	log.Printf("Documentation successfully generated at %s", outFile)
-	return nil
+// findResultStruct finds the struct documenting a result type, looking through pointers,
+// slices and maps. For generic types it is the concrete instantiation created by the parser.
+func findResultStruct(apiFunc models.APIFunction, result models.APIReturn, structDefinitions map[models.StructKey]models.StructDefinition) (models.StructKey, bool) {
+	held := resultTypeRef(apiFunc, result, structDefinitions).Held()
+	if held == nil || held.Kind != models.TypeStruct {
+		return models.StructKey{}, false
+	}
+	return held.Struct, true
+}
+
+// resultTypeRef returns the parsed type of a result of apiFunc.
+func resultTypeRef(apiFunc models.APIFunction, result models.APIReturn, structDefinitions map[models.StructKey]models.StructDefinition) *models.TypeRef {
+	return typeRefOf(result.TypeRef, result.Type, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
+}
+
+// typeRefOf returns ref, or the type string typ parsed and resolved in pkg when the model
+// was not built by the parser and has no parsed type.
+func typeRefOf(ref *models.TypeRef, typ string, pkg string, importAliases map[string]string, structDefinitions map[models.StructKey]models.StructDefinition) *models.TypeRef {
+	if ref != nil {
+		return ref
+	}
+	return utils.ResolveTypeRef(utils.ParseType(typ), pkg, importAliases, structDefinitions)
+}
+
+// structHeading returns the heading text used for a struct definition.
+func structHeading(key models.StructKey, structDef models.StructDefinition) string {
+	return fmt.Sprintf("%s.%s", key.Package, structDef.Name)
+}
+
+// printStructDefinitions prints the root structs, then every struct they reference exactly once,
+// in breadth-first order of first reference. Each referenced struct is introduced by the fields
+// that refer to it. Structs in printed were already documented for the endpoint and are skipped,
+// roots among them are linked instead. With opts.Collapsible, each struct is printed in a
+// <details> block, see collapsibleTree. With opts.TypesAppendix, the structs are linked to
+// the Types section instead of printed.
+func printStructDefinitions(writer io.Writer, roots []models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, printed map[models.StructKey]bool, anchors *anchorRegistry, appendix *typeAppendix, opts Options) {
+	if opts.TypesAppendix {
+		writeTypeLinks(writer, roots, structDefinitions, printed, opts)
+		return
+	}
+	for _, root := range roots {
+		if printed[root] {
+			fmt.Fprintf(writer, "See [%s](#%s) above.\n\n", structHeading(root, structDefinitions[root]), anchors.structs[root])
+		}
+	}
+
+	// Field types and embedded structs are linked before the heading of their struct is written
+	graph := collectStructGraph(roots, structDefinitions, opts)
+	if opts.Collapsible {
+		tops, children, order := collapsibleTree(graph, printed)
+		planned := anchors.plan(order, structDefinitions, printed)
+		for _, key := range tops {
+			printCollapsibleStruct(writer, key, children, graph, structDefinitions, printed, planned, anchors, appendix, opts)
+		}
+		return
+	}
+	planned := anchors.plan(graph.order, structDefinitions, printed)
+	for _, key := range graph.order {
+		if printed[key] {
+			continue
+		}
+		printed[key] = true
+		printStructDefinition(writer, key, structDefinitions, graph.references[key], planned, anchors, appendix, opts)
+	}
 }
 
-// printStructDefinitionInline prints a given struct's definition and all referenced structs inline.
-// It uses a visited map to avoid duplicates.
-func printStructDefinitionInline(writer *bufio.Writer, key models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, visited map[models.StructKey]bool) {
+// printStructDefinition prints a single struct definition, preceded by the fields referring to
+// it. The types of its fields link to the structs they hold, preferably to their tables in
+// planned, printed along with it.
+func printStructDefinition(writer io.Writer, key models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, references []structReference, planned map[models.StructKey]string, anchors *anchorRegistry, appendix *typeAppendix, opts Options) {
 	structDef, exists := structDefinitions[key]
 	if !exists {
-		log.Printf("Warning: Struct '%s.%s' not found in definitions.", key.Package, key.Name)
+		opts.warn(Warning{
+			Class:   unresolvedTypeClass,
+			Message: fmt.Sprintf("struct '%s.%s' not found in definitions", key.Package, key.Name),
+		})
 		return
 	}
 
-	fmt.Fprintf(writer, "#### %s.%s\n\n", key.Package, structDef.Name)
+	anchors.structHeading(writer, 4, key, structDef)
+	if len(references) > 0 {
+		fmt.Fprintf(writer, "Referenced by: %s.\n\n", describeReferences(references, structDefinitions))
+	}
 	if structDef.Description != "" {
 		fmt.Fprintf(writer, "%s\n\n", structDef.Description)
 	}
-	if len(structDef.Fields) > 0 {
-		fmt.Fprintf(writer, "| Name | Type | Description | JSON Name |\n")
-		fmt.Fprintf(writer, "|------|------|-------------|-----------|\n")
-		for _, field := range structDef.Fields {
-			description := strings.ReplaceAll(field.Description, "|", "\\|")
-			jsonName := field.JSONName
-			if jsonName == "-" {
-				jsonName = "omitempty"
-			}
-			fmt.Fprintf(writer, "| %s | %s | %s | %s |\n", field.Name, field.Type, description, jsonName)
-		}
-		fmt.Fprintf(writer, "\n")
-	} else {
-		fmt.Fprintf(writer, "_No fields defined._\n\n")
+	table := tableStruct(key, structDef, structDefinitions, opts)
+	fields := linkEmbedded(appendix.visibleFields(key, table), key.Package, structDefinitions, anchors)
+	writeFieldTable(writer, fields, len(table.Fields)-len(fields), appendix.link(key, structDef), structLinker(key.Package, planned, structDefinitions, anchors), opts)
+	if opts.StructSource {
+		writeStructSource(writer, key, structDef, opts)
 	}
+	writeMethodNotes(writer, structDef.Methods)
+}
 
-	// Now, for each field, if it's a struct type, print it inline
-	for _, field := range structDef.Fields {
-		baseType, typeArgs := utils.ParseGenericType(field.Type)
-		if utils.IsBasicType(baseType) {
-			continue
+// structLinker returns the typeLink of writeFieldTable for the fields of a struct of package
+// pkg: the anchor of the struct held by a field, looking through pointers, slices and maps,
+// taken from planned or else from the first heading documenting it. Structs replaced by
+// their description as terminal types hold no link, since no heading documents them.
+func structLinker(pkg string, planned map[models.StructKey]string, structDefinitions map[models.StructKey]models.StructDefinition, anchors *anchorRegistry) func(models.StructField) string {
+	return func(field models.StructField) string {
+		key, found := resolveFieldStruct(field, pkg, structDefinitions)
+		if !found {
+			return ""
 		}
-
-		// Resolve the field type
-		fieldPkg, fieldTypeName := resolvePackageAndType(baseType, key.Package, map[string]string{}, structDefinitions)
-		if fieldTypeName == "" {
-			// Cannot resolve type, skip
-			continue
+		if anchor, exists := planned[key]; exists {
+			return anchor
 		}
+		return anchors.structs[key]
+	}
+}
 
-		// If this is a generic instantiation, construct the concrete type name
-		var concreteType string
-		if len(typeArgs) > 0 {
-			concreteType = fmt.Sprintf("%s[%s]", fieldTypeName, strings.Join(typeArgs, ", "))
-		} else {
-			concreteType = fieldTypeName
-		}
+// writeMethodNotes lists the documented methods of a struct annotated with @IncludeMethodDocs.
+func writeMethodNotes(writer io.Writer, methods []models.MethodDoc) {
+	if len(methods) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "**Notes**\n\n")
+	for _, method := range methods {
+		fmt.Fprintf(writer, "- `%s`: %s\n", method.Name, method.Doc)
+	}
+	fmt.Fprintf(writer, "\n")
+}
 
-		var found bool
-		var fieldResolvedKey models.StructKey
-		for k := range structDefinitions {
-			if k.Name == concreteType {
-				fieldResolvedKey = k
-				found = true
-				break
-			}
+// writeFieldTable writes the fields table of a struct. When omitted is positive, a last row
+// reports the number of fields left out and links to the complete definition. typeLink
+// returns the anchor of the struct held by a field, or "" when it has none.
+func writeFieldTable(writer io.Writer, fields []models.StructField, omitted int, link string, typeLink func(models.StructField) string, opts Options) {
+	if len(fields) == 0 && omitted == 0 {
+		fmt.Fprintf(writer, "_No fields defined._\n\n")
+		return
+	}
+	fmt.Fprintf(writer, "| Name | Type | Description | JSON Name | Required |\n")
+	fmt.Fprintf(writer, "|------|------|-------------|-----------|----------|\n")
+	for _, field := range fields {
+		description := cellDescription(fieldDescription(field), opts)
+		jsonName := field.JSONName
+		if field.Excluded {
+			jsonName = excludedFieldLabel
 		}
+		fmt.Fprintf(writer, "| %s | %s | %s | %s | %s |\n", field.Name, fieldTypeCell(field, typeLink(field), opts), description, jsonName, fieldRequirement(field))
+	}
+	if omitted > 0 {
+		fmt.Fprintf(writer, "| … and %d more fields, see [appendix](%s) | | | | |\n", omitted, link)
+	}
+	fmt.Fprintf(writer, "\n")
+}
 
-		if !found && len(typeArgs) == 0 {
-			// If not found as a generic instantiation, try base type
-			for k := range structDefinitions {
-				if k.Name == fieldTypeName && (fieldPkg == "" || k.Package == fieldPkg) {
-					fieldResolvedKey = k
-					found = true
-					break
-				}
-			}
-		}
+// paramRequirement returns the Required cell of a parameter, "Yes" or "No".
+func paramRequirement(param models.APIParameter) string {
+	if param.Required {
+		return "Yes"
+	}
+	return "No"
+}
 
-		if found {
-			printStructDefinitionInline(writer, fieldResolvedKey, structDefinitions, visited)
-		}
+// fieldTypeCell returns the Type cell of a field: the label of linkedFieldTypeLabel, or the
+// phrase describing the keys of a map with @DynamicKeys.
+func fieldTypeCell(field models.StructField, anchor string, opts Options) string {
+	if phrase := dynamicKeysPhrase(field.Type, field.DynamicKeys); len(field.DynamicKeys) > 0 && phrase != "" {
+		return phrase
 	}
+	return linkedFieldTypeLabel(field, anchor, opts)
 }
 
-// resolvePackageAndType resolves the package and type name for a given type.
-// If the type is unqualified, it assumes it's in the current package if it exists there.
-func resolvePackageAndType(typ string, currentPackage string, importAliases map[string]string, structDefinitions map[models.StructKey]models.StructDefinition) (pkg string, typeName string) {
-	if strings.Contains(typ, ".") {
-		// Fully qualified type
-		parts := strings.Split(typ, ".")
-		if len(parts) != 2 {
-			return "", ""
-		}
-		alias := parts[0]
-		typeName = parts[1]
-		pkgAlias, exists := importAliases[alias]
-		if exists {
-			return pkgAlias, typeName
-		}
-		// If alias not found, assume alias is the package name
-		pkgAlias = alias
-		return pkgAlias, typeName
+// fieldRequirement returns the Required cell of a field: "No" for fields that may be absent
+// or null, because they are omitempty, pointers or excluded from JSON, "Yes" otherwise, and
+// "Embedded" for embedded fields, whose own fields are promoted into the struct.
+func fieldRequirement(field models.StructField) string {
+	switch {
+	case field.Embedded:
+		return "Embedded"
+	case field.Excluded || field.Omitempty || isPointerField(field):
+		return "No"
+	default:
+		return "Yes"
+	}
+}
+
+// isPointerField reports whether a field holds a pointer, which encodes as null when nil.
+func isPointerField(field models.StructField) bool {
+	if field.TypeRef != nil {
+		return field.TypeRef.Kind == models.TypePointer
+	}
+	return strings.HasPrefix(field.Type, "*")
+}
+
+// fieldTypeLabel returns the type of a field followed by the struct held by a map, its format
+// and its units, such as "int64 (milliseconds)" or "string (email)". Formats implied by the
+// type, such as the date-time of time.Time, are left out. Terminal types are replaced by
+// their description, unless their format is overridden.
+func fieldTypeLabel(field models.StructField, opts Options) string {
+	return linkedFieldTypeLabel(field, "", opts)
+}
+
+// linkedFieldTypeLabel returns the label of fieldTypeLabel with the type, but not the notes
+// following it, linked to anchor, such as "[[]Item](#rpcitem)". An empty anchor links nothing.
+func linkedFieldTypeLabel(field models.StructField, anchor string, opts Options) string {
+	label := underlyingLabel(field.Type, field.TypeRef)
+	overridden := field.Format != "" && field.Format != utils.ImpliedFormat(field.Type)
+	if description, terminal := utils.WellKnownType(typeRefOf(field.TypeRef, field.Type, "", nil, nil), opts.TerminalTypes); terminal && !overridden {
+		label = description
+	} else if anchor != "" {
+		label = fmt.Sprintf("[%s](#%s)", label, anchor)
+	}
+	var hints []string
+	if ref := field.TypeRef; ref != nil && ref.Kind == models.TypeMap && holdingNote(ref, opts) != "" {
+		hints = append(hints, holdingNote(ref, opts))
+	}
+	if overridden {
+		hints = append(hints, field.Format)
+	}
+	if field.Units != "" {
+		hints = append(hints, field.Units)
 	}
+	if len(hints) == 0 {
+		return label
+	}
+	return fmt.Sprintf("%s (%s)", label, strings.Join(hints, ", "))
+}
 
-	// Unqualified type
-	key := models.StructKey{
-		Package: currentPackage,
-		Name:    typ,
+// cellDescription returns a description ready for a table cell, see tableCell, with the
+// empty description placeholder in place of an empty description.
+func cellDescription(description string, opts Options) string {
+	if strings.TrimSpace(description) == "" {
+		return emptyDescription(opts)
 	}
-	if _, exists := structDefinitions[key]; exists {
-		return currentPackage, typ
+	return tableCell(description)
+}
+
+// emptyDescription returns the placeholder rendered in table cells in place of an empty
+// description: opts.EmptyDescription, or defaultEmptyDescription when it is not set.
+func emptyDescription(opts Options) string {
+	if opts.EmptyDescription != "" {
+		return opts.EmptyDescription
 	}
+	return defaultEmptyDescription
+}
 
-	// Not found in current package
-	log.Printf("Type '%s' not found in package '%s'. Ensure it is imported or fully qualified.", typ, currentPackage)
-	return "", ""
+// tableCell keeps text written in Markdown inside a single table cell: pipes are escaped so
+// they do not end the cell, and line breaks, which would end the row, become <br>. Code
+// spans, links and emphasis are left as they are.
+func tableCell(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.ReplaceAll(strings.Join(lines, "<br>"), "|", "\\|")
 }
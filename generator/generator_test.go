@@ -0,0 +1,1417 @@
+// generator/generator_test.go
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// testModel returns a small project model shared by the golden tests.
+func testModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "user.Get",
+			Description: "Get a user by id.",
+			Parameters: []models.APIParameter{
+				{Name: "id", Type: "int", Description: "User id.", Required: true},
+			},
+			Results: []models.APIReturn{
+				{Name: "result", Type: "User", Description: "The user.", Required: true},
+			},
+			Errors: []models.APIError{
+				{Code: 404, Description: "User not found."},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "stats.GetAllMetrics",
+			Description: "Get statistics for the last 30 days.",
+			Parameters: []models.APIParameter{
+				{Name: "tz", Type: "string", Description: "Timezone.", Required: false},
+			},
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "User"}: {
+			Name:        "User",
+			Description: "User account.",
+			Fields: []models.StructField{
+				{Name: "ID", Type: "int", Description: "Identifier.", JSONName: "id"},
+				{Name: "Name", Type: "string", Description: "Display name.", JSONName: "name"},
+			},
+		},
+	}
+	projectInfo := models.ProjectInfo{
+		Title:       "Test API",
+		Version:     "1.0.0",
+		Description: "API used by the generator tests.",
+	}
+	return apiFunctions, structs, projectInfo
+}
+
+// generateString runs GenerateDocumentation into a temporary file and returns its content.
+func generateString(t *testing.T, apiFunctions []models.APIFunction, structs map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) string {
+	t.Helper()
+	var content bytes.Buffer
+	if err := WriteDocumentation(&content, apiFunctions, structs, projectInfo, opts); err != nil {
+		t.Fatalf("WriteDocumentation returned error: %v", err)
+	}
+	return content.String()
+}
+
+// assertGolden compares got with testdata/<name>.golden, rewriting it when -update is set.
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+	goldenFile := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(goldenFile, []byte(got), 0644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("Output does not match %s (run go test -update to refresh)\n--- got ---\n%s", goldenFile, got)
+	}
+}
+
+func TestRFCSectionGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		modify   func(info *models.ProjectInfo)
+		commands func(apiFunctions []models.APIFunction)
+	}{
+		{
+			name: "rfc_default",
+			opts: Options{IncludeRFC: true},
+		},
+		{
+			name: "rfc_customized",
+			opts: Options{
+				IncludeRFC:            true,
+				IDType:                IDTypeString,
+				SupportsBatch:         true,
+				SupportsNotifications: true,
+			},
+			modify: func(info *models.ProjectInfo) {
+				info.Servers = []string{"https://api.example.com/rpc"}
+			},
+		},
+		{
+			name: "rfc_template",
+			opts: Options{
+				IncludeRFC:  true,
+				RFCTemplate: "## Protocol\n\nSend `{{ .ExampleCommand }}` to {{ range .Servers }}{{ . }}{{ end }} with header `Authorization: Bearer <token>`.\n\n",
+			},
+			modify: func(info *models.ProjectInfo) {
+				info.Servers = []string{"https://api.example.com/rpc"}
+			},
+		},
+		{
+			name: "rfc_conventions",
+			opts: Options{
+				IncludeRFC:         true,
+				StandardErrorsText: "See [Common Errors](https://example.com/errors) for the codes every method may return.",
+			},
+			commands: func(apiFunctions []models.APIFunction) {
+				apiFunctions[0].Auth = "bearer"
+				apiFunctions[1].Auth = "apikey"
+			},
+		},
+		{
+			name: "rfc_error_sections",
+			opts: Options{IncludeRFC: true},
+			modify: func(info *models.ProjectInfo) {
+				info.GlobalErrors = []models.APIError{{Code: 1001, Name: "Unauthorized", Description: "The request is not authenticated."}}
+				info.ErrorCatalog = []models.CatalogError{{Code: 1001, Name: "Unauthorized", Description: "The request is not authenticated."}}
+			},
+		},
+		{
+			name: "rfc_omitted",
+			opts: Options{IncludeRFC: false, RFCTemplate: "{{ .Missing }}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiFunctions, structs, projectInfo := testModel()
+			if tt.modify != nil {
+				tt.modify(&projectInfo)
+			}
+			if tt.commands != nil {
+				tt.commands(apiFunctions)
+			}
+			got := generateString(t, apiFunctions, structs, projectInfo, tt.opts)
+			assertGolden(t, tt.name, got)
+		})
+	}
+}
+
+func TestHeaderWrittenOnce(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	projectInfo.Author = "Jane Doe"
+	projectInfo.License = "MIT"
+	projectInfo.Tags = []string{"stats", "users"}
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{IncludeRFC: true})
+	assertGolden(t, "header", got)
+
+	for _, once := range []string{
+		"# " + projectInfo.Title + "\n",
+		"Version: " + projectInfo.Version + "\n",
+		projectInfo.Description + "\n",
+		"**Author:** Jane Doe\n",
+		"**License:** MIT\n",
+		"**Tags:** stats, users\n",
+		"## JSON-RPC 2.0 Specification\n",
+		"This API adheres to the",
+	} {
+		if count := strings.Count(got, once); count != 1 {
+			t.Errorf("Expected %q once, found %d times", once, count)
+		}
+	}
+}
+
+func TestRFCSectionInvalidTemplate(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	outFile := filepath.Join(t.TempDir(), "out.md")
+	err := GenerateDocumentation(apiFunctions, structs, projectInfo, outFile, Options{IncludeRFC: true, RFCTemplate: "{{ .Unclosed "})
+	if err == nil {
+		t.Fatal("Expected an error for a malformed RFC template")
+	}
+}
+
+func TestInvalidIDType(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	outFile := filepath.Join(t.TempDir(), "out.md")
+	err := GenerateDocumentation(apiFunctions, structs, projectInfo, outFile, Options{IDType: "uuid"})
+	if err == nil {
+		t.Fatal("Expected an error for an unknown id type")
+	}
+}
+
+func TestVariantTitle(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{
+		IncludeRFC:  true,
+		Variant:     "v2",
+		RFCTemplate: "Variant {{ .Variant }} of {{ .Project.Title }}\n\n",
+	})
+	if !strings.HasPrefix(got, GeneratedMarker+"\n\n# Test API (v2)\n") {
+		t.Errorf("Expected variant in title, got:\n%s", got)
+	}
+	if !strings.Contains(got, "Variant v2 of Test API (v2)\n") {
+		t.Errorf("Expected variant in template output, got:\n%s", got)
+	}
+}
+
+func TestFlattenParamsGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "reports.Search",
+			Description: "Search reports.",
+			Parameters: []models.APIParameter{
+				{Name: "filter", Type: "ReportFilter", Description: "Filters.", Required: true},
+				{Name: "page", Type: "*Paging", Description: "Paging.", Required: false},
+				{Name: "tz", Type: "string", Description: "Timezone.", Required: true},
+			},
+			PackageName:   "rpc",
+			FlattenParams: true,
+		},
+		{
+			Command:     "reports.Count",
+			Description: "Count reports.",
+			Parameters: []models.APIParameter{
+				{Name: "filter", Type: "ReportFilter", Description: "Filters.", Required: true},
+			},
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "ReportFilter"}: {
+			Name: "ReportFilter",
+			Fields: []models.StructField{
+				{Name: "DateFrom", Type: "string", Description: "Start date.", JSONName: "date_from"},
+				{Name: "DateTo", Type: "*string", Description: "End date.", JSONName: "date_to"},
+				{Name: "Owner", Type: "Owner", Description: "Owner filter.", JSONName: "owner", Omitempty: true},
+				{Name: "Parent", Type: "*ReportFilter", Description: "Parent filter.", JSONName: "parent"},
+				{Name: "internal", Type: "string", Excluded: true},
+			},
+		},
+		{Package: "rpc", Name: "Owner"}: {
+			Name: "Owner",
+			Fields: []models.StructField{
+				{Name: "ID", Type: "int", Description: "Owner id.", JSONName: "id"},
+				{Name: "Team", Type: "Team", Description: "Owner team.", JSONName: "team"},
+			},
+		},
+		{Package: "rpc", Name: "Team"}: {
+			Name: "Team",
+			Fields: []models.StructField{
+				{Name: "Name", Type: "string", Description: "Team name.", JSONName: "name"},
+			},
+		},
+		{Package: "rpc", Name: "Paging"}: {
+			Name: "Paging",
+			Fields: []models.StructField{
+				{Name: "Size", Type: "int", Description: "Page size.", JSONName: "size"},
+			},
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	// reports.Search opts in with @FlattenParams, reports.Count keeps the default presentation
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{FlattenDepth: 2})
+	assertGolden(t, "flatten_params", got)
+
+	// The option flattens every command
+	got = generateString(t, apiFunctions, structs, projectInfo, Options{FlattenParams: true})
+	if !strings.Contains(got, "## reports.Count\n\nCount reports.\n\n### Parameters:\n\n| Name | Type | Description | Required |\n|------|------|-------------|----------|\n| filter.date_from |") {
+		t.Errorf("Expected reports.Count parameters to be flattened, got:\n%s", got)
+	}
+}
+
+func TestCurlSamplesGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "user.Get",
+			Description: "Get a user.",
+			Parameters: []models.APIParameter{
+				{Name: "id", Type: "int", Required: true},
+				{Name: "tz", Type: "string", Required: false},
+			},
+			Auth: "bearer",
+		},
+		{
+			Command:     "reports.Search",
+			Description: "Search reports.",
+			Parameters: []models.APIParameter{
+				{Name: "query", Type: "string", Required: true},
+				{Name: "owner's_ids", Type: "[]int64", Required: true},
+				{Name: "filter", Type: "ReportFilter", Required: true},
+				{Name: "exact", Type: "*bool", Required: true},
+			},
+		},
+	}
+	projectInfo := models.ProjectInfo{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Servers: []string{"https://api.example.com/rpc"},
+	}
+	got := generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{CodeSamples: []string{CodeSampleCurl}})
+	assertGolden(t, "curl_samples", got)
+
+	// The endpoint option wins over the declared servers
+	got = generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{CodeSamples: []string{CodeSampleCurl}, Endpoint: "http://localhost:9000", IDType: IDTypeString})
+	if !strings.Contains(got, `curl -X POST 'http://localhost:9000' \`) || !strings.Contains(got, `"id":"1"`) {
+		t.Errorf("Expected endpoint override and string id, got:\n%s", got)
+	}
+}
+
+func TestUnsupportedCodeSample(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	outFile := filepath.Join(t.TempDir(), "out.md")
+	if err := GenerateDocumentation(apiFunctions, structs, projectInfo, outFile, Options{CodeSamples: []string{"cobol"}}); err == nil {
+		t.Fatal("Expected an error for an unsupported code sample")
+	}
+}
+
+func TestQuickSummaryGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "a.NoRequired",
+			Description: "No required parameters.",
+			PackageName: "rpc",
+			Parameters: []models.APIParameter{
+				{Name: "tz", Type: "string", Required: false},
+			},
+			Results: []models.APIReturn{
+				{Name: "result", Type: "int", Description: "A number."},
+			},
+		},
+		{
+			Command:     "b.OneRequired",
+			Description: "One required parameter.",
+			PackageName: "rpc",
+			Parameters: []models.APIParameter{
+				{Name: "user_id", Type: "int", Required: true},
+				{Name: "tz", Type: "string", Required: false},
+			},
+			Results: []models.APIReturn{
+				{Name: "result", Type: "User", Description: "The user."},
+			},
+		},
+		{
+			Command:     "c.ManyRequired",
+			Description: "Many required parameters.",
+			PackageName: "rpc",
+			Parameters: []models.APIParameter{
+				{Name: "user_id", Type: "int", Required: true},
+				{Name: "tz", Type: "string", Required: true},
+				{Name: "limit", Type: "int", Required: true},
+			},
+			Results: []models.APIReturn{
+				{Name: "result", Type: "User", Description: "The user again."},
+			},
+		},
+		{
+			Command:     "d.Nothing",
+			Description: "No parameters and no result.",
+			PackageName: "rpc",
+		},
+	}
+	_, structs, projectInfo := testModel()
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{QuickSummary: true})
+	assertGolden(t, "quick_summary", got)
+
+	// Off by default
+	got = generateString(t, apiFunctions, structs, projectInfo, Options{})
+	if strings.Contains(got, "**Requires:**") || strings.Contains(got, "**Returns:**") {
+		t.Errorf("Expected no quick summary by default, got:\n%s", got)
+	}
+}
+
+func TestMaxFieldsGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "config.Get",
+			Description: "Get the configuration.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "Config", Description: "The configuration."},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "config.Limits",
+			Description: "Get the limits.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "Limits", Description: "The limits."},
+			},
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "Config"}: {
+			Name:        "Config",
+			Description: "Server configuration.",
+			Fields: []models.StructField{
+				{Name: "Host", Type: "string", Description: "Host name.", JSONName: "host"},
+				{Name: "Port", Type: "int", Description: "Port.", JSONName: "port"},
+				{Name: "Debug", Type: "bool", Description: "Debug mode.", JSONName: "debug"},
+				{Name: "Workers", Type: "int", Description: "Worker count.", JSONName: "workers"},
+			},
+		},
+		{Package: "rpc", Name: "Limits"}: {
+			Name:        "Limits",
+			Description: "Request limits.",
+			NoTruncate:  true,
+			Fields: []models.StructField{
+				{Name: "MaxBody", Type: "int", Description: "Maximum body size.", JSONName: "max_body"},
+				{Name: "MaxBatch", Type: "int", Description: "Maximum batch size.", JSONName: "max_batch"},
+				{Name: "Timeout", Type: "int", Description: "Timeout in seconds.", JSONName: "timeout"},
+			},
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{MaxFields: 2})
+	assertGolden(t, "max_fields", got)
+
+	// No limit by default
+	got = generateString(t, apiFunctions, structs, projectInfo, Options{})
+	if strings.Contains(got, "more fields") || strings.Contains(got, appendixHeading) {
+		t.Errorf("Expected no truncation by default, got:\n%s", got)
+	}
+}
+
+func TestMaxFieldsSplit(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{MaxFields: 1}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(outDir, "user.get.md"))
+	if err != nil {
+		t.Fatalf("Failed to read command page: %v", err)
+	}
+	if !strings.Contains(string(page), "| … and 1 more fields, see [appendix](types.md#rpcuser-complete) | | | |") {
+		t.Errorf("Expected truncation row linking to types.md, got:\n%s", page)
+	}
+
+	types, err := os.ReadFile(filepath.Join(outDir, splitTypesFile))
+	if err != nil {
+		t.Fatalf("Failed to read types appendix: %v", err)
+	}
+	if !strings.Contains(string(types), "### rpc.User (complete)") || !strings.Contains(string(types), "| Name | string |") {
+		t.Errorf("Expected complete User definition, got:\n%s", types)
+	}
+}
+
+func TestStructReferencesGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "reports.Get",
+			Description: "Get a report.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "Report", Description: "The report."},
+			},
+			AdditionalStructs: []string{"Summary"},
+			PackageName:       "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "Report"}: {
+			Name: "Report",
+			Fields: []models.StructField{
+				{Name: "Daily", Type: "[]ReportItem", JSONName: "daily"},
+				{Name: "Weekly", Type: "[]ReportItem", JSONName: "weekly"},
+				{Name: "Monthly", Type: "[]*ReportItem", JSONName: "monthly"},
+				{Name: "ByOwner", Type: "map[string]Owner", JSONName: "by_owner"},
+				{Name: "Summary", Type: "*Summary", JSONName: "summary"},
+			},
+		},
+		{Package: "rpc", Name: "ReportItem"}: {
+			Name: "ReportItem",
+			Fields: []models.StructField{
+				{Name: "Value", Type: "int", JSONName: "value"},
+				{Name: "Owner", Type: "Owner", JSONName: "owner"},
+			},
+		},
+		{Package: "rpc", Name: "Owner"}: {
+			Name: "Owner",
+			Fields: []models.StructField{
+				{Name: "Name", Type: "string", JSONName: "name"},
+				{Name: "Manager", Type: "*Owner", JSONName: "manager"},
+			},
+		},
+		{Package: "rpc", Name: "Summary"}: {
+			Name: "Summary",
+			Fields: []models.StructField{
+				{Name: "Top", Type: "[]ReportItem", JSONName: "top"},
+				{Name: "Report", Type: "*Report", JSONName: "report"},
+			},
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "struct_references", got)
+}
+
+func TestExcludedFields(t *testing.T) {
+	sessionKey := models.StructKey{Package: "rpc", Name: "Session"}
+	structs := map[models.StructKey]models.StructDefinition{
+		sessionKey: {
+			Name: "Session",
+			Fields: []models.StructField{
+				{Name: "Token", Type: "string", JSONName: "token"},
+				{Name: "Secret", Type: "string", Description: "Signing secret.", Excluded: true},
+				{Name: "Expiry", Type: "int64", JSONName: "expiry", Omitempty: true},
+			},
+		},
+	}
+	apiFunctions := []models.APIFunction{{
+		Command:     "auth.Login",
+		Description: "Open a session.",
+		Results:     []models.APIReturn{{Name: "result", Type: "Session", Description: "The session."}},
+		PackageName: "rpc",
+	}}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{StructSource: true})
+	for _, want := range []string{
+		"| Secret | string | Signing secret. | _excluded from JSON_ |",
+		"| Expiry | int64 | — | expiry |",
+		"Secret string `json:\"-\"`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "omitempty |") || strings.Contains(got, `"": ""`) {
+		t.Errorf("Excluded field rendered as a JSON member:\n%s", got)
+	}
+}
+
+func TestFieldRequiredColumn(t *testing.T) {
+	accountKey := models.StructKey{Package: "rpc", Name: "Account"}
+	structs := map[models.StructKey]models.StructDefinition{
+		accountKey: {
+			Name: "Account",
+			Fields: []models.StructField{
+				{Name: "Audit", Type: "Audit", JSONName: "Audit", Embedded: true},
+				{Name: "ID", Type: "int", JSONName: "id"},
+				{Name: "Nickname", Type: "string", JSONName: "nickname", Omitempty: true},
+				{Name: "Manager", Type: "*int", JSONName: "manager"},
+				{Name: "Parent", Type: "*string", JSONName: "parent", Omitempty: true},
+				{Name: "Password", Type: "string", Excluded: true},
+			},
+		},
+	}
+	apiFunctions := []models.APIFunction{{
+		Command:     "account.Get",
+		Description: "Get an account.",
+		Results:     []models.APIReturn{{Name: "result", Type: "Account", Description: "The account."}},
+		PackageName: "rpc",
+	}}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	want := "| Name | Type | Description | JSON Name | Required |\n" +
+		"|------|------|-------------|-----------|----------|\n" +
+		"| Audit | Audit | — | Audit | Embedded |\n" +
+		"| ID | int | — | id | Yes |\n" +
+		"| Nickname | string | — | nickname | No |\n" +
+		"| Manager | *int | — | manager | No |\n" +
+		"| Parent | *string | — | parent | No |\n" +
+		"| Password | string | — | _excluded from JSON_ | No |\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Expected fields table:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestStructGraphRecursionAndDiamonds(t *testing.T) {
+	nodeKey := models.StructKey{Package: "rpc", Name: "Node"}
+	structs := map[models.StructKey]models.StructDefinition{
+		// A tree node refers to itself
+		nodeKey: {
+			Name: "Node",
+			Fields: []models.StructField{
+				{Name: "Children", Type: "[]Node", JSONName: "children"},
+				{Name: "Parent", Type: "*Node", JSONName: "parent"},
+			},
+		},
+		// Top reaches Shared through Left and Right
+		{Package: "rpc", Name: "Top"}: {
+			Name: "Top",
+			Fields: []models.StructField{
+				{Name: "Left", Type: "Left", JSONName: "left"},
+				{Name: "Right", Type: "Right", JSONName: "right"},
+				{Name: "Tree", Type: "Node", JSONName: "tree"},
+			},
+		},
+		{Package: "rpc", Name: "Left"}: {
+			Name:   "Left",
+			Fields: []models.StructField{{Name: "Shared", Type: "Shared", JSONName: "shared"}},
+		},
+		{Package: "rpc", Name: "Right"}: {
+			Name:   "Right",
+			Fields: []models.StructField{{Name: "Shared", Type: "*Shared", JSONName: "shared"}},
+		},
+		{Package: "rpc", Name: "Shared"}: {
+			Name:   "Shared",
+			Fields: []models.StructField{{Name: "Value", Type: "int", JSONName: "value"}},
+		},
+	}
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "tree.Get",
+			Description: "Get a tree.",
+			Results:     []models.APIReturn{{Name: "result", Type: "Node", Description: "The root."}},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "top.Get",
+			Description: "Get the top.",
+			Results:     []models.APIReturn{{Name: "result", Type: "Top", Description: "The top."}},
+			PackageName: "rpc",
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	graph := collectStructGraph([]models.StructKey{nodeKey}, structs, Options{})
+	if len(graph.order) != 1 || len(graph.references[nodeKey]) != 0 {
+		t.Errorf("Expected the self-referencing node once as a root, got %v", graph)
+	}
+	graph = collectStructGraph([]models.StructKey{{Package: "rpc", Name: "Top"}}, structs, Options{})
+	var order []string
+	for _, key := range graph.order {
+		order = append(order, key.Name)
+	}
+	if got := strings.Join(order, ","); got != "Top,Left,Right,Node,Shared" {
+		t.Errorf("Unexpected struct order %s", got)
+	}
+	if refs := graph.references[models.StructKey{Package: "rpc", Name: "Shared"}]; len(refs) != 2 {
+		t.Errorf("Expected Shared referenced by Left and Right, got %v", refs)
+	}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	sections := strings.Split(got, "\n## ")
+	for _, section := range sections[1:] {
+		command := section[:strings.Index(section, "\n")]
+		for _, heading := range []string{"#### rpc.Node\n", "#### rpc.Shared\n"} {
+			if count := strings.Count(section, heading); count > 1 {
+				t.Errorf("%s documents %q %d times", command, heading, count)
+			}
+		}
+	}
+	if !strings.Contains(got, "Referenced by: Shared of rpc.Left; Shared of rpc.Right.") {
+		t.Errorf("Expected Shared to list both referrers, got:\n%s", got)
+	}
+}
+
+func TestJSONCExamplesGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "reports.Search",
+			Description: "Search reports.",
+			Parameters: []models.APIParameter{
+				{Name: "query", Type: "string", Description: "Full-text query,\nmatched against titles.", Required: true},
+				{Name: "filter", Type: "Filter", Description: "Filter applied to the results.", Required: false},
+				{Name: "ids", Type: "[]int", Description: "Report ids.", Required: false},
+				{Name: "exact", Type: "bool", Required: false},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "reports.Count",
+			Description: "Count reports.",
+			PackageName: "rpc",
+		},
+	}
+	_, structs, projectInfo := testModel()
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{ExampleStyle: ExampleStyleJSONC, IDType: IDTypeString, ExampleCommentLength: 40})
+	assertGolden(t, "jsonc_examples", got)
+
+	// Plain JSON is the default
+	got = generateString(t, apiFunctions, structs, projectInfo, Options{})
+	if strings.Contains(got, "```jsonc") {
+		t.Errorf("Expected no JSONC examples by default, got:\n%s", got)
+	}
+}
+
+func TestExampleRequestGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "reports.Search",
+			Description: "Search reports.",
+			Parameters: []models.APIParameter{
+				{Name: "query", Type: "string", Description: "Full-text query.", Required: true},
+				{Name: "limit", Type: "int64", Description: "Page size.", Default: &models.ParamDefault{Value: "50"}},
+				{Name: "exact", Type: "bool", Description: "Match whole words."},
+				{Name: "ids", Type: "[]int", Description: "Report ids."},
+				{Name: "filter", Type: "*Filter", Description: "Filter applied to the results.", Required: true},
+				{Name: "labels", Type: "map[string]string", Description: "Labels to match."},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "reports.Count",
+			Description: "Count reports.",
+			PackageName: "rpc",
+		},
+		{
+			Command:      "reports.Watch",
+			Description:  "Watch new reports.",
+			Results:      []models.APIReturn{{Name: "result", Type: "string"}},
+			Subscription: &models.Subscription{Method: "reports.New", Payload: models.NotificationPayload{Type: "string"}},
+			PackageName:  "rpc",
+		},
+	}
+	_, structs, projectInfo := testModel()
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "example_request", got)
+
+	// The Subscription section already shows the request of a subscribe command
+	if count := strings.Count(got, "### Example Request:"); count != 2 {
+		t.Errorf("Expected 2 example requests, got %d", count)
+	}
+}
+
+func TestExampleResponseGolden(t *testing.T) {
+	pageKey := models.StructKey{Package: "rpc", Name: "Pagination[ReportItem]"}
+	itemKey := models.StructKey{Package: "rpc", Name: "ReportItem"}
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "reports.List",
+			Description: "List reports.",
+			Results: []models.APIReturn{{
+				Name:        "result",
+				Type:        "Pagination[ReportItem]",
+				Description: "A page of reports.",
+				TypeRef: &models.TypeRef{
+					Kind:     models.TypeStruct,
+					Name:     "Pagination",
+					Package:  "rpc",
+					TypeArgs: []*models.TypeRef{{Kind: models.TypeStruct, Name: "ReportItem", Package: "rpc", Struct: itemKey}},
+					Struct:   pageKey,
+				},
+			}},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "reports.Delete",
+			Description: "Delete a report.",
+			NoEnvelope:  true,
+			Results:     []models.APIReturn{{Name: "result", Type: "bool", Description: "Whether it existed."}},
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		pageKey: {
+			Name: "Pagination[ReportItem]",
+			Fields: []models.StructField{
+				{Name: "Items", Type: "[]ReportItem", JSONName: "items"},
+				{Name: "Total", Type: "int", JSONName: "total"},
+			},
+		},
+		itemKey: {
+			Name: "ReportItem",
+			Fields: []models.StructField{
+				{Name: "ID", Type: "string", JSONName: "id"},
+				{Name: "Author", Type: "string", JSONName: "author", Format: "email"},
+				{Name: "Created", Type: "time.Time", JSONName: "created", Format: "date-time"},
+				{Name: "Content", Type: "[]byte", JSONName: "content"},
+				{Name: "Tags", Type: "map[string]int", JSONName: "tags"},
+				{Name: "Parent", Type: "*ReportItem", JSONName: "parent"},
+			},
+		},
+	}
+	_, _, projectInfo := testModel()
+	projectInfo.ResultEnvelope = models.ResultEnvelope{Members: []models.EnvelopeMember{
+		{Name: "data", Type: models.EnvelopeResult},
+		{Name: "took", Type: "int"},
+	}}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{ExampleDepth: 2})
+	assertGolden(t, "example_response", got)
+
+	// The self-referencing parent is expanded once at depth 2
+	if !strings.Contains(got, `"parent": {}`) {
+		t.Errorf("Expected the example depth to stop the parent chain, got:\n%s", got)
+	}
+}
+
+func TestInvalidExampleDepth(t *testing.T) {
+	if err := (Options{ExampleDepth: -1}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid example depth") {
+		t.Errorf("Expected an invalid example depth error, got %v", err)
+	}
+}
+
+func TestInvalidExampleStyle(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	err := GenerateDocumentation(apiFunctions, structs, projectInfo, filepath.Join(t.TempDir(), "out.md"), Options{ExampleStyle: "json5"})
+	if err == nil || !strings.Contains(err.Error(), "invalid example style") {
+		t.Errorf("Expected invalid example style error, got %v", err)
+	}
+}
+
+func TestFormerNamesGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions[0].FormerNames = []string{"account.GetUser", "users.Fetch"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "former_names", got)
+
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outDir, splitManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	if !strings.Contains(string(content), `"account.GetUser": "user.get.md#accountgetuser"`) {
+		t.Errorf("Expected former name in manifest, got:\n%s", content)
+	}
+}
+
+func TestEnvelopeGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions[0].Envelope = models.Envelope{JSONRPC: "1.0", IDType: "string"}
+	apiFunctions[1].Envelope = models.Envelope{IDType: "number"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{
+		ExampleStyle: ExampleStyleJSONC,
+		CodeSamples:  []string{"curl"},
+	})
+	assertGolden(t, "envelope", got)
+}
+
+func TestEmptyDescriptionPlaceholder(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions[0].Parameters[0].Description = ""
+	user := structs[models.StructKey{Package: "rpc", Name: "User"}]
+	user.Fields[1].Description = ""
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{EmptyDescription: "_Undocumented._"})
+	for _, row := range []string{
+		"| id | int | _Undocumented._ | Yes |",
+		"| Name | string | _Undocumented._ | name |",
+	} {
+		if !strings.Contains(got, row) {
+			t.Errorf("Expected row %q in output:\n%s", row, got)
+		}
+	}
+	if user.Fields[1].Description != "" {
+		t.Errorf("The placeholder leaked into the model: %q", user.Fields[1].Description)
+	}
+}
+
+func TestEmptySections(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions[1].Parameters = nil
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	if !strings.Contains(got, "## stats.GetAllMetrics\n\nGet statistics for the last 30 days.\n\nThis method takes no parameters.\n\n") {
+		t.Errorf("Expected the no parameters sentence, got:\n%s", got)
+	}
+	if strings.Count(got, defaultStandardErrorsText) != 1 {
+		t.Errorf("Expected the standard errors sentence once, for stats.GetAllMetrics only, got:\n%s", got)
+	}
+
+	got = generateString(t, apiFunctions, structs, projectInfo, Options{StandardErrorsText: "See [Error Codes](errors.md)."})
+	if !strings.Contains(got, "\nSee [Error Codes](errors.md).\n\n") || strings.Contains(got, defaultStandardErrorsText) {
+		t.Errorf("Expected the custom standard errors sentence, got:\n%s", got)
+	}
+
+	got = generateString(t, apiFunctions, structs, projectInfo, Options{OmitEmptySections: true})
+	if strings.Contains(got, noParametersText) || strings.Contains(got, defaultStandardErrorsText) {
+		t.Errorf("Expected the sentences to be omitted, got:\n%s", got)
+	}
+}
+
+// envelopeModel returns the test model with a project @envelope whose meta member is a struct.
+func envelopeModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions[1].Results = []models.APIReturn{{Name: "result", Type: "bool", Description: "Always true."}}
+	apiFunctions[1].NoEnvelope = true
+	structs[models.StructKey{Package: "rpc", Name: "ResponseMeta"}] = models.StructDefinition{
+		Name:        "ResponseMeta",
+		Description: "Added by the server to every response.",
+		Fields: []models.StructField{
+			{Name: "RequestID", Type: "string", Description: "Identifier of the request.", JSONName: "request_id"},
+		},
+	}
+	projectInfo.ResultEnvelope = models.ResultEnvelope{
+		Members: []models.EnvelopeMember{
+			{Name: "data", Type: models.EnvelopeResult},
+			{Name: "meta", Type: "ResponseMeta", TypeRef: &models.TypeRef{
+				Kind:    models.TypeStruct,
+				Name:    "ResponseMeta",
+				Package: "rpc",
+				Struct:  models.StructKey{Package: "rpc", Name: "ResponseMeta"},
+			}},
+		},
+	}
+	return apiFunctions, structs, projectInfo
+}
+
+func TestResultEnvelopeGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := envelopeModel()
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "result_envelope", got)
+}
+
+func TestResultEnvelopeSplit(t *testing.T) {
+	apiFunctions, structs, projectInfo := envelopeModel()
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outDir, splitIndexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), "## Result Envelope") || !strings.Contains(string(index), "#### rpc.ResponseMeta") {
+		t.Errorf("Expected the envelope and its structs in the index, got:\n%s", index)
+	}
+
+	command, err := os.ReadFile(filepath.Join(outDir, "user.get.md"))
+	if err != nil {
+		t.Fatalf("Failed to read command file: %v", err)
+	}
+	if !strings.Contains(string(command), "The result is wrapped in the [result envelope](index.md#result-envelope) as `data`.") {
+		t.Errorf("Expected a link to the envelope in the index, got:\n%s", command)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(outDir, splitManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	if !strings.Contains(string(manifest), `"rpc.ResponseMeta": "index.md#rpcresponsemeta"`) {
+		t.Errorf("Expected the envelope struct in the manifest, got:\n%s", manifest)
+	}
+}
+
+// errorCatalogModel returns commands sharing the errors of an @errorcatalog, as the parser
+// resolves them.
+func errorCatalogModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "users.Get",
+			Description: "Get a user.",
+			Errors: []models.APIError{
+				{Code: -32004, Name: "UserNotFound", Description: "The user does not exist."},
+				{Code: 400, Description: "Bad request."},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "users.Delete",
+			Description: "Delete a user.",
+			Errors: []models.APIError{
+				{Code: -32004, Name: "UserNotFound", Description: "The user was already deleted."},
+			},
+			PackageName: "rpc",
+		},
+	}
+	projectInfo := models.ProjectInfo{
+		Title:   "Users API",
+		Version: "1.0.0",
+		ErrorCatalog: []models.CatalogError{
+			{Code: -32004, Name: "UserNotFound", Description: "The user does not exist."},
+			{Code: -32005, Name: "QuotaExceeded", Description: "The quota of the account is exhausted."},
+		},
+	}
+	return apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo
+}
+
+func TestErrorCatalogGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := errorCatalogModel()
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "error_catalog", got)
+}
+
+func TestErrorCatalogSplit(t *testing.T) {
+	apiFunctions, structs, projectInfo := errorCatalogModel()
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outDir, splitIndexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), "| <a id=\"error--32004\"></a>-32004 | `UserNotFound` | The user does not exist. | [users.Delete](users.delete.md), [users.Get](users.get.md) |") {
+		t.Errorf("Expected the catalog in the index, got:\n%s", index)
+	}
+
+	command, err := os.ReadFile(filepath.Join(outDir, "users.get.md"))
+	if err != nil {
+		t.Fatalf("Failed to read command file: %v", err)
+	}
+	if !strings.Contains(string(command), "| [-32004](index.md#error--32004) | `UserNotFound` | The user does not exist. |") {
+		t.Errorf("Expected the error to link to the catalog in the index, got:\n%s", command)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(outDir, splitManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	if !strings.Contains(string(manifest), `"QuotaExceeded": "index.md#error--32005"`) {
+		t.Errorf("Expected the catalog errors in the manifest, got:\n%s", manifest)
+	}
+}
+
+func TestMethodNotesGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "invoice.Get",
+			Description: "Returns an invoice.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "Invoice", Description: "The invoice."},
+			},
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "Invoice"}: {
+			Name: "Invoice",
+			Fields: []models.StructField{
+				{Name: "Total", Type: "Money", Description: "Invoice total.", JSONName: "total"},
+			},
+		},
+		{Package: "rpc", Name: "Money"}: {
+			Name:              "Money",
+			Description:       "Money is an amount in a currency.",
+			IncludeMethodDocs: true,
+			Fields: []models.StructField{
+				{Name: "Amount", Type: "int64", Description: "Amount in the smallest unit.", JSONName: "amount"},
+				{Name: "Currency", Type: "string", Description: "ISO 4217 code.", JSONName: "currency"},
+			},
+			Methods: []models.MethodDoc{
+				{Name: "Validate", Doc: "Validate checks that the amount is not negative."},
+				{Name: "Cents", Doc: "Cents returns the amount in cents."},
+			},
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "method_notes", got)
+}
+
+func TestDynamicKeysGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "stats.Get",
+			Description: "Returns traffic by host.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "map[string]HostStats", Description: "Traffic by host.", DynamicKeys: []string{"host name"}},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "stats.Counts",
+			Description: "Returns request counts by host and day.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "map[string]map[string]int64", DynamicKeys: []string{"host name"}},
+			},
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "HostStats"}: {
+			Name: "HostStats",
+			Fields: []models.StructField{
+				{Name: "Requests", Type: "int64", Description: "Requests served.", JSONName: "requests"},
+				{Name: "Daily", Type: "map[string]int64", Description: "Requests by day.", JSONName: "daily", DynamicKeys: []string{"date (YYYY-MM-DD)"}},
+			},
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "dynamic_keys", got)
+}
+
+func TestDynamicKeysPhrase(t *testing.T) {
+	tests := []struct {
+		typ  string
+		keys []string
+		want string
+	}{
+		{"map[string]HostStats", []string{"host name"}, "Object with dynamic keys (host name) whose values are `HostStats`"},
+		{"*map[string]map[string]int64", []string{"host name", "date"}, "Object with dynamic keys (host name) whose values are objects with dynamic keys (date) whose values are `int64`"},
+		{"map[string]map[string]int64", []string{"host name"}, "Object with dynamic keys (host name) whose values are objects with dynamic keys (key) whose values are `int64`"},
+		{"HostStats", []string{"host name"}, ""},
+	}
+	for _, tt := range tests {
+		if got := dynamicKeysPhrase(tt.typ, tt.keys); got != tt.want {
+			t.Errorf("dynamicKeysPhrase(%q, %q) = %q, want %q", tt.typ, tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestContentTypeGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "invoice.Pdf",
+			Description: "Returns an invoice as a PDF.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "string", Description: "The document.", ContentTypes: []models.ContentType{
+					{MediaType: "application/pdf", Encoding: "base64"},
+				}},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "invoice.Render",
+			Description: "Renders an invoice in the accepted format.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "string", Description: "The rendered invoice.", ContentTypes: []models.ContentType{
+					{MediaType: "application/pdf", Encoding: "base64"},
+					{MediaType: "text/html"},
+				}},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "invoice.Total",
+			Description: "Returns the invoice total.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "int64", Description: "Total in cents."},
+			},
+			PackageName: "rpc",
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{})
+	assertGolden(t, "content_type", got)
+}
+
+func TestParamRulesGolden(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "search.Find",
+			Description: "Finds documents by query or by ids.",
+			Parameters: []models.APIParameter{
+				{Name: "query", Type: "string", Description: "Full-text query."},
+				{Name: "ids", Type: "[]int", Description: "Document ids."},
+				{Name: "page_size", Type: "int", Description: "Results per page."},
+				{Name: "page_token", Type: "string", Description: "Token of the next page.", Required: true},
+				{Name: "sort", Type: "string", Description: "Sort order."},
+			},
+			Requires: []models.ParamRule{
+				{Param: "page_token", Others: []string{"page_size"}},
+			},
+			ConflictsWith: []models.ParamRule{
+				{Param: "query", Others: []string{"ids"}},
+				{Param: "sort", Others: []string{"query", "ids"}},
+			},
+			PackageName: "rpc",
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{
+		ExampleStyle: ExampleStyleJSONC,
+		CodeSamples:  []string{CodeSampleCurl},
+	})
+	assertGolden(t, "param_rules", got)
+}
+
+func TestStructSourceGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	user := structs[models.StructKey{Package: "rpc", Name: "User"}]
+	user.Fields = append(user.Fields,
+		models.StructField{Name: "*Audit", Type: "*Audit", JSONName: "Audit"},
+		models.StructField{Name: "Tags", Type: "map[string][]string", Description: "Labels of the user.", JSONName: "tags", Omitempty: true},
+	)
+	user.Description = "User account.\nCreated on sign-up."
+	structs[models.StructKey{Package: "rpc", Name: "User"}] = user
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{StructSource: true})
+	assertGolden(t, "struct_source", got)
+	assertGoSnippetsFormatted(t, got)
+}
+
+func TestStructSourceGeneric(t *testing.T) {
+	source, err := structSource(models.StructDefinition{Name: "Page", TypeParams: []models.TypeParam{{Name: "T", Constraint: "any"}}, Fields: []models.StructField{
+		{Name: "Items", Type: "[]T", JSONName: "items"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "type Page[T any] struct {\n\tItems []T `json:\"items\"`\n}\n"; source != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, source)
+	}
+
+	source, err = structSource(models.StructDefinition{Name: "Page[User]", Fields: []models.StructField{
+		{Name: "Items", Type: "[]User", JSONName: "items"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "// Page[User], with its type parameters substituted.\ntype Page struct {\n\tItems []User `json:\"items\"`\n}\n"; source != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, source)
+	}
+}
+
+// assertGoSnippetsFormatted checks that every go code block of doc is left unchanged by go/format.
+func assertGoSnippetsFormatted(t *testing.T, doc string) {
+	t.Helper()
+	blocks := strings.Split(doc, "```go\n")
+	if len(blocks) < 2 {
+		t.Fatalf("Expected go code blocks in:\n%s", doc)
+	}
+	for _, block := range blocks[1:] {
+		snippet, _, _ := strings.Cut(block, "```")
+		formatted, err := format.Source([]byte(snippet))
+		if err != nil {
+			t.Errorf("Snippet does not parse: %v\n%s", err, snippet)
+		} else if string(formatted) != snippet {
+			t.Errorf("Snippet is not gofmt-formatted:\n%s\nexpected:\n%s", snippet, formatted)
+		}
+	}
+}
+
+// subscriptionModel returns a subscribe command pushing a struct, and one pushing a number
+// over JSON-RPC 1.0.
+func subscriptionModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	eventKey := models.StructKey{Package: "rpc", Name: "EventPayload"}
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "events.Subscribe",
+			Description: "Subscribes to account events.",
+			Parameters: []models.APIParameter{
+				{Name: "account", Type: "string", Description: "Account to watch.", Required: true},
+			},
+			Results: []models.APIReturn{
+				{Name: "result", Type: "string", Description: "Subscription id."},
+			},
+			Subscription: &models.Subscription{
+				Method: "events.push",
+				Payload: models.NotificationPayload{
+					Type:        "EventPayload",
+					TypeRef:     &models.TypeRef{Kind: models.TypeStruct, Name: "EventPayload", Package: "rpc", Struct: eventKey},
+					Description: "Pushed for each event.",
+				},
+			},
+			PackageName: "rpc",
+		},
+		{
+			Command:     "clock.Subscribe",
+			Description: "Subscribes to clock ticks.",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "int", Description: "Subscription id."},
+			},
+			Subscription: &models.Subscription{
+				Method:  "subscription",
+				Payload: models.NotificationPayload{Type: "int64", Description: "Unix time of the tick."},
+			},
+			Envelope:    models.Envelope{JSONRPC: "1.0"},
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		eventKey: {
+			Name:        "EventPayload",
+			Description: "Pushed for each event.",
+			Fields: []models.StructField{
+				{Name: "Kind", Type: "string", Description: "Kind of event.", JSONName: "kind"},
+				{Name: "At", Type: "int64", Description: "Unix time of the event.", JSONName: "at"},
+			},
+		},
+	}
+	return apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+}
+
+func TestSubscriptionGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := subscriptionModel()
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "subscription", got)
+}
+
+func TestSubscriptionSplitIndex(t *testing.T) {
+	apiFunctions, structs, projectInfo := subscriptionModel()
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outDir, splitIndexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), "- [events.Subscribe](events.subscribe.md) _(subscription)_\n") {
+		t.Errorf("Expected subscribe commands to be marked in the index, got:\n%s", index)
+	}
+}
+
+func TestCheatSheetGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	outFile := filepath.Join(t.TempDir(), "cheatsheet.md")
+	if err := GenerateCheatSheet(apiFunctions, structs, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateCheatSheet returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	assertGolden(t, "cheatsheet", string(content))
+}
+
+func TestCheatSheetTagsGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	for i := range apiFunctions {
+		if apiFunctions[i].Command == "user.Get" {
+			apiFunctions[i].Tags = []string{"Users"}
+		}
+	}
+	var sheet strings.Builder
+	if err := WriteCheatSheet(&sheet, apiFunctions, structs, projectInfo, Options{}); err != nil {
+		t.Fatalf("WriteCheatSheet returned error: %v", err)
+	}
+	assertGolden(t, "cheatsheet_tags", sheet.String())
+}
+
+func TestCheatSheetFlattenedParams(t *testing.T) {
+	apiFunctions, structs, projectInfo := unitsModel()
+	outFile := filepath.Join(t.TempDir(), "cheatsheet.md")
+	if err := GenerateCheatSheet(apiFunctions, structs, projectInfo, outFile, Options{FlattenParams: true}); err != nil {
+		t.Fatalf("GenerateCheatSheet returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	// Required parameters match the flattened Parameters table of the full documentation
+	want := "| `jobs.Create` | `job.timeout_ms: int64 (milliseconds)`, `job.owner: string (email)`, `job.created: string (RFC 3339 timestamp)`, `job.day: time.Time (date)`, `job.size: int64 (int64, bytes)` | `Job` | — |\n"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("Expected the cheat sheet to contain %q, got:\n%s", want, content)
+	}
+}
+
+func TestOmittedSectionsGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{OmitEmptySections: true, MaxFields: 1})
+	assertGolden(t, "omitted_sections", got)
+}
+
+func TestSectionSeparators(t *testing.T) {
+	// Separators go between commands, and between the last command and the appendix
+	tests := []struct {
+		name       string
+		commands   int
+		opts       Options
+		separators int
+	}{
+		{"no commands", 0, Options{IncludeRFC: true}, 0},
+		{"one command", 1, Options{OmitEmptySections: true}, 0},
+		{"commands and appendix", 2, Options{OmitEmptySections: true, MaxFields: 1}, 2},
+		{"commands without appendix", 2, Options{IncludeRFC: true, QuickSummary: true}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiFunctions, structs, projectInfo := testModel()
+			got := generateString(t, apiFunctions[:tt.commands], structs, projectInfo, tt.opts)
+
+			if !strings.HasSuffix(got, "\n") || strings.HasSuffix(got, "\n\n") {
+				t.Errorf("Expected the document to end with a single newline, got %q", got[max(0, len(got)-20):])
+			}
+			if strings.HasSuffix(strings.TrimSpace(got), "---") {
+				t.Errorf("Expected no separator at the end of the document:\n%s", got)
+			}
+			if strings.Contains(got, "\n\n\n") {
+				t.Errorf("Expected no runs of blank lines:\n%s", got)
+			}
+			if separators := strings.Count(got, "\n---\n"); separators != tt.separators {
+				t.Errorf("Expected %d separators, got %d:\n%s", tt.separators, separators, got)
+			}
+		})
+	}
+}
+
+func TestHandWrittenExamples(t *testing.T) {
+	apiFunctions := []models.APIFunction{{
+		Command:         "users.Get",
+		Description:     "Get a user.",
+		Parameters:      []models.APIParameter{{Name: "id", Type: "int", Description: "User id.", Required: true}},
+		ExampleRequest:  `{"jsonrpc": "2.0", "method": "users.Get", "params": {"id": 7}, "id": 1}`,
+		ExampleResponse: "{\n  \"jsonrpc\": \"2.0\",\n  \"result\": {\"id\": 7},\n  \"id\": 1\n}",
+	}}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+	for _, style := range []string{"", ExampleStyleJSONC} {
+		got := generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{ExampleStyle: style})
+
+		want := "### Example Request:\n\n```json\n" + apiFunctions[0].ExampleRequest + "\n```\n\n" +
+			"### Example Response:\n\n```json\n" + apiFunctions[0].ExampleResponse + "\n```\n"
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the hand-written examples verbatim with style %q:\n%s\ngot:\n%s", style, want, got)
+		}
+		if strings.Count(got, "Example Request:") != 1 || strings.Count(got, "Example Response:") != 1 {
+			t.Errorf("Expected the hand-written examples to replace the synthesized ones with style %q, got:\n%s", style, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// generator/globalerrors.go
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// globalErrorsHeading is the heading of the section listing the @globalerror errors.
+const globalErrorsHeading = "Common Errors"
+
+// globalErrorsIntro opens the Common Errors section.
+const globalErrorsIntro = "Errors every command may return, in addition to the errors of its section. A command listing one of these codes overrides its description."
+
+// globalErrorsText is rendered in place of the Errors section of a command without @Error
+// annotations when the documentation has a Common Errors section. Its placeholders take the
+// file and anchor of the section.
+const globalErrorsText = "No method-specific errors are defined; only the [common errors](%s#%s) and standard JSON-RPC errors may be returned."
+
+// noMethodErrorsText is the HTML counterpart of globalErrorsText, which the page follows with
+// a link to the common errors.
+const noMethodErrorsText = "No method-specific errors are defined."
+
+// mergeGlobalErrors returns the errors of a command followed by the global errors, except
+// those whose code the command declares itself: its own description overrides the global one.
+func mergeGlobalErrors(apiErrors, globalErrors []models.APIError) []models.APIError {
+	if len(globalErrors) == 0 {
+		return apiErrors
+	}
+	declared := make(map[int]bool, len(apiErrors))
+	for _, apiError := range apiErrors {
+		declared[apiError.Code] = true
+	}
+	merged := append([]models.APIError(nil), apiErrors...)
+	for _, globalError := range globalErrors {
+		if !declared[globalError.Code] {
+			merged = append(merged, globalError)
+		}
+	}
+	return merged
+}
+
+// commandErrors returns the errors listed in the Errors section of a command: its own, and
+// the global errors of the project with opts.MergeGlobalErrors.
+func commandErrors(apiFunc models.APIFunction, projectInfo models.ProjectInfo, opts Options) []models.APIError {
+	if !opts.MergeGlobalErrors {
+		return apiFunc.Errors
+	}
+	return mergeGlobalErrors(apiFunc.Errors, projectInfo.GlobalErrors)
+}
+
+// hasCommonErrors reports whether the documentation has a Common Errors section.
+func hasCommonErrors(projectInfo models.ProjectInfo, opts Options) bool {
+	return len(projectInfo.GlobalErrors) > 0 && !opts.MergeGlobalErrors
+}
+
+// globalErrorsNote returns the sentence pointing the Errors section of a command to the
+// Common Errors section.
+func globalErrorsNote(opts Options) string {
+	return fmt.Sprintf("The [common errors](%s#%s) may also be returned.", opts.indexFile, slugify(globalErrorsHeading))
+}
+
+// writeGlobalErrorsNote writes the globalErrorsNote under the Errors table of a command.
+// Nothing is written without a Common Errors section.
+func writeGlobalErrorsNote(writer io.Writer, projectInfo models.ProjectInfo, opts Options) {
+	if hasCommonErrors(projectInfo, opts) {
+		fmt.Fprintf(writer, "%s\n\n", globalErrorsNote(opts))
+	}
+}
+
+// standardErrorsText returns the sentence rendered in place of the Errors section of a
+// command without errors: opts.StandardErrorsText followed by the globalErrorsNote, or a
+// default mentioning the common errors when the documentation has them.
+func standardErrorsText(projectInfo models.ProjectInfo, opts Options) string {
+	switch {
+	case opts.StandardErrorsText != "" && hasCommonErrors(projectInfo, opts):
+		return opts.StandardErrorsText + "\n\n" + globalErrorsNote(opts)
+	case opts.StandardErrorsText != "":
+		return opts.StandardErrorsText
+	case hasCommonErrors(projectInfo, opts):
+		return fmt.Sprintf(globalErrorsText, opts.indexFile, slugify(globalErrorsHeading))
+	}
+	return defaultStandardErrorsText
+}
+
+// writeGlobalErrors writes the Common Errors section listing the @globalerror errors, which
+// every command may return, and returns the anchor of its heading for the table of contents.
+// Nothing is written without global errors, or when they are merged into the Errors section
+// of each command.
+func writeGlobalErrors(writer io.Writer, projectInfo models.ProjectInfo, anchors *anchorRegistry, opts Options) string {
+	if !hasCommonErrors(projectInfo, opts) {
+		return ""
+	}
+
+	anchor := anchors.heading(writer, 2, globalErrorsHeading)
+	fmt.Fprintf(writer, "%s\n\n", globalErrorsIntro)
+	writeErrorTable(writer, projectInfo.GlobalErrors, projectInfo.ErrorCatalog, opts)
+	return anchor
+}
@@ -0,0 +1,118 @@
+// generator/globalerrors_test.go
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/validate"
+)
+
+func TestMergeGlobalErrors(t *testing.T) {
+	apiErrors := []models.APIError{{Code: 404, Description: "User not found."}, {Code: 1001, Description: "The session expired."}}
+	globalErrors := []models.APIError{{Code: 1001, Description: "Unauthorized."}, {Code: 1002, Description: "Rate limited."}}
+
+	// The command keeps its own description of a global code
+	want := []models.APIError{apiErrors[0], apiErrors[1], globalErrors[1]}
+	if got := mergeGlobalErrors(apiErrors, globalErrors); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeGlobalErrors() = %+v, want %+v", got, want)
+	}
+	if got := mergeGlobalErrors(nil, globalErrors); !reflect.DeepEqual(got, globalErrors) {
+		t.Errorf("mergeGlobalErrors() = %+v, want %+v", got, globalErrors)
+	}
+	if len(apiErrors) != 2 {
+		t.Errorf("Expected the errors of the command to be left as they are, got %+v", apiErrors)
+	}
+}
+
+func TestGlobalErrors(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions[0].Errors = append(apiFunctions[0].Errors, models.APIError{Code: 1001, Description: "The session expired."})
+	projectInfo.GlobalErrors = []models.APIError{
+		{Code: -32600, Description: "The request is not a valid JSON-RPC request."},
+		{Code: 1001, Description: "Unauthorized."},
+	}
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "global_errors", got)
+	if diagnostics := validate.Markdown("API.md", got); len(diagnostics) > 0 {
+		t.Errorf("Expected valid Markdown, got %v", diagnostics)
+	}
+
+	// The section comes before the first command, which both link to it
+	section := strings.Index(got, "## Common Errors\n")
+	if first := strings.Index(got, "## stats.GetAllMetrics\n"); section < 0 || section > first {
+		t.Errorf("Expected a Common Errors section before the first command, got:\n%s", got)
+	}
+	if count := strings.Count(got, "[common errors](#common-errors)"); count != 2 {
+		t.Errorf("Expected every command to link to the common errors, got %d links:\n%s", count, got)
+	}
+
+	// A custom sentence for commands without errors is followed by the link
+	custom := generateString(t, apiFunctions, structs, projectInfo, Options{StandardErrorsText: "See the error guide."})
+	if want := "See the error guide.\n\nThe [common errors](#common-errors) may also be returned.\n\n"; !strings.Contains(custom, want) {
+		t.Errorf("Expected output to contain %q, got:\n%s", want, custom)
+	}
+
+	// Merged, every command lists the global errors after its own
+	merged := generateString(t, apiFunctions, structs, projectInfo, Options{MergeGlobalErrors: true})
+	for _, unwanted := range []string{"Common Errors", "common-errors", "| 1001 | Unauthorized. |\n| -32600"} {
+		if strings.Contains(merged, unwanted) {
+			t.Errorf("Expected no %q with merged global errors, got:\n%s", unwanted, merged)
+		}
+	}
+	for _, want := range []string{
+		"| 404 | User not found. |\n| 1001 | The session expired. |\n| -32600 | The request is not a valid JSON-RPC request. |\n\n",
+		"| -32600 | The request is not a valid JSON-RPC request. |\n| 1001 | Unauthorized. |\n\n",
+	} {
+		if !strings.Contains(merged, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, merged)
+		}
+	}
+	if strings.Contains(merged, defaultStandardErrorsText) {
+		t.Errorf("Expected no command without errors once the global errors are merged, got:\n%s", merged)
+	}
+}
+
+func TestHTMLGlobalErrors(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	projectInfo.GlobalErrors = []models.APIError{{Code: 1001, Description: "Unauthorized."}}
+	outFile := filepath.Join(t.TempDir(), "api.html")
+	if err := GenerateHTML(apiFunctions, structs, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	// The global errors are listed once, and linked from the sidebar and every command
+	page := string(content)
+	if count := strings.Count(page, "<td>1001</td>"); count != 1 {
+		t.Errorf("Expected the global error listed once, got %d times:\n%s", count, page)
+	}
+	for _, want := range []string{
+		`<li><a href="#common-errors">Common Errors</a></li>`,
+		`<section id="common-errors">`,
+		"<p>No method-specific errors are defined.</p>\n<p>The <a href=\"#common-errors\">common errors</a> may also be returned.</p>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Output does not contain %q:\n%s", want, page)
+		}
+	}
+	if count := strings.Count(page, `<a href="#common-errors">common errors</a>`); count != len(apiFunctions) {
+		t.Errorf("Expected every command to link to the common errors, got %d links", count)
+	}
+
+	// Merged, every command lists them instead
+	if err := GenerateHTML(apiFunctions, structs, projectInfo, outFile, Options{MergeGlobalErrors: true}); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	content, _ = os.ReadFile(outFile)
+	if strings.Contains(string(content), "common-errors") || strings.Count(string(content), "<td>1001</td>") != len(apiFunctions) {
+		t.Errorf("Expected the global error under every command:\n%s", content)
+	}
+}
@@ -0,0 +1,99 @@
+// generator/header_test.go
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// TestProjectInfoRendered fills every text field of the project information and checks that
+// the documentation shows each of them, so a field parsed from the project annotations is
+// never silently dropped.
+func TestProjectInfoRendered(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	info := reflect.ValueOf(&projectInfo).Elem()
+	var values []string
+	for i := 0; i < info.NumField(); i++ {
+		field, value := info.Type().Field(i), "value of "+info.Type().Field(i).Name
+		switch field.Type {
+		case reflect.TypeOf(""):
+			info.Field(i).SetString(value)
+		case reflect.TypeOf([]string{}):
+			info.Field(i).Set(reflect.ValueOf([]string{value}))
+		default:
+			continue
+		}
+		values = append(values, value)
+	}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{IncludeRFC: true})
+	for _, value := range values {
+		if !strings.Contains(got, value) {
+			t.Errorf("Expected the documentation to show %q, got:\n%s", value, got)
+		}
+	}
+}
+
+func TestProjectInfoLabels(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	projectInfo.Contact = "support@example.com"
+	projectInfo.Terms = "https://example.com/terms"
+	projectInfo.Repository = "https://github.com/acme/api"
+	projectInfo.Copyright = "2024 Acme Inc."
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+
+	for _, want := range []string{
+		"API used by the generator tests.\n\n**Contact:** support@example.com\n\n",
+		"**Terms of Service:** https://example.com/terms\n\n",
+		"**Repository:** [https://github.com/acme/api](https://github.com/acme/api)\n\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if want := "\n---\n\n© 2024 Acme Inc.\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("Expected output to end with the copyright footer %q, got:\n%s", want, got)
+	}
+
+	// A notice that already reads as one is kept as written
+	projectInfo.Copyright = "Copyright 2024 Acme Inc."
+	if got := generateString(t, apiFunctions, structs, projectInfo, Options{}); !strings.HasSuffix(got, "\n---\n\nCopyright 2024 Acme Inc.\n") {
+		t.Errorf("Expected the copyright notice as written, got:\n%s", got)
+	}
+
+	// Empty fields get no label and no footer
+	empty := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, Options{})
+	for _, label := range []string{"**Author:**", "**Contact:**", "**License:**", "**Terms of Service:**", "**Repository:**", "**Tags:**", "©"} {
+		if strings.Contains(empty, label) {
+			t.Errorf("Expected no %q without a value, got:\n%s", label, empty)
+		}
+	}
+	if strings.HasSuffix(empty, "---\n") {
+		t.Errorf("Expected no footer without a copyright notice, got:\n%s", empty)
+	}
+}
+
+func TestNoHeader(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	projectInfo.Repository = "https://github.com/acme/api"
+	apiFunctions[1].SourcePath = "stats.go"
+	apiFunctions[1].SourceLine = 12
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{NoHeader: true, NoTOC: true, IncludeRFC: true})
+
+	body := strings.TrimPrefix(got, GeneratedMarker+"\n\n")
+	if !strings.HasPrefix(body, "## stats.GetAllMetrics\n\n") {
+		t.Errorf("Expected the output to start at the first command, got:\n%s", got)
+	}
+	for _, header := range []string{"# " + projectInfo.Title, "Version:", "JSON-RPC 2.0 Specification"} {
+		if strings.Contains(got, header) {
+			t.Errorf("Expected no %q without a header, got:\n%s", header, got)
+		}
+	}
+	// The repository of the project still links the commands to their source
+	if want := "Defined in [stats.go#L12](https://github.com/acme/api/blob/HEAD/stats.go#L12)"; !strings.Contains(got, want) {
+		t.Errorf("Expected output to contain %q, got:\n%s", want, got)
+	}
+}
@@ -0,0 +1,232 @@
+// generator/health.go
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// HealthSchemaVersion is the version of the Health JSON schema. Like DocumentSchemaVersion,
+// it is increased when fields are renamed, removed or change meaning.
+const HealthSchemaVersion = 1
+
+// DefaultBadgeFormula is the formula of the badge percentage when none is configured.
+const DefaultBadgeFormula = "(examples + errors) / 2"
+
+// Health summarizes how complete the documentation of a project is, for dashboards.
+type Health struct {
+	SchemaVersion int `json:"schemaVersion"`
+	Commands      int `json:"commands"`
+	// WithExamples is the percentage of commands rendered with at least one example: an example
+	// request, a code sample or the examples of a subscription.
+	WithExamples float64 `json:"withExamplesPercent"`
+	// WithErrors is the percentage of commands with at least one @Error.
+	WithErrors float64 `json:"withErrorsPercent"`
+	// Deprecated counts the commands marked @Deprecated.
+	Deprecated int `json:"deprecated"`
+	// Warnings counts the warnings reported for the project.
+	Warnings int `json:"warnings"`
+}
+
+// NewHealth returns the health of a document rendered with opts, given the number of warnings
+// reported while parsing and linting it. Percentages are 100 when there are no commands.
+func NewHealth(doc Document, warnings int, opts Options) Health {
+	health := Health{
+		SchemaVersion: HealthSchemaVersion,
+		Commands:      len(doc.Commands),
+		Warnings:      warnings,
+	}
+	// Every command renders an example request, or the examples of its subscription, except
+	// the stubs of incomplete commands
+	examples, errors := len(doc.Commands), 0
+	for _, apiFunc := range doc.Commands {
+		if apiFunc.Incomplete != "" {
+			examples--
+		}
+		if len(apiFunc.Errors) > 0 {
+			errors++
+		}
+		if apiFunc.Deprecated {
+			health.Deprecated++
+		}
+	}
+	health.WithExamples = percentOf(examples, health.Commands)
+	health.WithErrors = percentOf(errors, health.Commands)
+	return health
+}
+
+// percentOf returns n as a percentage of total rounded to one decimal, 100 when total is 0.
+func percentOf(n int, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(n*1000/total) / 10
+}
+
+// BadgePercent evaluates a badge formula over the health metrics and clamps the result to
+// 0-100. The formula is an arithmetic expression with + - * / and parentheses over numbers
+// and the variables commands, examples, errors, deprecated and warnings, where examples and
+// errors are percentages. An empty formula uses DefaultBadgeFormula.
+func (h Health) BadgePercent(formula string) (float64, error) {
+	if strings.TrimSpace(formula) == "" {
+		formula = DefaultBadgeFormula
+	}
+	e := &formulaParser{
+		input: formula,
+		variables: map[string]float64{
+			"commands":   float64(h.Commands),
+			"examples":   h.WithExamples,
+			"errors":     h.WithErrors,
+			"deprecated": float64(h.Deprecated),
+			"warnings":   float64(h.Warnings),
+		},
+	}
+	value, err := e.parse()
+	if err != nil {
+		return 0, fmt.Errorf("invalid badge formula %q: %v", formula, err)
+	}
+	return min(max(value, 0), 100), nil
+}
+
+// ValidateBadgeFormula checks the syntax and variables of a badge formula.
+func ValidateBadgeFormula(formula string) error {
+	_, err := Health{}.BadgePercent(formula)
+	return err
+}
+
+// formulaParser evaluates a badge formula by recursive descent.
+type formulaParser struct {
+	input     string
+	pos       int
+	variables map[string]float64
+}
+
+func (p *formulaParser) parse() (float64, error) {
+	value, err := p.expression()
+	if err != nil {
+		return 0, err
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos], p.pos)
+	}
+	return value, nil
+}
+
+// expression parses terms separated by + and -.
+func (p *formulaParser) expression() (float64, error) {
+	value, err := p.term()
+	for err == nil {
+		p.skipSpace()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			break
+		}
+		op := p.input[p.pos]
+		p.pos++
+		var right float64
+		if right, err = p.term(); op == '+' {
+			value += right
+		} else {
+			value -= right
+		}
+	}
+	return value, err
+}
+
+// term parses factors separated by * and /. Dividing by zero gives 0.
+func (p *formulaParser) term() (float64, error) {
+	value, err := p.factor()
+	for err == nil {
+		p.skipSpace()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '*' && p.input[p.pos] != '/') {
+			break
+		}
+		op := p.input[p.pos]
+		p.pos++
+		var right float64
+		right, err = p.factor()
+		switch {
+		case op == '*':
+			value *= right
+		case right == 0:
+			value = 0
+		default:
+			value /= right
+		}
+	}
+	return value, err
+}
+
+// factor parses a number, a variable, a negated factor or a parenthesized expression.
+func (p *formulaParser) factor() (float64, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0, fmt.Errorf("unexpected end of formula")
+	}
+	start := p.pos
+	switch c := p.input[p.pos]; {
+	case c == '-':
+		p.pos++
+		value, err := p.factor()
+		return -value, err
+	case c == '(':
+		p.pos++
+		value, err := p.expression()
+		if err != nil {
+			return 0, err
+		}
+		if p.skipSpace(); p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return 0, fmt.Errorf("missing ')' at offset %d", p.pos)
+		}
+		p.pos++
+		return value, nil
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		return strconv.ParseFloat(p.input[start:p.pos], 64)
+	case unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && unicode.IsLetter(rune(p.input[p.pos])) {
+			p.pos++
+		}
+		name := p.input[start:p.pos]
+		value, known := p.variables[name]
+		if !known {
+			return 0, fmt.Errorf("unknown variable %q, expected commands, examples, errors, deprecated or warnings", name)
+		}
+		return value, nil
+	default:
+		return 0, fmt.Errorf("unexpected %q at offset %d", c, p.pos)
+	}
+}
+
+func (p *formulaParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// WriteHealth writes the health summary as JSON to path.
+func WriteHealth(path string, health Health, opts Options) error {
+	content, err := json.MarshalIndent(health, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %v", err)
+	}
+	output := &stagedFiles{noClobber: opts.NoClobber}
+	defer output.discard()
+	err = output.stage(path, func(writer *bufio.Writer) error {
+		_, err := writer.Write(append(content, '\n'))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
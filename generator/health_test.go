@@ -0,0 +1,93 @@
+// generator/health_test.go
+package generator
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestNewHealth(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions = append(apiFunctions, models.APIFunction{
+		Command:      "events.Subscribe",
+		Description:  "Subscribes to events.",
+		Results:      []models.APIReturn{{Name: "result", Type: "string"}},
+		Subscription: &models.Subscription{Method: "subscription", Payload: models.NotificationPayload{Type: "string"}},
+		PackageName:  "rpc",
+	})
+	doc := NewDocument(apiFunctions, structs, projectInfo)
+
+	health := NewHealth(doc, 2, Options{})
+	want := Health{SchemaVersion: HealthSchemaVersion, Commands: 3, WithExamples: 100, WithErrors: 33.3, Warnings: 2}
+	if health != want {
+		t.Errorf("Expected %+v, got %+v", want, health)
+	}
+
+	if health := NewHealth(Document{}, 0, Options{}); health.WithExamples != 100 || health.WithErrors != 100 {
+		t.Errorf("Expected 100%% without commands, got %+v", health)
+	}
+}
+
+func TestBadgePercent(t *testing.T) {
+	health := Health{Commands: 40, WithExamples: 100, WithErrors: 90, Warnings: 3}
+	tests := []struct {
+		formula string
+		want    float64
+	}{
+		{"", 95},
+		{"errors", 90},
+		{"examples - warnings * 2", 94},
+		{"(examples + 2 * errors) / 3", 280.0 / 3},
+		{"-errors", 0},
+		{"examples * 2", 100},
+		{"errors / deprecated", 0},
+		{"\terrors - 0.5", 89.5},
+	}
+	for _, tt := range tests {
+		got, err := health.BadgePercent(tt.formula)
+		if err != nil {
+			t.Errorf("BadgePercent(%q) returned error: %v", tt.formula, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("BadgePercent(%q) = %v, want %v", tt.formula, got, tt.want)
+		}
+	}
+
+	for _, formula := range []string{"examples +", "(errors", "errors)", "coverage", "1.2.3", "errors % 2"} {
+		if err := ValidateBadgeFormula(formula); err == nil {
+			t.Errorf("Expected formula %q to be rejected", formula)
+		}
+	}
+}
+
+func TestWriteBadge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "badge.svg")
+	health := Health{Commands: 10, WithExamples: 100, WithErrors: 94}
+	if err := WriteBadge(path, health, "", Options{}); err != nil {
+		t.Fatalf("WriteBadge returned error: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read badge: %v", err)
+	}
+
+	var svg struct {
+		XMLName xml.Name
+		Title   string `xml:"title"`
+	}
+	if err := xml.Unmarshal(content, &svg); err != nil {
+		t.Fatalf("Badge is not valid XML: %v\n%s", err, content)
+	}
+	if svg.XMLName.Local != "svg" || svg.Title != "API docs: 97%" {
+		t.Errorf("Expected an svg titled \"API docs: 97%%\", got %s titled %q", svg.XMLName.Local, svg.Title)
+	}
+	if !strings.Contains(string(content), `fill="#4c1"`) {
+		t.Errorf("Expected a green badge, got:\n%s", content)
+	}
+}
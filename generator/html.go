@@ -0,0 +1,286 @@
+// generator/html.go
+package generator
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// DefaultHTMLTemplate is the built-in html/template source of -format html.
+//
+//go:embed templates/page.html.tmpl
+var DefaultHTMLTemplate string
+
+// HTMLData is the data passed to the HTML template.
+type HTMLData struct {
+	Project  models.ProjectInfo
+	Commands []HTMLCommand
+	// NoParameters is the sentence shown for commands without parameters, empty with
+	// -omit-empty-sections.
+	NoParameters string
+	// StandardErrors is the sentence shown for commands without @Error annotations, empty
+	// with -omit-empty-sections.
+	StandardErrors string
+	// CommonErrors are the @globalerror errors, listed once in the section of anchor
+	// CommonErrorsAnchor, which the Errors of every command link to. Both are empty without
+	// global errors and with -merge-global-errors, which lists them under every command.
+	CommonErrors       []models.APIError
+	CommonErrorsAnchor string
+	// CommonErrorsIntro is the sentence opening the Common Errors section.
+	CommonErrorsIntro string
+	// Appendix holds the complete definitions of the structs truncated by Options.MaxFields,
+	// sorted by package and name, like the types appendix of the Markdown documentation, in
+	// the section of anchor AppendixAnchor.
+	Appendix       []HTMLStruct
+	AppendixAnchor string
+}
+
+// HTMLCommand is a command of the HTML page. Anchor is the id of its section, the same as
+// the anchor of its heading in Markdown, so deep links work in both.
+type HTMLCommand struct {
+	Anchor            string
+	Command           string
+	Description       string
+	Deprecated        bool
+	DeprecationReason string
+	// Incomplete is the reason a stub documented with -keep-going is incomplete.
+	Incomplete string
+	// FormerNames are the names of @FormerName annotations, with anchors so links made
+	// before a rename still land on the command.
+	FormerNames []HTMLFormerName
+	// Parameters are flattened like in the Markdown documentation.
+	Parameters []HTMLParameter
+	Results    []HTMLResult
+	Errors     []models.APIError
+	// Structs are the structs of the results and @Additional annotations and the structs they
+	// use, breadth-first.
+	Structs []HTMLStruct
+}
+
+// HTMLFormerName is a former name of a command and its anchor.
+type HTMLFormerName struct {
+	Anchor string
+	Name   string
+}
+
+// HTMLParameter is a parameter of a command. TypeLabel and DescriptionCell are the Type and
+// Description cells of the Markdown parameters table, and Required is "Yes" or "No".
+type HTMLParameter struct {
+	models.APIParameter
+	TypeLabel       string
+	DescriptionCell string
+	Required        string
+}
+
+// HTMLResult is a result of a command. TypeLabel and DescriptionCell are the Type and
+// Description cells of the Markdown results table.
+type HTMLResult struct {
+	models.APIReturn
+	TypeLabel       string
+	DescriptionCell string
+}
+
+// HTMLStruct is a struct definition inlined in the section of a command, or a complete
+// definition of the Appendix.
+type HTMLStruct struct {
+	Anchor      string
+	Title       string
+	Description string
+	Fields      []HTMLField
+	// Omitted is the number of fields left out by Options.MaxFields, whose complete
+	// definition is in the Appendix at AppendixAnchor.
+	Omitted        int
+	AppendixAnchor string
+}
+
+// HTMLField is a field of a struct. TypeLabel and DescriptionCell are the Type and
+// Description cells of the Markdown fields table, and Required is "Yes", "No" or "Embedded",
+// like its Required column.
+type HTMLField struct {
+	models.StructField
+	TypeLabel       string
+	DescriptionCell string
+	Required        string
+}
+
+// GenerateHTML writes a standalone HTML page documenting a project to outFile, rendered by
+// the html/template in opts.HTMLTemplate or DefaultHTMLTemplate. Descriptions are escaped
+// by html/template.
+func GenerateHTML(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outFile string, opts Options) error {
+	projectInfo, err := prepare(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
+	}
+	text := opts.HTMLTemplate
+	if text == "" {
+		text = DefaultHTMLTemplate
+	}
+	tmpl, err := template.New("html").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML template: %v", err)
+	}
+
+	data := newHTMLData(apiFunctions, structDefinitions, projectInfo, opts)
+	output := documentOutput(opts)
+	defer output.discard()
+	err = output.stage(outFile, func(writer *bufio.Writer) error {
+		fmt.Fprintf(writer, "%s\n", GeneratedMarker)
+		if err := tmpl.Execute(writer, data); err != nil {
+			return fmt.Errorf("failed to render HTML template: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// newHTMLData builds the template data of the HTML page, with commands sorted like in the
+// Markdown documentation.
+func newHTMLData(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) HTMLData {
+	data := HTMLData{Project: projectInfo}
+	data.Project.Copyright = copyrightNotice(projectInfo)
+	if !opts.OmitEmptySections {
+		data.NoParameters = noParametersText
+		data.StandardErrors = opts.StandardErrorsText
+		if data.StandardErrors == "" {
+			data.StandardErrors = defaultStandardErrorsText
+		}
+	}
+
+	anchors := newAnchorRegistry()
+	appendix := newTypeAppendix(opts.MaxFields, "")
+	if hasCommonErrors(projectInfo, opts) {
+		// Registered first, so the section gets the anchor it has in Markdown
+		data.CommonErrors = projectInfo.GlobalErrors
+		data.CommonErrorsAnchor = anchors.register(globalErrorsHeading)
+		data.CommonErrorsIntro = globalErrorsIntro
+		if !opts.OmitEmptySections && opts.StandardErrorsText == "" {
+			data.StandardErrors = noMethodErrorsText
+		}
+	}
+	apiFunctions = sortCommands(apiFunctions)
+	for _, apiFunc := range apiFunctions {
+		command := HTMLCommand{
+			Anchor:            anchors.register(apiFunc.Command),
+			Command:           apiFunc.Command,
+			Description:       apiFunc.Description,
+			Deprecated:        apiFunc.Deprecated,
+			DeprecationReason: apiFunc.DeprecationReason,
+			Incomplete:        apiFunc.Incomplete,
+			Errors:            commandErrors(apiFunc, projectInfo, opts),
+		}
+		for _, former := range apiFunc.FormerNames {
+			command.FormerNames = append(command.FormerNames, HTMLFormerName{Anchor: slugify(former), Name: former})
+		}
+		parameters := apiFunc.Parameters
+		if opts.FlattenParams || apiFunc.FlattenParams {
+			parameters = flattenParameters(apiFunc, structDefinitions, opts)
+		}
+		for _, param := range parameters {
+			command.Parameters = append(command.Parameters, HTMLParameter{
+				APIParameter:    param,
+				TypeLabel:       underlyingLabel(param.Type, param.TypeRef),
+				DescriptionCell: htmlParamDescription(param, opts),
+				Required:        paramRequirement(param),
+			})
+		}
+		for _, result := range apiFunc.Results {
+			command.Results = append(command.Results, HTMLResult{
+				APIReturn:       result,
+				TypeLabel:       resultType(apiFunc, result, structDefinitions, opts),
+				DescriptionCell: htmlDescription(result.Description, opts),
+			})
+		}
+
+		var roots []models.StructKey
+		for _, result := range apiFunc.Results {
+			if key, found := findResultStruct(apiFunc, result, structDefinitions); found {
+				roots = append(roots, key)
+			}
+		}
+		for _, additional := range apiFunc.AdditionalStructs {
+			if ref := utils.ResolveTypeRef(utils.ParseType(additional), apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions).Held(); ref.Kind == models.TypeStruct {
+				roots = append(roots, ref.Struct)
+			}
+		}
+		for _, key := range collectStructGraph(roots, structDefinitions, opts).order {
+			structDef := structDefinitions[key]
+			title := structHeading(key, structDef)
+			table := tableStruct(key, structDef, structDefinitions, opts)
+			fields := appendix.visibleFields(key, table)
+			htmlStruct := newHTMLStruct(anchors.register(title), title, structDef.Description, fields, opts)
+			if htmlStruct.Omitted = len(table.Fields) - len(fields); htmlStruct.Omitted > 0 {
+				htmlStruct.AppendixAnchor = slugify(appendixEntry(key, structDef))
+			}
+			command.Structs = append(command.Structs, htmlStruct)
+		}
+		data.Commands = append(data.Commands, command)
+	}
+
+	// The appendix is always complete
+	keys := appendix.fileKeys()[""]
+	if len(keys) > 0 {
+		data.AppendixAnchor = anchors.register(appendixHeading)
+	}
+	for _, key := range keys {
+		structDef := structDefinitions[key]
+		fields := documentedFields(key, structDef, structDefinitions, opts)
+		data.Appendix = append(data.Appendix, newHTMLStruct(anchors.register(appendixEntry(key, structDef)), structHeading(key, structDef), structDef.Description, fields, opts))
+	}
+	return data
+}
+
+// newHTMLStruct returns the HTMLStruct of a struct table with the given fields, their cells
+// rendered like in the Markdown fields table.
+func newHTMLStruct(anchor, title, description string, fields []models.StructField, opts Options) HTMLStruct {
+	htmlStruct := HTMLStruct{Anchor: anchor, Title: title, Description: description}
+	for _, field := range fields {
+		htmlStruct.Fields = append(htmlStruct.Fields, HTMLField{
+			StructField:     field,
+			TypeLabel:       fieldTypeCell(field, "", opts),
+			DescriptionCell: htmlDescription(htmlNote(field.Description, enumNote(field.Enum)), opts),
+			Required:        fieldRequirement(field),
+		})
+	}
+	return htmlStruct
+}
+
+// htmlDescription returns a description for a cell of the HTML page, with the empty
+// description placeholder in place of an empty description. html/template escapes it.
+func htmlDescription(description string, opts Options) string {
+	if strings.TrimSpace(description) == "" {
+		return emptyDescription(opts)
+	}
+	return description
+}
+
+// htmlParamDescription returns the Description cell of a parameter, see paramDescription.
+// The template follows it with the default, so a parameter with only a default gets no
+// placeholder.
+func htmlParamDescription(param models.APIParameter, opts Options) string {
+	description := htmlNote(param.Description, enumNote(param.Enum))
+	if description == "" && defaultNote(param) != "" {
+		return ""
+	}
+	return htmlDescription(description, opts)
+}
+
+// htmlNote is the appendNote of the HTML page, which leaves the note without emphasis.
+func htmlNote(description, note string) string {
+	if note == "" || description == "" {
+		return description + note
+	}
+	return description + " " + note
+}
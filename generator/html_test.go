@@ -0,0 +1,120 @@
+// generator/html_test.go
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestHTMLGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := openRPCModel()
+	apiFunctions[1].FormerNames = []string{"user.Get"}
+	outFile := filepath.Join(t.TempDir(), "api.html")
+	if err := GenerateHTML(apiFunctions, structs, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	assertGolden(t, "page.html", string(content))
+
+	for _, want := range []string{
+		GeneratedMarker,
+		`<li><a href="#usersget" class="deprecated">users.Get</a></li>`,
+		`<section id="usersget">`,
+		`<a id="userget"></a>`,
+		`<h4 id="rpcpaginationuser"><code>rpc.Pagination[User]</code></h4>`,
+		`<h4 id="rpcuser"><code>rpc.User</code></h4>`,
+		`Documentation incomplete:</strong> its file failed to parse`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Output does not contain %q", want)
+		}
+	}
+}
+
+func TestHTMLEscapesDescriptions(t *testing.T) {
+	apiFunctions := []models.APIFunction{{
+		Command:     "users.Get",
+		Description: `Get a user. <script>alert("x")</script>`,
+		Parameters:  []models.APIParameter{{Name: "id", Type: "int", Description: "Id <b>of</b> the user."}},
+	}}
+	projectInfo := models.ProjectInfo{Title: "Users <API>", Version: "1.0.0"}
+	outFile := filepath.Join(t.TempDir(), "api.html")
+	if err := GenerateHTML(apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	for _, unwanted := range []string{"<script>", "<b>", "Users <API>"} {
+		if strings.Contains(string(content), unwanted) {
+			t.Errorf("Output contains unescaped %q", unwanted)
+		}
+	}
+	if !strings.Contains(string(content), "Get a user. &lt;script&gt;") {
+		t.Errorf("Description not escaped in output:\n%s", content)
+	}
+}
+
+func TestHTMLTemplateOverride(t *testing.T) {
+	apiFunctions, structs, projectInfo := openRPCModel()
+	outFile := filepath.Join(t.TempDir(), "api.html")
+	opts := Options{HTMLTemplate: `{{range .Commands}}<a href="#{{.Anchor}}">{{.Command}}</a>{{end}}`}
+	if err := GenerateHTML(apiFunctions, structs, projectInfo, outFile, opts); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	want := GeneratedMarker + "\n" + `<a href="#brokenget">broken.Get</a><a href="#legacyping">legacy.Ping</a><a href="#ping">ping</a><a href="#usersexport">users.Export</a><a href="#usersget">users.Get</a><a href="#userslist">users.List</a><a href="#userswatch">users.Watch</a>`
+	if string(content) != want {
+		t.Errorf("Unexpected output:\n%s", content)
+	}
+
+	opts.HTMLTemplate = "{{range .Commands}"
+	if err := GenerateHTML(apiFunctions, structs, projectInfo, outFile, opts); err == nil || !strings.Contains(err.Error(), "failed to parse HTML template") {
+		t.Errorf("Expected a template parse error, got %v", err)
+	}
+}
+
+func TestHTMLTablesMatchMarkdown(t *testing.T) {
+	apiFunctions, structs, projectInfo := openRPCModel()
+	outFile := filepath.Join(t.TempDir(), "api.html")
+	opts := Options{MaxFields: 2, EmptyDescription: "(undocumented)"}
+	if err := GenerateHTML(apiFunctions, structs, projectInfo, outFile, opts); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	page := string(content)
+
+	// Truncated tables link to the complete definition, listed once in the appendix
+	_, appendix, found := strings.Cut(page, `<section id="types-appendix">`)
+	if !found {
+		t.Fatalf("Expected a types appendix, got:\n%s", page)
+	}
+	truncated := "<tr><td><code>email</code></td><td><code>string (email)</code></td><td>(undocumented)</td><td>Yes</td></tr>\n" +
+		"<tr><td colspan=\"4\">… and 6 more fields, see <a href=\"#rpcuser-complete\">appendix</a></td></tr>\n</table>"
+	if !strings.Contains(strings.TrimSuffix(page, appendix), truncated) {
+		t.Errorf("Expected the truncated table of rpc.User, got:\n%s", page)
+	}
+	for _, row := range []string{
+		`<h3 id="rpcuser-complete"><code>rpc.User</code> (complete)</h3>`,
+		"<tr><td><code>created</code></td><td><code>string (RFC 3339 timestamp)</code></td><td>(undocumented)</td><td>Yes</td></tr>",
+		"<tr><td><code>quota</code></td><td><code>*int64 (bytes)</code></td><td>Storage quota, null when unlimited.</td><td>No</td></tr>",
+	} {
+		if count := strings.Count(appendix, row); count != 1 {
+			t.Errorf("Expected the appendix to contain %q once, got %d times:\n%s", row, count, appendix)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// generator/jsonc.go
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Supported values for Options.ExampleStyle.
+const (
+	ExampleStyleJSON  = "json"
+	ExampleStyleJSONC = "jsonc"
+)
+
+// defaultCommentLength is the longest field comment in JSONC examples when Options.ExampleCommentLength is not set.
+const defaultCommentLength = 80
+
+// validateExampleStyle checks that the example style is supported.
+func validateExampleStyle(style string) error {
+	if style != "" && style != ExampleStyleJSON && style != ExampleStyleJSONC {
+		return fmt.Errorf("invalid example style %q: expected %q or %q", style, ExampleStyleJSON, ExampleStyleJSONC)
+	}
+	return nil
+}
+
+// fieldComment returns the comment attached to a field in JSONC examples:
+// "description (type, required)", at most maxLength characters. The description is
+// shortened first so the type and requirement stay visible.
+func fieldComment(description string, typ string, required bool, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = defaultCommentLength
+	}
+	requirement := "optional"
+	if required {
+		requirement = "required"
+	}
+	details := fmt.Sprintf("(%s, %s)", typ, requirement)
+
+	comment := singleLine(description)
+	room := maxLength - len([]rune(details)) - 1
+	if comment == "" || room < 2 {
+		return truncateComment(details, maxLength)
+	}
+	return truncateComment(comment, room) + " " + details
+}
+
+// singleLine collapses every run of whitespace and control characters, including line and
+// paragraph separators, into a single space so the text fits in a line comment.
+func singleLine(text string) string {
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}), " ")
+}
+
+// truncateComment cuts text to maxLength characters, ending with "…" when cut.
+func truncateComment(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return strings.TrimRightFunc(string(runes[:maxLength-1]), unicode.IsSpace) + "…"
+}
+
+// marshalJSONC renders value as indented JSON, writing the comment of each object field
+// as a trailing line comment. Comments are single-line, so the result is valid JSONC.
+func marshalJSONC(value interface{}) (string, error) {
+	var b strings.Builder
+	if err := writeJSONC(&b, value, ""); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func writeJSONC(b *strings.Builder, value interface{}, indent string) error {
+	switch v := value.(type) {
+	case jsonObject:
+		if len(v) == 0 {
+			b.WriteString("{}")
+			return nil
+		}
+		b.WriteString("{")
+		for i, field := range v {
+			key, err := json.Marshal(field.Key)
+			if err != nil {
+				return err
+			}
+			b.WriteString("\n" + indent + "  ")
+			b.Write(key)
+			b.WriteString(": ")
+
+			// The comment of a non-empty object or array follows its opening bracket
+			if nested, ok := field.Value.(jsonObject); ok && len(nested) > 0 {
+				if err := writeJSONCNested(b, nested, indent, field.Comment); err != nil {
+					return err
+				}
+			} else if nested, ok := field.Value.([]interface{}); ok && len(nested) > 0 {
+				if err := writeJSONCNested(b, nested, indent, field.Comment); err != nil {
+					return err
+				}
+			} else if err := writeJSONC(b, field.Value, indent+"  "); err != nil {
+				return err
+			}
+
+			if i < len(v)-1 {
+				b.WriteString(",")
+			}
+			if field.Comment != "" && !isNested(field.Value) {
+				b.WriteString(" // " + singleLine(field.Comment))
+			}
+		}
+		b.WriteString("\n" + indent + "}")
+	case []interface{}:
+		if len(v) == 0 {
+			b.WriteString("[]")
+			return nil
+		}
+		b.WriteString("[")
+		for i, item := range v {
+			b.WriteString("\n" + indent + "  ")
+			if err := writeJSONC(b, item, indent+"  "); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				b.WriteString(",")
+			}
+		}
+		b.WriteString("\n" + indent + "]")
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(encoded)
+	}
+	return nil
+}
+
+// writeJSONCNested writes a non-empty object or array with comment placed after its opening bracket.
+func writeJSONCNested(b *strings.Builder, value interface{}, indent string, comment string) error {
+	var body strings.Builder
+	if err := writeJSONC(&body, value, indent+"  "); err != nil {
+		return err
+	}
+	opening, rest, _ := strings.Cut(body.String(), "\n")
+	b.WriteString(opening)
+	if comment != "" {
+		b.WriteString(" // " + singleLine(comment))
+	}
+	b.WriteString("\n" + rest)
+	return nil
+}
+
+// isNested reports whether value is rendered over several lines.
+func isNested(value interface{}) bool {
+	switch v := value.(type) {
+	case jsonObject:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// generator/jsonc_test.go
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// stripLineComments removes // comments outside JSON strings.
+func stripLineComments(text string) string {
+	var b strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString:
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(text) && text[i+1] == '/':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			if i < len(text) {
+				b.WriteByte('\n')
+			}
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func TestMarshalJSONC(t *testing.T) {
+	value := jsonObject{
+		{Key: "name", Value: "a // not a comment", Comment: "Line one.\nLine two. Line three."},
+		{Key: "filter", Value: jsonObject{
+			{Key: "ids", Value: []interface{}{0, 1}, Comment: "Ids."},
+			{Key: "empty", Value: jsonObject{}, Comment: "Empty */ object."},
+		}, Comment: "Filter."},
+		{Key: "tags", Value: []interface{}{}, Comment: "Tags."},
+		{Key: "last", Value: false},
+	}
+
+	got, err := marshalJSONC(value)
+	if err != nil {
+		t.Fatalf("marshalJSONC returned error: %v", err)
+	}
+	want := `{
+  "name": "a // not a comment", // Line one. Line two. Line three.
+  "filter": { // Filter.
+    "ids": [ // Ids.
+      0,
+      1
+    ],
+    "empty": {} // Empty */ object.
+  },
+  "tags": [], // Tags.
+  "last": false
+}`
+	if got != want {
+		t.Errorf("marshalJSONC returned:\n%s\nwant:\n%s", got, want)
+	}
+
+	stripped := stripLineComments(got)
+	if !json.Valid([]byte(stripped)) {
+		t.Errorf("Output is not valid JSON once comments are stripped:\n%s", stripped)
+	}
+	if strings.Count(got, "\n") != strings.Count(want, "\n") {
+		t.Errorf("Comments must not add lines")
+	}
+}
+
+func TestFieldComment(t *testing.T) {
+	tests := []struct {
+		description string
+		required    bool
+		maxLength   int
+		want        string
+	}{
+		{"User id.", true, 0, "User id. (int, required)"},
+		{"", false, 0, "(int, optional)"},
+		{"Spans\n\tseveral   lines.", true, 0, "Spans several lines. (int, required)"},
+		{"A rather long description that goes on.", true, 30, "A rather long… (int, required)"},
+		{"A rather long description that goes on.", true, 10, "(int, req…"},
+		{strings.Repeat("x", 100), true, 0, strings.Repeat("x", 63) + "… (int, required)"},
+	}
+	for _, tt := range tests {
+		if got := fieldComment(tt.description, "int", tt.required, tt.maxLength); got != tt.want {
+			t.Errorf("fieldComment(%q, %v, %d) = %q, want %q", tt.description, tt.required, tt.maxLength, got, tt.want)
+		}
+	}
+}
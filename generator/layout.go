@@ -0,0 +1,297 @@
+// generator/layout.go
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// listItemPattern matches the marker of a list item, such as "- " or "  12. ".
+var listItemPattern = regexp.MustCompile(`^ *([-*+]|\d+\.) +`)
+
+// blockStartPattern matches the words that would start a block, such as a list item, a
+// heading or a thematic break, when wrapped to the start of a line.
+var blockStartPattern = regexp.MustCompile(`^([-*+]|\d+\.|#+|>.*|\|.*|<.*|` + "```.*|~~~.*" + `|[-=_*]{3,})$`)
+
+// tableSeparatorPattern matches a cell of the separator row of a table, such as "---" or ":--:".
+var tableSeparatorPattern = regexp.MustCompile(`^:?-+:?$`)
+
+// layoutMarkdown wraps the paragraphs, list items and block quotes of Markdown content at
+// wrap columns when wrap is positive, and pads the cells of its tables so their pipes line up
+// when alignTables is set. Fenced code blocks, headings, HTML lines and indented code are
+// left as they are. Lines of a paragraph are joined before wrapping, so laying out the output
+// again returns it unchanged.
+func layoutMarkdown(content string, wrap int, alignTables bool) string {
+	if wrap <= 0 && !alignTables {
+		return content
+	}
+
+	var out []string
+	var paragraph []string
+	var first, rest string // prefixes of the first and following lines of the paragraph
+	var table []string
+	fence := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			text := strings.Join(paragraph, " ")
+			if wrap > 0 {
+				out = append(out, wrapText(text, first, rest, wrap)...)
+			} else {
+				out = append(out, first+text)
+			}
+		}
+		paragraph = nil
+	}
+	flushTable := func() {
+		if alignTables {
+			table = alignTable(table)
+		}
+		out = append(out, table...)
+		table = nil
+	}
+	startParagraph := func(text, firstPrefix, restPrefix string) {
+		flushParagraph()
+		paragraph, first, rest = []string{text}, firstPrefix, restPrefix
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(table) > 0 && !strings.HasPrefix(trimmed, "|") {
+			flushTable()
+		}
+
+		switch {
+		case fence != "":
+			out = append(out, line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flushParagraph()
+			fence = trimmed[:3]
+			out = append(out, line)
+			continue
+		case strings.HasPrefix(trimmed, "|"):
+			flushParagraph()
+			table = append(table, line)
+			continue
+		}
+
+		// A line ending with a hard break ends its paragraph
+		hardBreak := strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\")
+		marker := listItemPattern.FindString(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "<") || trimmed == "---" || hardBreak:
+			flushParagraph()
+			out = append(out, line)
+		case marker != "":
+			startParagraph(strings.TrimSpace(line[len(marker):]), marker, strings.Repeat(" ", len(marker)))
+		case strings.HasPrefix(line, ">"):
+			text := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			if len(paragraph) > 0 && first == "> " {
+				paragraph = append(paragraph, text)
+			} else {
+				startParagraph(text, "> ", "> ")
+			}
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			// Continuation of a list item, or indented code left as it is
+			if len(paragraph) > 0 && rest != "" && strings.TrimSpace(rest) == "" {
+				paragraph = append(paragraph, trimmed)
+			} else {
+				flushParagraph()
+				out = append(out, line)
+			}
+		case len(paragraph) > 0 && first == "":
+			paragraph = append(paragraph, trimmed)
+		default:
+			startParagraph(trimmed, "", "")
+		}
+	}
+	flushParagraph()
+	if len(table) > 0 {
+		flushTable()
+	}
+	return strings.Join(out, "\n")
+}
+
+// wrapText wraps text at width columns, prefixing the first line with first and the others
+// with rest. Code spans and links are never broken, and words that would start a block at
+// the start of a line, such as "-" or "1.", stay on the previous line, so a few lines may
+// exceed width.
+func wrapText(text, first, rest string, width int) []string {
+	var lines []string
+	line := first
+	empty := true
+	for _, word := range wrapWords(text) {
+		if !empty && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width && !blockStartPattern.MatchString(word) {
+			lines = append(lines, line)
+			line, empty = rest, true
+		}
+		if !empty {
+			line += " "
+		}
+		line += word
+		empty = false
+	}
+	return append(lines, line)
+}
+
+// wrapWords splits text on the spaces outside code spans and links.
+func wrapWords(text string) []string {
+	var words []string
+	var word strings.Builder
+	code := 0     // length of the backtick run opening the current code span
+	brackets := 0 // depth of the brackets of the current link text
+	url := false  // inside the (url) of a link
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '`':
+			n := 1
+			for i+n < len(text) && text[i+n] == '`' {
+				n++
+			}
+			if code == 0 {
+				code = n
+			} else if n == code {
+				code = 0
+			}
+			word.WriteString(text[i : i+n])
+			i += n - 1
+			continue
+		case code > 0:
+		case c == '\\' && i+1 < len(text):
+			word.WriteString(text[i : i+2])
+			i++
+			continue
+		case c == '[':
+			brackets++
+		case c == ']' && brackets > 0:
+			brackets--
+			url = brackets == 0 && i+1 < len(text) && text[i+1] == '('
+		case c == ')' && url:
+			url = false
+		case c == ' ' && brackets == 0 && !url:
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			continue
+		}
+		word.WriteByte(c)
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// alignTable pads the cells of the rows of a table so the pipes of every row line up. The
+// dashes of the separator row fill its columns, keeping their alignment colons.
+func alignTable(rows []string) []string {
+	cells := make([][]string, len(rows))
+	var widths []int
+	for i, row := range rows {
+		cells[i] = tableCells(row)
+		for j, cell := range cells[i] {
+			if j == len(widths) {
+				widths = append(widths, 3)
+			}
+			if !isSeparatorRow(cells[i]) {
+				widths[j] = max(widths[j], utf8.RuneCountInString(cell))
+			}
+		}
+	}
+
+	aligned := make([]string, len(rows))
+	for i, row := range cells {
+		var line strings.Builder
+		line.WriteString("|")
+		for j, cell := range row {
+			if isSeparatorRow(row) {
+				dashes := widths[j] + 2
+				left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+				if left {
+					line.WriteString(":")
+					dashes--
+				}
+				if right {
+					dashes--
+				}
+				line.WriteString(strings.Repeat("-", dashes))
+				if right {
+					line.WriteString(":")
+				}
+			} else {
+				line.WriteString(" " + cell + strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell)) + " ")
+			}
+			line.WriteString("|")
+		}
+		aligned[i] = line.String()
+	}
+	return aligned
+}
+
+// tableCells returns the trimmed cells of a table row, split on the pipes not escaped with a
+// backslash.
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, "\\|") {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	start := 0
+	for i := 0; i < len(row); i++ {
+		switch row[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, strings.TrimSpace(row[start:i]))
+			start = i + 1
+		}
+	}
+	return append(cells, strings.TrimSpace(row[start:]))
+}
+
+// isSeparatorRow reports whether the cells are those of the separator row of a table.
+func isSeparatorRow(cells []string) bool {
+	for _, cell := range cells {
+		if !tableSeparatorPattern.MatchString(cell) {
+			return false
+		}
+	}
+	return len(cells) > 0
+}
+
+// headingPattern matches the marker of an ATX heading, such as "## " or a lone "###".
+var headingPattern = regexp.MustCompile(`^#{1,6}( |$)`)
+
+// shiftHeadings moves the headings of Markdown content down offset levels, stopping at
+// level 6. Lines of fenced code blocks are left as they are.
+func shiftHeadings(content string, offset int) string {
+	if offset <= 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case headingPattern.MatchString(line):
+			level := len(line) - len(strings.TrimLeft(line, "#"))
+			lines[i] = strings.Repeat("#", min(level+offset, 6)) + line[level:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,155 @@
+// generator/layout_test.go
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutMarkdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wrap        int
+		alignTables bool
+		want        string
+	}{
+		{
+			name:    "paragraph",
+			content: "Lists the items of the `items store` in [the item catalog](https://example.com/catalog), newest first.",
+			wrap:    30,
+			want:    "Lists the items of the\n`items store` in\n[the item catalog](https://example.com/catalog),\nnewest first.",
+		},
+		{
+			name:    "joined lines",
+			content: "First line\nof a paragraph.\n\nSecond paragraph.",
+			wrap:    40,
+			want:    "First line of a paragraph.\n\nSecond paragraph.",
+		},
+		{
+			name:    "list items",
+			content: "- A first item that is long enough to wrap.\n- Short.\n  - A nested item that wraps as well.",
+			wrap:    24,
+			want:    "- A first item that is\n  long enough to wrap.\n- Short.\n  - A nested item that\n    wraps as well.",
+		},
+		{
+			name:    "block quote",
+			content: "> **Deprecated:** use users.GetProfileV2 instead of this method.",
+			wrap:    30,
+			want:    "> **Deprecated:** use\n> users.GetProfileV2 instead\n> of this method.",
+		},
+		{
+			name:    "block syntax is not wrapped to the start of a line",
+			content: "Accepted values are one of - or 1. only",
+			wrap:    26,
+			want:    "Accepted values are one of -\nor 1. only",
+		},
+		{
+			name:    "untouched blocks",
+			content: "## A heading that is much longer than the column\n\n```json\n{\"a\": \"a value that is much longer than the column\"}\n```\n\n<a id=\"a-long-anchor-name\"></a>",
+			wrap:    10,
+			want:    "## A heading that is much longer than the column\n\n```json\n{\"a\": \"a value that is much longer than the column\"}\n```\n\n<a id=\"a-long-anchor-name\"></a>",
+		},
+		{
+			name:        "table",
+			content:     "| Name | Type | Description |\n|------|:----:|-------------|\n| id | int | User id. |\n| filter | a \\| b | — |",
+			alignTables: true,
+			want:        "| Name   | Type   | Description |\n|--------|:------:|-------------|\n| id     | int    | User id.    |\n| filter | a \\| b | —           |",
+		},
+		{
+			name:    "tables are not wrapped",
+			content: "| Name | Description |\n|------|-------------|\n| id | A description longer than the column. |",
+			wrap:    10,
+			want:    "| Name | Description |\n|------|-------------|\n| id | A description longer than the column. |",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := layoutMarkdown(tt.content, tt.wrap, tt.alignTables)
+			if got != tt.want {
+				t.Errorf("layoutMarkdown returned:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if again := layoutMarkdown(got, tt.wrap, tt.alignTables); again != got {
+				t.Errorf("layoutMarkdown is not idempotent, the second pass returned:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestLayoutGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	apiFunctions[0].Description = "Get a user by id. Deleted users are returned too, with `deleted_at` set, until they are purged; see [retention policy](https://example.com/retention) for the delays."
+	opts := Options{IncludeRFC: true, Wrap: 60, AlignTables: true, ExampleStyle: ExampleStyleJSONC}
+	got := generateString(t, apiFunctions, structs, projectInfo, opts)
+	assertGolden(t, "layout", got)
+
+	// Laying out the output again, as when it is regenerated, changes nothing
+	body := strings.TrimPrefix(got, GeneratedMarker+"\n\n")
+	if again := layoutMarkdown(strings.TrimSuffix(body, "\n"), opts.Wrap, opts.AlignTables) + "\n"; again != body {
+		t.Errorf("Laying out the output again changed it:\n%s", again)
+	}
+	if again := generateString(t, apiFunctions, structs, projectInfo, opts); again != got {
+		t.Errorf("Generating twice returned different output:\n%s", again)
+	}
+}
+
+func TestShiftHeadings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		offset  int
+		want    string
+	}{
+		{
+			name:    "headings",
+			content: "# Title\n\nText.\n\n## get.user\n\n#### rpc.User",
+			offset:  1,
+			want:    "## Title\n\nText.\n\n### get.user\n\n##### rpc.User",
+		},
+		{
+			name:    "clamped at level 6",
+			content: "# Title\n\n#### rpc.User\n\n######",
+			offset:  3,
+			want:    "#### Title\n\n###### rpc.User\n\n######",
+		},
+		{
+			name:    "code blocks and other lines",
+			content: "```sh\n# comment\n```\n\n#hashtag\n\n ## indented",
+			offset:  2,
+			want:    "```sh\n# comment\n```\n\n#hashtag\n\n ## indented",
+		},
+		{
+			name:    "no offset",
+			content: "# Title",
+			want:    "# Title",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shiftHeadings(tt.content, tt.offset); got != tt.want {
+				t.Errorf("shiftHeadings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadingOffset(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	opts := Options{IncludeRFC: true}
+	plain := generateString(t, apiFunctions, structs, projectInfo, opts)
+	opts.HeadingOffset = 1
+	got := generateString(t, apiFunctions, structs, projectInfo, opts)
+
+	// Only the levels change, so the anchors of the table of contents stay the same
+	if want := shiftHeadings(plain, 1); got != want {
+		t.Errorf("Expected the headings of the documentation moved down one level, got:\n%s", got)
+	}
+	if !strings.Contains(got, "\n## "+projectInfo.Title+"\n") {
+		t.Errorf("Expected the project title as a level 2 heading, got:\n%s", got)
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if strings.HasPrefix(line, "# ") {
+			t.Errorf("Expected no level 1 heading, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// generator/maps_test.go
+package generator
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+func TestMapResultsAndFields(t *testing.T) {
+	series := models.StructKey{Package: "rpc", Name: "MetricSeries"}
+	dashboard := models.StructKey{Package: "rpc", Name: "Dashboard"}
+	structs := map[models.StructKey]models.StructDefinition{
+		series: {Name: "MetricSeries", Description: "MetricSeries is a series of measurements.", Fields: []models.StructField{
+			{Name: "Points", Type: "[]float64", JSONName: "points", Description: "Measurements of the series"},
+		}},
+		dashboard: {Name: "Dashboard", Fields: []models.StructField{
+			{Name: "Series", Type: "map[string]*MetricSeries", JSONName: "series", Description: "Series by name"},
+			{Name: "Groups", Type: "map[string][]MetricSeries", JSONName: "groups", Description: "Series by group"},
+			{Name: "Hours", Type: "map[int]MetricSeries", JSONName: "hours", Description: "Series by hour"},
+			{Name: "Counts", Type: "map[string]int", JSONName: "counts", Description: "Points by series name"},
+		}},
+	}
+	// Field types are resolved by the parser
+	for i, field := range structs[dashboard].Fields {
+		structs[dashboard].Fields[i].TypeRef = utils.ResolveTypeRef(utils.ParseType(field.Type), "rpc", nil, structs)
+	}
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "metrics.Get",
+			Description: "Returns the series by name.",
+			PackageName: "rpc",
+			Results:     []models.APIReturn{{Name: "result", Type: "map[string]MetricSeries", Description: "metrics keyed by name"}},
+		},
+		{
+			Command:     "metrics.Dashboard",
+			Description: "Returns the dashboard.",
+			PackageName: "rpc",
+			Results:     []models.APIReturn{{Name: "result", Type: "Dashboard", Description: "the dashboard"}},
+		},
+	}
+	got := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, Options{})
+
+	// Maps of structs are described and documented under both commands, maps of other types
+	// are left as written
+	assertGolden(t, "maps", got)
+}
@@ -0,0 +1,76 @@
+// generator/model_test.go
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// generators are the entry points validating their model, writing to a file or a directory.
+var generators = map[string]func([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo, string, Options) error{
+	"markdown": GenerateDocumentation,
+	"split":    GenerateSplitDocumentation,
+	"json":     GenerateJSON,
+	"openrpc":  GenerateOpenRPC,
+	"html":     GenerateHTML,
+}
+
+func TestDegenerateInputs(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	unnamed := append([]models.APIFunction{}, apiFunctions...)
+	unnamed[1].Command = " "
+	unnamed[1].SourceFile, unnamed[1].SourceLine = "rpc/stats.go", 12
+
+	tests := []struct {
+		name         string
+		apiFunctions []models.APIFunction
+		structs      map[models.StructKey]models.StructDefinition
+		projectInfo  models.ProjectInfo
+		err          error
+		message      string
+	}{
+		{"nil struct map", apiFunctions, nil, projectInfo, ErrNilInput, "the struct definitions map is nil"},
+		{"zero project info", apiFunctions, structs, models.ProjectInfo{}, ErrNoProjectInfo, "the project has no title and no version"},
+		{"missing version", apiFunctions, structs, models.ProjectInfo{Title: "Test API"}, ErrNoProjectInfo, "the project has no version"},
+		{"unnamed command", unnamed, structs, projectInfo, ErrInvalidCommand, "command 1 (rpc/stats.go:12) has no name"},
+	}
+	for _, tt := range tests {
+		for format, generate := range generators {
+			t.Run(tt.name+"/"+format, func(t *testing.T) {
+				out := filepath.Join(t.TempDir(), "out")
+				err := generate(tt.apiFunctions, tt.structs, tt.projectInfo, out, Options{})
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Expected %v, got %v", tt.err, err)
+				}
+				if !strings.Contains(err.Error(), tt.message) {
+					t.Errorf("Expected the error to say %q, got %q", tt.message, err)
+				}
+				if _, err := os.Stat(out); !os.IsNotExist(err) {
+					t.Errorf("Expected nothing to be written for an invalid model, got %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestEmptyModel(t *testing.T) {
+	projectInfo := models.ProjectInfo{Title: "Empty API", Version: "1.0.0"}
+	for _, apiFunctions := range [][]models.APIFunction{nil, {}} {
+		for format, generate := range generators {
+			out := filepath.Join(t.TempDir(), "out")
+			if err := generate(apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, out, Options{}); err != nil {
+				t.Errorf("%s: expected an empty model to be documented, got %v", format, err)
+			}
+		}
+	}
+
+	got := generateString(t, nil, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{IncludeRFC: true})
+	if !strings.HasPrefix(got, GeneratedMarker+"\n\n# Empty API\n") || strings.Contains(got, "---") || strings.Contains(got, "## Types") {
+		t.Errorf("Expected only the project header, got:\n%s", got)
+	}
+}
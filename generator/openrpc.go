@@ -0,0 +1,430 @@
+// generator/openrpc.go
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// OpenRPCVersion is the version of the OpenRPC specification followed by GenerateOpenRPC.
+const OpenRPCVersion = "1.3.2"
+
+// openRPCGenerator is the value of the x-generator extension of the OpenRPC documents,
+// which tells -no-clobber they were written by jdocgen.
+const openRPCGenerator = "jdocgen"
+
+// schemaRefPrefix prefixes the references to the struct schemas of the components.
+const schemaRefPrefix = "#/components/schemas/"
+
+// nonIdentifier matches the characters of Go type names left out of schema names.
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// GenerateOpenRPC writes an OpenRPC document of a project to outFile. Commands become methods
+// with their parameters sent by name, their first result and their errors, and the structs they
+// use become schemas of the components, referenced with $ref. What OpenRPC has no field for,
+// such as stable ids, subscriptions and size limits, is carried by x- extensions. The stubs of
+// incomplete commands are left out, and so are the commands of other JSON-RPC versions than
+// 2.0, with a warning.
+func GenerateOpenRPC(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outFile string, opts Options) error {
+	projectInfo, err := prepare(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(openRPCDocument(apiFunctions, structDefinitions, projectInfo, opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenRPC document: %v", err)
+	}
+
+	output := documentOutput(opts)
+	defer output.discard()
+	err = output.stage(outFile, func(writer *bufio.Writer) error {
+		_, err := writer.Write(append(content, '\n'))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// openRPCDocument builds the OpenRPC document of a project, with methods sorted like in the
+// Markdown documentation and schemas sorted by name.
+func openRPCDocument(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, opts Options) jsonObject {
+	schemas := newSchemaBuilder(structDefinitions)
+
+	apiFunctions = sortCommands(apiFunctions)
+	methods := []interface{}{}
+	for _, apiFunc := range apiFunctions {
+		if apiFunc.Incomplete != "" {
+			continue
+		}
+		if version := envelopeVersion(apiFunc); version != jsonrpcVersion {
+			opts.warn(Warning{
+				File:    apiFunc.SourceFile,
+				Line:    apiFunc.AnnotationLine("@Envelope"),
+				Class:   envelopeClass,
+				Message: fmt.Sprintf("command '%s' uses JSON-RPC %s and is left out of the OpenRPC document, which describes JSON-RPC %s", apiFunc.Command, version, jsonrpcVersion),
+			})
+			continue
+		}
+		// OpenRPC has no shared errors, so every method lists the global errors
+		apiFunc.Errors = mergeGlobalErrors(apiFunc.Errors, projectInfo.GlobalErrors)
+		apiFunc.Sizes = apiFunc.Sizes.Or(projectInfo.Sizes)
+		methods = append(methods, openRPCMethod(apiFunc, projectInfo.ResultEnvelope, schemas))
+	}
+
+	doc := jsonObject{
+		{Key: "openrpc", Value: OpenRPCVersion},
+		{Key: "info", Value: openRPCInfo(projectInfo)},
+		{Key: "x-generator", Value: openRPCGenerator},
+	}
+	if len(projectInfo.Servers) > 0 {
+		var servers []interface{}
+		for _, server := range projectInfo.Servers {
+			servers = append(servers, jsonObject{{Key: "name", Value: server}, {Key: "url", Value: server}})
+		}
+		doc = append(doc, jsonField{Key: "servers", Value: servers})
+	}
+	doc = append(doc, jsonField{Key: "methods", Value: methods})
+	if len(schemas.schemas) > 0 {
+		doc = append(doc, jsonField{Key: "components", Value: jsonObject{{Key: "schemas", Value: schemas.components()}}})
+	}
+	return doc
+}
+
+// openRPCInfo returns the info object of a project. The contact is an email address, a URL
+// or a name, depending on how it is written.
+func openRPCInfo(projectInfo models.ProjectInfo) jsonObject {
+	info := jsonObject{{Key: "title", Value: projectInfo.Title}}
+	if projectInfo.Description != "" {
+		info = append(info, jsonField{Key: "description", Value: projectInfo.Description})
+	}
+	if projectInfo.Terms != "" {
+		info = append(info, jsonField{Key: "termsOfService", Value: projectInfo.Terms})
+	}
+	if contact := projectInfo.Contact; contact != "" {
+		key := "name"
+		switch {
+		case strings.HasPrefix(contact, "http://") || strings.HasPrefix(contact, "https://"):
+			key = "url"
+		case strings.Contains(contact, "@") && !strings.ContainsAny(contact, " \t"):
+			key = "email"
+		}
+		info = append(info, jsonField{Key: "contact", Value: jsonObject{{Key: key, Value: contact}}})
+	}
+	if projectInfo.License != "" {
+		info = append(info, jsonField{Key: "license", Value: jsonObject{{Key: "name", Value: projectInfo.License}}})
+	}
+	return append(info, jsonField{Key: "version", Value: projectInfo.Version})
+}
+
+// openRPCMethod returns the method object of a command. Commands without @Result return
+// null, and results are wrapped in the result envelope unless the command has @NoEnvelope.
+// The stable id, parameter rules, subscription and sizes of the command, and the content
+// types of its result, are x- extensions, and @Requires is also a JSON Schema
+// dependentRequired in x-params-schema.
+func openRPCMethod(apiFunc models.APIFunction, envelope models.ResultEnvelope, schemas *schemaBuilder) jsonObject {
+	method := jsonObject{{Key: "name", Value: apiFunc.Command}}
+	if apiFunc.ID != "" {
+		method = append(method, jsonField{Key: "x-id", Value: apiFunc.ID})
+	}
+	description := apiFunc.Description
+	if apiFunc.Deprecated && apiFunc.DeprecationReason != "" {
+		description = strings.TrimSpace(description + "\n\nDeprecated: " + apiFunc.DeprecationReason)
+	}
+	if description != "" {
+		method = append(method, jsonField{Key: "description", Value: description})
+	}
+	if len(apiFunc.Tags) > 0 {
+		tags := []interface{}{}
+		for _, tag := range apiFunc.Tags {
+			tags = append(tags, jsonObject{{Key: "name", Value: tag}})
+		}
+		method = append(method, jsonField{Key: "tags", Value: tags})
+	}
+
+	params := []interface{}{}
+	for _, param := range apiFunc.Parameters {
+		ref := typeRefOf(param.TypeRef, param.Type, apiFunc.PackageName, apiFunc.ImportAliases, schemas.structs)
+		schema := enumSchema(schemas.schema(ref, ""), param.Enum)
+		if param.Default != nil {
+			if value, ok := defaultValue(param.Default.Value); ok {
+				schema = append(schema, jsonField{Key: "default", Value: value})
+			}
+		}
+		descriptor := jsonObject{{Key: "name", Value: param.Name}}
+		if param.Description != "" {
+			descriptor = append(descriptor, jsonField{Key: "description", Value: param.Description})
+		}
+		descriptor = append(descriptor, jsonField{Key: "required", Value: param.Required}, jsonField{Key: "schema", Value: schema})
+		params = append(params, descriptor)
+	}
+	method = append(method, jsonField{Key: "params", Value: params})
+	if rules := paramRules(apiFunc.Requires); len(rules) > 0 {
+		// The params are content descriptors, so the standard dependentRequired goes in a
+		// schema of the params object that applies along them
+		method = append(method, jsonField{Key: "x-requires", Value: rules}, jsonField{Key: "x-params-schema", Value: jsonObject{
+			{Key: "type", Value: "object"},
+			{Key: "dependentRequired", Value: rules},
+		}})
+	}
+	if rules := paramRules(apiFunc.ConflictsWith); len(rules) > 0 {
+		method = append(method, jsonField{Key: "x-conflicts-with", Value: rules})
+	}
+
+	result := jsonObject{{Key: "name", Value: "result"}}
+	schema := jsonObject{{Key: "type", Value: "null"}}
+	if len(apiFunc.Results) > 0 {
+		first := apiFunc.Results[0]
+		if first.Name != "" {
+			result[0].Value = first.Name
+		}
+		if first.Description != "" {
+			result = append(result, jsonField{Key: "description", Value: first.Description})
+		}
+		if len(first.ContentTypes) > 0 {
+			var contentTypes []interface{}
+			for _, contentType := range first.ContentTypes {
+				object := jsonObject{{Key: "mediaType", Value: contentType.MediaType}}
+				if contentType.Encoding != "" {
+					object = append(object, jsonField{Key: "encoding", Value: contentType.Encoding})
+				}
+				contentTypes = append(contentTypes, object)
+			}
+			result = append(result, jsonField{Key: "x-content-types", Value: contentTypes})
+		}
+		schema = schemas.schema(resultTypeRef(apiFunc, first, schemas.structs), "")
+	}
+	if len(envelope.Members) > 0 && !apiFunc.NoEnvelope {
+		schema = schemas.envelope(envelope, schema)
+	}
+	method = append(method, jsonField{Key: "result", Value: append(result, jsonField{Key: "schema", Value: schema})})
+
+	if len(apiFunc.Errors) > 0 {
+		var errs []interface{}
+		for _, apiError := range apiFunc.Errors {
+			errs = append(errs, jsonObject{{Key: "code", Value: apiError.Code}, {Key: "message", Value: apiError.Description}})
+		}
+		method = append(method, jsonField{Key: "errors", Value: errs})
+	}
+	if apiFunc.Subscription != nil {
+		payload := apiFunc.Subscription.Payload
+		schema := schemas.schema(typeRefOf(payload.TypeRef, payload.Type, apiFunc.PackageName, apiFunc.ImportAliases, schemas.structs), "")
+		if payload.Description != "" {
+			schema = describedSchema(schema, payload.Description)
+		}
+		method = append(method, jsonField{Key: "x-subscription", Value: jsonObject{
+			{Key: "method", Value: apiFunc.Subscription.Method},
+			{Key: "payload", Value: schema},
+		}})
+	}
+	if apiFunc.Sizes.MaxRequest > 0 {
+		method = append(method, jsonField{Key: "x-max-request-size", Value: apiFunc.Sizes.MaxRequest})
+	}
+	if apiFunc.Sizes.TypicalResponse > 0 {
+		method = append(method, jsonField{Key: "x-typical-response-size", Value: apiFunc.Sizes.TypicalResponse})
+	}
+	if apiFunc.Deprecated {
+		method = append(method, jsonField{Key: "deprecated", Value: true})
+	}
+	return append(method, jsonField{Key: "paramStructure", Value: "by-name"})
+}
+
+// paramRules returns the @Requires or @ConflictsWith rules of a command as an object mapping
+// each parameter to the parameters it relates to, in declaration order.
+func paramRules(rules []models.ParamRule) jsonObject {
+	object := jsonObject{}
+	for _, rule := range rules {
+		object = append(object, jsonField{Key: rule.Param, Value: rule.Others})
+	}
+	return object
+}
+
+// schemaBuilder builds the JSON Schemas of types, collecting the schemas of the structs they
+// use for the components of the document.
+type schemaBuilder struct {
+	structs map[models.StructKey]models.StructDefinition
+	// names are the schema names of the structs, valid identifiers such as
+	// "Pagination_ReportItem".
+	names map[models.StructKey]string
+	// schemas holds the schemas of the structs referenced so far, by schema name.
+	schemas map[string]jsonObject
+}
+
+// newSchemaBuilder returns a builder for the schemas of the given structs. Structs are named
+// after their type, with the package in front when several packages declare the same name.
+func newSchemaBuilder(structDefinitions map[models.StructKey]models.StructDefinition) *schemaBuilder {
+	b := &schemaBuilder{
+		structs: structDefinitions,
+		names:   make(map[models.StructKey]string, len(structDefinitions)),
+		schemas: make(map[string]jsonObject),
+	}
+	declared := make(map[string]int)
+	for key := range structDefinitions {
+		declared[schemaName(key.Name)]++
+	}
+	for key := range structDefinitions {
+		name := schemaName(key.Name)
+		if declared[name] > 1 {
+			name = schemaName(key.Package + "_" + key.Name)
+		}
+		b.names[key] = name
+	}
+	return b
+}
+
+// schemaName turns a Go type name, such as "Pagination[ReportItem]", into a schema name,
+// such as "Pagination_ReportItem".
+func schemaName(typeName string) string {
+	return strings.Trim(nonIdentifier.ReplaceAllString(typeName, "_"), "_")
+}
+
+// schema returns the JSON Schema of a type. format is the format of the field holding the
+// value, such as "date-time".
+func (b *schemaBuilder) schema(ref *models.TypeRef, format string) jsonObject {
+	ref = ref.Deref()
+	if ref == nil {
+		return jsonObject{}
+	}
+	switch ref.Kind {
+	case models.TypeBasic:
+		schema := jsonObject{{Key: "type", Value: basicSchemaType(ref.Name)}}
+		if format != "" && ref.Name == "string" {
+			schema = append(schema, jsonField{Key: "format", Value: format})
+		}
+		return schema
+	case models.TypeSlice:
+		if isByteSlice(ref) {
+			return jsonObject{{Key: "type", Value: "string"}, {Key: "contentEncoding", Value: "base64"}}
+		}
+		return jsonObject{{Key: "type", Value: "array"}, {Key: "items", Value: b.schema(ref.Elem, format)}}
+	case models.TypeMap:
+		return jsonObject{{Key: "type", Value: "object"}, {Key: "additionalProperties", Value: b.schema(ref.Elem, format)}}
+	case models.TypeStruct:
+		if _, exists := b.structs[ref.Struct]; !exists {
+			return jsonObject{{Key: "type", Value: "object"}}
+		}
+		return jsonObject{{Key: "$ref", Value: schemaRefPrefix + b.structSchema(ref.Struct)}}
+	case models.TypeNamed:
+		// Well-known types such as time.Time are strings with a format
+		if format != "" {
+			return jsonObject{{Key: "type", Value: "string"}, {Key: "format", Value: format}}
+		}
+	}
+	return jsonObject{}
+}
+
+// basicSchemaType returns the JSON Schema type of a basic Go type.
+func basicSchemaType(name string) string {
+	switch {
+	case name == "string":
+		return "string"
+	case name == "bool":
+		return "boolean"
+	case strings.HasPrefix(name, "float"):
+		return "number"
+	default:
+		return "integer"
+	}
+}
+
+// structSchema adds the schema of a struct to the components, unless it is already there,
+// and returns its name. Fields list their JSON names and are required like in the Required
+// column of the Markdown tables, see fieldRequirement. Pointer fields may also be null, and
+// the units of a field are an x-units extension.
+func (b *schemaBuilder) structSchema(key models.StructKey) string {
+	name := b.names[key]
+	if _, exists := b.schemas[name]; exists {
+		return name
+	}
+	// Registered first, so self-referencing structs end the recursion
+	b.schemas[name] = nil
+
+	structDef := b.structs[key]
+	schema := jsonObject{{Key: "type", Value: "object"}}
+	if structDef.Description != "" {
+		schema = append(schema, jsonField{Key: "description", Value: structDef.Description})
+	}
+	properties := jsonObject{}
+	var required []string
+	for _, field := range promotedFields(key, structDef, b.structs) {
+		if field.Excluded {
+			continue
+		}
+		fieldRef := typeRefOf(field.TypeRef, field.Type, key.Package, nil, b.structs)
+		property := enumSchema(b.schema(fieldRef, field.Format), field.Enum)
+		if isPointerField(field) {
+			property = nullableSchema(property)
+		}
+		if field.Description != "" {
+			property = describedSchema(property, field.Description)
+		}
+		if field.Units != "" {
+			property = append(property, jsonField{Key: "x-units", Value: field.Units})
+		}
+		properties = append(properties, jsonField{Key: field.JSONName, Value: property})
+		if fieldRequirement(field) == "Yes" {
+			required = append(required, field.JSONName)
+		}
+	}
+	schema = append(schema, jsonField{Key: "properties", Value: properties})
+	if len(required) > 0 {
+		schema = append(schema, jsonField{Key: "required", Value: required})
+	}
+	b.schemas[name] = schema
+	return name
+}
+
+// describedSchema adds a description to a schema. Keywords next to $ref are ignored by JSON
+// Schema, so references are wrapped in allOf.
+func describedSchema(schema jsonObject, description string) jsonObject {
+	if len(schema) == 1 && schema[0].Key == "$ref" {
+		return jsonObject{{Key: "description", Value: description}, {Key: "allOf", Value: []interface{}{schema}}}
+	}
+	return append(jsonObject{{Key: "description", Value: description}}, schema...)
+}
+
+// nullableSchema returns a schema also allowing null, for the fields encoding/json writes as
+// null when nil.
+func nullableSchema(schema jsonObject) jsonObject {
+	return jsonObject{{Key: "oneOf", Value: []interface{}{schema, jsonObject{{Key: "type", Value: "null"}}}}}
+}
+
+// envelope returns the schema of the result envelope wrapping a result of the given schema.
+func (b *schemaBuilder) envelope(envelope models.ResultEnvelope, result jsonObject) jsonObject {
+	properties := jsonObject{}
+	var required []string
+	for _, member := range envelope.Members {
+		schema := result
+		if member.Type != models.EnvelopeResult {
+			schema = b.schema(typeRefOf(member.TypeRef, member.Type, envelope.Package, envelope.ImportAliases, b.structs), "")
+		}
+		properties = append(properties, jsonField{Key: member.Name, Value: schema})
+		required = append(required, member.Name)
+	}
+	return jsonObject{{Key: "type", Value: "object"}, {Key: "properties", Value: properties}, {Key: "required", Value: required}}
+}
+
+// components returns the schemas of the structs referenced by the document, sorted by name.
+func (b *schemaBuilder) components() jsonObject {
+	names := make([]string, 0, len(b.schemas))
+	for name := range b.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	components := make(jsonObject, 0, len(names))
+	for _, name := range names {
+		components = append(components, jsonField{Key: name, Value: b.schemas[name]})
+	}
+	return components
+}
@@ -0,0 +1,194 @@
+// generator/openrpc_test.go
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// openRPCModel returns commands using a generic struct, a self-referencing struct, structs
+// of the same name in two packages and a result envelope, along with the annotations carried
+// by x- extensions and a JSON-RPC 1.0 command.
+func openRPCModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	pageKey := models.StructKey{Package: "rpc", Name: "Pagination[User]"}
+	userKey := models.StructKey{Package: "rpc", Name: "User"}
+	metaKey := models.StructKey{Package: "rpc", Name: "Meta"}
+	billingMetaKey := models.StructKey{Package: "billing", Name: "Meta"}
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "users.List",
+			Description: "List users.",
+			Parameters: []models.APIParameter{
+				{Name: "limit", Type: "int64", Description: "Page size.", Default: &models.ParamDefault{Value: "50"}},
+				{Name: "billing", Type: "billing.Meta", Description: "Billing account.", TypeRef: &models.TypeRef{Kind: models.TypeStruct, Name: "Meta", Package: "billing", Struct: billingMetaKey}},
+			},
+			Results: []models.APIReturn{{
+				Name:        "page",
+				Type:        "Pagination[User]",
+				Description: "A page of users.",
+				TypeRef: &models.TypeRef{
+					Kind:     models.TypeStruct,
+					Name:     "Pagination",
+					Package:  "rpc",
+					TypeArgs: []*models.TypeRef{{Kind: models.TypeStruct, Name: "User", Package: "rpc", Struct: userKey}},
+					Struct:   pageKey,
+				},
+			}},
+			Errors:      []models.APIError{{Code: -32004, Name: "Forbidden", Description: "Not allowed."}},
+			PackageName: "rpc",
+			ID:          "usr-list",
+			Requires:    []models.ParamRule{{Param: "billing", Others: []string{"limit"}}},
+			Sizes:       models.Sizes{MaxRequest: 4096},
+		},
+		{
+			Command:      "users.Watch",
+			Description:  "Watch the changes of users.",
+			Results:      []models.APIReturn{{Name: "result", Type: "string", Description: "The subscription id."}},
+			Subscription: &models.Subscription{Method: "users.Changed", Payload: models.NotificationPayload{Type: "User", Description: "The changed user."}},
+			PackageName:  "rpc",
+		},
+		{
+			Command:       "users.Export",
+			Description:   "Export the users.",
+			Parameters:    []models.APIParameter{{Name: "format", Type: "string", Description: "File format."}, {Name: "sheet", Type: "string", Description: "Sheet name."}},
+			Results:       []models.APIReturn{{Name: "file", Type: "[]byte", Description: "The exported file.", ContentTypes: []models.ContentType{{MediaType: "text/csv", Encoding: "base64"}}}},
+			ConflictsWith: []models.ParamRule{{Param: "sheet", Others: []string{"format"}}},
+			PackageName:   "rpc",
+		},
+		{
+			Command:         "legacy.Ping",
+			Description:     "Check the server with JSON-RPC 1.0.",
+			Envelope:        models.Envelope{JSONRPC: "1.0"},
+			SourceFile:      "legacy.go",
+			SourceLine:      8,
+			AnnotationLines: map[string]int{"@Envelope": 7},
+			PackageName:     "rpc",
+		},
+		{
+			Command:           "users.Get",
+			Description:       "Get a user.",
+			Parameters:        []models.APIParameter{{Name: "id", Type: "int", Description: "User id.", Required: true}},
+			Results:           []models.APIReturn{{Name: "result", Type: "*User", Description: "The user."}},
+			Deprecated:        true,
+			DeprecationReason: "Use users.List.",
+			PackageName:       "rpc",
+		},
+		{
+			Command:     "ping",
+			Description: "Check the server.",
+			NoEnvelope:  true,
+			PackageName: "rpc",
+		},
+		{
+			Command:     "broken.Get",
+			SourceFile:  "broken.go",
+			SourceLine:  4,
+			Incomplete:  "its file failed to parse",
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		pageKey: {
+			Name: "Pagination[User]",
+			Fields: []models.StructField{
+				{Name: "Items", Type: "[]User", JSONName: "items"},
+				{Name: "Next", Type: "string", JSONName: "next", Omitempty: true, Description: "Token of the next page."},
+			},
+		},
+		userKey: {
+			Name:        "User",
+			Description: "A user account.",
+			Fields: []models.StructField{
+				{Name: "ID", Type: "int", JSONName: "id"},
+				{Name: "Email", Type: "string", JSONName: "email", Format: "email"},
+				{Name: "Created", Type: "time.Time", JSONName: "created", Format: "date-time"},
+				{Name: "Avatar", Type: "[]byte", JSONName: "avatar", Omitempty: true},
+				{Name: "Labels", Type: "map[string]string", JSONName: "labels", Omitempty: true},
+				{Name: "Score", Type: "float64", JSONName: "score"},
+				{Name: "Manager", Type: "*User", JSONName: "manager", Omitempty: true, Description: "Manager of the user."},
+				{Name: "Quota", Type: "*int64", JSONName: "quota", Description: "Storage quota, null when unlimited.", Units: "bytes"},
+			},
+		},
+		metaKey: {
+			Name:   "Meta",
+			Fields: []models.StructField{{Name: "Took", Type: "int", JSONName: "took"}},
+		},
+		billingMetaKey: {
+			Name:   "Meta",
+			Fields: []models.StructField{{Name: "Account", Type: "string", JSONName: "account"}},
+		},
+	}
+	projectInfo := models.ProjectInfo{
+		Title:       "Users API",
+		Version:     "1.0.0",
+		Description: "Manage users.",
+		Contact:     "support@example.com",
+		License:     "MIT",
+		Servers:     []string{"https://api.example.com/rpc"},
+		Sizes:       models.Sizes{TypicalResponse: 512},
+		ResultEnvelope: models.ResultEnvelope{Package: "rpc", Members: []models.EnvelopeMember{
+			{Name: "data", Type: models.EnvelopeResult},
+			{Name: "meta", Type: "Meta"},
+		}},
+	}
+	return apiFunctions, structs, projectInfo
+}
+
+func TestOpenRPCGolden(t *testing.T) {
+	apiFunctions, structs, projectInfo := openRPCModel()
+	outFile := filepath.Join(t.TempDir(), "openrpc.json")
+	var warnings []Warning
+	opts := Options{Warn: func(warning Warning) { warnings = append(warnings, warning) }}
+	if err := GenerateOpenRPC(apiFunctions, structs, projectInfo, outFile, opts); err != nil {
+		t.Fatalf("GenerateOpenRPC returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	assertGolden(t, "openrpc", string(content))
+
+	var doc struct {
+		Methods []struct {
+			Name string `json:"name"`
+		} `json:"methods"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	// Incomplete stubs are left out, and so are the JSON-RPC 1.0 commands, with a warning
+	var methods []string
+	for _, method := range doc.Methods {
+		methods = append(methods, method.Name)
+	}
+	if got := strings.Join(methods, ","); got != "ping,users.Export,users.Get,users.List,users.Watch" {
+		t.Errorf("Unexpected methods %s", got)
+	}
+	want := Warning{File: "legacy.go", Line: 7, Class: "envelope", Message: "command 'legacy.Ping' uses JSON-RPC 1.0 and is left out of the OpenRPC document, which describes JSON-RPC 2.0"}
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("Expected the warning %+v, got %+v", want, warnings)
+	}
+
+	// Every reference names a schema of the components, and schema names are identifiers
+	identifier := regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	for name := range doc.Components.Schemas {
+		if !identifier.MatchString(name) {
+			t.Errorf("Schema name %q is not a valid identifier", name)
+		}
+	}
+	for _, match := range regexp.MustCompile(`"\$ref": "#/components/schemas/([^"]*)"`).FindAllStringSubmatch(string(content), -1) {
+		if _, exists := doc.Components.Schemas[match[1]]; !exists {
+			t.Errorf("Reference to undefined schema %q", match[1])
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// generator/output.go
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GeneratedMarker is the first line of every Markdown file written by jdocgen. NoClobber only
+// overwrites files starting with it.
+const GeneratedMarker = "<!-- Generated by jdocgen. DO NOT EDIT. -->"
+
+// stagedFiles writes output files to temporary files next to their targets and renames them
+// over the targets on commit, so a generation failing half-way leaves the previous output
+// untouched instead of truncated.
+type stagedFiles struct {
+	noClobber bool
+	// wrap and alignTables lay out the Markdown files, see Options.Wrap and Options.AlignTables.
+	wrap        int
+	alignTables bool
+	// headingOffset shifts the headings of the Markdown files, see Options.HeadingOffset.
+	headingOffset int
+	files         []stagedFile
+	// stream, when set, receives the staged content on commit instead of the target files,
+	// see Options.Output. pending holds the content staged for it.
+	stream  io.Writer
+	pending []*bytes.Buffer
+}
+
+// documentOutput returns the staged files of the documentation of a single-file format,
+// written to opts.Output when it is set.
+func documentOutput(opts Options) *stagedFiles {
+	return &stagedFiles{noClobber: opts.NoClobber, wrap: opts.Wrap, alignTables: opts.AlignTables, headingOffset: opts.HeadingOffset, stream: opts.Output}
+}
+
+// stagedFile is a temporary file waiting to replace its target.
+type stagedFile struct {
+	target string
+	temp   string
+}
+
+// write stages a Markdown file starting with the GeneratedMarker, laid out with the wrapping,
+// table alignment and heading offset of s. Trailing blank lines are dropped, so the file ends with a single
+// newline whatever its last section writes.
+func (s *stagedFiles) write(path string, write func(writer *bufio.Writer) error) error {
+	return s.stage(path, func(writer *bufio.Writer) error {
+		var content bytes.Buffer
+		buffered := bufio.NewWriter(&content)
+		if err := write(buffered); err != nil {
+			return err
+		}
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "%s\n\n", GeneratedMarker)
+		laidOut := shiftHeadings(strings.TrimRight(content.String(), "\n"), s.headingOffset)
+		laidOut = layoutMarkdown(laidOut, s.wrap, s.alignTables)
+		_, err := writer.WriteString(laidOut + "\n")
+		return err
+	})
+}
+
+// writeManifest stages a manifest.json file.
+func (s *stagedFiles) writeManifest(path string, manifest Manifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %v", err)
+	}
+	return s.stage(path, func(writer *bufio.Writer) error {
+		_, err := writer.Write(append(content, '\n'))
+		return err
+	})
+}
+
+// stage writes a temporary file in the directory of path through a buffered writer, or a
+// buffer when the content goes to the stream.
+func (s *stagedFiles) stage(path string, write func(writer *bufio.Writer) error) error {
+	if s.stream != nil {
+		var content bytes.Buffer
+		writer := bufio.NewWriter(&content)
+		if err := write(writer); err != nil {
+			return err
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		s.pending = append(s.pending, &content)
+		return nil
+	}
+	if s.noClobber {
+		if err := checkClobber(path); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	s.files = append(s.files, stagedFile{target: path, temp: file.Name()})
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := write(writer); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write to output file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write to output file: %v", err)
+	}
+
+	// Temporary files are private, the output keeps the mode of the file it replaces
+	mode := fs.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(file.Name(), mode); err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	return nil
+}
+
+// commit renames every staged file over its target. A rename failing stops the commit and
+// removes the files not renamed yet.
+func (s *stagedFiles) commit() error {
+	for _, content := range s.pending {
+		if _, err := content.WriteTo(s.stream); err != nil {
+			s.pending = nil
+			return fmt.Errorf("failed to write output: %v", err)
+		}
+	}
+	s.pending = nil
+	for i, file := range s.files {
+		if err := os.Rename(file.temp, file.target); err != nil {
+			s.files = s.files[i:]
+			s.discard()
+			return fmt.Errorf("failed to replace output file: %v", err)
+		}
+	}
+	s.files = nil
+	return nil
+}
+
+// discard removes the staged files, leaving their targets as they were.
+func (s *stagedFiles) discard() {
+	for _, file := range s.files {
+		os.Remove(file.temp)
+	}
+	s.files = nil
+	s.pending = nil
+}
+
+// checkClobber returns an error when path exists and was not written by jdocgen: Markdown
+// files start with the GeneratedMarker, a manifest decodes as a Manifest with an index, a
+// JSON document or health summary has a schema version, and an OpenRPC document has the
+// x-generator extension of jdocgen.
+func checkClobber(path string) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read existing output file: %v", err)
+	}
+
+	generated := strings.HasPrefix(string(content), GeneratedMarker)
+	if filepath.Base(path) == splitManifestFile {
+		var manifest Manifest
+		generated = json.Unmarshal(content, &manifest) == nil && manifest.Index != ""
+	} else if !generated {
+		var versioned struct {
+			SchemaVersion int    `json:"schemaVersion"`
+			Generator     string `json:"x-generator"`
+		}
+		generated = json.Unmarshal(content, &versioned) == nil && (versioned.SchemaVersion > 0 || versioned.Generator == openRPCGenerator)
+	}
+	if !generated {
+		return fmt.Errorf("refusing to overwrite %s, which was not generated by jdocgen (no-clobber)", path)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// generator/output_test.go
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// readDir returns the content of every file in dir by name.
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", entry.Name(), err)
+		}
+		files[entry.Name()] = string(content)
+	}
+	return files
+}
+
+func TestFailedGenerationKeepsOutput(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "API.md")
+	previous := GeneratedMarker + "\n\n# Previous documentation\n"
+	if err := os.WriteFile(outFile, []byte(previous), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The preamble template fails after the header is written
+	opts := Options{IncludeRFC: true, RFCTemplate: "{{ .Missing }}"}
+	if err := GenerateDocumentation(apiFunctions, structs, projectInfo, outFile, opts); err == nil {
+		t.Fatal("Expected the template error to be returned")
+	}
+	if files := readDir(t, dir); len(files) != 1 || files["API.md"] != previous {
+		t.Errorf("Expected only the previous file, got %v", files)
+	}
+
+	if err := GenerateDocumentation(apiFunctions, structs, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	info, err := os.Stat(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the mode of the replaced file to be kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestFailedSplitGenerationKeepsOutput(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	previous := readDir(t, outDir)
+
+	opts := Options{IncludeRFC: true, RFCTemplate: "{{ .Missing }}"}
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts); err == nil {
+		t.Fatal("Expected the template error to be returned")
+	}
+	got := readDir(t, outDir)
+	if len(got) != len(previous) {
+		t.Errorf("Expected files %v, got %v", len(previous), len(got))
+	}
+	for name, content := range previous {
+		if got[name] != content {
+			t.Errorf("%s changed after a failed generation:\n%s", name, got[name])
+		}
+	}
+}
+
+func TestNoClobber(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	dir := t.TempDir()
+	opts := Options{NoClobber: true}
+
+	// Missing and generated files are written
+	outFile := filepath.Join(dir, "API.md")
+	for i := 0; i < 2; i++ {
+		if err := GenerateDocumentation(apiFunctions, structs, projectInfo, outFile, opts); err != nil {
+			t.Fatalf("GenerateDocumentation returned error: %v", err)
+		}
+	}
+
+	// OpenRPC documents are recognized by their x-generator extension
+	openRPCFile := filepath.Join(dir, "openrpc.json")
+	for i := 0; i < 2; i++ {
+		if err := GenerateOpenRPC(apiFunctions, structs, projectInfo, openRPCFile, opts); err != nil {
+			t.Fatalf("GenerateOpenRPC returned error: %v", err)
+		}
+	}
+
+	// HTML pages start with the marker too
+	htmlFile := filepath.Join(dir, "api.html")
+	for i := 0; i < 2; i++ {
+		if err := GenerateHTML(apiFunctions, structs, projectInfo, htmlFile, opts); err != nil {
+			t.Fatalf("GenerateHTML returned error: %v", err)
+		}
+	}
+
+	handWritten := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(handWritten, []byte("# Hand-written\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := GenerateDocumentation(apiFunctions, structs, projectInfo, handWritten, opts)
+	if err == nil || !strings.Contains(err.Error(), "not generated by jdocgen") {
+		t.Fatalf("Expected a no-clobber error, got %v", err)
+	}
+	if content, _ := os.ReadFile(handWritten); string(content) != "# Hand-written\n" {
+		t.Errorf("Expected the hand-written file to be kept, got:\n%s", content)
+	}
+
+	// Split output checks every file, including the manifest
+	outDir := filepath.Join(dir, "docs")
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation over its own output returned error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, splitManifestFile), []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, opts)
+	if err == nil || !strings.Contains(err.Error(), splitManifestFile) {
+		t.Errorf("Expected a no-clobber error for the manifest, got %v", err)
+	}
+	for name := range readDir(t, outDir) {
+		if strings.HasSuffix(name, ".tmp") {
+			t.Errorf("Temporary file %s was left behind", name)
+		}
+	}
+}
+
+func TestStreamOutput(t *testing.T) {
+	apiFunctions, structs, projectInfo := testModel()
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "API.md")
+	if err := GenerateDocumentation(apiFunctions, structs, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	want, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same document goes to the stream and no file is written
+	var buf bytes.Buffer
+	streamed := filepath.Join(dir, "streamed.md")
+	if err := GenerateDocumentation(apiFunctions, structs, projectInfo, streamed, Options{Output: &buf}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("Expected the streamed document to match the file, got:\n%s", buf.String())
+	}
+	if _, err := os.Stat(streamed); !os.IsNotExist(err) {
+		t.Errorf("Expected no file at %s, got %v", streamed, err)
+	}
+
+	// A failing generation writes nothing
+	buf.Reset()
+	if err := WriteDocumentation(&buf, apiFunctions, structs, models.ProjectInfo{}, Options{}); err == nil {
+		t.Fatal("Expected an error for missing project information")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be written, got:\n%s", buf.String())
+	}
+}
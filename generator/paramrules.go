@@ -0,0 +1,97 @@
+// generator/paramrules.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// writeParamRules writes the @Requires and @ConflictsWith rules of apiFunc as a list under
+// its Parameters table.
+func writeParamRules(writer io.Writer, apiFunc models.APIFunction) {
+	if len(apiFunc.Requires) == 0 && len(apiFunc.ConflictsWith) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "**Parameter rules:**\n\n")
+	for _, rule := range apiFunc.Requires {
+		fmt.Fprintf(writer, "- `%s` requires %s.\n", rule.Param, joinCodes(rule.Others, "and"))
+	}
+	for _, rule := range apiFunc.ConflictsWith {
+		fmt.Fprintf(writer, "- `%s` cannot be sent together with %s.\n", rule.Param, joinCodes(rule.Others, "or"))
+	}
+	fmt.Fprintf(writer, "\n")
+}
+
+// joinCodes joins names as code spans: "`a`", "`a` and `b`", "`a`, `b` and `c`".
+func joinCodes(names []string, conjunction string) string {
+	codes := make([]string, len(names))
+	for i, name := range names {
+		codes[i] = "`" + name + "`"
+	}
+	if len(codes) == 1 {
+		return codes[0]
+	}
+	return strings.Join(codes[:len(codes)-1], ", ") + " " + conjunction + " " + codes[len(codes)-1]
+}
+
+// exampleParameters returns the parameters sent in an example request of apiFunc: the
+// required ones, or all of them with all set, then those they @Requires. A parameter
+// conflicting with one kept before it is left out, so examples never break @ConflictsWith.
+func exampleParameters(apiFunc models.APIFunction, all bool) []models.APIParameter {
+	selected := make(map[string]bool)
+	for _, param := range apiFunc.Parameters {
+		if all || param.Required {
+			selected[param.Name] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range apiFunc.Requires {
+			if !selected[rule.Param] {
+				continue
+			}
+			for _, other := range rule.Others {
+				if !selected[other] {
+					selected[other] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	var params []models.APIParameter
+	kept := make(map[string]bool)
+	for _, param := range apiFunc.Parameters {
+		if selected[param.Name] && !conflictsWithKept(apiFunc, param.Name, kept) {
+			params = append(params, param)
+			kept[param.Name] = true
+		}
+	}
+	return params
+}
+
+// conflictsWithKept reports whether a @ConflictsWith rule of apiFunc forbids sending name
+// together with one of the kept parameters.
+func conflictsWithKept(apiFunc models.APIFunction, name string, kept map[string]bool) bool {
+	for _, rule := range apiFunc.ConflictsWith {
+		if rule.Param == name {
+			for _, other := range rule.Others {
+				if kept[other] {
+					return true
+				}
+			}
+			continue
+		}
+		if kept[rule.Param] {
+			for _, other := range rule.Others {
+				if other == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// generator/params.go
+package generator
+
+import (
+	"fmt"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// defaultFlattenDepth bounds how many nested struct levels are expanded when flattening parameters.
+const defaultFlattenDepth = 3
+
+// flattenParameters returns the parameters of apiFunc with every struct-typed parameter
+// replaced by its fields, using dotted names for nesting (filter.date_from).
+// A field is required when its parent is required and it is neither a pointer nor omitempty.
+// Expansion stops at opts.FlattenDepth levels and at structs already being expanded, leaving
+// a note.
+func flattenParameters(apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, opts Options) []models.APIParameter {
+	if opts.FlattenDepth <= 0 {
+		opts.FlattenDepth = defaultFlattenDepth
+	}
+
+	var flattened []models.APIParameter
+	for _, param := range apiFunc.Parameters {
+		ref := typeRefOf(param.TypeRef, param.Type, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
+		key, found := resolveStructType(ref)
+		if _, terminal := utils.WellKnownType(ref, opts.TerminalTypes); !found || terminal {
+			flattened = append(flattened, param)
+			continue
+		}
+		flattened = appendStructFields(flattened, param.Name, param.Required, key, structDefinitions, map[models.StructKey]bool{}, 1, opts)
+	}
+	return flattened
+}
+
+// appendStructFields appends one parameter row per JSON-visible field of the struct identified by key.
+func appendStructFields(params []models.APIParameter, prefix string, required bool, key models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, expanding map[models.StructKey]bool, depth int, opts Options) []models.APIParameter {
+	expanding[key] = true
+	defer delete(expanding, key)
+
+	for _, field := range promotedFields(key, structDefinitions[key], structDefinitions) {
+		if field.Excluded {
+			continue
+		}
+
+		fieldRef := typeRefOf(field.TypeRef, field.Type, key.Package, map[string]string{}, structDefinitions)
+		param := models.APIParameter{
+			Name:        prefix + "." + field.JSONName,
+			Type:        fieldTypeLabel(field, opts),
+			TypeRef:     fieldRef,
+			Description: field.Description,
+			Required:    required && !field.Omitempty && fieldRef.Kind != models.TypePointer,
+			Enum:        field.Enum,
+		}
+
+		fieldKey, found := resolveStructType(fieldRef)
+		_, terminal := utils.WellKnownType(fieldRef, opts.TerminalTypes)
+		switch {
+		case !found || terminal:
+			params = append(params, param)
+		case expanding[fieldKey]:
+			param.Description = appendNote(param.Description, fmt.Sprintf("Recursive reference to %s, not expanded.", fieldKey.Name))
+			params = append(params, param)
+		case depth >= opts.FlattenDepth:
+			param.Description = appendNote(param.Description, fmt.Sprintf("See %s for its fields.", fieldKey.Name))
+			params = append(params, param)
+		default:
+			params = appendStructFields(params, param.Name, param.Required, fieldKey, structDefinitions, expanding, depth+1, opts)
+		}
+	}
+	return params
+}
+
+// resolveStructType returns the struct a type refers to, directly or through pointers.
+// Slices, maps and basic types are not resolved.
+func resolveStructType(ref *models.TypeRef) (models.StructKey, bool) {
+	ref = ref.Deref()
+	if ref == nil || ref.Kind != models.TypeStruct {
+		return models.StructKey{}, false
+	}
+	return ref.Struct, true
+}
+
+// appendNote appends an italic note to a description.
+func appendNote(description string, note string) string {
+	if description == "" {
+		return "_" + note + "_"
+	}
+	return description + " _" + note + "_"
+}
@@ -0,0 +1,85 @@
+// generator/references.go
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// structReference is a field of a struct whose type refers to another struct.
+type structReference struct {
+	From  models.StructKey
+	Field string
+}
+
+// structGraph is the set of structs reachable from the structs documented with a command.
+type structGraph struct {
+	// order lists the roots, then every referenced struct once, in breadth-first order of first reference.
+	order []models.StructKey
+	// references lists, for each struct, the fields referring to it in order of discovery.
+	references map[models.StructKey][]structReference
+}
+
+// collectStructGraph walks the structs reachable from roots breadth-first. The whole graph is
+// collected before anything is printed, so each struct is emitted once, after its referrers.
+// The structs of opts.TerminalTypes are left out, see utils.WellKnownType, and so are embedded
+// structs whose fields are promoted, see documentedFields.
+func collectStructGraph(roots []models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, opts Options) structGraph {
+	terminalTypes := opts.TerminalTypes
+	graph := structGraph{references: make(map[models.StructKey][]structReference)}
+	queued := make(map[models.StructKey]bool)
+	enqueue := func(key models.StructKey) {
+		if _, terminal := terminalTypes[key.Package+"."+key.Name]; !queued[key] && !terminal {
+			queued[key] = true
+			graph.order = append(graph.order, key)
+		}
+	}
+
+	for _, root := range roots {
+		enqueue(root)
+	}
+	for i := 0; i < len(graph.order); i++ {
+		key := graph.order[i]
+		for _, field := range documentedFields(key, structDefinitions[key], structDefinitions, opts) {
+			fieldKey, found := resolveFieldStruct(field, key.Package, structDefinitions)
+			if _, terminal := terminalTypes[fieldKey.Package+"."+fieldKey.Name]; !found || terminal {
+				continue
+			}
+			graph.references[fieldKey] = append(graph.references[fieldKey], structReference{From: key, Field: field.Name})
+			enqueue(fieldKey)
+		}
+	}
+
+	// Roots are documented for their own sake, not because a field refers to them
+	for _, root := range roots {
+		delete(graph.references, root)
+	}
+	return graph
+}
+
+// resolveFieldStruct returns the struct held by a field of a struct in pkg, looking through
+// pointers, slices and maps.
+func resolveFieldStruct(field models.StructField, pkg string, structDefinitions map[models.StructKey]models.StructDefinition) (models.StructKey, bool) {
+	held := typeRefOf(field.TypeRef, field.Type, pkg, map[string]string{}, structDefinitions).Held()
+	if held == nil || held.Kind != models.TypeStruct {
+		return models.StructKey{}, false
+	}
+	return held.Struct, true
+}
+
+// describeReferences lists the fields referring to a struct, grouped by the struct declaring them.
+// For example, "Items, Extra of rpc.Report; Rows of rpc.Page"
+func describeReferences(references []structReference, structDefinitions map[models.StructKey]models.StructDefinition) string {
+	var groups []string
+	var fields []string
+	for i, ref := range references {
+		fields = append(fields, ref.Field)
+		if i == len(references)-1 || references[i+1].From != ref.From {
+			groups = append(groups, fmt.Sprintf("%s of %s", strings.Join(fields, ", "), structHeading(ref.From, structDefinitions[ref.From])))
+			fields = nil
+		}
+	}
+	return strings.Join(groups, "; ")
+}
@@ -0,0 +1,60 @@
+// generator/result_envelope.go
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// envelopeHeading is the heading of the section describing the project @envelope.
+const envelopeHeading = "Result Envelope"
+
+// writeResultEnvelope writes the section describing the object the server wraps every
+// result in, followed by the structs of its named members. Nothing is written without @envelope.
+func writeResultEnvelope(writer io.Writer, envelope models.ResultEnvelope, structDefinitions map[models.StructKey]models.StructDefinition, anchors *anchorRegistry, appendix *typeAppendix, opts Options) {
+	if len(envelope.Members) == 0 {
+		return
+	}
+
+	anchors.heading(writer, 2, envelopeHeading)
+	fmt.Fprintf(writer, "The server wraps the result of every command in this object, unless the command says otherwise.\n\n")
+	fmt.Fprintf(writer, "| Member | Type | Description |\n")
+	fmt.Fprintf(writer, "|--------|------|-------------|\n")
+	var roots []models.StructKey
+	for _, member := range envelope.Members {
+		if member.Type == models.EnvelopeResult {
+			fmt.Fprintf(writer, "| %s | %s | The result of the command. |\n", member.Name, member.Type)
+			continue
+		}
+		description := ""
+		if key, found := resolveStructType(member.TypeRef); found {
+			description = structDefinitions[key].Description
+			roots = append(roots, key)
+		}
+		fmt.Fprintf(writer, "| %s | %s | %s |\n", member.Name, member.Type, cellDescription(description, opts))
+	}
+	fmt.Fprintf(writer, "\n")
+
+	printStructDefinitions(writer, roots, structDefinitions, make(map[models.StructKey]bool), anchors, appendix, opts)
+}
+
+// writeEnvelopeUse writes, under the Results table of apiFunc, whether its result is
+// wrapped in the result envelope and as which member.
+func writeEnvelopeUse(writer io.Writer, apiFunc models.APIFunction, envelope models.ResultEnvelope, opts Options) {
+	if len(envelope.Members) == 0 {
+		return
+	}
+	link := opts.indexFile + "#" + slugify(envelopeHeading)
+	if apiFunc.NoEnvelope {
+		fmt.Fprintf(writer, "_The result is not wrapped in the [result envelope](%s)._\n\n", link)
+		return
+	}
+	for _, member := range envelope.Members {
+		if member.Type == models.EnvelopeResult {
+			fmt.Fprintf(writer, "The result is wrapped in the [result envelope](%s) as `%s`.\n\n", link, member.Name)
+			return
+		}
+	}
+}
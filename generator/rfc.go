@@ -0,0 +1,131 @@
+// generator/rfc.go
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"text/template"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// DefaultRFCTemplate is the built-in template used to render the JSON-RPC 2.0 preamble.
+//
+//go:embed templates/rfc.md.tmpl
+var DefaultRFCTemplate string
+
+// RFCData is the data passed to the JSON-RPC preamble template.
+type RFCData struct {
+	Project               models.ProjectInfo
+	Variant               string
+	Servers               []string
+	IDType                string
+	ExampleID             string
+	ExampleCommand        string
+	SupportsBatch         bool
+	SupportsNotifications bool
+	// AuthSchemes are the authentication schemes declared with @Auth, sorted by name. It is
+	// empty when no command declares one.
+	AuthSchemes []AuthScheme
+	// StandardErrors is the -standard-errors-text sentence about the errors shared by every
+	// method, such as a link to a page of common errors, or empty when it is not set.
+	StandardErrors string
+	// CommonErrorsAnchor and ErrorCatalogAnchor are the anchors of the Common Errors and
+	// Error Catalog sections of the document, empty when it has no such section.
+	CommonErrorsAnchor string
+	ErrorCatalogAnchor string
+}
+
+// AuthScheme is an authentication scheme used by documented commands.
+type AuthScheme struct {
+	Name string
+	// Header is an example of the header carrying the credentials.
+	Header string
+	// Commands are the commands using the scheme, sorted by name.
+	Commands []string
+}
+
+// newRFCData builds the template data for the preamble from the project model and options.
+// The anchors of the sections written after the preamble are the ones anchors will give them.
+func newRFCData(apiFunctions []models.APIFunction, projectInfo models.ProjectInfo, anchors *anchorRegistry, opts Options) RFCData {
+	data := RFCData{
+		Project:               projectInfo,
+		Variant:               opts.Variant,
+		Servers:               projectInfo.Servers,
+		IDType:                opts.IDType,
+		ExampleID:             "1",
+		ExampleCommand:        "example.Method",
+		SupportsBatch:         opts.SupportsBatch,
+		SupportsNotifications: opts.SupportsNotifications,
+		AuthSchemes:           authSchemes(apiFunctions),
+		StandardErrors:        opts.StandardErrorsText,
+	}
+	if hasCommonErrors(projectInfo, opts) {
+		data.CommonErrorsAnchor = anchors.peek(globalErrorsHeading)
+	}
+	if len(projectInfo.ErrorCatalog) > 0 {
+		data.ErrorCatalogAnchor = anchors.peek(errorCatalogHeading)
+	}
+	if data.IDType == "" {
+		data.IDType = IDTypeNumber
+	}
+	if data.IDType == IDTypeString {
+		data.ExampleID = `"1"`
+	}
+
+	// Use the first command in documentation order for the examples
+	commands := make([]string, 0, len(apiFunctions))
+	for _, apiFunc := range apiFunctions {
+		commands = append(commands, apiFunc.Command)
+	}
+	sort.Strings(commands)
+	if len(commands) > 0 {
+		data.ExampleCommand = commands[0]
+	}
+
+	return data
+}
+
+// authSchemes returns the authentication schemes of the commands, with the commands using
+// each of them.
+func authSchemes(apiFunctions []models.APIFunction) []AuthScheme {
+	commands := make(map[string][]string)
+	for _, apiFunc := range apiFunctions {
+		if apiFunc.Auth != "" {
+			commands[apiFunc.Auth] = append(commands[apiFunc.Auth], apiFunc.Command)
+		}
+	}
+	schemes := make([]AuthScheme, 0, len(commands))
+	for name, names := range commands {
+		sort.Strings(names)
+		schemes = append(schemes, AuthScheme{Name: name, Header: authHeader(name), Commands: names})
+	}
+	sort.Slice(schemes, func(i, j int) bool { return schemes[i].Name < schemes[j].Name })
+	return schemes
+}
+
+// writeRFCSection renders the JSON-RPC preamble using the template in opts, or the default one.
+func writeRFCSection(w io.Writer, apiFunctions []models.APIFunction, projectInfo models.ProjectInfo, anchors *anchorRegistry, opts Options) error {
+	text := opts.RFCTemplate
+	if text == "" {
+		text = DefaultRFCTemplate
+	}
+
+	tmpl, err := template.New("rfc").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse RFC template: %v", err)
+	}
+
+	var section bytes.Buffer
+	if err := tmpl.Execute(&section, newRFCData(apiFunctions, projectInfo, anchors, opts)); err != nil {
+		return fmt.Errorf("failed to render RFC template: %v", err)
+	}
+	// The headings of the preamble come before the body, so commands and sections named
+	// like them get the numbered anchors
+	anchors.registerHeadings(section.String())
+	_, err = w.Write(section.Bytes())
+	return err
+}
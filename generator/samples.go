@@ -0,0 +1,103 @@
+// generator/samples.go
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// Supported values for Options.CodeSamples.
+const (
+	CodeSampleCurl = "curl"
+)
+
+// defaultEndpoint is used in code samples when neither Options.Endpoint nor a @server is set.
+const defaultEndpoint = "http://localhost:8080/rpc"
+
+// maxInlineBodyLength is the longest request body kept on the same line as the curl command.
+const maxInlineBodyLength = 80
+
+// validateCodeSamples checks that every requested code sample language is supported.
+func validateCodeSamples(samples []string) error {
+	for _, sample := range samples {
+		if sample != CodeSampleCurl {
+			return fmt.Errorf("unsupported code sample %q: expected %q", sample, CodeSampleCurl)
+		}
+	}
+	return nil
+}
+
+// writeCodeSamples writes the requested code samples for apiFunc.
+func writeCodeSamples(w io.Writer, apiFunc models.APIFunction, projectInfo models.ProjectInfo, opts Options, anchors *anchorRegistry) error {
+	for _, sample := range opts.CodeSamples {
+		switch sample {
+		case CodeSampleCurl:
+			command, err := curlCommand(apiFunc, sampleEndpoint(projectInfo, opts), opts)
+			if err != nil {
+				return err
+			}
+			anchors.heading(w, 3, "cURL:")
+			fmt.Fprintf(w, "```bash\n%s\n```\n\n", command)
+		}
+	}
+	return nil
+}
+
+// sampleEndpoint returns the URL used in code samples.
+func sampleEndpoint(projectInfo models.ProjectInfo, opts Options) string {
+	if opts.Endpoint != "" {
+		return opts.Endpoint
+	}
+	if len(projectInfo.Servers) > 0 {
+		return projectInfo.Servers[0]
+	}
+	return defaultEndpoint
+}
+
+// curlCommand builds a ready-to-paste curl invocation sending the minimal request of apiFunc.
+// Long bodies are indented over several lines inside the quoted argument.
+func curlCommand(apiFunc models.APIFunction, endpoint string, opts Options) (string, error) {
+	request := minimalRequest(apiFunc, opts)
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request body for %s: %v", apiFunc.Command, err)
+	}
+	if len(body) > maxInlineBodyLength {
+		body, err = json.MarshalIndent(request, "  ", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to build request body for %s: %v", apiFunc.Command, err)
+		}
+	}
+
+	lines := []string{
+		"curl -X POST " + shellQuote(endpoint),
+		"-H " + shellQuote("Content-Type: application/json"),
+	}
+	if apiFunc.Auth != "" {
+		lines = append(lines, "-H "+shellQuote(authHeader(apiFunc.Auth)))
+	}
+	lines = append(lines, "-d "+shellQuote(string(body)))
+
+	return strings.Join(lines, " \\\n  "), nil
+}
+
+// authHeader returns a placeholder header for an authentication scheme.
+func authHeader(scheme string) string {
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		return "Authorization: Bearer <token>"
+	case "basic":
+		return "Authorization: Basic <credentials>"
+	default:
+		return "Authorization: " + scheme + " <credentials>"
+	}
+}
+
+// shellQuote quotes s for POSIX shells using single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,42 @@
+// generator/sizes.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// writeSizeNote writes the size limits of apiFunc under its description, falling back to the
+// project defaults. Commands without documented sizes get no note.
+func writeSizeNote(w io.Writer, apiFunc models.APIFunction, projectInfo models.ProjectInfo) {
+	sizes := apiFunc.Sizes.Or(projectInfo.Sizes)
+	var limits []string
+	if sizes.MaxRequest > 0 {
+		limits = append(limits, "requests up to "+formatSize(sizes.MaxRequest))
+	}
+	if sizes.TypicalResponse > 0 {
+		limits = append(limits, "responses typically around "+formatSize(sizes.TypicalResponse))
+	}
+	if len(limits) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "**Size limits:** %s.\n\n", strings.Join(limits, ", "))
+}
+
+// formatSize returns a number of bytes in the largest binary unit it reaches, such as
+// "1 MB", "1.5 MB" or "300 bytes".
+func formatSize(bytes int64) string {
+	for _, unit := range []struct {
+		name  string
+		bytes int64
+	}{{"MB", 1 << 20}, {"KB", 1 << 10}} {
+		if bytes >= unit.bytes {
+			return strconv.FormatFloat(float64(bytes*10/unit.bytes)/10, 'f', -1, 64) + " " + unit.name
+		}
+	}
+	return fmt.Sprintf("%d bytes", bytes)
+}
@@ -0,0 +1,76 @@
+// generator/sizes_test.go
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func sizesModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	apiFunctions := []models.APIFunction{
+		{Command: "items.Get", Description: "Returns an item."},
+		{Command: "items.List", Description: "Lists every item.", Sizes: models.Sizes{TypicalResponse: 5 << 20}},
+		{Command: "items.Upload", Description: "Uploads an item.", Sizes: models.Sizes{MaxRequest: 1536}},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0", Sizes: models.Sizes{MaxRequest: 1 << 20}}
+	return apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo
+}
+
+func TestSizeNote(t *testing.T) {
+	apiFunctions, structs, projectInfo := sizesModel()
+	assertGolden(t, "sizes", generateString(t, apiFunctions, structs, projectInfo, Options{}))
+
+	// Without sizes, the commands get no note
+	projectInfo.Sizes = models.Sizes{}
+	assertGolden(t, "sizes_unset", generateString(t, apiFunctions[:1], structs, projectInfo, Options{}))
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		300:       "300 bytes",
+		1 << 10:   "1 KB",
+		1536:      "1.5 KB",
+		1 << 20:   "1 MB",
+		5<<20 + 1: "5 MB",
+	}
+	for bytes, want := range tests {
+		if got := formatSize(bytes); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
+
+func TestJSONSizes(t *testing.T) {
+	apiFunctions, structs, projectInfo := sizesModel()
+	outFile := filepath.Join(t.TempDir(), "api.json")
+	if err := GenerateJSON(apiFunctions, structs, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateJSON returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	var doc Document
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+
+	// Commands carry the project defaults where they do not override them
+	want := map[string]models.Sizes{
+		"items.Get":    {MaxRequest: 1 << 20},
+		"items.List":   {MaxRequest: 1 << 20, TypicalResponse: 5 << 20},
+		"items.Upload": {MaxRequest: 1536},
+	}
+	for _, command := range doc.Commands {
+		if command.Sizes != want[command.Command] {
+			t.Errorf("Expected %s to have sizes %+v, got %+v", command.Command, want[command.Command], command.Sizes)
+		}
+	}
+	if doc.Project.Sizes != projectInfo.Sizes {
+		t.Errorf("Expected project sizes %+v, got %+v", projectInfo.Sizes, doc.Project.Sizes)
+	}
+}
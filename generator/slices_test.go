@@ -0,0 +1,36 @@
+// generator/slices_test.go
+package generator
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestSliceResults(t *testing.T) {
+	key := models.StructKey{Package: "rpc", Name: "ReportItem"}
+	structs := map[models.StructKey]models.StructDefinition{
+		key: {Name: "ReportItem", Description: "ReportItem is a line of a report.", Fields: []models.StructField{
+			{Name: "Name", Type: "string", JSONName: "name", Description: "Name of the line"},
+		}},
+	}
+	var apiFunctions []models.APIFunction
+	for command, typ := range map[string]string{
+		"reports.List":     "[]ReportItem",
+		"reports.Pointers": "[]*ReportItem",
+		"reports.Grid":     "[][]ReportItem",
+		"reports.Top":      "[3]ReportItem",
+		"reports.One":      "*ReportItem",
+	} {
+		apiFunctions = append(apiFunctions, models.APIFunction{
+			Command:     command,
+			Description: "Lists report items.",
+			PackageName: "rpc",
+			Results:     []models.APIReturn{{Name: "result", Type: typ, Description: "report items"}},
+		})
+	}
+	got := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, Options{})
+
+	// Every command documents the element struct, and a pointer is not an array
+	assertGolden(t, "slices", got)
+}
@@ -0,0 +1,71 @@
+// generator/sourcelinks.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// Source link templates of the hosts recognized in @repository. HEAD is the default branch
+// of the repository.
+const (
+	githubLinkTemplate = "{repository}/blob/HEAD/{path}#L{line}"
+	gitlabLinkTemplate = "{repository}/-/blob/HEAD/{path}#L{line}"
+)
+
+// writeSourceNote writes where apiFunc is defined under its description: a link to the line
+// when the repository has a known or configured URL pattern, otherwise the plain file and
+// line with ShowSource. Commands without a source path, such as commands built by hand,
+// get no note.
+func writeSourceNote(w io.Writer, apiFunc models.APIFunction, projectInfo models.ProjectInfo, opts Options) {
+	if apiFunc.SourcePath == "" {
+		return
+	}
+	if link := sourceLink(apiFunc, projectInfo.Repository, opts.LinkTemplate); link != "" {
+		fmt.Fprintf(w, "Defined in [%s#L%d](%s)\n\n", apiFunc.SourcePath, apiFunc.SourceLine, link)
+		return
+	}
+	if opts.ShowSource {
+		fmt.Fprintf(w, "Defined in `%s:%d`\n\n", apiFunc.SourcePath, apiFunc.SourceLine)
+	}
+}
+
+// sourceLink returns the URL of the line apiFunc is defined at. linkTemplate replaces
+// {repository}, {path} and {line}, and defaults to the pattern of GitHub or GitLab when the
+// repository is hosted there. It returns "" when there is no pattern.
+func sourceLink(apiFunc models.APIFunction, repository string, linkTemplate string) string {
+	repository = strings.TrimSuffix(strings.TrimRight(strings.TrimSpace(repository), "/"), ".git")
+	if linkTemplate == "" {
+		linkTemplate = hostLinkTemplate(repository)
+	}
+	if linkTemplate == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{repository}", repository,
+		"{path}", apiFunc.SourcePath,
+		"{line}", strconv.Itoa(apiFunc.SourceLine),
+	).Replace(linkTemplate)
+}
+
+// hostLinkTemplate returns the source link template of the host of a repository URL, or ""
+// for hosts without a known pattern.
+func hostLinkTemplate(repository string) string {
+	parsed, err := url.Parse(repository)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case host == "github.com" || strings.HasSuffix(host, ".github.com"):
+		return githubLinkTemplate
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return gitlabLinkTemplate
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+// generator/sourcelinks_test.go
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestSourceNote(t *testing.T) {
+	apiFunctions := []models.APIFunction{{
+		Command:     "users.Get",
+		Description: "Gets a user.",
+		PackageName: "main",
+		SourceFile:  "/src/api/handlers/users.go",
+		SourceLine:  42,
+		SourcePath:  "handlers/users.go",
+	}}
+	tests := []struct {
+		name       string
+		repository string
+		opts       Options
+		want       string
+	}{
+		{"github", "https://github.com/acme/api", Options{}, "Defined in [handlers/users.go#L42](https://github.com/acme/api/blob/HEAD/handlers/users.go#L42)\n\n"},
+		{"github with .git", "https://github.com/acme/api.git/", Options{}, "Defined in [handlers/users.go#L42](https://github.com/acme/api/blob/HEAD/handlers/users.go#L42)\n\n"},
+		{"gitlab", "https://gitlab.com/acme/api", Options{}, "Defined in [handlers/users.go#L42](https://gitlab.com/acme/api/-/blob/HEAD/handlers/users.go#L42)\n\n"},
+		{"template", "https://bitbucket.org/acme/api", Options{LinkTemplate: "{repository}/src/main/{path}#lines-{line}"}, "Defined in [handlers/users.go#L42](https://bitbucket.org/acme/api/src/main/handlers/users.go#lines-42)\n\n"},
+		{"unknown host", "https://git.example.com/acme/api", Options{}, ""},
+		{"no repository", "", Options{}, ""},
+		{"show source", "", Options{ShowSource: true}, "Defined in `handlers/users.go:42`\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0", Repository: tt.repository}
+			got := generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, tt.opts)
+			if tt.want == "" {
+				if strings.Contains(got, "Defined in") {
+					t.Errorf("Expected no source note, got:\n%s", got)
+				}
+				return
+			}
+			if !strings.Contains(got, "Gets a user.\n\n"+tt.want) {
+				t.Errorf("Expected output to contain %q under the description, got:\n%s", tt.want, got)
+			}
+		})
+	}
+
+	// Commands without a source path, built by hand, have nothing to link to
+	apiFunctions[0].SourcePath = ""
+	got := generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, models.ProjectInfo{Title: "Test API", Version: "1.0.0", Repository: "https://github.com/acme/api"}, Options{ShowSource: true})
+	if strings.Contains(got, "Defined in") {
+		t.Errorf("Expected no source note, got:\n%s", got)
+	}
+}
@@ -0,0 +1,310 @@
+// generator/split.go
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// Names of the fixed files written in split mode.
+const (
+	splitIndexFile    = "index.md"
+	splitManifestFile = "manifest.json"
+	splitTypesFile    = "types.md"
+)
+
+// Manifest records which file documents each command in split mode, and the file and anchor
+// where each struct is first documented, so links can always be resolved.
+type Manifest struct {
+	Index    string            `json:"index"`
+	Commands map[string]string `json:"commands"`
+	Structs  map[string]string `json:"structs"`
+	// FormerNames maps the former names of renamed commands to the file and anchor documenting them.
+	FormerNames map[string]string `json:"formerNames,omitempty"`
+	// Types is the file holding the complete definitions of truncated structs, if any.
+	Types string `json:"types,omitempty"`
+	// TypeFiles are the files holding the complete definitions of truncated structs when the
+	// appendix is split, and Appendix maps each of these structs to its file and anchor.
+	TypeFiles []string          `json:"typeFiles,omitempty"`
+	Appendix  map[string]string `json:"appendix,omitempty"`
+	// UsedBy maps each struct to the commands documenting it, the reverse of the struct
+	// references of the commands.
+	UsedBy map[string][]StructUse `json:"usedBy,omitempty"`
+	// Errors maps the names of the @errorcatalog errors to their file and anchor.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// GenerateSplitDocumentation writes one Markdown file per command into outDir, an index.md
+// with the project header linking to them, and a manifest.json mapping commands to files.
+func GenerateSplitDocumentation(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outDir string, opts Options) error {
+	projectInfo, err := prepare(apiFunctions, structDefinitions, projectInfo, opts)
+	if err != nil {
+		return err
+	}
+	opts.TypesAppendix = false
+	namer := opts.FileNamer
+	if namer == nil {
+		if namer, err = fileNamerFor(opts.FileNameScheme); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	apiFunctions = sortCommands(apiFunctions)
+	opts.indexFile = splitIndexFile
+
+	// Existing files are only replaced once every file is written
+	output := &stagedFiles{noClobber: opts.NoClobber, wrap: opts.Wrap, alignTables: opts.AlignTables, headingOffset: opts.HeadingOffset}
+	defer output.discard()
+	if opts.Partial {
+		return patchSplitDocumentation(apiFunctions, structDefinitions, projectInfo, outDir, namer, output, opts)
+	}
+
+	// The appendix files are assigned up front, so command pages can link into them
+	appendix := newTypeAppendix(opts.MaxFields, splitTypesFile)
+	reserved := []string{splitIndexFile, splitManifestFile, splitTypesFile}
+	if opts.AppendixSplit != "" {
+		candidates := appendixCandidates(apiFunctions, structDefinitions, projectInfo.ResultEnvelope, opts)
+		appendix.files = assignAppendixFiles(candidates, structDefinitions, opts.AppendixSplit, opts.AppendixLines)
+		reserved = append(reserved, appendixFileNames(appendix.files)...)
+	}
+
+	// Assign file names in command order so collision suffixes are deterministic
+	files := newFileNames(namer, reserved...)
+	manifest := Manifest{
+		Index:       splitIndexFile,
+		Commands:    make(map[string]string),
+		Structs:     make(map[string]string),
+		FormerNames: make(map[string]string),
+		UsedBy:      make(map[string][]StructUse),
+	}
+	for _, apiFunc := range apiFunctions {
+		manifest.Commands[apiFunc.Command] = files.assign(apiFunc.Command, ".md")
+		for _, former := range apiFunc.FormerNames {
+			manifest.FormerNames[former] = manifest.Commands[apiFunc.Command] + "#" + slugify(former)
+		}
+	}
+
+	usage := collectStructUsage(apiFunctions, structDefinitions, opts)
+	for key, uses := range usage {
+		manifest.UsedBy[structHeading(key, structDefinitions[key])] = uses
+	}
+
+	indexAnchors := newAnchorRegistry()
+	err = output.write(filepath.Join(outDir, splitIndexFile), func(writer *bufio.Writer) error {
+		if err := writeHeader(writer, apiFunctions, projectInfo, indexAnchors, opts); err != nil {
+			return err
+		}
+		indexAnchors.heading(writer, 2, "Commands")
+		for _, apiFunc := range apiFunctions {
+			fmt.Fprintf(writer, "- [%s](%s)%s\n", apiFunc.Command, manifest.Commands[apiFunc.Command], commandMarker(apiFunc))
+		}
+		fmt.Fprintf(writer, "\n")
+		writeResultEnvelope(writer, projectInfo.ResultEnvelope, structDefinitions, indexAnchors, appendix, opts)
+		writeGlobalErrors(writer, projectInfo, indexAnchors, opts)
+		writeErrorCatalog(writer, projectInfo.ErrorCatalog, projectInfo.GlobalErrors, apiFunctions, manifest.Commands, indexAnchors, opts)
+		writeCopyright(writer, projectInfo)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for key, anchor := range indexAnchors.structs {
+		manifest.Structs[structHeading(key, structDefinitions[key])] = splitIndexFile + "#" + anchor
+	}
+	for _, entry := range projectInfo.ErrorCatalog {
+		if manifest.Errors == nil {
+			manifest.Errors = make(map[string]string)
+		}
+		manifest.Errors[entry.Name] = splitIndexFile + "#" + errorAnchor(entry.Code)
+	}
+
+	for _, apiFunc := range apiFunctions {
+		fileName := manifest.Commands[apiFunc.Command]
+		anchors := newAnchorRegistry()
+		err := output.write(filepath.Join(outDir, fileName), func(writer *bufio.Writer) error {
+			return writeCommand(writer, apiFunc, structDefinitions, projectInfo, opts, anchors, appendix)
+		})
+		if err != nil {
+			return err
+		}
+
+		// Structs shared by several commands point at the first page documenting them
+		for key, anchor := range anchors.structs {
+			name := structHeading(key, structDefinitions[key])
+			if _, exists := manifest.Structs[name]; !exists {
+				manifest.Structs[name] = fileName + "#" + anchor
+			}
+		}
+	}
+
+	// Truncated structs are listed complete in their own files
+	appendixKeys := appendix.fileKeys()
+	appendixFiles := make([]string, 0, len(appendixKeys))
+	for fileName := range appendixKeys {
+		appendixFiles = append(appendixFiles, fileName)
+	}
+	sort.Strings(appendixFiles)
+	for _, fileName := range appendixFiles {
+		keys := appendixKeys[fileName]
+		if opts.AppendixSplit == "" {
+			manifest.Types = fileName
+		} else {
+			if manifest.Appendix == nil {
+				manifest.Appendix = make(map[string]string)
+			}
+			manifest.TypeFiles = append(manifest.TypeFiles, fileName)
+			for _, key := range keys {
+				manifest.Appendix[structHeading(key, structDefinitions[key])] = appendix.link(key, structDefinitions[key])
+			}
+		}
+		err := output.write(filepath.Join(outDir, fileName), func(writer *bufio.Writer) error {
+			writeTypeAppendix(writer, keys, structDefinitions, usage, manifest.Commands, newAnchorRegistry(), opts)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := output.writeManifest(filepath.Join(outDir, splitManifestFile), manifest); err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// patchSplitDocumentation rewrites the files of the given commands in split output written by
+// an earlier full run, and replaces their entries in its manifest. The index and the types
+// file are kept, so a command added since the full run is not listed in the index.
+func patchSplitDocumentation(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, outDir string, namer FileNamer, output *stagedFiles, opts Options) error {
+	manifestPath := filepath.Join(outDir, splitManifestFile)
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("partial regeneration needs the manifest of a full run: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return fmt.Errorf("failed to read manifest: %v", err)
+	}
+	if manifest.Commands == nil {
+		manifest.Commands = make(map[string]string)
+	}
+	if manifest.Structs == nil {
+		manifest.Structs = make(map[string]string)
+	}
+	if manifest.FormerNames == nil {
+		manifest.FormerNames = make(map[string]string)
+	}
+	if manifest.UsedBy == nil {
+		manifest.UsedBy = make(map[string][]StructUse)
+	}
+
+	// New commands get a file name not used by any command of the full run
+	reserved := []string{splitIndexFile, splitManifestFile, splitTypesFile}
+	reserved = append(reserved, manifest.TypeFiles...)
+	for _, fileName := range manifest.Commands {
+		reserved = append(reserved, fileName)
+	}
+	files := newFileNames(namer, reserved...)
+	rewritten := make(map[string]bool)
+	for _, apiFunc := range apiFunctions {
+		fileName, exists := manifest.Commands[apiFunc.Command]
+		if !exists {
+			fileName = files.assign(apiFunc.Command, ".md")
+			manifest.Commands[apiFunc.Command] = fileName
+			opts.warn(Warning{Message: fmt.Sprintf("command '%s' is not in %s, it is missing from the index until the next full run", apiFunc.Command, splitIndexFile)})
+		}
+		rewritten[fileName] = true
+	}
+
+	// Entries pointing into the rewritten files are recreated below
+	for _, entries := range []map[string]string{manifest.Structs, manifest.FormerNames} {
+		for name, target := range entries {
+			if fileName, _, _ := strings.Cut(target, "#"); rewritten[fileName] {
+				delete(entries, name)
+			}
+		}
+	}
+
+	// Uses by the regenerated commands are replaced, uses by the other commands are kept
+	selected := make(map[string]bool)
+	for _, apiFunc := range apiFunctions {
+		selected[apiFunc.Command] = true
+	}
+	for name, uses := range manifest.UsedBy {
+		kept := uses[:0]
+		for _, use := range uses {
+			if !selected[use.Command] {
+				kept = append(kept, use)
+			}
+		}
+		manifest.UsedBy[name] = kept
+	}
+	for key, uses := range collectStructUsage(apiFunctions, structDefinitions, opts) {
+		name := structHeading(key, structDefinitions[key])
+		manifest.UsedBy[name] = append(manifest.UsedBy[name], uses...)
+	}
+	for name, uses := range manifest.UsedBy {
+		if len(uses) == 0 {
+			delete(manifest.UsedBy, name)
+			continue
+		}
+		sort.SliceStable(uses, func(i, j int) bool { return uses[i].Command < uses[j].Command })
+	}
+
+	// Truncated structs keep linking to the appendix files of the full run
+	appendix := newTypeAppendix(opts.MaxFields, splitTypesFile)
+	appendix.files = make(map[models.StructKey]string)
+	for key, structDef := range structDefinitions {
+		if target, exists := manifest.Appendix[structHeading(key, structDef)]; exists {
+			appendix.files[key], _, _ = strings.Cut(target, "#")
+		}
+	}
+	for _, apiFunc := range apiFunctions {
+		fileName := manifest.Commands[apiFunc.Command]
+		for _, former := range apiFunc.FormerNames {
+			manifest.FormerNames[former] = fileName + "#" + slugify(former)
+		}
+		anchors := newAnchorRegistry()
+		err := output.write(filepath.Join(outDir, fileName), func(writer *bufio.Writer) error {
+			return writeCommand(writer, apiFunc, structDefinitions, projectInfo, opts, anchors, appendix)
+		})
+		if err != nil {
+			return err
+		}
+		for key, anchor := range anchors.structs {
+			name := structHeading(key, structDefinitions[key])
+			if _, exists := manifest.Structs[name]; !exists {
+				manifest.Structs[name] = fileName + "#" + anchor
+			}
+		}
+	}
+	for _, key := range appendix.fileKeys()[splitTypesFile] {
+		if manifest.Types == "" {
+			opts.warn(Warning{Message: fmt.Sprintf("truncated struct '%s' links to %s, which is only written by a full run", structHeading(key, structDefinitions[key]), splitTypesFile)})
+		}
+	}
+
+	if err := output.writeManifest(manifestPath, manifest); err != nil {
+		return err
+	}
+	if err := output.commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
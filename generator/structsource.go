@@ -0,0 +1,112 @@
+// generator/structsource.go
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// writeStructSource writes the Go definition of a struct, reconstructed from its model, in a
+// collapsed code block. Hidden fields are left out like in the fields table, and the fields
+// of an instantiated generic struct have their type parameters substituted. A definition that
+// fails to format is left out with a warning.
+func writeStructSource(writer io.Writer, key models.StructKey, structDef models.StructDefinition, opts Options) {
+	source, err := structSource(structDef)
+	if err != nil {
+		opts.warn(Warning{
+			File:    structDef.SourceFile,
+			Line:    structDef.SourceLine,
+			Message: fmt.Sprintf("Go definition of struct '%s.%s' left out: %v", key.Package, key.Name, err),
+		})
+		return
+	}
+	fmt.Fprintf(writer, "<details>\n<summary>Go definition</summary>\n\n```go\n%s```\n\n</details>\n\n", source)
+}
+
+// structSource returns the gofmt-formatted declaration of a struct. An instantiation such as
+// "Page[User]" is declared under its base name, with a comment naming the instantiation, and
+// an anonymous struct such as "Order.Details" under the name of its field.
+func structSource(structDef models.StructDefinition) (string, error) {
+	var b strings.Builder
+	name := structDef.Name
+	if base, _, generic := strings.Cut(name, "["); generic {
+		fmt.Fprintf(&b, "// %s, with its type parameters substituted.\n", name)
+		name = base
+	}
+	if dot := strings.LastIndex(name, "."); dot != -1 {
+		fmt.Fprintf(&b, "// %s, declared inline.\n", name)
+		name = name[dot+1:]
+	}
+	writeSourceComment(&b, structDef.Description)
+	fmt.Fprintf(&b, "type %s", name)
+	if len(structDef.TypeParams) > 0 {
+		params := make([]string, len(structDef.TypeParams))
+		for i, param := range structDef.TypeParams {
+			params[i] = param.Name + " " + param.Constraint
+		}
+		fmt.Fprintf(&b, "[%s]", strings.Join(params, ", "))
+	}
+	fmt.Fprintf(&b, " struct {\n")
+	for _, field := range structDef.Fields {
+		writeSourceComment(&b, field.Description)
+		// Embedded fields are named after their type
+		if field.Name == field.Type {
+			fmt.Fprintf(&b, "%s %s\n", field.Type, sourceTag(field))
+		} else {
+			fmt.Fprintf(&b, "%s %s %s\n", field.Name, sourceType(field), sourceTag(field))
+		}
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// writeSourceComment writes a description as line comments.
+func writeSourceComment(b *strings.Builder, description string) {
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(description, "\n") {
+		fmt.Fprintf(b, "// %s\n", strings.TrimSpace(line))
+	}
+}
+
+// sourceTag returns the struct tag of a field, reconstructed from its JSON name, units and
+// explicit format.
+func sourceTag(field models.StructField) string {
+	jsonName := field.JSONName
+	switch {
+	case field.Excluded:
+		jsonName = "-"
+	case field.Omitempty:
+		jsonName += ",omitempty"
+	}
+	tag := "json:\"" + jsonName + "\""
+	if field.Units != "" {
+		tag += " units:\"" + field.Units + "\""
+	}
+	if field.Format != "" && field.Format != utils.ImpliedFormat(field.Type) {
+		tag += " format:\"" + field.Format + "\""
+	}
+	return "`" + tag + "`"
+}
+
+// sourceType returns the type of a field in the declaration of its struct. Anonymous struct
+// types are declared on their own, under the name of their field.
+func sourceType(field models.StructField) string {
+	held := field.TypeRef.Held()
+	if held == nil || held.Kind != models.TypeStruct || !strings.HasSuffix(field.Type, utils.InlineStructType) {
+		return field.Type
+	}
+	name := held.Struct.Name[strings.LastIndex(held.Struct.Name, ".")+1:]
+	return strings.TrimSuffix(field.Type, utils.InlineStructType) + name
+}
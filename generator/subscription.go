@@ -0,0 +1,109 @@
+// generator/subscription.go
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// writeSubscription writes the Subscription section of a subscribe command: how the
+// notifications are pushed, the payload and its structs, and an example of the request, its
+// response and one pushed notification.
+func writeSubscription(writer io.Writer, apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, projectInfo models.ProjectInfo, printed map[models.StructKey]bool, anchors *anchorRegistry, appendix *typeAppendix, opts Options) error {
+	subscription := apiFunc.Subscription
+	if subscription == nil {
+		return nil
+	}
+
+	anchors.heading(writer, 3, "Subscription:")
+	fmt.Fprintf(writer, "This command opens a subscription. Its result is the subscription id, after which the server pushes `%s` notifications to the client. ", subscription.Method)
+	fmt.Fprintf(writer, "The `params` of each notification hold the subscription id as `subscription` and the payload as `result`. Notifications are not answered.\n\n")
+
+	payload := subscription.Payload
+	fmt.Fprintf(writer, "| Name | Type | Description |\n")
+	fmt.Fprintf(writer, "|------|------|-------------|\n")
+	fmt.Fprintf(writer, "| payload | %s | %s |\n\n", payload.Type, cellDescription(payload.Description, opts))
+	if key, found := payloadStruct(apiFunc, structDefinitions); found {
+		printStructDefinitions(writer, []models.StructKey{key}, structDefinitions, printed, anchors, appendix, opts)
+	}
+
+	examples := []struct {
+		label string
+		value jsonObject
+	}{
+		{"Request", minimalRequest(apiFunc, opts)},
+		{"Response", subscribeResponse(apiFunc, projectInfo.ResultEnvelope, opts)},
+		{"Notification", subscriptionNotification(apiFunc, structDefinitions)},
+	}
+	for _, example := range examples {
+		body, err := json.MarshalIndent(example.value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to build subscription example for %s: %v", apiFunc.Command, err)
+		}
+		fmt.Fprintf(writer, "**%s:**\n\n```json\n%s\n```\n\n", example.label, body)
+	}
+	return nil
+}
+
+// payloadStruct finds the struct documenting the notification payload of a subscribe command.
+func payloadStruct(apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition) (models.StructKey, bool) {
+	payload := apiFunc.Subscription.Payload
+	held := typeRefOf(payload.TypeRef, payload.Type, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions).Held()
+	if held == nil || held.Kind != models.TypeStruct {
+		return models.StructKey{}, false
+	}
+	return held.Struct, true
+}
+
+// subscriptionID returns the placeholder subscription id returned by a subscribe command.
+func subscriptionID(apiFunc models.APIFunction) interface{} {
+	if len(apiFunc.Results) == 0 {
+		return ""
+	}
+	return placeholderOf(apiFunc.Results[0].TypeRef, apiFunc.Results[0].Type)
+}
+
+// subscribeResponse builds the response to the subscribe request of apiFunc, which returns
+// the subscription id.
+func subscribeResponse(apiFunc models.APIFunction, envelope models.ResultEnvelope, opts Options) jsonObject {
+	return responseEnvelope(apiFunc, subscriptionID(apiFunc), envelope, opts)
+}
+
+// subscriptionNotification builds a notification pushed for the subscription opened by
+// apiFunc. A struct payload lists its fields with placeholder values. JSON-RPC 1.0
+// notifications have a null id.
+func subscriptionNotification(apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition) jsonObject {
+	payload := apiFunc.Subscription.Payload
+	ref := typeRefOf(payload.TypeRef, payload.Type, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
+	value := placeholderOf(ref, payload.Type)
+	if direct := ref.Deref(); direct != nil && direct.Kind == models.TypeStruct {
+		fields := jsonObject{}
+		for _, field := range promotedFields(direct.Struct, structDefinitions[direct.Struct], structDefinitions) {
+			if field.Excluded {
+				continue
+			}
+			fields = append(fields, jsonField{Key: field.JSONName, Value: placeholderOf(field.TypeRef, field.Type)})
+		}
+		value = fields
+	}
+
+	version := envelopeVersion(apiFunc)
+	var notification jsonObject
+	if version != "1.0" {
+		notification = append(notification, jsonField{Key: "jsonrpc", Value: version})
+	}
+	notification = append(notification,
+		jsonField{Key: "method", Value: apiFunc.Subscription.Method},
+		jsonField{Key: "params", Value: jsonObject{
+			{Key: "subscription", Value: subscriptionID(apiFunc)},
+			{Key: "result", Value: value},
+		}},
+	)
+	if version == "1.0" {
+		notification = append(notification, jsonField{Key: "id", Value: nil})
+	}
+	return notification
+}
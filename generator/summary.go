@@ -0,0 +1,37 @@
+// generator/summary.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// writeQuickSummary writes the one-line "Requires" and "Returns" summaries of a command.
+// It receives the same parameters as the table, so both always agree.
+// The result links to the struct heading that is printed inline later in the same section.
+func writeQuickSummary(w io.Writer, apiFunc models.APIFunction, parameters []models.APIParameter, structDefinitions map[models.StructKey]models.StructDefinition, anchors *anchorRegistry) {
+	var required []string
+	for _, param := range parameters {
+		if param.Required {
+			required = append(required, "`"+param.Name+"`")
+		}
+	}
+	if len(required) > 0 {
+		fmt.Fprintf(w, "**Requires:** %s\n\n", strings.Join(required, ", "))
+	}
+
+	var returns []string
+	for _, result := range apiFunc.Results {
+		entry := "`" + result.Type + "`"
+		if key, found := findResultStruct(apiFunc, result, structDefinitions); found {
+			entry = fmt.Sprintf("[%s](#%s)", entry, anchors.peek(structHeading(key, structDefinitions[key])))
+		}
+		returns = append(returns, entry)
+	}
+	if len(returns) > 0 {
+		fmt.Fprintf(w, "**Returns:** %s\n\n", strings.Join(returns, ", "))
+	}
+}
@@ -0,0 +1,75 @@
+// generator/tags.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// untaggedSection is the section of the commands without @Tag, in projects where other
+// commands have one.
+const untaggedSection = "General"
+
+// tagSection is a section of the Markdown documentation grouping the commands of a tag.
+type tagSection struct {
+	// name is the tag, empty for the single section of a project without tags, which has no
+	// heading of its own.
+	name string
+	// anchor is the anchor of the heading of the section.
+	anchor   string
+	commands []models.APIFunction
+	// commandAnchors holds the anchor of each command, in the order of commands.
+	commandAnchors []string
+}
+
+// groupByTag groups sorted commands into sections by their first @Tag, sorted by tag with
+// the General section of untagged commands last. Commands keep their order within a section.
+// A project without tags has a single unnamed section, so its documentation is not nested.
+func groupByTag(apiFunctions []models.APIFunction) []tagSection {
+	tagged := false
+	for _, apiFunc := range apiFunctions {
+		if len(apiFunc.Tags) > 0 {
+			tagged = true
+			break
+		}
+	}
+	if !tagged {
+		return []tagSection{{commands: apiFunctions}}
+	}
+
+	indexes := make(map[string]int)
+	var sections []tagSection
+	for _, apiFunc := range apiFunctions {
+		name := untaggedSection
+		if len(apiFunc.Tags) > 0 {
+			name = apiFunc.Tags[0]
+		}
+		index, exists := indexes[name]
+		if !exists {
+			index = len(sections)
+			indexes[name] = index
+			sections = append(sections, tagSection{name: name})
+		}
+		sections[index].commands = append(sections[index].commands, apiFunc)
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		if (sections[i].name == untaggedSection) != (sections[j].name == untaggedSection) {
+			return sections[j].name == untaggedSection
+		}
+		return sections[i].name < sections[j].name
+	})
+	return sections
+}
+
+// writeCommandTags lists the tags of a command with several tags, which is only documented in
+// the section of its first tag.
+func writeCommandTags(w io.Writer, tags []string) {
+	if len(tags) < 2 {
+		return
+	}
+	fmt.Fprintf(w, "**Tags:** %s (documented under %s)\n\n", strings.Join(tags, ", "), tags[0])
+}
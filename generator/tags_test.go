@@ -0,0 +1,59 @@
+// generator/tags_test.go
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestTagSections(t *testing.T) {
+	userKey := models.StructKey{Package: "rpc", Name: "User"}
+	apiFunctions := []models.APIFunction{
+		{Command: "ping", Description: "Check the server."},
+		{Command: "users.Get", Description: "Get a user.", Tags: []string{"users"}, PackageName: "rpc",
+			Results: []models.APIReturn{{Name: "user", Type: "User", Description: "The user."}}},
+		{Command: "billing.Charge", Description: "Charge a user.", Tags: []string{"billing", "users"}},
+		{Command: "billing.Refund", Description: "Refund a charge.", Tags: []string{"billing"}},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		userKey: {Name: "User", Fields: []models.StructField{{Name: "ID", Type: "int", JSONName: "id"}}},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	assertGolden(t, "tags", got)
+
+	// Sections are sorted by tag with General last, and nested in the table of contents
+	want := "## Table of Contents\n\n" +
+		"- [billing](#billing)\n" +
+		"  - [billing.Charge](#billingcharge)\n" +
+		"  - [billing.Refund](#billingrefund)\n" +
+		"- [users](#users)\n" +
+		"  - [users.Get](#usersget)\n" +
+		"- [General](#general)\n" +
+		"  - [ping](#ping)\n\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Expected table of contents:\n%s\ngot:\n%s", want, got)
+	}
+	// Commands with several tags are only documented under the first one
+	if count := strings.Count(got, "\n### billing.Charge\n"); count != 1 {
+		t.Errorf("Expected billing.Charge to be documented once, got %d", count)
+	}
+	if !strings.Contains(got, "**Tags:** billing, users (documented under billing)\n") {
+		t.Errorf("Expected the tags of billing.Charge to be listed, got:\n%s", got)
+	}
+	// Headings inside a section move down a level
+	if !strings.Contains(got, "\n#### Results:\n") || !strings.Contains(got, "\n##### rpc.User\n") {
+		t.Errorf("Expected nested command headings, got:\n%s", got)
+	}
+
+	// Projects without tags are not nested
+	for i := range apiFunctions {
+		apiFunctions[i].Tags = nil
+	}
+	got = generateString(t, apiFunctions, structs, projectInfo, Options{})
+	if strings.Contains(got, untaggedSection) || !strings.Contains(got, "\n## ping\n") {
+		t.Errorf("Expected flat output without tags, got:\n%s", got)
+	}
+}
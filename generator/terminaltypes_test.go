@@ -0,0 +1,57 @@
+// generator/terminaltypes_test.go
+package generator
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestTerminalTypes(t *testing.T) {
+	invoice := models.StructKey{Package: "rpc", Name: "Invoice"}
+	money := models.StructKey{Package: "rpc", Name: "Money"}
+	structs := map[models.StructKey]models.StructDefinition{
+		invoice: {Name: "Invoice", Fields: []models.StructField{
+			{Name: "Total", Type: "Money", JSONName: "total", Description: "Amount due",
+				TypeRef: &models.TypeRef{Kind: models.TypeStruct, Package: "rpc", Name: "Money", Struct: money}},
+			{Name: "Issued", Type: "time.Time", JSONName: "issued", Description: "Issue time"},
+			{Name: "Extra", Type: "json.RawMessage", JSONName: "extra", Description: "Client data"},
+		}},
+		// Money encodes itself as a string
+		money: {Name: "Money", Fields: []models.StructField{
+			{Name: "Cents", Type: "int64", JSONName: "cents", Description: "Amount in cents"},
+		}},
+	}
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "invoices.Get",
+			Description: "Returns an invoice.",
+			PackageName: "rpc",
+			Parameters:  []models.APIParameter{{Name: "invoice", Type: "Invoice", Description: "The invoice", Required: true}},
+			Results:     []models.APIReturn{{Name: "result", Type: "Invoice", Description: "The invoice"}},
+		},
+		{
+			Command:     "invoices.Total",
+			Description: "Returns the total of an invoice.",
+			PackageName: "rpc",
+			Results:     []models.APIReturn{{Name: "result", Type: "Money", Description: "The total"}},
+		},
+	}
+	var warnings []Warning
+	opts := Options{
+		TerminalTypes: map[string]string{"rpc.Money": "string (decimal amount)"},
+		Warn:          func(warning Warning) { warnings = append(warnings, warning) },
+	}
+	got := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, opts)
+
+	// The terminal struct Money is not documented
+	assertGolden(t, "terminal_types", got)
+	if len(warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	// Flattening stops at terminal types
+	opts.FlattenParams = true
+	flattened := generateString(t, apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}, opts)
+	assertGolden(t, "terminal_types_flattened", flattened)
+}
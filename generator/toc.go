@@ -0,0 +1,33 @@
+// generator/toc.go
+package generator
+
+import (
+	"fmt"
+	"io"
+)
+
+// tocHeading is the heading of the table of contents.
+const tocHeading = "Table of Contents"
+
+// writeTOC writes the table of contents of the Markdown documentation: a link per command to
+// the anchor of its heading, nested under a link per tag section in projects with @Tag.
+// Commands link to the anchor of their own section, so commands sharing a name link to their
+// own heading. The Common Errors section, written before the commands, is linked first when
+// globalErrorsAnchor is not empty.
+func writeTOC(writer io.Writer, sections []tagSection, globalErrorsAnchor string) {
+	fmt.Fprintf(writer, "## %s\n\n", tocHeading)
+	if globalErrorsAnchor != "" {
+		fmt.Fprintf(writer, "- [%s](#%s)\n", globalErrorsHeading, globalErrorsAnchor)
+	}
+	for _, section := range sections {
+		indent := ""
+		if section.name != "" {
+			fmt.Fprintf(writer, "- [%s](#%s)\n", section.name, section.anchor)
+			indent = "  "
+		}
+		for i, apiFunc := range section.commands {
+			fmt.Fprintf(writer, "%s- [%s](#%s)%s\n", indent, apiFunc.Command, section.commandAnchors[i], commandMarker(apiFunc))
+		}
+	}
+	fmt.Fprintf(writer, "\n")
+}
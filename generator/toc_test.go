@@ -0,0 +1,70 @@
+// generator/toc_test.go
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestTableOfContents(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{Command: "stats.GetAllMetrics", Description: "Get metrics."},
+		{Command: "user.Get", Description: "Get a user.", PackageName: "rpc"},
+		{Command: "user.Get", Description: "Get a user of the admin API.", PackageName: "admin"},
+		{Command: "user.Delete", Description: "Delete a user.", Deprecated: true},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+	outFile := filepath.Join(t.TempDir(), "API.md")
+	if err := GenerateDocumentation(apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, outFile, Options{}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	want := "## Table of Contents\n\n" +
+		"- [stats.GetAllMetrics](#statsgetallmetrics)\n" +
+		"- [user.Delete](#userdelete) _(deprecated)_\n" +
+		"- [user.Get](#userget)\n" +
+		"- [user.Get](#userget-1)\n\n"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("Expected table of contents:\n%s\ngot:\n%s", want, content)
+	}
+	// Each link resolves to a distinct command heading
+	if got := strings.Count(string(content), "\n## user.Get\n"); got != 2 {
+		t.Errorf("Expected 2 user.Get headings, got %d", got)
+	}
+
+	if err := GenerateDocumentation(apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, outFile, Options{NoTOC: true}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	content, err = os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if strings.Contains(string(content), tocHeading) {
+		t.Errorf("Expected no table of contents with NoTOC, got:\n%s", content)
+	}
+}
+
+func TestTableOfContentsAfterPreamble(t *testing.T) {
+	// Commands named like the title and the preamble heading must not share their anchors
+	apiFunctions := []models.APIFunction{
+		{Command: "json-rpc-20-specification", Description: "Named like the preamble."},
+		{Command: "test-api", Description: "Named like the title."},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+	got := generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, Options{IncludeRFC: true})
+
+	want := "## Table of Contents\n\n" +
+		"- [json-rpc-20-specification](#json-rpc-20-specification-1)\n" +
+		"- [test-api](#test-api-1)\n\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Expected table of contents:\n%s\ngot:\n%s", want, got)
+	}
+}
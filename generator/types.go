@@ -0,0 +1,115 @@
+// generator/types.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// typesHeading is the title of the section documenting every struct once with
+// Options.TypesAppendix.
+const typesHeading = "Types"
+
+// typeAnchor returns the anchor of the entry of a struct in the Types section, such as
+// "type-rpc-pagination-reportitem" for rpc.Pagination[ReportItem]: lower-case, with every run
+// of other characters than letters, digits and '_' replaced by a hyphen. It only depends on
+// the struct, so links stay valid whatever other headings the document has.
+func typeAnchor(key models.StructKey) string {
+	var anchor strings.Builder
+	anchor.WriteString("type")
+	pendingHyphen := true
+	for _, r := range strings.ToLower(key.Package + "." + key.Name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			pendingHyphen = true
+			continue
+		}
+		if pendingHyphen {
+			anchor.WriteByte('-')
+			pendingHyphen = false
+		}
+		anchor.WriteRune(r)
+	}
+	return anchor.String()
+}
+
+// writeTypeLinks writes links to the Types section entries of the structs reachable from
+// roots, in place of their tables. Structs in printed were already linked for the endpoint
+// and are skipped, unless they are roots.
+func writeTypeLinks(writer io.Writer, roots []models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, printed map[models.StructKey]bool, opts Options) {
+	isRoot := make(map[models.StructKey]bool)
+	for _, root := range roots {
+		isRoot[root] = true
+	}
+	var links []string
+	for _, key := range collectStructGraph(roots, structDefinitions, opts).order {
+		if printed[key] && !isRoot[key] {
+			continue
+		}
+		printed[key] = true
+		if _, exists := structDefinitions[key]; exists {
+			links = append(links, fmt.Sprintf("[%s](#%s)", structHeading(key, structDefinitions[key]), typeAnchor(key)))
+		}
+	}
+	if len(links) > 0 {
+		fmt.Fprintf(writer, "**Types:** %s.\n\n", strings.Join(links, ", "))
+	}
+}
+
+// collectTypes returns the structs documented in the Types section: the structs of the
+// results, @Additional annotations and notification payloads of every command and of the
+// result envelope, and the structs they reference, sorted by package and name.
+func collectTypes(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, envelope models.ResultEnvelope, opts Options) []models.StructKey {
+	var roots []models.StructKey
+	for _, apiFunc := range apiFunctions {
+		roots = append(roots, commandStructs(apiFunc, structDefinitions)...)
+	}
+	for _, member := range envelope.Members {
+		if key, found := resolveStructType(member.TypeRef); found {
+			roots = append(roots, key)
+		}
+	}
+	var keys []models.StructKey
+	for _, key := range collectStructGraph(roots, structDefinitions, opts).order {
+		if _, exists := structDefinitions[key]; exists {
+			keys = append(keys, key)
+		}
+	}
+	sortStructKeys(keys)
+	return keys
+}
+
+// writeTypes writes the Types section: the complete definition of each struct in keys,
+// with the commands using it linked through commandLinks. Nothing is written without structs.
+func writeTypes(writer io.Writer, keys []models.StructKey, structDefinitions map[models.StructKey]models.StructDefinition, usage map[models.StructKey][]StructUse, commandLinks map[string]string, anchors *anchorRegistry, opts Options) {
+	if len(keys) == 0 {
+		return
+	}
+
+	// Embedded structs listed later in the section are linked too
+	for _, key := range keys {
+		if _, exists := anchors.structs[key]; !exists {
+			anchors.structs[key] = typeAnchor(key)
+		}
+	}
+	anchors.heading(writer, 2, typesHeading)
+	for _, key := range keys {
+		structDef := structDefinitions[key]
+		fmt.Fprintf(writer, "<a id=\"%s\"></a>\n\n", typeAnchor(key))
+		anchors.structHeading(writer, 3, key, structDef)
+		writeUsedBy(writer, usage[key], commandLinks)
+		if structDef.Description != "" {
+			fmt.Fprintf(writer, "%s\n\n", structDef.Description)
+		}
+		table := tableStruct(key, structDef, structDefinitions, opts)
+		fields := linkEmbedded(table.Fields, key.Package, structDefinitions, anchors)
+		writeFieldTable(writer, fields, 0, "", structLinker(key.Package, nil, structDefinitions, anchors), opts)
+		if opts.StructSource {
+			writeStructSource(writer, key, structDef, opts)
+		}
+		writeMethodNotes(writer, structDef.Methods)
+	}
+}
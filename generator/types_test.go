@@ -0,0 +1,107 @@
+// generator/types_test.go
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/validate"
+)
+
+func typesModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	page := models.StructKey{Package: "rpc", Name: "Pagination[ReportItem]"}
+	structs := map[models.StructKey]models.StructDefinition{
+		page: {Name: "Pagination[ReportItem]", Description: "A page of results.", Fields: []models.StructField{
+			{Name: "Items", Type: "[]ReportItem", JSONName: "items", Description: "Items of the page."},
+			{Name: "Next", Type: "string", JSONName: "next", Description: "Cursor of the next page."},
+		}},
+		{Package: "rpc", Name: "ReportItem"}: {Name: "ReportItem", Description: "A report.", Fields: []models.StructField{
+			{Name: "Title", Type: "string", JSONName: "title", Description: "Title of the report."},
+			{Name: "Owner", Type: "Owner", JSONName: "owner", Description: "Owner of the report."},
+		}},
+		{Package: "rpc", Name: "Owner"}: {Name: "Owner", Fields: []models.StructField{
+			{Name: "Name", Type: "string", JSONName: "name", Description: "Name of the owner."},
+		}},
+	}
+	result := models.APIReturn{
+		Name:        "result",
+		Type:        "Pagination[ReportItem]",
+		Description: "The reports.",
+		TypeRef:     &models.TypeRef{Kind: models.TypeStruct, Name: "Pagination", Package: "rpc", Struct: page},
+	}
+	apiFunctions := []models.APIFunction{
+		{Command: "reports.List", Description: "Lists reports.", PackageName: "rpc", Results: []models.APIReturn{result}},
+		{Command: "reports.Search", Description: "Searches reports.", PackageName: "rpc", Results: []models.APIReturn{result}, AdditionalStructs: []string{"Owner"}},
+	}
+	return apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+}
+
+func TestTypesAppendix(t *testing.T) {
+	apiFunctions, structs, projectInfo := typesModel()
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{TypesAppendix: true})
+	assertGolden(t, "types_appendix", got)
+
+	// Each struct is documented once, and linked from the commands using it
+	for _, heading := range []string{"### rpc.Pagination[ReportItem]\n", "### rpc.ReportItem\n", "### rpc.Owner\n"} {
+		if count := strings.Count(got, heading); count != 1 {
+			t.Errorf("Expected %q once, got %d times", heading, count)
+		}
+	}
+	links := "**Types:** [rpc.Pagination[ReportItem]](#type-rpc-pagination-reportitem), [rpc.ReportItem](#type-rpc-reportitem), [rpc.Owner](#type-rpc-owner).\n"
+	if count := strings.Count(got, links); count != 2 {
+		t.Errorf("Expected %q under both commands, got it %d times", links, count)
+	}
+	// Structs already linked under the results are linked again only when named by @Additional
+	if want := "### Additional Structs:\n\n**Types:** [rpc.Owner](#type-rpc-owner).\n"; !strings.Contains(got, want) {
+		t.Errorf("Expected output to contain %q, got:\n%s", want, got)
+	}
+	if diagnostics := validate.Markdown("API.md", got); len(diagnostics) > 0 {
+		t.Errorf("Expected valid Markdown, got %v", diagnostics)
+	}
+
+	// Commands document their structs inline by default
+	inline := generateString(t, apiFunctions, structs, projectInfo, Options{})
+	if count := strings.Count(inline, "#### rpc.ReportItem\n"); count != 2 {
+		t.Errorf("Expected rpc.ReportItem documented under both commands by default, got %d times", count)
+	}
+	if strings.Contains(inline, "## Types\n") {
+		t.Errorf("Expected no Types section by default, got:\n%s", inline)
+	}
+}
+
+func TestTypeAnchor(t *testing.T) {
+	tests := map[models.StructKey]string{
+		{Package: "rpc", Name: "User"}:                      "type-rpc-user",
+		{Package: "rpc", Name: "Pagination[ReportItem]"}:    "type-rpc-pagination-reportitem",
+		{Package: "common", Name: "Pair[string, rpc.Item]"}: "type-common-pair-string-rpc-item",
+		{Package: "rpc", Name: "Order.Details"}:             "type-rpc-order-details",
+		{Package: "rpc", Name: "snake_case"}:                "type-rpc-snake_case",
+	}
+	for key, want := range tests {
+		if got := typeAnchor(key); got != want {
+			t.Errorf("typeAnchor(%v) = %q, expected %q", key, got, want)
+		}
+	}
+}
+
+func TestFieldTypesLinkToStructs(t *testing.T) {
+	apiFunctions, structs, projectInfo := typesModel()
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{TerminalTypes: map[string]string{"rpc.Owner": "string (owner name)"}})
+
+	for _, want := range []string{
+		// Each command links to the tables documented in its own section
+		"#### rpc.Pagination[ReportItem]\n",
+		"| Items | [[]ReportItem](#rpcreportitem) | Items of the page. | items | Yes |\n",
+		"| Items | [[]ReportItem](#rpcreportitem-1) | Items of the page. | items | Yes |\n",
+		// Terminal types have no table to link to
+		"| Owner | Owner | Owner of the report. | owner | Yes |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if diagnostics := validate.Markdown("API.md", got); len(diagnostics) > 0 {
+		t.Errorf("Expected valid Markdown, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,36 @@
+// generator/units_test.go
+package generator
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func unitsModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo) {
+	key := models.StructKey{Package: "main", Name: "Job"}
+	structs := map[models.StructKey]models.StructDefinition{
+		key: {Name: "Job", Fields: []models.StructField{
+			{Name: "Timeout", Type: "int64", JSONName: "timeout_ms", Description: "Time the job may run.", Units: "milliseconds"},
+			{Name: "Owner", Type: "string", JSONName: "owner", Description: "Email of the owner.", Format: "email"},
+			{Name: "Created", Type: "time.Time", JSONName: "created", Description: "Creation time.", Format: "date-time"},
+			{Name: "Day", Type: "time.Time", JSONName: "day", Description: "Day the job runs.", Format: "date"},
+			{Name: "Size", Type: "int64", JSONName: "size", Description: "Size of the output.", Units: "bytes", Format: "int64"},
+		}},
+	}
+	apiFunctions := []models.APIFunction{{
+		Command:     "jobs.Create",
+		Description: "Creates a job.",
+		PackageName: "main",
+		Parameters:  []models.APIParameter{{Name: "job", Type: "Job", Description: "The job", Required: true}},
+		Results:     []models.APIReturn{{Name: "result", Type: "Job", Description: "The job"}},
+	}}
+	return apiFunctions, structs, models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+}
+
+func TestFieldUnitsAndFormats(t *testing.T) {
+	apiFunctions, structs, projectInfo := unitsModel()
+	// The format implied by the type, such as date-time for time.Time, is not repeated
+	assertGolden(t, "units", generateString(t, apiFunctions, structs, projectInfo, Options{StructSource: true}))
+	assertGolden(t, "units_flattened", generateString(t, apiFunctions, structs, projectInfo, Options{FlattenParams: true}))
+}
@@ -0,0 +1,92 @@
+// generator/usage.go
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// maxUsedByLinks is the number of commands listed on the "Used by" line of a struct before
+// the list is collapsed.
+const maxUsedByLinks = 10
+
+// StructUse is a command documenting a struct in its results or additional structs, directly
+// or through the fields of another struct. For a generic struct, Instance is the
+// instantiation the command documents, such as "Page[User]".
+type StructUse struct {
+	Command  string `json:"command"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// collectStructUsage returns the commands documenting each struct, in command order. The uses
+// of the instantiations of a generic struct are also counted for the generic struct itself,
+// noting the instantiation. Structs are documented as collected by collectStructGraph.
+func collectStructUsage(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, opts Options) map[models.StructKey][]StructUse {
+	usage := make(map[models.StructKey][]StructUse)
+	for _, apiFunc := range apiFunctions {
+		used := make(map[models.StructKey]bool)
+		add := func(key models.StructKey, use StructUse) {
+			if !used[key] {
+				used[key] = true
+				usage[key] = append(usage[key], use)
+			}
+		}
+		for _, key := range collectStructGraph(commandStructs(apiFunc, structDefinitions), structDefinitions, opts).order {
+			add(key, StructUse{Command: apiFunc.Command})
+			if base, _, generic := strings.Cut(key.Name, "["); generic {
+				baseKey := models.StructKey{Package: key.Package, Name: base}
+				if _, exists := structDefinitions[baseKey]; exists {
+					add(baseKey, StructUse{Command: apiFunc.Command, Instance: key.Name})
+				}
+			}
+		}
+	}
+	return usage
+}
+
+// commandStructs returns the structs held by the results, additional structs and notification
+// payload of apiFunc.
+func commandStructs(apiFunc models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition) []models.StructKey {
+	var roots []models.StructKey
+	for _, result := range apiFunc.Results {
+		if key, found := findResultStruct(apiFunc, result, structDefinitions); found {
+			roots = append(roots, key)
+		}
+	}
+	for _, additional := range apiFunc.AdditionalStructs {
+		ref := utils.ResolveTypeRef(utils.ParseType(additional), apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions).Held()
+		if ref != nil && ref.Kind == models.TypeStruct {
+			roots = append(roots, ref.Struct)
+		}
+	}
+	if apiFunc.Subscription != nil {
+		if key, found := payloadStruct(apiFunc, structDefinitions); found {
+			roots = append(roots, key)
+		}
+	}
+	return roots
+}
+
+// writeUsedBy writes the commands using a struct as links, with commandLinks mapping each
+// command to its link target. Long lists are collapsed into a details block.
+func writeUsedBy(writer io.Writer, uses []StructUse, commandLinks map[string]string) {
+	if len(uses) == 0 {
+		return
+	}
+	links := make([]string, len(uses))
+	for i, use := range uses {
+		links[i] = fmt.Sprintf("[%s](%s)", use.Command, commandLinks[use.Command])
+		if use.Instance != "" {
+			links[i] += fmt.Sprintf(" (`%s`)", use.Instance)
+		}
+	}
+	if len(uses) <= maxUsedByLinks {
+		fmt.Fprintf(writer, "Used by: %s.\n\n", strings.Join(links, ", "))
+		return
+	}
+	fmt.Fprintf(writer, "<details>\n<summary>Used by %d commands</summary>\n\n%s.\n\n</details>\n\n", len(uses), strings.Join(links, ", "))
+}
@@ -0,0 +1,165 @@
+// generator/usage_test.go
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// usageModel returns commands sharing an address, and two instantiations of a generic page.
+func usageModel() ([]models.APIFunction, map[models.StructKey]models.StructDefinition) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "user.Get",
+			Results:     []models.APIReturn{{Name: "result", Type: "User"}},
+			PackageName: "rpc",
+		},
+		{
+			Command: "user.List",
+			Results: []models.APIReturn{{Name: "result", Type: "Page[User]", TypeRef: &models.TypeRef{
+				Kind: models.TypeStruct, Name: "Page", Package: "rpc", Struct: models.StructKey{Package: "rpc", Name: "Page[User]"},
+			}}},
+			PackageName: "rpc",
+		},
+		{
+			Command: "office.List",
+			Results: []models.APIReturn{{Name: "result", Type: "Page[Office]", TypeRef: &models.TypeRef{
+				Kind: models.TypeStruct, Name: "Page", Package: "rpc", Struct: models.StructKey{Package: "rpc", Name: "Page[Office]"},
+			}}},
+			PackageName: "rpc",
+		},
+		{
+			Command:           "office.Types",
+			AdditionalStructs: []string{"Address"},
+			PackageName:       "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "User"}: {Name: "User", Fields: []models.StructField{
+			{Name: "Home", Type: "Address", JSONName: "home"},
+		}},
+		{Package: "rpc", Name: "Office"}: {Name: "Office", Fields: []models.StructField{
+			{Name: "Address", Type: "*Address", JSONName: "address"},
+		}},
+		{Package: "rpc", Name: "Address"}: {Name: "Address", Fields: []models.StructField{
+			{Name: "Street", Type: "string", JSONName: "street"},
+			{Name: "City", Type: "string", JSONName: "city"},
+		}},
+		{Package: "rpc", Name: "Page"}: {Name: "Page", TypeParams: []models.TypeParam{{Name: "T", Constraint: "any"}}, Fields: []models.StructField{
+			{Name: "Items", Type: "[]T", JSONName: "items"},
+		}},
+		{Package: "rpc", Name: "Page[User]"}: {Name: "Page[User]", Fields: []models.StructField{
+			{Name: "Items", Type: "[]User", JSONName: "items"},
+		}},
+		{Package: "rpc", Name: "Page[Office]"}: {Name: "Page[Office]", Fields: []models.StructField{
+			{Name: "Items", Type: "[]Office", JSONName: "items"},
+		}},
+	}
+	apiFunctions = sortCommands(apiFunctions)
+	return apiFunctions, structs
+}
+
+func TestCollectStructUsage(t *testing.T) {
+	apiFunctions, structs := usageModel()
+	usage := collectStructUsage(apiFunctions, structs, Options{})
+
+	want := map[string][]StructUse{
+		"Address": {{Command: "office.List"}, {Command: "office.Types"}, {Command: "user.Get"}, {Command: "user.List"}},
+		"Office":  {{Command: "office.List"}},
+		"User":    {{Command: "user.Get"}, {Command: "user.List"}},
+		// The generic struct aggregates its instantiations
+		"Page":         {{Command: "office.List", Instance: "Page[Office]"}, {Command: "user.List", Instance: "Page[User]"}},
+		"Page[Office]": {{Command: "office.List"}},
+		"Page[User]":   {{Command: "user.List"}},
+	}
+	got := make(map[string][]StructUse)
+	for key, uses := range usage {
+		got[key.Name] = uses
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected usage:\n%v\ngot:\n%v", want, got)
+	}
+}
+
+func TestWriteUsedBy(t *testing.T) {
+	links := map[string]string{"user.Get": "#userget", "user.List": "#userlist"}
+	var buf bytes.Buffer
+	writeUsedBy(&buf, []StructUse{{Command: "user.Get"}, {Command: "user.List", Instance: "Page[User]"}}, links)
+	if want := "Used by: [user.Get](#userget), [user.List](#userlist) (`Page[User]`).\n\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+
+	var uses []StructUse
+	for i := 0; i <= maxUsedByLinks; i++ {
+		uses = append(uses, StructUse{Command: fmt.Sprintf("cmd.C%02d", i)})
+	}
+	buf.Reset()
+	writeUsedBy(&buf, uses, links)
+	if !strings.HasPrefix(buf.String(), fmt.Sprintf("<details>\n<summary>Used by %d commands</summary>\n\n[cmd.C00]", maxUsedByLinks+1)) {
+		t.Errorf("Expected a collapsed list, got:\n%s", buf.String())
+	}
+}
+
+func TestUsedByAppendixAndManifest(t *testing.T) {
+	apiFunctions, structs := usageModel()
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	got := generateString(t, apiFunctions, structs, projectInfo, Options{MaxFields: 1})
+	if !strings.Contains(got, "### rpc.Address (complete)\n\nUsed by: [office.List](#officelist), [office.Types](#officetypes), [user.Get](#userget), [user.List](#userlist).\n") {
+		t.Errorf("Expected the users of Address in the appendix, got:\n%s", got)
+	}
+
+	outDir := t.TempDir()
+	if err := GenerateSplitDocumentation(apiFunctions, structs, projectInfo, outDir, Options{MaxFields: 1}); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+	types, err := os.ReadFile(filepath.Join(outDir, splitTypesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(types), "Used by: [office.List](office.list.md), ") {
+		t.Errorf("Expected links to the command files, got:\n%s", types)
+	}
+	manifest := readManifest(t, outDir)
+	if want := []StructUse{{Command: "office.List", Instance: "Page[Office]"}, {Command: "user.List", Instance: "Page[User]"}}; !reflect.DeepEqual(manifest.UsedBy["rpc.Page"], want) {
+		t.Errorf("Expected rpc.Page to be used by %v, got %v", want, manifest.UsedBy["rpc.Page"])
+	}
+
+	// Partial regeneration replaces the uses of the selected commands only
+	var selected []models.APIFunction
+	for _, apiFunc := range apiFunctions {
+		if apiFunc.Command == "office.Types" {
+			apiFunc.AdditionalStructs = nil
+			selected = append(selected, apiFunc)
+		}
+	}
+	if err := GenerateSplitDocumentation(selected, structs, projectInfo, outDir, Options{MaxFields: 1, Partial: true}); err != nil {
+		t.Fatalf("Partial GenerateSplitDocumentation returned error: %v", err)
+	}
+	manifest = readManifest(t, outDir)
+	if want := []StructUse{{Command: "office.List"}, {Command: "user.Get"}, {Command: "user.List"}}; !reflect.DeepEqual(manifest.UsedBy["rpc.Address"], want) {
+		t.Errorf("Expected rpc.Address to be used by %v after the partial run, got %v", want, manifest.UsedBy["rpc.Address"])
+	}
+}
+
+// readManifest decodes the manifest.json of split output in dir.
+func readManifest(t *testing.T, dir string) Manifest {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, splitManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	return manifest
+}
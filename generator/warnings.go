@@ -0,0 +1,31 @@
+// generator/warnings.go
+package generator
+
+// unresolvedTypeClass is the diagnostic class of the warnings about types without a struct
+// definition, the same as the class of the parser's unresolved-type diagnostics.
+const unresolvedTypeClass = "unresolved-type"
+
+// envelopeClass is the diagnostic class of the warnings about commands whose JSON-RPC
+// envelope a format cannot describe, the same as the class of the parser's envelope
+// diagnostics.
+const envelopeClass = "envelope"
+
+// Warning is a problem found while generating, such as a result type without a struct
+// definition, whose fields cannot be documented.
+type Warning struct {
+	// File and Line locate the annotation naming the type, Line is zero when not known.
+	File string
+	Line int
+	// Class identifies the kind of warning like the classes of parser diagnostics, empty for
+	// warnings about the output rather than the annotations.
+	Class   string
+	Message string
+}
+
+// warn reports a warning to opts.Warn. The generator does not log, so warnings are dropped
+// when no function is set.
+func (opts Options) warn(warning Warning) {
+	if opts.Warn != nil {
+		opts.Warn(warning)
+	}
+}
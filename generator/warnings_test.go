@@ -0,0 +1,40 @@
+// generator/warnings_test.go
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestWarnings(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:           "report.Get",
+			Description:       "Get a report.",
+			PackageName:       "rpc",
+			SourceFile:        "rpc/report.go",
+			SourceLine:        12,
+			Results:           []models.APIReturn{{Name: "result", Type: "Report", Description: "The report.", SourceLine: 9}},
+			AdditionalStructs: []string{"Row"},
+			AnnotationLines:   map[string]int{"@Additional Row": 10},
+		},
+	}
+	projectInfo := models.ProjectInfo{Title: "Test API", Version: "1.0.0"}
+
+	var got []string
+	opts := Options{Warn: func(warning Warning) {
+		got = append(got, fmt.Sprintf("%s:%d: %s [%s]", warning.File, warning.Line, warning.Message, warning.Class))
+	}}
+	generateString(t, apiFunctions, map[models.StructKey]models.StructDefinition{}, projectInfo, opts)
+
+	want := []string{
+		"rpc/report.go:9: struct 'Report' of the result of command 'report.Get' not found, its fields are not documented [unresolved-type]",
+		"rpc/report.go:10: struct 'Row' of @Additional of command 'report.Get' not found, its fields are not documented [unresolved-type]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected warnings:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
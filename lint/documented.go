@@ -0,0 +1,135 @@
+// lint/documented.go
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/parser"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// CommandCoverage counts the description cells of the tables documenting one command:
+// its parameters, its results and the fields of the structs shown with it.
+type CommandCoverage struct {
+	Command    string
+	SourceFile string
+	SourceLine int
+	Total      int
+	// Undocumented names the cells whose description is empty, such as "id" or "rpc.User.Name".
+	Undocumented []string
+}
+
+// DocumentationReport counts empty descriptions per command. Structs shown with several
+// commands are counted for each of them, like the tables that repeat them.
+type DocumentationReport struct {
+	Commands []CommandCoverage
+	Total    int
+	Empty    int
+}
+
+// Percent returns the percentage of documented cells, 100 when there are none.
+func (r DocumentationReport) Percent() float64 {
+	if r.Total == 0 {
+		return 100
+	}
+	return float64(r.Total-r.Empty) * 100 / float64(r.Total)
+}
+
+// Documentation counts the empty descriptions of every command.
+func Documentation(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition) DocumentationReport {
+	var report DocumentationReport
+	for _, apiFunc := range apiFunctions {
+		coverage := CommandCoverage{Command: apiFunc.Command, SourceFile: apiFunc.SourceFile, SourceLine: apiFunc.SourceLine}
+		count := func(name string, description string) {
+			coverage.Total++
+			if strings.TrimSpace(description) == "" {
+				coverage.Undocumented = append(coverage.Undocumented, name)
+			}
+		}
+
+		for _, param := range apiFunc.Parameters {
+			count(param.Name, param.Description)
+		}
+		var roots []models.StructKey
+		for _, result := range apiFunc.Results {
+			count(result.Name, result.Description)
+			if key, found := heldStruct(result.TypeRef, result.Type, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions); found {
+				roots = append(roots, key)
+			}
+		}
+		for _, additional := range apiFunc.AdditionalStructs {
+			if key, found := heldStruct(nil, additional, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions); found {
+				roots = append(roots, key)
+			}
+		}
+		if subscription := apiFunc.Subscription; subscription != nil {
+			count("payload", subscription.Payload.Description)
+			if key, found := heldStruct(subscription.Payload.TypeRef, subscription.Payload.Type, apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions); found {
+				roots = append(roots, key)
+			}
+		}
+
+		// Every struct reachable from the roots is shown once with the command
+		seen := make(map[models.StructKey]bool)
+		for len(roots) > 0 {
+			key := roots[0]
+			roots = roots[1:]
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			for _, field := range structDefinitions[key].Fields {
+				count(key.Package+"."+key.Name+"."+field.Name, field.Description)
+				if fieldKey, found := heldStruct(field.TypeRef, field.Type, key.Package, nil, structDefinitions); found {
+					roots = append(roots, fieldKey)
+				}
+			}
+		}
+
+		report.Commands = append(report.Commands, coverage)
+		report.Total += coverage.Total
+		report.Empty += len(coverage.Undocumented)
+	}
+	return report
+}
+
+// Diagnostics reports the undocumented cells of each command as information, and an error
+// when less than minDocumented percent of the cells are documented. Zero disables the threshold.
+func (r DocumentationReport) Diagnostics(minDocumented int) parser.Diagnostics {
+	var diagnostics parser.Diagnostics
+	for _, coverage := range r.Commands {
+		if len(coverage.Undocumented) == 0 {
+			continue
+		}
+		diagnostics = append(diagnostics, parser.Diagnostic{
+			Severity: parser.SeverityInfo,
+			File:     coverage.SourceFile,
+			Line:     coverage.SourceLine,
+			Class:    parser.ClassUndocumented,
+			Message:  fmt.Sprintf("command '%s': %d of %d descriptions are empty (%s)", coverage.Command, len(coverage.Undocumented), coverage.Total, strings.Join(coverage.Undocumented, ", ")),
+		})
+	}
+	if minDocumented > 0 && r.Percent() < float64(minDocumented) {
+		diagnostics = append(diagnostics, parser.Diagnostic{
+			Severity: parser.SeverityError,
+			Class:    parser.ClassUndocumented,
+			Message:  fmt.Sprintf("%.1f%% of descriptions are documented (%d of %d), below the minimum of %d%%", r.Percent(), r.Total-r.Empty, r.Total, minDocumented),
+		})
+	}
+	return diagnostics
+}
+
+// heldStruct returns the struct held by a type, looking through pointers, slices and maps.
+// The type string typ is parsed in pkg when ref is nil.
+func heldStruct(ref *models.TypeRef, typ string, pkg string, importAliases map[string]string, structDefinitions map[models.StructKey]models.StructDefinition) (models.StructKey, bool) {
+	if ref == nil {
+		ref = utils.ResolveTypeRef(utils.ParseType(typ), pkg, importAliases, structDefinitions)
+	}
+	held := ref.Held()
+	if held == nil || held.Kind != models.TypeStruct {
+		return models.StructKey{}, false
+	}
+	return held.Struct, true
+}
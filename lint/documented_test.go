@@ -0,0 +1,87 @@
+// lint/documented_test.go
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+func TestDocumentation(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command: "user.Get",
+			Parameters: []models.APIParameter{
+				{Name: "id", Description: "User id."},
+				{Name: "tz", Description: " "},
+			},
+			Results: []models.APIReturn{
+				{Name: "result", Type: "*User", Description: "The user."},
+			},
+			PackageName: "rpc",
+			SourceFile:  "api.go",
+			SourceLine:  10,
+		},
+		{
+			Command: "user.List",
+			Results: []models.APIReturn{
+				{Name: "result", Type: "[]User"},
+			},
+			AdditionalStructs: []string{"Address"},
+			PackageName:       "rpc",
+			SourceFile:        "api.go",
+			SourceLine:        20,
+		},
+		{
+			Command:     "ping",
+			PackageName: "rpc",
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "User"}: {
+			Name: "User",
+			Fields: []models.StructField{
+				{Name: "Name", Type: "string", Description: "Display name."},
+				{Name: "Manager", Type: "*User"},
+				{Name: "Addresses", Type: "map[string]Address", Description: "Addresses by label."},
+			},
+		},
+		{Package: "rpc", Name: "Address"}: {
+			Name: "Address",
+			Fields: []models.StructField{
+				{Name: "City", Type: "string"},
+			},
+		},
+	}
+
+	report := Documentation(apiFunctions, structs)
+	if report.Total != 12 || report.Empty != 6 {
+		t.Errorf("Expected 6 of 12 empty descriptions, got %d of %d", report.Empty, report.Total)
+	}
+
+	diagnostics := report.Diagnostics(0)
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, d.String())
+	}
+	expected := []string{
+		"api.go:10: info: command 'user.Get': 3 of 7 descriptions are empty (tz, rpc.User.Manager, rpc.Address.City) [undocumented]",
+		"api.go:20: info: command 'user.List': 3 of 5 descriptions are empty (result, rpc.User.Manager, rpc.Address.City) [undocumented]",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	if errs := report.Diagnostics(50).Count(parser.SeverityError); errs != 0 {
+		t.Errorf("Expected no error at a minimum of 50%%, got %d", errs)
+	}
+	thresholded := report.Diagnostics(60)
+	if errs := thresholded.Count(parser.SeverityError); errs != 1 {
+		t.Fatalf("Expected an error at a minimum of 60%%, got %d", errs)
+	}
+	if msg := thresholded[len(thresholded)-1].Message; msg != "50.0% of descriptions are documented (6 of 12), below the minimum of 60%" {
+		t.Errorf("Unexpected threshold message: %s", msg)
+	}
+}
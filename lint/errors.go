@@ -0,0 +1,72 @@
+// lint/errors.go
+package lint
+
+import (
+	"fmt"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// RequireErrors reports commands without @Error annotations, for projects requiring every
+// command to document at least one failure mode. The @globalerror errors of the project
+// document one for every command, unless they are all JSON-RPC protocol errors, such as
+// -32600 for an invalid request, which say nothing about what the command may fail on.
+func RequireErrors(apiFunctions []models.APIFunction, globalErrors []models.APIError) parser.Diagnostics {
+	for _, apiError := range globalErrors {
+		if !protocolError(apiError.Code) {
+			return nil
+		}
+	}
+
+	var diagnostics parser.Diagnostics
+	for _, apiFunc := range apiFunctions {
+		if len(apiFunc.Errors) == 0 {
+			diagnostics = append(diagnostics, parser.Diagnostic{
+				Severity: parser.SeverityWarning,
+				File:     apiFunc.SourceFile,
+				Line:     apiFunc.SourceLine,
+				Class:    parser.ClassMissingErrors,
+				Message:  fmt.Sprintf("command '%s' documents no errors, add at least one @Error", apiFunc.Command),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// protocolError reports whether code is one of the pre-defined JSON-RPC 2.0 errors, in the
+// reserved range from -32768 to -32000 less the -32099 to -32000 range of server errors,
+// which implementations define.
+func protocolError(code int) bool {
+	return code >= -32768 && code < -32099
+}
+
+// ErrorCatalog reports @Error codes missing from the @errorcatalog of the project, when it
+// declares one, so every shared code keeps a single canonical description.
+func ErrorCatalog(apiFunctions []models.APIFunction, catalog []models.CatalogError) parser.Diagnostics {
+	if len(catalog) == 0 {
+		return nil
+	}
+	codes := make(map[int]bool, len(catalog))
+	for _, entry := range catalog {
+		codes[entry.Code] = true
+	}
+
+	var diagnostics parser.Diagnostics
+	for _, apiFunc := range apiFunctions {
+		for _, apiError := range apiFunc.Errors {
+			// Codes without a description were already reported as errors by the parser
+			if codes[apiError.Code] || apiError.Description == "" {
+				continue
+			}
+			diagnostics = append(diagnostics, parser.Diagnostic{
+				Severity: parser.SeverityWarning,
+				File:     apiFunc.SourceFile,
+				Line:     apiError.SourceLine,
+				Class:    parser.ClassErrorCatalog,
+				Message:  fmt.Sprintf("error %d of command '%s' is not in the @errorcatalog", apiError.Code, apiFunc.Command),
+			})
+		}
+	}
+	return diagnostics
+}
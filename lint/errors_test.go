@@ -0,0 +1,61 @@
+// lint/errors_test.go
+package lint
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestRequireErrors(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{Command: "user.Get", Errors: []models.APIError{{Code: 404, Description: "User not found"}}, SourceFile: "user.go", SourceLine: 10},
+		{Command: "ping", SourceFile: "ping.go", SourceLine: 3},
+	}
+
+	diagnostics := RequireErrors(apiFunctions, nil)
+	want := "ping.go:3: warning: command 'ping' documents no errors, add at least one @Error [missing-errors]"
+	if len(diagnostics) != 1 || diagnostics[0].String() != want {
+		t.Errorf("Expected %q, got %v", want, diagnostics)
+	}
+
+	// Protocol errors returned by every command document no failure mode of ping
+	protocol := []models.APIError{{Code: -32700, Description: "Parse error."}, {Code: -32600, Description: "Invalid request."}}
+	if diagnostics := RequireErrors(apiFunctions, protocol); len(diagnostics) != 1 || diagnostics[0].String() != want {
+		t.Errorf("Expected %q with protocol global errors, got %v", want, diagnostics)
+	}
+
+	// An application or server error returned by every command documents one
+	for _, code := range []int{1001, -32099, -32000} {
+		global := append(protocol, models.APIError{Code: code, Description: "Not authenticated."})
+		if diagnostics := RequireErrors(apiFunctions, global); len(diagnostics) != 0 {
+			t.Errorf("Expected no diagnostics with global error %d, got %v", code, diagnostics)
+		}
+	}
+}
+
+func TestErrorCatalog(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command: "user.Get",
+			Errors: []models.APIError{
+				{Code: -32004, Description: "The user does not exist.", Name: "UserNotFound", SourceLine: 11},
+				{Code: -32099, Description: "The moon is full.", SourceLine: 12},
+				{Code: -32098, SourceLine: 13},
+			},
+			SourceFile: "user.go",
+		},
+	}
+	catalog := []models.CatalogError{{Code: -32004, Name: "UserNotFound", Description: "The user does not exist."}}
+
+	diagnostics := ErrorCatalog(apiFunctions, catalog)
+	want := "user.go:12: warning: error -32099 of command 'user.Get' is not in the @errorcatalog [error-catalog]"
+	if len(diagnostics) != 1 || diagnostics[0].String() != want {
+		t.Errorf("Expected %q, got %v", want, diagnostics)
+	}
+
+	// Projects without a catalog are not checked
+	if diagnostics := ErrorCatalog(apiFunctions, nil); len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics without a catalog, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,82 @@
+// lint/methods.go
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// DefaultMethodPattern accepts every method name without whitespace.
+const DefaultMethodPattern = `^\S+$`
+
+// MethodNames reports command names and former names not matching pattern, a regular
+// expression matched against the whole name. With caseInsensitive, names differing only in
+// case are also reported, mirroring servers that match method names case-insensitively.
+func MethodNames(apiFunctions []models.APIFunction, pattern string, caseInsensitive bool) (parser.Diagnostics, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid method pattern: %v", err)
+	}
+
+	var diagnostics parser.Diagnostics
+	report := func(apiFunc models.APIFunction, name string, format string, args ...any) {
+		diagnostics = append(diagnostics, parser.Diagnostic{
+			Severity: parser.SeverityWarning,
+			File:     apiFunc.SourceFile,
+			Line:     nameLine(apiFunc, name),
+			Class:    parser.ClassMethodName,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, apiFunc := range apiFunctions {
+		if !re.MatchString(apiFunc.Command) {
+			report(apiFunc, apiFunc.Command, "command name '%s' does not match the method pattern %s", apiFunc.Command, pattern)
+		}
+		for _, former := range apiFunc.FormerNames {
+			if !re.MatchString(former) {
+				report(apiFunc, former, "former name '%s' of command '%s' does not match the method pattern %s", former, apiFunc.Command, pattern)
+			}
+		}
+	}
+	if !caseInsensitive {
+		return diagnostics, nil
+	}
+
+	// Former names are still routed, so they collide like live names
+	type owner struct {
+		name    string
+		apiFunc models.APIFunction
+	}
+	owners := make(map[string]owner)
+	for _, apiFunc := range apiFunctions {
+		for _, name := range append([]string{apiFunc.Command}, apiFunc.FormerNames...) {
+			folded := strings.ToLower(name)
+			previous, exists := owners[folded]
+			if !exists {
+				owners[folded] = owner{name: name, apiFunc: apiFunc}
+				continue
+			}
+			if previous.name == name {
+				// Exact duplicates are reported by the parser
+				continue
+			}
+			report(apiFunc, name, "method name '%s' of command '%s' collides case-insensitively with '%s' of command '%s' at %s:%d",
+				name, apiFunc.Command, previous.name, previous.apiFunc.Command, previous.apiFunc.SourceFile, nameLine(previous.apiFunc, previous.name))
+		}
+	}
+	return diagnostics, nil
+}
+
+// nameLine returns the line of the annotation giving name to apiFunc, its @Command or one of
+// its @FormerName annotations.
+func nameLine(apiFunc models.APIFunction, name string) int {
+	if name == apiFunc.Command {
+		return apiFunc.AnnotationLine("@Command")
+	}
+	return apiFunc.AnnotationLine("@FormerName " + name)
+}
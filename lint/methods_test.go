@@ -0,0 +1,67 @@
+// lint/methods_test.go
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestMethodNames(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{Command: "user.Get", FormerNames: []string{"GetUser"}, SourceFile: "user.go", SourceLine: 10},
+		{Command: "user.get", SourceFile: "user.go", SourceLine: 20},
+		{Command: "getuser", SourceFile: "legacy.go", SourceLine: 5},
+		{Command: "user.Delete", SourceFile: "user.go", SourceLine: 30},
+		{Command: "héllo", SourceFile: "hello.go", SourceLine: 3},
+	}
+
+	tests := []struct {
+		name            string
+		pattern         string
+		caseInsensitive bool
+		want            []string
+	}{
+		{
+			name:    "default pattern",
+			pattern: DefaultMethodPattern,
+		},
+		{
+			name:    "strict pattern",
+			pattern: `^[a-z][a-zA-Z0-9._]*$`,
+			want: []string{
+				"user.go:10: warning: former name 'GetUser' of command 'user.Get' does not match the method pattern ^[a-z][a-zA-Z0-9._]*$ [method-name]",
+				"hello.go:3: warning: command name 'héllo' does not match the method pattern ^[a-z][a-zA-Z0-9._]*$ [method-name]",
+			},
+		},
+		{
+			name:            "case-insensitive",
+			pattern:         DefaultMethodPattern,
+			caseInsensitive: true,
+			want: []string{
+				"user.go:20: warning: method name 'user.get' of command 'user.get' collides case-insensitively with 'user.Get' of command 'user.Get' at user.go:10 [method-name]",
+				"legacy.go:5: warning: method name 'getuser' of command 'getuser' collides case-insensitively with 'GetUser' of command 'user.Get' at user.go:10 [method-name]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics, err := MethodNames(apiFunctions, tt.pattern, tt.caseInsensitive)
+			if err != nil {
+				t.Fatalf("MethodNames returned error: %v", err)
+			}
+			var got []string
+			for _, diag := range diagnostics {
+				got = append(got, diag.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+
+	if _, err := MethodNames(apiFunctions, "[", false); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,84 @@
+// lint/placeholders.go
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+// DefaultPlaceholderPatterns are the placeholder patterns reported when none are configured.
+var DefaultPlaceholderPatterns = []string{"TODO", "FIXME", "XXX", "TBD"}
+
+// codeSpan matches fenced code blocks and inline code spans, which are never checked.
+var codeSpan = regexp.MustCompile("(?s)```.*?```|`[^`]*`")
+
+// Placeholders reports published descriptions containing placeholder text such as TODO.
+// Each pattern is a regular expression matched case-insensitively as a whole word.
+func Placeholders(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, patterns []string) (parser.Diagnostics, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	re, err := regexp.Compile(`(?i)\b(?:` + strings.Join(patterns, "|") + `)\b`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid placeholder pattern: %v", err)
+	}
+
+	var diagnostics parser.Diagnostics
+	check := func(file string, line int, what string, text string) {
+		match := re.FindString(codeSpan.ReplaceAllString(text, ""))
+		if match == "" {
+			return
+		}
+		diagnostics = append(diagnostics, parser.Diagnostic{
+			Severity: parser.SeverityWarning,
+			File:     file,
+			Line:     line,
+			Class:    parser.ClassPlaceholder,
+			Message:  fmt.Sprintf("placeholder '%s' in description of %s", match, what),
+		})
+	}
+
+	for _, apiFunc := range apiFunctions {
+		command := fmt.Sprintf("command '%s'", apiFunc.Command)
+		check(apiFunc.SourceFile, apiFunc.AnnotationLine("@Description"), command, apiFunc.Description)
+		for _, param := range apiFunc.Parameters {
+			check(apiFunc.SourceFile, apiFunc.LineOr(param.SourceLine), fmt.Sprintf("parameter '%s' of %s", param.Name, command), param.Description)
+		}
+		for _, result := range apiFunc.Results {
+			check(apiFunc.SourceFile, apiFunc.LineOr(result.SourceLine), fmt.Sprintf("result of %s", command), result.Description)
+		}
+		for _, apiError := range apiFunc.Errors {
+			check(apiFunc.SourceFile, apiFunc.LineOr(apiError.SourceLine), fmt.Sprintf("error %d of %s", apiError.Code, command), apiError.Description)
+		}
+	}
+
+	keys := make([]models.StructKey, 0, len(structDefinitions))
+	for key := range structDefinitions {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Package != keys[j].Package {
+			return keys[i].Package < keys[j].Package
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	for _, key := range keys {
+		structDef := structDefinitions[key]
+		if structDef.SourceFile == "" {
+			// Generic instantiations repeat the descriptions of their base struct
+			continue
+		}
+		name := fmt.Sprintf("struct '%s.%s'", key.Package, key.Name)
+		check(structDef.SourceFile, structDef.SourceLine, name, structDef.Description)
+		for _, field := range structDef.Fields {
+			check(structDef.SourceFile, field.SourceLine, fmt.Sprintf("field '%s' of %s", field.Name, name), field.Description)
+		}
+	}
+
+	return diagnostics, nil
+}
@@ -0,0 +1,78 @@
+// lint/placeholders_test.go
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestPlaceholders(t *testing.T) {
+	apiFunctions := []models.APIFunction{
+		{
+			Command:     "user.Get",
+			Description: "TODO fill this in",
+			Parameters: []models.APIParameter{
+				{Name: "id", Description: "User id, see `TODO` in the code."},
+				{Name: "tz", Description: "Timezone (tbd).", SourceLine: 13},
+			},
+			Results: []models.APIReturn{
+				{Description: "Mastodon handle."},
+			},
+			Errors: []models.APIError{
+				{Code: 404, Description: "FIXME", SourceLine: 15},
+			},
+			SourceFile:      "api.go",
+			SourceLine:      10,
+			AnnotationLines: map[string]int{"@Description": 12},
+		},
+	}
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "User"}: {
+			Name:        "User",
+			Description: "A user.",
+			Fields: []models.StructField{
+				{Name: "Name", Description: "XXX: check length", SourceLine: 22},
+				{Name: "Email", Description: "Email address.\n```\n// TODO in example\n```", SourceLine: 23},
+			},
+			SourceFile: "types.go",
+			SourceLine: 20,
+		},
+		{Package: "rpc", Name: "Page[User]"}: {
+			Name:        "Page[User]",
+			Description: "TODO instantiation without source",
+		},
+	}
+
+	diagnostics, err := Placeholders(apiFunctions, structs, DefaultPlaceholderPatterns)
+	if err != nil {
+		t.Fatalf("Placeholders returned error: %v", err)
+	}
+	var got []string
+	for _, diag := range diagnostics {
+		got = append(got, diag.String())
+	}
+	want := []string{
+		"api.go:12: warning: placeholder 'TODO' in description of command 'user.Get' [placeholder]",
+		"api.go:13: warning: placeholder 'tbd' in description of parameter 'tz' of command 'user.Get' [placeholder]",
+		"api.go:15: warning: placeholder 'FIXME' in description of error 404 of command 'user.Get' [placeholder]",
+		"types.go:22: warning: placeholder 'XXX' in description of field 'Name' of struct 'rpc.User' [placeholder]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s", strings.Join(got, "\n"))
+	}
+
+	// Custom patterns replace the defaults, an empty list disables the check
+	diagnostics, _ = Placeholders(apiFunctions, structs, []string{"mastodon"})
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].Message, "result of command 'user.Get'") {
+		t.Errorf("Expected only the custom pattern to match, got %v", diagnostics)
+	}
+	if diagnostics, _ = Placeholders(apiFunctions, structs, nil); len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics without patterns, got %v", diagnostics)
+	}
+
+	if _, err := Placeholders(apiFunctions, structs, []string{"("}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
@@ -13,14 +13,82 @@ type StructDefinition struct {
 	Description string
 	Fields      []StructField
 	TypeParams  []TypeParam
+	OnlyTagged  bool
+	NoTruncate  bool
+	// IncludeMethodDocs is set by @IncludeMethodDocs; Methods is only filled when it is.
+	IncludeMethodDocs bool
+	Methods           []MethodDoc
+	ID                string
+	SourceFile        string
+	SourceLine        int
+	// HiddenFields are the fields marked @Hidden, left out of Fields. They are only documented
+	// for the internal audience and never written to the JSON document.
+	HiddenFields []StructField `json:"-"`
+	// ExcludedFields are the fields tagged json:"-", left out of Fields since they never
+	// appear in JSON payloads. They are only documented with -show-excluded-fields.
+	ExcludedFields []StructField `json:"-"`
+}
+
+// TypeAlias is a type declared with a type other than a struct, such as "type UserID string"
+// or "type IDList []int64", or an alias such as "type Status = string".
+type TypeAlias struct {
+	Name string
+	// Underlying is the type it is declared with, as written.
+	Underlying string
+	// Alias is set for alias declarations, written with "=".
+	Alias      bool
+	SourceFile string
+	SourceLine int
+}
+
+// EnumDefinition lists the constants declared with a named type, such as the constants of
+// type Status in "const ( Active Status = "active"; Suspended Status = "suspended" )".
+type EnumDefinition struct {
+	// Type is the qualified name of the type, such as "rpc.Status".
+	Type   string
+	Values []EnumValue
+}
+
+// EnumValue is a constant of an enum.
+type EnumValue struct {
+	Name string
+	// Value is the value of the constant, unquoted for strings and computed for expressions
+	// of iota, such as "active" or "4".
+	Value string
+	// Kind is "string" or "number".
+	Kind        string
+	Description string
+}
+
+// MethodDoc is the first paragraph of the doc comment of an exported method.
+type MethodDoc struct {
+	Name string
+	Doc  string
 }
 
 // StructField represents a single field within a struct.
 type StructField struct {
 	Name        string
 	Type        string
+	TypeRef     *TypeRef
 	Description string
 	JSONName    string
+	Omitempty   bool
+	SourceLine  int
+	// DynamicKeys describes the keys of a map field, one description per nesting level (@keys).
+	DynamicKeys []string
+	// Units is the unit of a numeric field, such as "milliseconds" (units tag).
+	Units string
+	// Format is the string format of the field, such as "date-time", "uri" or "email": the
+	// format tag, or the format implied by its type.
+	Format string
+	// Excluded is set for fields tagged json:"-", which have no JSONName.
+	Excluded bool
+	// Embedded is set for embedded fields without a JSON name, whose fields encoding/json
+	// promotes into the struct.
+	Embedded bool
+	// Enum lists the values of the field when its type has constants declared, nil otherwise.
+	Enum *EnumDefinition
 }
 
 // TypeParam represents a type parameter for generic structs.
@@ -39,28 +107,189 @@ type APIFunction struct {
 	ImportAliases     map[string]string
 	PackageName       string
 	AdditionalStructs []string
+	ID                string
+	SourceFile        string
+	SourceLine        int
+	FlattenParams     bool
+	Auth              string
+	Features          []string
+	FormerNames       []string
+	Envelope          Envelope
+	NoEnvelope        bool
+	// SourcePath is SourceFile relative to the root of its repository, slash-separated, as
+	// used in source links.
+	SourcePath string
+	// Requires and ConflictsWith relate parameters of the command (@Requires, @ConflictsWith).
+	Requires      []ParamRule
+	ConflictsWith []ParamRule
+	// Subscription is set on subscribe commands (@Subscription). Their result is the
+	// subscription id, and the server then pushes notifications carrying the payload.
+	Subscription *Subscription
+	// ParamGroups are the @ParamGroup groups merged into Parameters (@Params), in order.
+	ParamGroups []string
+	// Sizes are the size expectations set by @MaxRequestSize and @TypicalResponseSize. Sizes
+	// left at zero fall back to the project defaults.
+	Sizes Sizes
+	// Internal is set by @Internal on commands documented only for the internal audience.
+	Internal bool
+	// Deprecated is set by @Deprecated, with the reason given, if any, in DeprecationReason.
+	Deprecated        bool
+	DeprecationReason string
+	// Incomplete is set on the stubs of commands that could not be parsed, to the reason.
+	// Stubs document nothing but the command name.
+	Incomplete string
+	// Tags are the sections the command is documented in (@Tag), in order. The first tag is
+	// the section of the command in the Markdown documentation.
+	Tags []string
+	// ExampleRequest and ExampleResponse are hand-written JSON examples (@ExampleRequest,
+	// @ExampleResponse), documented verbatim in place of the synthesized ones.
+	ExampleRequest  string
+	ExampleResponse string
+	// AnnotationLines holds the source line of the single-valued annotations, keyed by
+	// annotation name such as "@ID", of each @FormerName keyed as "@FormerName name", of
+	// each @Additional keyed as "@Additional type" and of each @Params keyed as "@Params group".
+	AnnotationLines map[string]int
+}
+
+// AnnotationLine returns the source line of an annotation keyed like AnnotationLines, or the
+// line of the function declaration when it is not known.
+func (f APIFunction) AnnotationLine(key string) int {
+	if line, exists := f.AnnotationLines[key]; exists {
+		return line
+	}
+	return f.SourceLine
+}
+
+// LineOr returns line, or the line of the function declaration when line is not known.
+func (f APIFunction) LineOr(line int) int {
+	if line > 0 {
+		return line
+	}
+	return f.SourceLine
+}
+
+// Sizes are the documented sizes of the requests and responses of a command, in bytes. A
+// zero size is not documented.
+type Sizes struct {
+	// MaxRequest is the size of the largest request the server accepts.
+	MaxRequest int64
+	// TypicalResponse is the usual size of a response.
+	TypicalResponse int64
+}
+
+// Or returns the sizes with each size left at zero taken from defaults.
+func (s Sizes) Or(defaults Sizes) Sizes {
+	if s.MaxRequest == 0 {
+		s.MaxRequest = defaults.MaxRequest
+	}
+	if s.TypicalResponse == 0 {
+		s.TypicalResponse = defaults.TypicalResponse
+	}
+	return s
+}
+
+// Subscription describes the notifications pushed by the server after a subscribe command.
+type Subscription struct {
+	// Method is the method of the pushed notifications.
+	Method string
+	// Payload is the value pushed with each notification (@NotificationPayload).
+	Payload NotificationPayload
+}
+
+// NotificationPayload is the value carried by the notifications of a subscription.
+type NotificationPayload struct {
+	Type        string
+	TypeRef     *TypeRef
+	Description string
+	// SourceLine is the line of the @NotificationPayload annotation.
+	SourceLine int
+}
+
+// ParamRule relates a parameter to other parameters of the same command: the parameter
+// requires all of them, or cannot be sent together with any of them.
+type ParamRule struct {
+	Param      string
+	Others     []string
+	SourceLine int
+}
+
+// Envelope holds the per-command overrides of the JSON-RPC envelope used in examples.
+// Empty values fall back to the project-wide settings.
+type Envelope struct {
+	JSONRPC string
+	IDType  string
 }
 
 // APIParameter represents a parameter of an API function.
 type APIParameter struct {
 	Name        string
 	Type        string
+	TypeRef     *TypeRef
 	Description string
 	Required    bool
+	// SourceLine is the line of the @Parameter annotation, or of the @Params annotation for
+	// parameters of a group.
+	SourceLine int
+	// Group is the @ParamGroup the parameter comes from, empty for parameters declared by the
+	// command itself.
+	Group string
+	// Default is the value the server uses when the parameter is left out, nil when none is
+	// documented.
+	Default *ParamDefault
+	// Enum lists the values of the parameter when its type has constants declared, nil
+	// otherwise.
+	Enum *EnumDefinition
+}
+
+// ParamDefault is the default value of a parameter, written as default=value in @Parameter.
+type ParamDefault struct {
+	// Value is the Go literal of the default, such as 50 or "asc".
+	Value string
+	// Constant is the constant the value is taken from, as written after '@', such as
+	// "DefaultPageSize" or "paging.DefaultPageSize". It is empty for a literal default.
+	Constant string
 }
 
 // APIReturn represents the return value of an API function.
 type APIReturn struct {
 	Name        string
 	Type        string
+	TypeRef     *TypeRef
 	Description string
 	Required    bool
+	// DynamicKeys describes the keys of a map result, one description per nesting level (@DynamicKeys).
+	DynamicKeys []string
+	// ContentTypes lists the media types of a result carrying an encoded payload (@ContentType).
+	// Several content types are negotiated with the client.
+	ContentTypes []ContentType
+	// SourceLine is the line of the @Result annotation.
+	SourceLine int
+}
+
+// ContentType is a media type of a result, such as a PDF sent as a base64 string.
+type ContentType struct {
+	MediaType string
+	// Encoding is how the payload is encoded in the JSON result, such as "base64". Empty when
+	// the result is sent as is.
+	Encoding string
 }
 
 // APIError represents an error that an API function can return.
 type APIError struct {
 	Code        int
 	Description string
+	// Name is the identifier of the code in the @errorcatalog, empty for codes not in it.
+	Name string
+	// SourceLine is the line of the @Error annotation.
+	SourceLine int
+}
+
+// CatalogError is an entry of the @errorcatalog of the project: the canonical name and
+// description of an error code shared by commands.
+type CatalogError struct {
+	Code        int
+	Name        string
+	Description string
 }
 
 // ProjectInfo holds global tags and metadata for the project.
@@ -75,4 +304,37 @@ type ProjectInfo struct {
 	Repository  string
 	Tags        []string
 	Copyright   string
+	Servers     []string
+	// ResultEnvelope is the object the server wraps every result in, declared with @envelope.
+	ResultEnvelope ResultEnvelope
+	// Sizes are the default sizes of every command, set by @maxrequestsize and
+	// @typicalresponsesize.
+	Sizes Sizes
+	// ErrorCatalog lists the errors declared by @errorcatalog, in declaration order.
+	ErrorCatalog []CatalogError
+	// GlobalErrors lists the errors declared by @globalerror, which every command may
+	// return, in declaration order.
+	GlobalErrors []APIError
+}
+
+// EnvelopeResult is the type of the envelope member holding the result of the command.
+const EnvelopeResult = "RESULT"
+
+// ResultEnvelope describes the object wrapping command results on the wire, such as
+// {"data": <result>, "meta": ResponseMeta}. It has no members when results are not wrapped.
+type ResultEnvelope struct {
+	Members []EnvelopeMember
+	// Package, ImportAliases and SourceFile locate the @envelope declaration, member types
+	// are resolved there.
+	Package       string
+	ImportAliases map[string]string
+	SourceFile    string
+}
+
+// EnvelopeMember is one member of the result envelope, holding either the command result
+// (Type is EnvelopeResult) or a value of a named type.
+type EnvelopeMember struct {
+	Name    string
+	Type    string
+	TypeRef *TypeRef
 }
@@ -0,0 +1,92 @@
+// models/types.go
+package models
+
+// TypeKind classifies a type referenced by a parameter, result or struct field.
+type TypeKind string
+
+const (
+	// TypeBasic is a boolean, string or numeric type.
+	TypeBasic TypeKind = "basic"
+	// TypeStruct is a documented struct, possibly a generic instantiation.
+	TypeStruct TypeKind = "struct"
+	// TypeNamed is any other named type, such as time.Time or a struct that was not collected.
+	TypeNamed TypeKind = "named"
+	// TypeSlice is a slice, an array or a variadic parameter.
+	TypeSlice TypeKind = "slice"
+	// TypeMap is a map.
+	TypeMap TypeKind = "map"
+	// TypePointer is a pointer.
+	TypePointer TypeKind = "pointer"
+	// TypeAny is interface{}, any, or a type that could not be interpreted.
+	TypeAny TypeKind = "any"
+)
+
+// TypeRef is the parsed form of a type string such as "map[string][]*reports.Item".
+type TypeRef struct {
+	Kind TypeKind
+	// Name is the basic type name, or the name of a named type without package or type arguments.
+	Name string
+	// Package is the package of a named type, with import aliases resolved.
+	Package string
+	// TypeArgs are the type arguments of a generic instantiation.
+	TypeArgs []*TypeRef
+	// Elem is the element type of slices and pointers, and the value type of maps.
+	Elem *TypeRef
+	// Key is the key type of maps.
+	Key *TypeRef
+	// Struct identifies the struct definition of a TypeStruct, the concrete one for generic instantiations.
+	Struct StructKey
+	// Alias names the type declared with another type, such as "rpc.UserID" for
+	// "type UserID string", when the rest of the TypeRef describes that underlying type.
+	Alias string
+}
+
+// String returns the type as written in Go, with resolved package names.
+func (t *TypeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case TypePointer:
+		return "*" + t.Elem.String()
+	case TypeSlice:
+		return "[]" + t.Elem.String()
+	case TypeMap:
+		return "map[" + t.Key.String() + "]" + t.Elem.String()
+	case TypeStruct, TypeNamed:
+		name := t.Name
+		if t.Package != "" {
+			name = t.Package + "." + name
+		}
+		if len(t.TypeArgs) > 0 {
+			name += "["
+			for i, arg := range t.TypeArgs {
+				if i > 0 {
+					name += ", "
+				}
+				name += arg.String()
+			}
+			name += "]"
+		}
+		return name
+	default:
+		return t.Name
+	}
+}
+
+// Deref returns the type t points to, following every pointer.
+func (t *TypeRef) Deref() *TypeRef {
+	for t != nil && t.Kind == TypePointer {
+		t = t.Elem
+	}
+	return t
+}
+
+// Held returns the type of the values held by t, looking through pointers, slices and maps.
+// For example, the held type of "map[string][]*User" is "User".
+func (t *TypeRef) Held() *TypeRef {
+	for t != nil && (t.Kind == TypePointer || t.Kind == TypeSlice || t.Kind == TypeMap) {
+		t = t.Elem
+	}
+	return t
+}
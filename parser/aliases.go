@@ -0,0 +1,96 @@
+// parser/aliases.go
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// typeAlias returns the type declared by typeSpec when it is declared with a type other than
+// a struct, an interface, a function or a channel, such as "type UserID string". Generic
+// types are left out.
+func typeAlias(typeSpec *ast.TypeSpec, position token.Position) (models.TypeAlias, bool) {
+	if typeSpec.TypeParams != nil {
+		return models.TypeAlias{}, false
+	}
+	switch typeSpec.Type.(type) {
+	case *ast.StructType, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return models.TypeAlias{}, false
+	}
+	underlying := utils.ExprToString(typeSpec.Type)
+	if underlying == "" {
+		return models.TypeAlias{}, false
+	}
+	return models.TypeAlias{
+		Name:       typeSpec.Name.Name,
+		Underlying: underlying,
+		Alias:      typeSpec.Assign.IsValid(),
+		SourceFile: position.Filename,
+		SourceLine: position.Line,
+	}, true
+}
+
+// resolveTypeAliases replaces the type aliases held by the parsed types of the commands,
+// the structs and the result envelope by their underlying type, see underlyingType.
+func resolveTypeAliases(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, envelope *models.ResultEnvelope, typeAliases map[models.StructKey]models.TypeAlias, aliasImports map[models.StructKey]map[string]string) {
+	if len(typeAliases) == 0 {
+		return
+	}
+	resolve := func(ref *models.TypeRef) *models.TypeRef {
+		return underlyingType(ref, typeAliases, aliasImports, structDefinitions, map[models.StructKey]bool{})
+	}
+	for i := range apiFunctions {
+		apiFunc := &apiFunctions[i]
+		for j := range apiFunc.Parameters {
+			apiFunc.Parameters[j].TypeRef = resolve(apiFunc.Parameters[j].TypeRef)
+		}
+		for j := range apiFunc.Results {
+			apiFunc.Results[j].TypeRef = resolve(apiFunc.Results[j].TypeRef)
+		}
+		if apiFunc.Subscription != nil {
+			apiFunc.Subscription.Payload.TypeRef = resolve(apiFunc.Subscription.Payload.TypeRef)
+		}
+	}
+	for _, structDef := range structDefinitions {
+		for _, fields := range [][]models.StructField{structDef.Fields, structDef.HiddenFields, structDef.ExcludedFields} {
+			for j := range fields {
+				fields[j].TypeRef = resolve(fields[j].TypeRef)
+			}
+		}
+	}
+	for i := range envelope.Members {
+		envelope.Members[i].TypeRef = resolve(envelope.Members[i].TypeRef)
+	}
+}
+
+// underlyingType returns ref with the type aliases it holds replaced by their underlying type,
+// resolved in the package declaring them and followed through chains of aliases, with Alias
+// naming the type written in ref. Types in seen are being resolved, so cycles are left as is.
+func underlyingType(ref *models.TypeRef, typeAliases map[models.StructKey]models.TypeAlias, aliasImports map[models.StructKey]map[string]string, structDefinitions map[models.StructKey]models.StructDefinition, seen map[models.StructKey]bool) *models.TypeRef {
+	if ref == nil {
+		return nil
+	}
+	resolved := *ref
+	switch ref.Kind {
+	case models.TypePointer, models.TypeSlice:
+		resolved.Elem = underlyingType(ref.Elem, typeAliases, aliasImports, structDefinitions, seen)
+	case models.TypeMap:
+		resolved.Key = underlyingType(ref.Key, typeAliases, aliasImports, structDefinitions, seen)
+		resolved.Elem = underlyingType(ref.Elem, typeAliases, aliasImports, structDefinitions, seen)
+	case models.TypeNamed:
+		key := models.StructKey{Package: ref.Package, Name: ref.Name}
+		alias, exists := typeAliases[key]
+		if !exists || seen[key] || len(ref.TypeArgs) > 0 {
+			return ref
+		}
+		seen[key] = true
+		defer delete(seen, key)
+		underlying := utils.ResolveTypeRef(utils.ParseType(alias.Underlying), key.Package, aliasImports[key], structDefinitions)
+		resolved = *underlyingType(underlying, typeAliases, aliasImports, structDefinitions, seen)
+		resolved.Alias = ref.String()
+	}
+	return &resolved
+}
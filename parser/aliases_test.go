@@ -0,0 +1,82 @@
+// parser/aliases_test.go
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/generator"
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectTypeAliases(t *testing.T) {
+	result, err := ParseProject("testdata/aliases")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Expected no diagnostics, got %v", result.Diagnostics)
+	}
+
+	aliases := map[string]models.TypeAlias{
+		"UserID":  {Name: "UserID", Underlying: "string"},
+		"Status":  {Name: "Status", Underlying: "string", Alias: true},
+		"State":   {Name: "State", Underlying: "Status", Alias: true},
+		"IDList":  {Name: "IDList", Underlying: "[]int64"},
+		"Labels":  {Name: "Labels", Underlying: "map[string]string"},
+		"Members": {Name: "Members", Underlying: "[]Member"},
+	}
+	if len(result.TypeAliases) != len(aliases) {
+		t.Errorf("Expected %d type aliases, got %+v", len(aliases), result.TypeAliases)
+	}
+	for name, want := range aliases {
+		got := result.TypeAliases[models.StructKey{Package: "rpc", Name: name}]
+		got.SourceFile, got.SourceLine = "", 0
+		if got != want {
+			t.Errorf("Expected type alias %+v, got %+v", want, got)
+		}
+	}
+
+	// Fields are typed by the underlying type, following chains of aliases
+	fields := result.Structs[models.StructKey{Package: "rpc", Name: "Member"}].Fields
+	for i, want := range []string{"string", "string", "[]int64", "map[string]string", "*string"} {
+		if got := fields[i].TypeRef.String(); got != want {
+			t.Errorf("Expected field %s to be typed %s, got %s", fields[i].Name, want, got)
+		}
+	}
+	if got := fields[1].TypeRef.Alias; got != "rpc.State" {
+		t.Errorf("Expected field State to name its type rpc.State, got %q", got)
+	}
+
+	output := filepath.Join(t.TempDir(), "API.md")
+	if err := generator.GenerateDocumentation(result.Functions, result.Structs, result.ProjectInfo, output, generator.Options{}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| id | string (UserID) | Identifier of the member | Yes |\n",
+		"| ID | string (UserID) | Identifier of the member | id | Yes |\n",
+		"| State | string (State) | State of the member | state | Yes |\n",
+		"| Friends | []int64 (IDList) | Friends of the member | friends | Yes |\n",
+		"| Labels | map[string]string (Labels) | Labels of the member | labels | Yes |\n",
+		"| Manager | *string (*UserID) | Manager of the member | manager | No |\n",
+		"| result | []int64 (IDList) | The ids |\n",
+		"| result | Members (array of rpc.Member) | The members |\n",
+		`"friends": [`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, content)
+		}
+	}
+	// Named types other than structs have no table of their own
+	for _, unwanted := range []string{"#### rpc.UserID", "#### rpc.IDList"} {
+		if strings.Contains(string(content), unwanted) {
+			t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, content)
+		}
+	}
+}
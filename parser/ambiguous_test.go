@@ -0,0 +1,42 @@
+// parser/ambiguous_test.go
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectSameStructNameInTwoPackages(t *testing.T) {
+	want := map[string]models.StructKey{
+		"billing.Charge": {Package: "billing", Name: "Result"},
+		"users.Lookup":   {Package: "users", Name: "Result"},
+		"service.Status": {},
+	}
+
+	// Map iteration order changes between runs, the resolution must not
+	for run := 0; run < 10; run++ {
+		result, err := ParseProject("testdata/ambiguous")
+		if err != nil {
+			t.Fatalf("ParseProject returned error: %v", err)
+		}
+		for _, apiFunc := range result.Functions {
+			got := apiFunc.Results[0].TypeRef.Held().Struct
+			if got != want[apiFunc.Command] {
+				t.Fatalf("run %d: expected the result of %s to resolve to %+v, got %+v", run, apiFunc.Command, want[apiFunc.Command], got)
+			}
+		}
+
+		var warnings []string
+		for _, diag := range result.Diagnostics {
+			if diag.Class == ClassUnresolvedType {
+				warnings = append(warnings, diag.Message)
+			}
+		}
+		wantWarning := "type 'rpc.Result' of the result of command 'service.Status' is not declared in the parsed packages, did you mean 'billing.Result' or 'users.Result'?"
+		if strings.Join(warnings, "\n") != wantWarning {
+			t.Fatalf("run %d: expected the warning %q, got %q", run, wantWarning, warnings)
+		}
+	}
+}
@@ -0,0 +1,612 @@
+// parser/annotations.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// GrammarVersion is the version of the annotation grammar described by Annotations.
+const GrammarVersion = "0.2.0"
+
+// Scope is the kind of declaration an annotation is written on.
+type Scope string
+
+const (
+	ScopeProject  Scope = "project"
+	ScopeFunction Scope = "function"
+	ScopeStruct   Scope = "struct"
+	ScopeField    Scope = "field"
+	// ScopeFile is a comment block of its own in a file, not attached to a declaration.
+	ScopeFile Scope = "file"
+)
+
+// Shapes of annotation arguments.
+const (
+	// ShapeWord is a single token without spaces.
+	ShapeWord = "word"
+	// ShapeIdentifier is a word made of letters, digits, '.', '_' and '-'.
+	ShapeIdentifier = "identifier"
+	// ShapeType is a Go type expression, such as "int", "[]string" or "pkg.Page[User]".
+	ShapeType = "type"
+	// ShapeInteger is a decimal integer.
+	ShapeInteger = "integer"
+	// ShapeText is the rest of the line, optionally enclosed in double quotes.
+	ShapeText = "text"
+	// ShapeList is the rest of the line as a comma-separated list.
+	ShapeList = "list"
+	// ShapeURL is an absolute URL.
+	ShapeURL = "url"
+	// ShapePairs is the rest of the line as space-separated key=value pairs.
+	ShapePairs = "pairs"
+	// ShapeSize is a number of bytes with an optional B, KB or MB suffix, such as "512KB".
+	ShapeSize = "size"
+	// ShapeBlock is the following lines of the comment, up to an empty line or the next
+	// annotation, one entry per line.
+	ShapeBlock = "block"
+	// ShapeJSON is a JSON document on the following lines of the comment, either indented
+	// or enclosed in ``` fences.
+	ShapeJSON = "json"
+	// ShapeDefault is default=value, where value is a Go literal such as 50 or "asc", or
+	// @Name naming a constant, such as default=@DefaultPageSize.
+	ShapeDefault = "default"
+)
+
+// Argument describes one argument of an annotation.
+type Argument struct {
+	Name     string `json:"name"`
+	Shape    string `json:"shape"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Annotation describes an annotation recognized by the parser.
+type Annotation struct {
+	Name     string   `json:"name"`
+	Synonyms []string `json:"synonyms,omitempty"`
+	Scopes   []Scope  `json:"scopes"`
+	// Directive is the //jdocgen: form of a function annotation, such as "jdocgen:param".
+	Directive string `json:"directive,omitempty"`
+	// CaseInsensitive is set when the name is matched regardless of case.
+	CaseInsensitive bool       `json:"caseInsensitive,omitempty"`
+	Arguments       []Argument `json:"arguments"`
+	Repeatable      bool       `json:"repeatable"`
+	AddedIn         string     `json:"addedIn"`
+	Description     string     `json:"description"`
+}
+
+// Annotations is the registry of every annotation the parser recognizes. Recognition,
+// typo detection and `jdocgen schema` all read it, so a new annotation must be added here.
+var Annotations = []Annotation{
+	// Project annotations
+	projectAnnotation("@title", "Title of the API documentation.", "0.1.0", Argument{Name: "title", Shape: ShapeText}),
+	projectAnnotation("@version", "Version of the API.", "0.1.0", Argument{Name: "version", Shape: ShapeText}),
+	projectAnnotation("@description", "Description of the API.", "0.1.0", Argument{Name: "description", Shape: ShapeText}),
+	projectAnnotation("@author", "Author of the API.", "0.1.0", Argument{Name: "author", Shape: ShapeText}),
+	projectAnnotation("@license", "License of the API.", "0.1.0", Argument{Name: "license", Shape: ShapeText}),
+	projectAnnotation("@contact", "Contact information.", "0.1.0", Argument{Name: "contact", Shape: ShapeText}),
+	projectAnnotation("@terms", "Terms of service.", "0.1.0", Argument{Name: "terms", Shape: ShapeText}),
+	projectAnnotation("@repository", "Source repository URL.", "0.1.0", Argument{Name: "url", Shape: ShapeURL}),
+	projectAnnotation("@tags", "Tags describing the API.", "0.1.0", Argument{Name: "tags", Shape: ShapeList}),
+	projectAnnotation("@copyright", "Copyright notice.", "0.1.0", Argument{Name: "notice", Shape: ShapeText}),
+	{
+		Name:            "@server",
+		Scopes:          []Scope{ScopeProject},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "url", Shape: ShapeURL}},
+		Repeatable:      true,
+		AddedIn:         "0.2.0",
+		Description:     "Server URL listed in the preamble and used in code samples.",
+	},
+	{
+		Name:            "@envelope",
+		Scopes:          []Scope{ScopeProject},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "members", Shape: ShapePairs}},
+		AddedIn:         "0.2.0",
+		Description:     "Object the server wraps every result in, as name=type pairs where RESULT stands for the command result.",
+	},
+	{
+		Name:            "@errorcatalog",
+		Scopes:          []Scope{ScopeProject},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "entries", Shape: ShapeBlock}},
+		AddedIn:         "0.2.0",
+		Description:     "Canonical errors of the project, one per line as code name \"description\", referenced by @Error codes.",
+	},
+	{
+		Name:            "@globalerror",
+		Scopes:          []Scope{ScopeProject},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "code", Shape: ShapeInteger},
+			{Name: "description", Shape: ShapeText, Optional: true},
+		},
+		Repeatable:  true,
+		AddedIn:     "0.2.0",
+		Description: "Error every command may return, documented once instead of with an @Error on each command.",
+	},
+	{
+		Name:            "@maxrequestsize",
+		Scopes:          []Scope{ScopeProject},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "size", Shape: ShapeSize}},
+		AddedIn:         "0.2.0",
+		Description:     "Default size of the largest request accepted, for commands without @MaxRequestSize.",
+	},
+	{
+		Name:            "@typicalresponsesize",
+		Scopes:          []Scope{ScopeProject},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "size", Shape: ShapeSize}},
+		AddedIn:         "0.2.0",
+		Description:     "Default usual size of a response, for commands without @TypicalResponseSize.",
+	},
+
+	// File annotations
+	{
+		Name:        "@ParamGroup",
+		Scopes:      []Scope{ScopeFile},
+		Arguments:   []Argument{{Name: "name", Shape: ShapeIdentifier}},
+		AddedIn:     "0.2.0",
+		Description: "Named list of the @Parameter lines following it in the block, merged into commands with @Params.",
+	},
+
+	// Function annotations
+	{
+		Name:            "@Command",
+		Directive:       "jdocgen:command",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "name", Shape: ShapeWord}},
+		AddedIn:         "0.1.0",
+		Description:     "JSON-RPC method name. Functions without it are not documented.",
+	},
+	{
+		Name:            "@Description",
+		Directive:       "jdocgen:description",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "description", Shape: ShapeText}},
+		AddedIn:         "0.1.0",
+		Description:     "Description of the command.",
+	},
+	{
+		Name:            "@Parameter",
+		Directive:       "jdocgen:param",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "name", Shape: ShapeWord},
+			{Name: "type", Shape: ShapeType},
+			{Name: "default", Shape: ShapeDefault, Optional: true},
+			{Name: "description", Shape: ShapeText},
+		},
+		Repeatable:  true,
+		AddedIn:     "0.1.0",
+		Description: "Request parameter. A description starting with \"optional\" marks it optional, and default=value documents its default.",
+	},
+	{
+		Name:            "@Result",
+		Directive:       "jdocgen:result",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "type", Shape: ShapeType},
+			{Name: "description", Shape: ShapeText},
+		},
+		AddedIn:     "0.1.0",
+		Description: "Result of the command.",
+	},
+	{
+		Name:            "@Error",
+		Directive:       "jdocgen:error",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "code", Shape: ShapeInteger},
+			{Name: "description", Shape: ShapeText, Optional: true},
+		},
+		Repeatable:  true,
+		AddedIn:     "0.1.0",
+		Description: "Error the command may return. The description of codes in the @errorcatalog may be left out.",
+	},
+	{
+		Name:            "@Additional",
+		Directive:       "jdocgen:additional",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "type", Shape: ShapeType}},
+		Repeatable:      true,
+		AddedIn:         "0.1.0",
+		Description:     "Struct documented with the command although no parameter or result uses it.",
+	},
+	{
+		Name:        "@ID",
+		Directive:   "jdocgen:id",
+		Scopes:      []Scope{ScopeFunction, ScopeStruct},
+		Arguments:   []Argument{{Name: "id", Shape: ShapeIdentifier}},
+		AddedIn:     "0.2.0",
+		Description: "Stable identifier kept across renames.",
+	},
+	{
+		Name:            "@FlattenParams",
+		Directive:       "jdocgen:flattenparams",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{},
+		AddedIn:         "0.2.0",
+		Description:     "Replace struct-typed parameters by their fields in the Parameters table.",
+	},
+	{
+		Name:            "@Auth",
+		Directive:       "jdocgen:auth",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "scheme", Shape: ShapeWord}},
+		AddedIn:         "0.2.0",
+		Description:     "Authentication scheme used in code samples: bearer or basic.",
+	},
+	{
+		Name:            "@Feature",
+		Directive:       "jdocgen:feature",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "flag", Shape: ShapeWord}},
+		Repeatable:      true,
+		AddedIn:         "0.2.0",
+		Description:     "Server feature flag the command depends on, used by -with-feature and -without-feature.",
+	},
+	{
+		Name:            "@Tag",
+		Directive:       "jdocgen:tag",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "tag", Shape: ShapeWord}},
+		Repeatable:      true,
+		AddedIn:         "0.2.0",
+		Description:     "Section the command is documented in, such as users or billing. A command with several tags is documented in the section of its first tag.",
+	},
+	{
+		Name:            "@ExampleRequest",
+		Directive:       "jdocgen:examplerequest",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "request", Shape: ShapeJSON}},
+		AddedIn:         "0.2.0",
+		Description:     "Hand-written example request, documented in place of the synthesized one.",
+	},
+	{
+		Name:            "@ExampleResponse",
+		Directive:       "jdocgen:exampleresponse",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "response", Shape: ShapeJSON}},
+		AddedIn:         "0.2.0",
+		Description:     "Hand-written example response, documented in place of the synthesized one.",
+	},
+	{
+		Name:            "@FormerName",
+		Directive:       "jdocgen:formername",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "command", Shape: ShapeWord}},
+		Repeatable:      true,
+		AddedIn:         "0.2.0",
+		Description:     "Previous name of the command. Links to its old anchor keep working.",
+	},
+	{
+		Name:            "@Envelope",
+		Directive:       "jdocgen:envelope",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "settings", Shape: ShapePairs}},
+		AddedIn:         "0.2.0",
+		Description:     "Envelope of the example requests: jsonrpc=1.0 or 2.0, id=number or string.",
+	},
+	{
+		Name:            "@NoEnvelope",
+		Directive:       "jdocgen:noenvelope",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{},
+		AddedIn:         "0.2.0",
+		Description:     "The result of the command is not wrapped in the project @envelope.",
+	},
+	{
+		Name:            "@DynamicKeys",
+		Directive:       "jdocgen:dynamickeys",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "target", Shape: ShapeWord},
+			{Name: "keys", Shape: ShapeText},
+		},
+		AddedIn:     "0.2.0",
+		Description: "Describes the keys of a map result, comma-separated per nesting level. The target is \"result\".",
+	},
+	{
+		Name:            "@Requires",
+		Directive:       "jdocgen:requires",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "param", Shape: ShapeWord},
+			{Name: "others", Shape: ShapeText},
+		},
+		Repeatable:  true,
+		AddedIn:     "0.2.0",
+		Description: "The parameter can only be sent together with the other parameters, such as \"page_token page_size\".",
+	},
+	{
+		Name:            "@ConflictsWith",
+		Directive:       "jdocgen:conflictswith",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "param", Shape: ShapeWord},
+			{Name: "others", Shape: ShapeText},
+		},
+		Repeatable:  true,
+		AddedIn:     "0.2.0",
+		Description: "The parameter cannot be sent together with any of the other parameters, such as \"query ids\".",
+	},
+	{
+		Name:            "@ContentType",
+		Directive:       "jdocgen:contenttype",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "mediaType", Shape: ShapeWord},
+			{Name: "encoding", Shape: ShapeWord, Optional: true},
+		},
+		Repeatable:  true,
+		AddedIn:     "0.2.0",
+		Description: "Media type of the result payload and its encoding in the JSON result, such as \"application/pdf base64\". Repeated for negotiated content types.",
+	},
+	{
+		Name:            "@Params",
+		Directive:       "jdocgen:params",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "group", Shape: ShapeIdentifier}},
+		Repeatable:      true,
+		AddedIn:         "0.2.0",
+		Description:     "Merge the parameters of a @ParamGroup of the package after the parameters of the command.",
+	},
+	{
+		Name:            "@Subscription",
+		Directive:       "jdocgen:subscription",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "notification", Shape: ShapeWord, Optional: true}},
+		AddedIn:         "0.2.0",
+		Description:     "The command opens a subscription: its result is the subscription id and the server then pushes notifications, with the given method or \"subscription\".",
+	},
+	{
+		Name:            "@NotificationPayload",
+		Directive:       "jdocgen:notificationpayload",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments: []Argument{
+			{Name: "type", Shape: ShapeType},
+			{Name: "description", Shape: ShapeText},
+		},
+		AddedIn:     "0.2.0",
+		Description: "Value pushed with each notification of a @Subscription command.",
+	},
+	{
+		Name:            "@MaxRequestSize",
+		Directive:       "jdocgen:maxrequestsize",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "size", Shape: ShapeSize}},
+		AddedIn:         "0.2.0",
+		Description:     "Size of the largest request the server accepts, such as \"1MB\".",
+	},
+	{
+		Name:            "@TypicalResponseSize",
+		Directive:       "jdocgen:typicalresponsesize",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "size", Shape: ShapeSize}},
+		AddedIn:         "0.2.0",
+		Description:     "Usual size of a response, such as \"5MB\".",
+	},
+	{
+		Name:            "@Internal",
+		Directive:       "jdocgen:internal",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{},
+		AddedIn:         "0.2.0",
+		Description:     "The command is only documented for the internal audience (-audience internal).",
+	},
+	{
+		Name:            "@Deprecated",
+		Directive:       "jdocgen:deprecated",
+		Scopes:          []Scope{ScopeFunction},
+		CaseInsensitive: true,
+		Arguments:       []Argument{{Name: "reason", Shape: ShapeText, Optional: true}},
+		AddedIn:         "0.2.0",
+		Description:     "The command is deprecated, for the given reason, such as \"use users.GetProfileV2 instead\".",
+	},
+
+	// Struct and field annotations
+	{
+		Name:        "@OnlyTagged",
+		Scopes:      []Scope{ScopeStruct},
+		Arguments:   []Argument{},
+		AddedIn:     "0.2.0",
+		Description: "Document only exported fields with an explicit json tag.",
+	},
+	{
+		Name:        "@NoTruncate",
+		Scopes:      []Scope{ScopeStruct},
+		Arguments:   []Argument{},
+		AddedIn:     "0.2.0",
+		Description: "Always list every field, even beyond -max-fields.",
+	},
+	{
+		Name:        "@IncludeMethodDocs",
+		Scopes:      []Scope{ScopeStruct},
+		Arguments:   []Argument{},
+		AddedIn:     "0.2.0",
+		Description: "List the doc comments of the exported methods under the fields table.",
+	},
+	{
+		Name:        "@Hidden",
+		Scopes:      []Scope{ScopeField},
+		Arguments:   []Argument{},
+		AddedIn:     "0.2.0",
+		Description: "Only document this field for the internal audience (-audience internal).",
+	},
+	{
+		Name:        "@keys",
+		Scopes:      []Scope{ScopeField},
+		Arguments:   []Argument{{Name: "keys", Shape: ShapeText}},
+		AddedIn:     "0.2.0",
+		Description: "Describes the keys of a map field, comma-separated per nesting level.",
+	},
+}
+
+// projectAnnotation returns the definition of a single-valued project annotation.
+func projectAnnotation(name string, description string, addedIn string, arg Argument) Annotation {
+	return Annotation{
+		Name:            name,
+		Scopes:          []Scope{ScopeProject},
+		CaseInsensitive: true,
+		Arguments:       []Argument{arg},
+		AddedIn:         addedIn,
+		Description:     description,
+	}
+}
+
+// matches reports whether name refers to the annotation.
+func (a Annotation) matches(name string) bool {
+	for _, candidate := range append([]string{a.Name}, a.Synonyms...) {
+		if candidate == name || a.CaseInsensitive && strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether the annotation may be written in the scope.
+func (a Annotation) allows(scope Scope) bool {
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupAnnotation returns the annotation named name, or one of its synonyms, valid in scope.
+func LookupAnnotation(name string, scope Scope) (Annotation, bool) {
+	for _, annotation := range Annotations {
+		if annotation.allows(scope) && annotation.matches(name) {
+			return annotation, true
+		}
+	}
+	return Annotation{}, false
+}
+
+// checkAnnotations reports annotations in a comment group that are not valid in any of the
+// given scopes: misspelled ones, with a suggestion, and ones written on the wrong declaration.
+// Unknown annotations without a close match are left alone, since "@" lines may be prose.
+func checkAnnotations(cg *ast.CommentGroup, fset *token.FileSet, scopes ...Scope) Diagnostics {
+	if cg == nil {
+		return nil
+	}
+
+	var diagnostics Diagnostics
+	for _, c := range cg.List {
+		position := fset.Position(c.Pos())
+		text := strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), "/*")
+		text = strings.TrimSuffix(text, "*/")
+		for offset, line := range strings.Split(text, "\n") {
+			parts := strings.Fields(line)
+			if len(parts) == 0 || !strings.HasPrefix(parts[0], "@") || len(parts[0]) == 1 {
+				continue
+			}
+			class, message := annotationProblem(parts[0], scopes)
+			if message == "" {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				File:     position.Filename,
+				Line:     position.Line + offset,
+				Class:    class,
+				Message:  message,
+			})
+		}
+	}
+	return diagnostics
+}
+
+// annotationProblem returns the class of the problem with name and describes why it is not
+// valid in the scopes, or returns "" when it is valid or not close to any known annotation.
+func annotationProblem(name string, scopes []Scope) (string, string) {
+	for _, scope := range scopes {
+		if _, ok := LookupAnnotation(name, scope); ok {
+			return "", ""
+		}
+	}
+
+	for _, annotation := range Annotations {
+		if annotation.matches(name) {
+			return ClassMisplacedAnnotation, fmt.Sprintf("annotation '%s' is not valid on a %s, only on: %s", name, scopes[0], joinScopes(annotation.Scopes))
+		}
+	}
+
+	best, bestDistance := "", 3
+	for _, annotation := range Annotations {
+		for _, scope := range scopes {
+			if !annotation.allows(scope) {
+				continue
+			}
+			for _, candidate := range append([]string{annotation.Name}, annotation.Synonyms...) {
+				if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDistance {
+					best, bestDistance = candidate, d
+				}
+			}
+		}
+	}
+	if best == "" {
+		return "", ""
+	}
+	return ClassUnknownAnnotation, fmt.Sprintf("unknown annotation '%s', did you mean '%s'?", name, best)
+}
+
+// joinScopes returns the scopes as a comma-separated list.
+func joinScopes(scopes []Scope) string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return strings.Join(names, ", ")
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,193 @@
+// parser/annotations_test.go
+package parser
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// annotationLiterals returns the "@..." string literals used as switch cases in the named
+// function, or passed to hasMarker and markerValue anywhere in the file when name is empty.
+func annotationLiterals(t *testing.T, file *ast.File, name string) []string {
+	t.Helper()
+	found := make(map[string]bool)
+	collect := func(expr ast.Expr) {
+		lit, ok := expr.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err == nil && strings.HasPrefix(value, "@") {
+			found[value] = true
+		}
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || (name != "" && fn.Name.Name != name) {
+			continue
+		}
+		ast.Inspect(fn, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CaseClause:
+				if name != "" {
+					for _, expr := range node.List {
+						collect(expr)
+					}
+				}
+			case *ast.CallExpr:
+				if ident, ok := node.Fun.(*ast.Ident); ok && name == "" && (ident.Name == "hasMarker" || ident.Name == "markerValue" || ident.Name == "markerText") {
+					collect(node.Args[1])
+				}
+			}
+			return true
+		})
+	}
+
+	var literals []string
+	for value := range found {
+		literals = append(literals, value)
+	}
+	sort.Strings(literals)
+	return literals
+}
+
+func TestAnnotationRegistryCoversParser(t *testing.T) {
+	file, err := goparser.ParseFile(token.NewFileSet(), "parser.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse parser.go: %v", err)
+	}
+
+	tests := []struct {
+		function string
+		scopes   []Scope
+	}{
+		{"parseFunction", []Scope{ScopeFunction}},
+		{"parseGlobalTags", []Scope{ScopeProject}},
+		{"", []Scope{ScopeStruct, ScopeField}},
+	}
+
+	for _, tt := range tests {
+		handled := make(map[string]bool)
+		literals := annotationLiterals(t, file, tt.function)
+		if len(literals) == 0 {
+			t.Fatalf("No annotations found for %q, the test no longer matches parser.go", tt.function)
+		}
+
+		// Every annotation the parser handles is registered under its canonical name
+		for _, literal := range literals {
+			handled[literal] = true
+			registered := false
+			for _, scope := range tt.scopes {
+				if annotation, ok := LookupAnnotation(literal, scope); ok && annotation.Name == literal {
+					registered = true
+				}
+			}
+			if !registered {
+				t.Errorf("%s is handled by the parser but not registered in scopes %v", literal, tt.scopes)
+			}
+		}
+
+		// Every registered annotation is handled by the parser
+		for _, annotation := range Annotations {
+			for _, scope := range tt.scopes {
+				if annotation.allows(scope) && !handled[annotation.Name] {
+					t.Errorf("%s is registered in scope %s but not handled by the parser", annotation.Name, scope)
+				}
+			}
+		}
+	}
+}
+
+func TestAnnotationRegistry(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, annotation := range Annotations {
+		if !strings.HasPrefix(annotation.Name, "@") {
+			t.Errorf("annotation %q does not start with '@'", annotation.Name)
+		}
+		if len(annotation.Scopes) == 0 || annotation.AddedIn == "" || annotation.Description == "" {
+			t.Errorf("annotation %s is missing scopes, addedIn or description", annotation.Name)
+		}
+		for _, scope := range annotation.Scopes {
+			key := string(scope) + " " + strings.ToLower(annotation.Name)
+			if seen[key] {
+				t.Errorf("annotation %s registered twice in scope %s", annotation.Name, scope)
+			}
+			seen[key] = true
+		}
+	}
+
+	if annotation, ok := LookupAnnotation("@Title", ScopeProject); !ok || annotation.Name != "@title" {
+		t.Errorf("Expected project annotations to match regardless of case, got %v %v", annotation.Name, ok)
+	}
+	if annotation, ok := LookupAnnotation("@command", ScopeFunction); !ok || annotation.Name != "@Command" {
+		t.Errorf("Expected function annotations to match regardless of case, got %v %v", annotation.Name, ok)
+	}
+	if _, ok := LookupAnnotation("@onlytagged", ScopeStruct); ok {
+		t.Error("Expected struct annotations to be case-sensitive")
+	}
+	if _, ok := LookupAnnotation("@Hidden", ScopeFunction); ok {
+		t.Error("Expected @Hidden not to be valid on functions")
+	}
+}
+
+func TestParseProjectReportsAnnotationTypos(t *testing.T) {
+	result, err := ParseProject("testdata/typos")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Severity == SeverityWarning {
+			got = append(got, strconv.Itoa(diag.Line)+": "+diag.Message)
+		}
+	}
+	want := []string{
+		"5: unknown annotation '@licence', did you mean '@license'?",
+		"9: unknown annotation '@OnlyTaged', did you mean '@OnlyTagged'?",
+		"11: unknown annotation '@Hiden', did you mean '@Hidden'?",
+		"17: unknown annotation '@Paramter', did you mean '@Parameter'?",
+		"18: annotation '@Hidden' is not valid on a function, only on: field",
+		"21: function 'GetAccount' skipped: missing @Description annotation",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected warnings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestParseProjectAnnotationCase(t *testing.T) {
+	result, err := ParseProject("testdata/casing")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Functions) != 1 {
+		t.Fatalf("Expected 1 command, got %d", len(result.Functions))
+	}
+	apiFunc := result.Functions[0]
+	if apiFunc.Command != "account.Get" || apiFunc.Description != "Returns an account." {
+		t.Errorf("Expected the command account.Get described as \"Returns an account.\", got %q described as %q", apiFunc.Command, apiFunc.Description)
+	}
+	if len(apiFunc.Parameters) != 1 || apiFunc.Parameters[0].Name != "id" || apiFunc.Parameters[0].Description != "Account id." {
+		t.Errorf("Expected the parameter id, got %+v", apiFunc.Parameters)
+	}
+	if len(apiFunc.Results) != 1 || apiFunc.Results[0].Type != "Account" {
+		t.Errorf("Expected the result Account, got %+v", apiFunc.Results)
+	}
+
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Severity == SeverityWarning {
+			got = append(got, strconv.Itoa(diag.Line)+": "+diag.Message)
+		}
+	}
+	want := []string{"15: unknown annotation '@Parmeter', did you mean '@Parameter'?"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected warnings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
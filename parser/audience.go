@@ -0,0 +1,61 @@
+// parser/audience.go
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// Audiences of the documentation. The public audience is the default.
+const (
+	// AudiencePublic leaves out @Internal commands and @Hidden fields.
+	AudiencePublic = "public"
+	// AudienceInternal documents everything, @Internal commands and @Hidden fields included.
+	AudienceInternal = "internal"
+)
+
+// AudienceReport counts what ApplyAudience removed or added.
+type AudienceReport struct {
+	// InternalCommands is the number of @Internal commands left out for the public audience.
+	InternalCommands int
+	// HiddenFields is the number of @Hidden fields documented for the internal audience.
+	HiddenFields int
+}
+
+// ApplyAudience selects what the result documents for an audience: the public audience loses
+// the @Internal commands, and the internal audience gets the @Hidden fields back in their
+// structs, in declaration order. It runs before generation so every output format documents
+// the same commands and fields.
+func (r *Result) ApplyAudience(audience string) (AudienceReport, error) {
+	var report AudienceReport
+	switch audience {
+	case "", AudiencePublic:
+		kept := r.Functions[:0]
+		for _, apiFunc := range r.Functions {
+			if apiFunc.Internal {
+				report.InternalCommands++
+				continue
+			}
+			kept = append(kept, apiFunc)
+		}
+		r.Functions = kept
+	case AudienceInternal:
+		for key, structDef := range r.Structs {
+			if len(structDef.HiddenFields) == 0 {
+				continue
+			}
+			report.HiddenFields += len(structDef.HiddenFields)
+			fields := append(append([]models.StructField{}, structDef.Fields...), structDef.HiddenFields...)
+			sort.SliceStable(fields, func(i, j int) bool {
+				return fields[i].SourceLine < fields[j].SourceLine
+			})
+			structDef.Fields, structDef.HiddenFields = fields, nil
+			r.Structs[key] = structDef
+		}
+	default:
+		return report, fmt.Errorf("invalid audience %q: expected %q or %q", audience, AudiencePublic, AudienceInternal)
+	}
+	return report, nil
+}
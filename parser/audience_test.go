@@ -0,0 +1,60 @@
+// parser/audience_test.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestApplyAudience(t *testing.T) {
+	tests := []struct {
+		audience string
+		commands []string
+		fields   []string
+		report   AudienceReport
+	}{
+		{AudiencePublic, []string{"user.Get"}, []string{"ID", "Name"}, AudienceReport{InternalCommands: 1}},
+		{"", []string{"user.Get"}, []string{"ID", "Name"}, AudienceReport{InternalCommands: 1}},
+		{AudienceInternal, []string{"user.Get", "admin.Reindex"}, []string{"ID", "PasswordHash", "Name"}, AudienceReport{HiddenFields: 1}},
+	}
+	for _, tt := range tests {
+		result, err := ParseProject("testdata/audience")
+		if err != nil {
+			t.Fatalf("ParseProject returned error: %v", err)
+		}
+		report, err := result.ApplyAudience(tt.audience)
+		if err != nil {
+			t.Fatalf("ApplyAudience(%q) returned error: %v", tt.audience, err)
+		}
+		if report != tt.report {
+			t.Errorf("ApplyAudience(%q) reported %+v, want %+v", tt.audience, report, tt.report)
+		}
+
+		var commands []string
+		for _, fn := range result.Functions {
+			commands = append(commands, fn.Command)
+		}
+		if !reflect.DeepEqual(commands, tt.commands) {
+			t.Errorf("Audience %q documents commands %v, want %v", tt.audience, commands, tt.commands)
+		}
+
+		user := result.Structs[models.StructKey{Package: "rpc", Name: "User"}]
+		var fields []string
+		for _, field := range user.Fields {
+			fields = append(fields, field.Name)
+		}
+		if !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("Audience %q documents fields %v, want %v", tt.audience, fields, tt.fields)
+		}
+		if tt.audience == AudienceInternal && (len(user.HiddenFields) > 0 || user.Fields[1].TypeRef == nil) {
+			t.Errorf("Expected the hidden field to be resolved and moved to Fields, got %+v", user)
+		}
+	}
+
+	result := &Result{}
+	if _, err := result.ApplyAudience("partners"); err == nil {
+		t.Error("Expected an error for an unknown audience")
+	}
+}
@@ -0,0 +1,75 @@
+// parser/benchmark_test.go
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// writeLargeFixture writes a project with structs commands x fields fields, half of them
+// generic, to dir.
+func writeLargeFixture(b *testing.B, dir string, commands int, fields int) {
+	b.Helper()
+	var src strings.Builder
+	src.WriteString("// Package rpc\n// @title Large Fixture API\n// @version 1.0.0\n// @description Benchmark fixture.\npackage rpc\n\n")
+	src.WriteString("// Page is one page of results.\ntype Page[T any] struct {\n\tItems []T `json:\"items\"`\n\tNext *T `json:\"next\"`\n}\n\n")
+	for c := 0; c < commands; c++ {
+		fmt.Fprintf(&src, "// Item%d is an item.\ntype Item%d struct {\n", c, c)
+		for f := 0; f < fields; f++ {
+			switch f % 4 {
+			case 0:
+				fmt.Fprintf(&src, "\tField%d int `json:\"field%d\"` // Field %d.\n", f, f, f)
+			case 1:
+				fmt.Fprintf(&src, "\tField%d []*Item%d `json:\"field%d\"` // Field %d.\n", f, c, f, f)
+			case 2:
+				fmt.Fprintf(&src, "\tField%d map[string]Page[Item%d] `json:\"field%d\"` // Field %d.\n", f, c, f, f)
+			default:
+				fmt.Fprintf(&src, "\tField%d Page[map[string][]int] `json:\"field%d\"` // Field %d.\n", f, f, f)
+			}
+		}
+		src.WriteString("}\n\n")
+		fmt.Fprintf(&src, "// Get%d gets items.\n// @Command items.Get%d\n// @Description Get items.\n// @Parameter ids []int \"Item ids\"\n// @Result Page[Item%d] \"Items\"\nfunc Get%d() error { return nil }\n\n", c, c, c, c)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(src.String()), 0644); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkParseProjectLarge(b *testing.B) {
+	dir := b.TempDir()
+	writeLargeFixture(b, dir, 100, 40)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseProject(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseGenericType(b *testing.B) {
+	types := []string{"int", "[]*Item", "map[string]Item", "Page[Item]", "Pair[reports.Item, Page[map[string][]int]]"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, typ := range types {
+			utils.ParseGenericType(typ)
+		}
+	}
+}
+
+func BenchmarkReplaceTypeParams(b *testing.B) {
+	params := []models.TypeParam{{Name: "T"}, {Name: "K"}}
+	types := []string{"int", "string", "[]T", "map[K]T", "*Cursor"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, typ := range types {
+			utils.ReplaceTypeParams(typ, params, []string{"Item", "string"})
+		}
+	}
+}
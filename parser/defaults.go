@@ -0,0 +1,147 @@
+// parser/defaults.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// constKey identifies a package-level constant by its package and name.
+type constKey struct {
+	Package string
+	Name    string
+}
+
+// constant is a package-level constant. Kind is "string", "number" or "bool" for constants
+// declared with a literal value, and empty for any other constant, such as iota or an
+// expression, whose value is not known without type checking.
+type constant struct {
+	Value string
+	Kind  string
+}
+
+// parseDefault returns the default of a default=value @Parameter argument. A value starting
+// with '@' names a constant, resolved by resolveDefaults once every file is parsed.
+func parseDefault(value string) *models.ParamDefault {
+	if name, ok := strings.CutPrefix(value, "@"); ok {
+		return &models.ParamDefault{Constant: name}
+	}
+	return &models.ParamDefault{Value: value}
+}
+
+// collectConstants adds the package-level constants of a file to constants.
+func collectConstants(fileAst *ast.File, pkg string, constants map[constKey]constant) {
+	for _, decl := range fileAst.Decls {
+		genDecl, isGen := decl.(*ast.GenDecl)
+		if !isGen || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, isValue := spec.(*ast.ValueSpec)
+			if !isValue {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				var c constant
+				if i < len(valueSpec.Values) {
+					c = literalConstant(valueSpec.Values[i])
+				}
+				constants[constKey{Package: pkg, Name: name.Name}] = c
+			}
+		}
+	}
+}
+
+// literalConstant returns the value and kind of a constant declared as a literal, such as
+// 50, -1.5, "asc" or true.
+func literalConstant(expr ast.Expr) constant {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.X
+	}
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.STRING:
+			return constant{Value: expr.Value, Kind: "string"}
+		case token.INT, token.FLOAT, token.CHAR:
+			return constant{Value: expr.Value, Kind: "number"}
+		}
+	case *ast.UnaryExpr:
+		if lit, ok := expr.X.(*ast.BasicLit); ok && (expr.Op == token.SUB || expr.Op == token.ADD) && (lit.Kind == token.INT || lit.Kind == token.FLOAT) {
+			return constant{Value: strings.TrimPrefix(expr.Op.String(), "+") + lit.Value, Kind: "number"}
+		}
+	case *ast.Ident:
+		if expr.Name == "true" || expr.Name == "false" {
+			return constant{Value: expr.Name, Kind: "bool"}
+		}
+	}
+	return constant{}
+}
+
+// resolveDefaults sets the value of the parameter defaults naming a constant, as "Name" in the
+// package of the command or "pkg.Name" in another package. A constant that does not exist or
+// has no literal value is an error, and one whose kind does not match the parameter type is a
+// warning.
+func resolveDefaults(apiFunctions []models.APIFunction, constants map[constKey]constant) Diagnostics {
+	var diagnostics Diagnostics
+	for i := range apiFunctions {
+		apiFunc := &apiFunctions[i]
+		for j := range apiFunc.Parameters {
+			param := &apiFunc.Parameters[j]
+			if param.Default == nil || param.Default.Constant == "" {
+				continue
+			}
+			report := func(severity Severity, format string, args ...any) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: severity,
+					File:     apiFunc.SourceFile,
+					Line:     param.SourceLine,
+					Class:    ClassDefaultValue,
+					Message:  fmt.Sprintf(format, args...),
+				})
+			}
+
+			key := constKey{Package: apiFunc.PackageName, Name: param.Default.Constant}
+			if pkg, name, qualified := strings.Cut(param.Default.Constant, "."); qualified {
+				key = constKey{Package: pkg, Name: name}
+			}
+			c, exists := constants[key]
+			switch {
+			case !exists:
+				report(SeverityError, "default of parameter '%s' of command '%s' refers to undefined constant '%s'", param.Name, apiFunc.Command, param.Default.Constant)
+				continue
+			case c.Kind == "":
+				report(SeverityError, "default of parameter '%s' of command '%s' refers to constant '%s', which is not declared with a literal value", param.Name, apiFunc.Command, param.Default.Constant)
+				continue
+			}
+			param.Default.Value = c.Value
+			if want := defaultKind(param.Type); want != "" && want != c.Kind {
+				report(SeverityWarning, "default of parameter '%s' of command '%s' is the %s constant '%s', but the parameter is a %s", param.Name, apiFunc.Command, c.Kind, param.Default.Constant, param.Type)
+			}
+		}
+	}
+	return diagnostics
+}
+
+// defaultKind returns the kind of constant expected as the default of a parameter type, or ""
+// for types other than the basic ones.
+func defaultKind(typ string) string {
+	switch typ {
+	case "string":
+		return "string"
+	case "bool":
+		return "bool"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "byte", "rune":
+		return "number"
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+// parser/defaults_test.go
+package parser
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectDefaults(t *testing.T) {
+	result, err := ParseProject("testdata/defaults")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	want := map[string][]models.ParamDefault{
+		"items.List": {
+			{Value: "50", Constant: "DefaultPageSize"},
+			{Value: `"asc"`, Constant: "DefaultOrder"},
+			{Value: "-0.5", Constant: "DefaultRatio"},
+			{Value: "false"},
+			{Value: `"start"`, Constant: "paging.DefaultCursor"},
+		},
+		// Mismatched constants are still documented, unresolved ones have no value
+		"items.Broken": {
+			{Value: "50", Constant: "DefaultPageSize"},
+			{Constant: "DefaultSort"},
+			{Constant: "maxRetries"},
+			{Constant: "paging.MaxLimit"},
+		},
+	}
+	for _, fn := range result.Functions {
+		var got []models.ParamDefault
+		for _, param := range fn.Parameters {
+			got = append(got, *param.Default)
+		}
+		if !reflect.DeepEqual(got, want[fn.Command]) {
+			t.Errorf("Unexpected defaults of %s:\n%+v\nwant:\n%+v", fn.Command, got, want[fn.Command])
+		}
+	}
+
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Class == ClassDefaultValue {
+			got = append(got, diag.Severity.String()+" "+filepath.Base(diag.File)+":"+strconv.Itoa(diag.Line)+": "+diag.Message)
+		}
+	}
+	wantDiagnostics := []string{
+		"warning api.go:29: default of parameter 'page_size' of command 'items.Broken' is the number constant 'DefaultPageSize', but the parameter is a string",
+		"error api.go:30: default of parameter 'order' of command 'items.Broken' refers to undefined constant 'DefaultSort'",
+		"error api.go:31: default of parameter 'retries' of command 'items.Broken' refers to constant 'maxRetries', which is not declared with a literal value",
+		"error api.go:32: default of parameter 'limit' of command 'items.Broken' refers to undefined constant 'paging.MaxLimit'",
+	}
+	if !reflect.DeepEqual(got, wantDiagnostics) {
+		t.Errorf("Unexpected diagnostics:\n%v\nwant:\n%v", got, wantDiagnostics)
+	}
+}
+
+func TestParseParameterDefault(t *testing.T) {
+	param, err := parseParameter(annotationLine{Text: `@Parameter order string default="asc" "optional Sort order"`, Line: 3})
+	if err != nil {
+		t.Fatalf("parseParameter returned error: %v", err)
+	}
+	if param.Default == nil || param.Default.Value != `"asc"` || param.Description != "Sort order" || param.Required {
+		t.Errorf("Unexpected parameter: %+v", param)
+	}
+
+	for _, text := range []string{`@Parameter order string default= "Sort order"`, `@Parameter order string default=5`} {
+		if _, err := parseParameter(annotationLine{Text: text, Line: 3}); err == nil {
+			t.Errorf("parseParameter(%q) returned no error", text)
+		}
+	}
+}
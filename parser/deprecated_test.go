@@ -0,0 +1,32 @@
+// parser/deprecated_test.go
+package parser
+
+import "testing"
+
+func TestParseProjectDeprecated(t *testing.T) {
+	result, err := ParseProject("testdata/deprecated")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	type deprecation struct {
+		Deprecated bool
+		Reason     string
+	}
+	want := map[string]deprecation{
+		"users.GetProfile":   {true, "use users.GetProfileV2 instead"},
+		"users.Legacy":       {true, ""},
+		"users.GetProfileV2": {false, ""},
+	}
+	for _, fn := range result.Functions {
+		if got := (deprecation{fn.Deprecated, fn.DeprecationReason}); got != want[fn.Command] {
+			t.Errorf("Unexpected deprecation of %s: %+v, want %+v", fn.Command, got, want[fn.Command])
+		}
+	}
+	if len(result.Functions) != len(want) {
+		t.Errorf("Expected %d commands, got %d", len(want), len(result.Functions))
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Unexpected diagnostics: %v", result.Diagnostics)
+	}
+}
@@ -0,0 +1,133 @@
+// parser/diagnostics.go
+package parser
+
+import (
+	"fmt"
+)
+
+// Severity classifies a diagnostic.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the lower-case name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic classes are stable identifiers of the kinds of diagnostics. They are shown after
+// warnings and informational diagnostics, and named by //jdocgen:ignore pragmas, so existing
+// classes must not be renamed.
+const (
+	ClassParseFailure        = "parse-failure"
+	ClassUnknownAnnotation   = "unknown-annotation"
+	ClassMisplacedAnnotation = "misplaced-annotation"
+	ClassUnknownDirective    = "unknown-directive"
+	ClassDirectiveOverride   = "directive-override"
+	ClassInvalidID           = "invalid-id"
+	ClassDuplicateID         = "duplicate-id"
+	ClassFormerName          = "former-name"
+	ClassEnvelope            = "envelope"
+	ClassHiddenFields        = "hidden-fields"
+	ClassUnresolvedType      = "unresolved-type"
+	ClassOnlyUnmatched       = "only-unmatched"
+	ClassUnknownIgnore       = "unknown-ignore"
+	ClassPlaceholder         = "placeholder"
+	ClassUndocumented        = "undocumented"
+	ClassMarkdown            = "markdown"
+	ClassDynamicKeys         = "dynamic-keys"
+	ClassMethodName          = "method-name"
+	ClassParamRule           = "param-rule"
+	ClassMissingErrors       = "missing-errors"
+	ClassInvalidSize         = "invalid-size"
+	ClassParamGroup          = "param-group"
+	ClassDefaultValue        = "default-value"
+	ClassErrorCatalog        = "error-catalog"
+	ClassProjectInfo         = "project-info"
+	ClassFormatOverride      = "format-override"
+	ClassDuplicateCommand    = "duplicate-command"
+	ClassGlobalError         = "global-error"
+)
+
+// DiagnosticClasses lists every diagnostic class.
+var DiagnosticClasses = []string{
+	ClassParseFailure, ClassUnknownAnnotation, ClassMisplacedAnnotation, ClassUnknownDirective,
+	ClassDirectiveOverride, ClassInvalidID, ClassDuplicateID, ClassFormerName, ClassEnvelope,
+	ClassHiddenFields, ClassUnresolvedType, ClassOnlyUnmatched, ClassUnknownIgnore,
+	ClassPlaceholder, ClassUndocumented, ClassMarkdown, ClassDynamicKeys, ClassMethodName,
+	ClassParamRule, ClassMissingErrors, ClassInvalidSize, ClassParamGroup, ClassDefaultValue,
+	ClassErrorCatalog, ClassProjectInfo, ClassFormatOverride, ClassDuplicateCommand,
+	ClassGlobalError,
+}
+
+// Diagnostic is a message about the parsed sources, optionally tied to a location.
+type Diagnostic struct {
+	Severity Severity
+	File     string
+	Line     int
+	Column   int
+	// Class identifies the kind of diagnostic, one of DiagnosticClasses.
+	Class   string
+	Message string
+}
+
+// String formats the diagnostic as "file:line:column: severity: message", followed by the
+// class in brackets for warnings and informational diagnostics, which can be silenced.
+func (d Diagnostic) String() string {
+	if d.Class != "" && d.Severity < SeverityError {
+		return d.format() + " [" + d.Class + "]"
+	}
+	return d.format()
+}
+
+// format formats the diagnostic without its class.
+func (d Diagnostic) format() string {
+	location := d.File
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Line)
+		if d.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, d.Column)
+		}
+	}
+	if location == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, d.Severity, d.Message)
+}
+
+// Diagnostics is the list of diagnostics produced during a run.
+type Diagnostics []Diagnostic
+
+// Count returns the number of diagnostics with at least the given severity.
+func (d Diagnostics) Count(min Severity) int {
+	count := 0
+	for _, diag := range d {
+		if diag.Severity >= min {
+			count++
+		}
+	}
+	return count
+}
+
+// Stats summarizes what ParseProject looked at.
+type Stats struct {
+	FilesParsed  int
+	FilesSkipped int
+	Commands     int
+	Structs      int
+	// Suppressed counts the diagnostics silenced by //jdocgen:ignore pragmas, by class.
+	Suppressed map[string]int
+}
@@ -0,0 +1,134 @@
+// parser/directives.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// directivePrefix starts a //jdocgen: directive. Like //go: directives it has no space after
+// the slashes, so go/doc leaves it out of the rendered comment.
+const directivePrefix = "//jdocgen:"
+
+// annotationLine is one function annotation in @-syntax.
+type annotationLine struct {
+	Text      string
+	Line      int
+	Directive bool
+	// Block holds the comment lines following an annotation of ShapeJSON, see jsonBlock.
+	Block []string
+	// Unterminated is set when the ``` fence of Block is not closed in the comment.
+	Unterminated bool
+}
+
+// functionAnnotations returns the annotation lines of a function doc comment. Directives are
+// rewritten to the @-syntax they stand for, so "//jdocgen:param id int" becomes
+// "@Parameter id int" and both styles go through the same grammar. When both styles give the
+// same key, the directive wins and the @-line is dropped with a warning.
+func functionAnnotations(cg *ast.CommentGroup, fset *token.FileSet) ([]annotationLine, Diagnostics) {
+	if cg == nil {
+		return nil, nil
+	}
+
+	var lines []annotationLine
+	var diagnostics Diagnostics
+	block := jsonBlock{index: -1}
+	for _, c := range cg.List {
+		position := fset.Position(c.Pos())
+		if strings.HasPrefix(c.Text, ignorePragma) {
+			continue
+		}
+		if strings.HasPrefix(c.Text, directivePrefix) {
+			block.index = -1
+			name, args, _ := strings.Cut(strings.TrimPrefix(c.Text, "//"), " ")
+			annotation, ok := lookupDirective(name)
+			if !ok {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityWarning,
+					File:     position.Filename,
+					Line:     position.Line,
+					Class:    ClassUnknownDirective,
+					Message:  fmt.Sprintf("unknown directive '//%s'", name),
+				})
+				continue
+			}
+			text := strings.TrimSpace(annotation.Name + " " + strings.TrimSpace(args))
+			lines = append(lines, annotationLine{Text: text, Line: position.Line, Directive: true})
+			block.start(lines)
+			continue
+		}
+
+		text := strings.TrimPrefix(strings.TrimPrefix(stripPragma(c.Text), "//"), "/*")
+		text = strings.TrimSuffix(text, "*/")
+		for offset, raw := range strings.Split(text, "\n") {
+			if block.collect(lines, raw) {
+				continue
+			}
+			line := strings.TrimSpace(raw)
+			if strings.HasPrefix(line, "@") {
+				lines = append(lines, annotationLine{Text: line, Line: position.Line + offset})
+				block.start(lines)
+			}
+		}
+	}
+
+	directives := map[string]int{}
+	for _, line := range lines {
+		if key := annotationKey(line.Text); line.Directive && key != "" {
+			directives[key] = line.Line
+		}
+	}
+	if len(directives) == 0 {
+		return lines, diagnostics
+	}
+
+	kept := lines[:0]
+	for _, line := range lines {
+		key := annotationKey(line.Text)
+		directiveLine, overridden := directives[key]
+		if line.Directive || !overridden {
+			kept = append(kept, line)
+			continue
+		}
+		name := strings.Fields(line.Text)[0]
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			File:     fset.Position(cg.Pos()).Filename,
+			Line:     line.Line,
+			Class:    ClassDirectiveOverride,
+			Message:  fmt.Sprintf("annotation '%s' is also given by the directive on line %d, using the directive", name, directiveLine),
+		})
+	}
+	return kept, diagnostics
+}
+
+// lookupDirective returns the function annotation written as the directive name, such as
+// "jdocgen:param".
+func lookupDirective(name string) (Annotation, bool) {
+	for _, annotation := range Annotations {
+		if annotation.Directive != "" && annotation.Directive == name {
+			return annotation, true
+		}
+	}
+	return Annotation{}, false
+}
+
+// annotationKey identifies what an annotation line sets: the annotation itself, or for a
+// repeatable annotation the annotation and its first argument, so "@Parameter id" and
+// "@Parameter name" are different keys. It returns "" for lines that are not annotations.
+func annotationKey(line string) string {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return ""
+	}
+	annotation, ok := LookupAnnotation(parts[0], ScopeFunction)
+	if !ok {
+		return ""
+	}
+	if annotation.Repeatable && len(parts) > 1 {
+		return annotation.Name + " " + parts[1]
+	}
+	return annotation.Name
+}
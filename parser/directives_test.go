@@ -0,0 +1,85 @@
+// parser/directives_test.go
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectDirectives(t *testing.T) {
+	result, err := ParseProject("testdata/directives")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	functions := map[string]models.APIFunction{}
+	for _, fn := range result.Functions {
+		functions[fn.Command] = fn
+	}
+	if len(functions) != 3 {
+		t.Fatalf("Expected 3 commands, got %d: %v", len(functions), functions)
+	}
+
+	get := functions["user.Get"]
+	if get.Description != "Returns a user." {
+		t.Errorf("user.Get: expected description from the directive, got %q", get.Description)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].Name != "id" || get.Parameters[0].Type != "int" || get.Parameters[0].Description != "User identifier" {
+		t.Errorf("user.Get: unexpected parameters %+v", get.Parameters)
+	}
+	if len(get.Results) != 1 || get.Results[0].Type != "User" {
+		t.Errorf("user.Get: unexpected results %+v", get.Results)
+	}
+	if len(get.Errors) != 1 || get.Errors[0].Code != 404 {
+		t.Errorf("user.Get: unexpected errors %+v", get.Errors)
+	}
+
+	rename, exists := functions["user.Rename"]
+	if !exists {
+		t.Fatalf("Expected the directive to set the command name, got %v", functions)
+	}
+	if rename.Description != "Renames a user." {
+		t.Errorf("user.Rename: expected the @Description, got %q", rename.Description)
+	}
+	params := map[string]string{}
+	for _, p := range rename.Parameters {
+		params[p.Name] = p.Description
+	}
+	if len(rename.Parameters) != 2 || params["id"] != "User identifier" || params["name"] != "New name" {
+		t.Errorf("user.Rename: unexpected parameters %+v", rename.Parameters)
+	}
+	if len(rename.Results) != 1 {
+		t.Errorf("user.Rename: expected the @Result, got %+v", rename.Results)
+	}
+
+	var warnings []string
+	for _, d := range result.Diagnostics {
+		if d.Severity == SeverityWarning {
+			warnings = append(warnings, d.Message)
+		}
+	}
+	expected := []string{
+		"annotation '@Command' is also given by the directive on line 29, using the directive",
+		"annotation '@Parameter' is also given by the directive on line 30, using the directive",
+		"unknown directive '//jdocgen:parameter'",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected warnings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(warnings, "\n"))
+	}
+}
+
+func TestDirectivesMatchAnnotations(t *testing.T) {
+	for _, annotation := range Annotations {
+		if annotation.allows(ScopeFunction) && annotation.Directive == "" {
+			t.Errorf("Function annotation %s has no directive", annotation.Name)
+		}
+		if annotation.Directive == "" {
+			continue
+		}
+		if found, ok := lookupDirective(annotation.Directive); !ok || found.Name != annotation.Name {
+			t.Errorf("Directive %s does not resolve to %s", annotation.Directive, annotation.Name)
+		}
+	}
+}
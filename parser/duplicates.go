@@ -0,0 +1,36 @@
+// parser/duplicates.go
+package parser
+
+import (
+	"fmt"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// checkDuplicateCommands finds commands declared by more than one function. A duplicate is an
+// error unless allow is set, in which case the first declaration is kept, the later ones are
+// dropped and each is reported as a warning.
+func checkDuplicateCommands(apiFunctions []models.APIFunction, allow bool) ([]models.APIFunction, Diagnostics, error) {
+	var diagnostics Diagnostics
+	first := make(map[string]models.APIFunction)
+	kept := apiFunctions[:0]
+	for _, apiFunc := range apiFunctions {
+		previous, exists := first[apiFunc.Command]
+		if !exists {
+			first[apiFunc.Command] = apiFunc
+			kept = append(kept, apiFunc)
+			continue
+		}
+		if !allow {
+			return nil, nil, fmt.Errorf("command '%s' is declared twice, at %s:%d and %s:%d", apiFunc.Command, previous.SourceFile, previous.AnnotationLine("@Command"), apiFunc.SourceFile, apiFunc.AnnotationLine("@Command"))
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			File:     apiFunc.SourceFile,
+			Line:     apiFunc.AnnotationLine("@Command"),
+			Class:    ClassDuplicateCommand,
+			Message:  fmt.Sprintf("command '%s' skipped: already declared at %s:%d", apiFunc.Command, previous.SourceFile, previous.AnnotationLine("@Command")),
+		})
+	}
+	return kept, diagnostics, nil
+}
@@ -0,0 +1,59 @@
+// parser/duplicates_test.go
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProjectDuplicateCommands(t *testing.T) {
+	samefile := filepath.Join("testdata", "duplicates", "samefile", "api.go")
+	api := filepath.Join("testdata", "duplicates", "packages", "api.go")
+	invoices := filepath.Join("testdata", "duplicates", "packages", "billing", "invoices.go")
+	tests := []struct {
+		name       string
+		dir        string
+		wantErr    string
+		wantWarn   string
+		wantKept   string
+		wantTotals int
+	}{
+		{
+			name:       "same file",
+			dir:        filepath.Join("testdata", "duplicates", "samefile"),
+			wantErr:    "command 'users.Get' is declared twice, at " + samefile + ":8 and " + samefile + ":13",
+			wantWarn:   samefile + ":13: warning: command 'users.Get' skipped: already declared at " + samefile + ":8 [duplicate-command]",
+			wantKept:   "Returns a user.",
+			wantTotals: 1,
+		},
+		{
+			name:       "across packages",
+			dir:        filepath.Join("testdata", "duplicates", "packages"),
+			wantErr:    "command 'invoices.Get' is declared twice, at " + api + ":8 and " + invoices + ":4",
+			wantWarn:   invoices + ":4: warning: command 'invoices.Get' skipped: already declared at " + api + ":8 [duplicate-command]",
+			wantKept:   "Returns an invoice.",
+			wantTotals: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseProject(tt.dir); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+
+			result, err := ParseProjects([]string{tt.dir}, Options{AllowDuplicateCommands: true})
+			if err != nil {
+				t.Fatalf("ParseProjects returned error: %v", err)
+			}
+			if len(result.Functions) != tt.wantTotals {
+				t.Fatalf("Expected %d commands, got %d", tt.wantTotals, len(result.Functions))
+			}
+			if result.Functions[0].Description != tt.wantKept {
+				t.Errorf("Expected the first declaration to be kept, got %q", result.Functions[0].Description)
+			}
+			if len(result.Diagnostics) != 1 || result.Diagnostics[0].String() != tt.wantWarn {
+				t.Errorf("Expected the warning %q, got %v", tt.wantWarn, result.Diagnostics)
+			}
+		})
+	}
+}
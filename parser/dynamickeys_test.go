@@ -0,0 +1,52 @@
+// parser/dynamickeys_test.go
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectDynamicKeys(t *testing.T) {
+	result, err := ParseProject("testdata/dynamickeys")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	keys := map[string][]string{}
+	for _, apiFunc := range result.Functions {
+		keys[apiFunc.Command] = apiFunc.Results[0].DynamicKeys
+	}
+	expected := map[string][]string{
+		"stats.Get":     {"host name"},
+		"stats.History": {"host name", "date"},
+		"stats.Total":   {"host name"},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected result keys %v, got %v", expected, keys)
+	}
+
+	stats := result.Structs[models.StructKey{Package: "rpc", Name: "HostStats"}]
+	fieldKeys := map[string][]string{}
+	for _, field := range stats.Fields {
+		fieldKeys[field.Name] = field.DynamicKeys
+	}
+	if want := []string{"date (YYYY-MM-DD)"}; !reflect.DeepEqual(fieldKeys["Daily"], want) {
+		t.Errorf("Expected Daily keys %v, got %v", want, fieldKeys["Daily"])
+	}
+
+	var got []string
+	for _, d := range result.Diagnostics {
+		got = append(got, fmt.Sprintf("%d: %s [%s]", d.Line, d.Message, d.Class))
+	}
+	want := []string{
+		"15: field 'Peak' of struct 'HostStats' has @keys, but its type 'int64' is not a map [dynamic-keys]",
+		"37: command 'stats.Total' has @DynamicKeys, but its result type 'HostStats' is not a map [dynamic-keys]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
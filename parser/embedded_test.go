@@ -0,0 +1,45 @@
+// parser/embedded_test.go
+package parser
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectEmbeddedFields(t *testing.T) {
+	result, err := ParseProject("testdata/embedded")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Expected no diagnostics, got %v", result.Diagnostics)
+	}
+
+	type field struct {
+		name, jsonName string
+		embedded       bool
+	}
+	tests := map[string][]field{
+		// Embedded fields are named after their type, without pointer or package
+		"UserResponse": {{"Meta", "Meta", true}, {"Audit", "Audit", true}, {"Name", "name", false}, {"Created", "created", false}},
+		// An embedded struct with a JSON name is a field like any other
+		"TaggedResponse": {{"Meta", "meta", false}, {"Note", "note", false}},
+	}
+	for name, want := range tests {
+		structDef := result.Structs[models.StructKey{Package: "rpc", Name: name}]
+		var got []field
+		for _, f := range structDef.Fields {
+			got = append(got, field{f.Name, f.JSONName, f.Embedded})
+		}
+		if len(got) != len(want) {
+			t.Errorf("Expected the fields %+v of %s, got %+v", want, name, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Expected field %d of %s to be %+v, got %+v", i, name, want[i], got[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// parser/enums.go
+package parser
+
+import (
+	"go/ast"
+	goconstant "go/constant"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// collectEnums adds the constants of a file declared with a named type, such as
+// "Active Status = "active"", to the enum of their type. Constants without a value of their
+// own repeat the expression above them, as in Go, so iota enums get their computed values.
+func collectEnums(fileAst *ast.File, pkg string, enums map[models.StructKey]*models.EnumDefinition) {
+	for _, decl := range fileAst.Decls {
+		genDecl, isGen := decl.(*ast.GenDecl)
+		if !isGen || genDecl.Tok != token.CONST {
+			continue
+		}
+		var typ ast.Expr
+		var values []ast.Expr
+		known := make(map[string]goconstant.Value)
+		for iota, spec := range genDecl.Specs {
+			valueSpec, isValue := spec.(*ast.ValueSpec)
+			if !isValue {
+				continue
+			}
+			if valueSpec.Type != nil || len(valueSpec.Values) > 0 {
+				typ, values = valueSpec.Type, valueSpec.Values
+			}
+			for i, name := range valueSpec.Names {
+				if i >= len(values) {
+					break
+				}
+				value, ok := constantValue(values[i], int64(iota), known)
+				if !ok {
+					continue
+				}
+				known[name.Name] = value
+				typeName := enumTypeName(typ, values[i])
+				if typeName == "" || name.Name == "_" {
+					continue
+				}
+				enumValue := models.EnumValue{
+					Name:        name.Name,
+					Description: extractFieldDescription(valueSpec.Doc, valueSpec.Comment),
+				}
+				switch value.Kind() {
+				case goconstant.String:
+					enumValue.Value, enumValue.Kind = goconstant.StringVal(value), "string"
+				case goconstant.Int:
+					enumValue.Value, enumValue.Kind = value.ExactString(), "number"
+				case goconstant.Float:
+					f, _ := goconstant.Float64Val(value)
+					enumValue.Value, enumValue.Kind = strconv.FormatFloat(f, 'g', -1, 64), "number"
+				default:
+					continue
+				}
+				key := models.StructKey{Package: pkg, Name: typeName}
+				if enums[key] == nil {
+					enums[key] = &models.EnumDefinition{Type: pkg + "." + typeName}
+				}
+				enums[key].Values = append(enums[key].Values, enumValue)
+			}
+		}
+	}
+}
+
+// enumTypeName returns the type of a constant declared with type typ, or with the conversion
+// value such as Status("active") when it has none, or "" for untyped constants and types of
+// other packages.
+func enumTypeName(typ ast.Expr, value ast.Expr) string {
+	if typ == nil {
+		if call, isCall := value.(*ast.CallExpr); isCall && len(call.Args) == 1 {
+			typ = call.Fun
+		}
+	}
+	if ident, isIdent := typ.(*ast.Ident); isIdent && !isBasicIdent(ident.Name) {
+		return ident.Name
+	}
+	return ""
+}
+
+// isBasicIdent reports whether name is a predeclared type, which constants such as
+// "const Max int = 10" are declared with, rather than an enum type.
+func isBasicIdent(name string) bool {
+	switch name {
+	case "string", "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"uintptr", "byte", "rune", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// constantValue evaluates a constant expression of literals, iota, the constants in known and
+// conversions, such as 1 << iota or Status("active"). It reports false for other expressions.
+func constantValue(expr ast.Expr, iota int64, known map[string]goconstant.Value) (goconstant.Value, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		value := goconstant.MakeFromLiteral(expr.Value, expr.Kind, 0)
+		return value, value.Kind() != goconstant.Unknown
+	case *ast.Ident:
+		if expr.Name == "iota" {
+			return goconstant.MakeInt64(iota), true
+		}
+		value, exists := known[expr.Name]
+		return value, exists
+	case *ast.ParenExpr:
+		return constantValue(expr.X, iota, known)
+	case *ast.CallExpr:
+		if len(expr.Args) != 1 {
+			return nil, false
+		}
+		return constantValue(expr.Args[0], iota, known)
+	case *ast.UnaryExpr:
+		x, ok := constantValue(expr.X, iota, known)
+		if !ok || !isNumber(x) || (expr.Op != token.ADD && expr.Op != token.SUB && (expr.Op != token.XOR || x.Kind() != goconstant.Int)) {
+			return nil, false
+		}
+		return goconstant.UnaryOp(expr.Op, x, 0), true
+	case *ast.BinaryExpr:
+		x, okX := constantValue(expr.X, iota, known)
+		y, okY := constantValue(expr.Y, iota, known)
+		if !okX || !okY {
+			return nil, false
+		}
+		if x.Kind() == goconstant.String && y.Kind() == goconstant.String && expr.Op == token.ADD {
+			return goconstant.BinaryOp(x, expr.Op, y), true
+		}
+		if !isNumber(x) || !isNumber(y) {
+			return nil, false
+		}
+		integers := x.Kind() == goconstant.Int && y.Kind() == goconstant.Int
+		switch expr.Op {
+		case token.ADD, token.SUB, token.MUL:
+			return goconstant.BinaryOp(x, expr.Op, y), true
+		case token.QUO:
+			if goconstant.Sign(y) == 0 {
+				return nil, false
+			}
+			if integers {
+				// Integer constants divide as integers
+				return goconstant.BinaryOp(x, token.QUO_ASSIGN, y), true
+			}
+			return goconstant.BinaryOp(x, expr.Op, y), true
+		case token.REM, token.AND, token.OR, token.XOR, token.AND_NOT:
+			if !integers || (expr.Op == token.REM && goconstant.Sign(y) == 0) {
+				return nil, false
+			}
+			return goconstant.BinaryOp(x, expr.Op, y), true
+		case token.SHL, token.SHR:
+			shift, ok := goconstant.Uint64Val(y)
+			if !ok || x.Kind() != goconstant.Int {
+				return nil, false
+			}
+			return goconstant.Shift(x, expr.Op, uint(shift)), true
+		}
+	}
+	return nil, false
+}
+
+// isNumber reports whether a constant is an integer or a floating-point number.
+func isNumber(value goconstant.Value) bool {
+	return value.Kind() == goconstant.Int || value.Kind() == goconstant.Float
+}
+
+// attachEnums sets the enum of the parameters and struct fields whose type, or the type held
+// by their slices, maps and pointers, has constants declared.
+func attachEnums(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, enums map[models.StructKey]*models.EnumDefinition) {
+	if len(enums) == 0 {
+		return
+	}
+	enumOf := func(ref *models.TypeRef) *models.EnumDefinition {
+		held := ref.Held()
+		if held == nil || held.Alias == "" {
+			return nil
+		}
+		dot := strings.LastIndex(held.Alias, ".")
+		return enums[models.StructKey{Package: held.Alias[:dot], Name: held.Alias[dot+1:]}]
+	}
+	for i := range apiFunctions {
+		for j, param := range apiFunctions[i].Parameters {
+			apiFunctions[i].Parameters[j].Enum = enumOf(param.TypeRef)
+		}
+	}
+	for _, structDef := range structDefinitions {
+		for _, fields := range [][]models.StructField{structDef.Fields, structDef.HiddenFields, structDef.ExcludedFields} {
+			for j, field := range fields {
+				fields[j].Enum = enumOf(field.TypeRef)
+			}
+		}
+	}
+}
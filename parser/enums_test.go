@@ -0,0 +1,107 @@
+// parser/enums_test.go
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/generator"
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectEnums(t *testing.T) {
+	result, err := ParseProject("testdata/enums")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	fields := result.Structs[models.StructKey{Package: "rpc", Name: "Account"}].Fields
+	tests := []struct {
+		field string
+		enum  *models.EnumDefinition
+	}{
+		{"Status", &models.EnumDefinition{Type: "rpc.Status", Values: []models.EnumValue{
+			{Name: "Active", Value: "active", Kind: "string", Description: "Can sign in"},
+			{Name: "Suspended", Value: "suspended", Kind: "string", Description: "Locked by an administrator"},
+			{Name: "Deleted", Value: "deleted", Kind: "string"},
+		}}},
+		// Constants repeat the iota expression above them, and _ is skipped
+		{"Priority", &models.EnumDefinition{Type: "rpc.Priority", Values: []models.EnumValue{
+			{Name: "Low", Value: "1", Kind: "number"},
+			{Name: "Normal", Value: "2", Kind: "number"},
+			{Name: "High", Value: "4", Kind: "number"},
+		}}},
+		// Slices list the values of their elements
+		{"Permissions", &models.EnumDefinition{Type: "rpc.Permission", Values: []models.EnumValue{
+			{Name: "Read", Value: "1", Kind: "number"},
+			{Name: "Write", Value: "2", Kind: "number"},
+			{Name: "Execute", Value: "4", Kind: "number"},
+		}}},
+		{"Limit", nil},
+	}
+	for i, tt := range tests {
+		got := fields[i].Enum
+		if fields[i].Name != tt.field || (got == nil) != (tt.enum == nil) {
+			t.Errorf("Expected field %s to have the enum %+v, got field %s with %+v", tt.field, tt.enum, fields[i].Name, got)
+			continue
+		}
+		if got == nil {
+			continue
+		}
+		if got.Type != tt.enum.Type || len(got.Values) != len(tt.enum.Values) {
+			t.Errorf("Expected field %s to have the enum %+v, got %+v", tt.field, tt.enum, got)
+			continue
+		}
+		for j := range tt.enum.Values {
+			if got.Values[j] != tt.enum.Values[j] {
+				t.Errorf("Expected value %d of field %s to be %+v, got %+v", j, tt.field, tt.enum.Values[j], got.Values[j])
+			}
+		}
+	}
+	if param := result.Functions[0].Parameters[0]; param.Enum == nil || param.Enum.Type != "rpc.Status" {
+		t.Errorf("Expected parameter %s to have the enum rpc.Status, got %+v", param.Name, param.Enum)
+	}
+
+	output := filepath.Join(t.TempDir(), "API.md")
+	if err := generator.GenerateDocumentation(result.Functions, result.Structs, result.ProjectInfo, output, generator.Options{}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| status | string (Status) | Status of the accounts _Allowed values: active (Can sign in), suspended (Locked by an administrator), deleted._ | Yes |\n",
+		"| Status | string (Status) | Status of the account _Allowed values: active (Can sign in), suspended (Locked by an administrator), deleted._ | status | Yes |\n",
+		"| Priority | int (Priority) | Priority of its tasks _Allowed values: 1, 2, 4._ | priority | Yes |\n",
+		"| Permissions | []uint16 ([]Permission) | Permissions of the account _Allowed values: 1, 2, 4._ | permissions | Yes |\n",
+		"| Limit | int | Page size | limit | Yes |\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	output = filepath.Join(t.TempDir(), "openrpc.json")
+	if err := generator.GenerateOpenRPC(result.Functions, result.Structs, result.ProjectInfo, output, generator.Options{}); err != nil {
+		t.Fatalf("GenerateOpenRPC returned error: %v", err)
+	}
+	if content, err = os.ReadFile(output); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"enum": [
+              "active",
+              "suspended",
+              "deleted"
+            ]`, `"enum": [
+              1,
+              2,
+              4
+            ]`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected the OpenRPC document to contain %q, got:\n%s", want, content)
+		}
+	}
+}
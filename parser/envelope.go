@@ -0,0 +1,106 @@
+// parser/envelope.go
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// JSONRPCVersions are the protocol versions accepted by @Envelope jsonrpc=.
+var JSONRPCVersions = []string{"1.0", "2.0"}
+
+// envelopeIDTypes are the request id types accepted by @Envelope id=.
+var envelopeIDTypes = []string{"number", "string"}
+
+// checkEnvelopes reports @Envelope values that are not known and clears them, so the
+// command falls back to the project-wide settings.
+func checkEnvelopes(apiFunctions []models.APIFunction) Diagnostics {
+	var diagnostics Diagnostics
+	for i := range apiFunctions {
+		apiFunc := &apiFunctions[i]
+		if apiFunc.Envelope.JSONRPC != "" && !contains(JSONRPCVersions, apiFunc.Envelope.JSONRPC) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				File:     apiFunc.SourceFile,
+				Line:     apiFunc.AnnotationLine("@Envelope"),
+				Class:    ClassEnvelope,
+				Message:  fmt.Sprintf("command '%s' has unknown @Envelope jsonrpc version '%s', expected one of: %s", apiFunc.Command, apiFunc.Envelope.JSONRPC, strings.Join(JSONRPCVersions, ", ")),
+			})
+			apiFunc.Envelope.JSONRPC = ""
+		}
+		if apiFunc.Envelope.IDType != "" && !contains(envelopeIDTypes, apiFunc.Envelope.IDType) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				File:     apiFunc.SourceFile,
+				Line:     apiFunc.AnnotationLine("@Envelope"),
+				Class:    ClassEnvelope,
+				Message:  fmt.Sprintf("command '%s' has unknown @Envelope id type '%s', expected one of: %s", apiFunc.Command, apiFunc.Envelope.IDType, strings.Join(envelopeIDTypes, ", ")),
+			})
+			apiFunc.Envelope.IDType = ""
+		}
+	}
+	return diagnostics
+}
+
+// locateEnvelope records where the project annotations of projectInfo were found, so the
+// member types of its result envelope resolve in that file.
+func locateEnvelope(projectInfo models.ProjectInfo, path string, pkg string, importAliases map[string]string) models.ProjectInfo {
+	projectInfo.ResultEnvelope.Package = pkg
+	projectInfo.ResultEnvelope.ImportAliases = importAliases
+	projectInfo.ResultEnvelope.SourceFile = path
+	return projectInfo
+}
+
+// resolveEnvelope resolves the member types of the result envelope and reports envelopes
+// without exactly one RESULT member, repeated member names and unknown member types.
+func resolveEnvelope(envelope *models.ResultEnvelope, structDefinitions map[models.StructKey]models.StructDefinition) Diagnostics {
+	if len(envelope.Members) == 0 {
+		return nil
+	}
+
+	var diagnostics Diagnostics
+	warn := func(format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			File:     envelope.SourceFile,
+			Class:    ClassEnvelope,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	results := 0
+	names := make(map[string]bool)
+	for i := range envelope.Members {
+		member := &envelope.Members[i]
+		if names[member.Name] {
+			warn("@envelope member '%s' is declared twice", member.Name)
+		}
+		names[member.Name] = true
+
+		if member.Type == models.EnvelopeResult {
+			results++
+			continue
+		}
+		member.TypeRef = utils.ResolveTypeRef(utils.ParseType(member.Type), envelope.Package, envelope.ImportAliases, structDefinitions)
+		if held := member.TypeRef.Held(); held.Kind == models.TypeNamed {
+			warn("@envelope member '%s' has type '%s', which is not a known struct", member.Name, member.Type)
+		}
+	}
+	if results != 1 {
+		warn("@envelope must have exactly one %s member, found %d", models.EnvelopeResult, results)
+	}
+	return diagnostics
+}
+
+// contains reports whether values contains value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
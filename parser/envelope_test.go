@@ -0,0 +1,103 @@
+// parser/envelope_test.go
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectEnvelopes(t *testing.T) {
+	result, err := ParseProject("testdata/envelope")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	envelopes := map[string]models.Envelope{}
+	for _, fn := range result.Functions {
+		envelopes[fn.Command] = fn.Envelope
+	}
+	want := map[string]models.Envelope{
+		"legacy.Get":  {JSONRPC: "1.0", IDType: "string"},
+		"current.Get": {},
+		// Unknown values fall back to the project-wide settings
+		"future.Get": {},
+	}
+	if len(envelopes) != len(want) {
+		t.Errorf("Expected commands %v, got %v", want, envelopes)
+	}
+	for command, envelope := range want {
+		if got, exists := envelopes[command]; !exists || got != envelope {
+			t.Errorf("%s: expected envelope %+v, got %+v", command, envelope, got)
+		}
+	}
+
+	var warnings []string
+	for _, d := range result.Diagnostics {
+		if d.Severity == SeverityWarning {
+			warnings = append(warnings, d.Message)
+		}
+	}
+	expected := []string{
+		"function 'Broken' skipped: invalid @Envelope setting 'version'. Expected key=value",
+		"command 'future.Get' has unknown @Envelope jsonrpc version '3.0', expected one of: 1.0, 2.0",
+		"command 'future.Get' has unknown @Envelope id type 'uuid', expected one of: number, string",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected warnings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(warnings, "\n"))
+	}
+}
+
+func TestParseProjectResultEnvelope(t *testing.T) {
+	result, err := ParseProject("testdata/resultenvelope")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if warnings := result.Diagnostics.Count(SeverityWarning); warnings > 0 {
+		t.Errorf("Expected no warnings, got %v", result.Diagnostics)
+	}
+
+	envelope := result.ProjectInfo.ResultEnvelope
+	if len(envelope.Members) != 2 || envelope.Members[0].Name != "data" || envelope.Members[0].Type != models.EnvelopeResult {
+		t.Fatalf("Expected members data=RESULT and meta=ResponseMeta, got %+v", envelope.Members)
+	}
+	meta := envelope.Members[1]
+	if meta.Name != "meta" || meta.TypeRef == nil || meta.TypeRef.Struct != (models.StructKey{Package: "rpc", Name: "ResponseMeta"}) {
+		t.Errorf("Expected meta to resolve to rpc.ResponseMeta, got %+v", meta)
+	}
+
+	for _, fn := range result.Functions {
+		if want := fn.Command == "health"; fn.NoEnvelope != want {
+			t.Errorf("%s: expected NoEnvelope %v, got %v", fn.Command, want, fn.NoEnvelope)
+		}
+	}
+}
+
+func TestResolveEnvelopeWarnings(t *testing.T) {
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "ResponseMeta"}: {Name: "ResponseMeta"},
+	}
+	envelope := models.ResultEnvelope{
+		Package:    "rpc",
+		SourceFile: "doc.go",
+		Members: []models.EnvelopeMember{
+			{Name: "meta", Type: "ResponseMeta"},
+			{Name: "meta", Type: "*Paging"},
+			{Name: "count", Type: "int"},
+		},
+	}
+
+	var got []string
+	for _, d := range resolveEnvelope(&envelope, structs) {
+		got = append(got, d.String())
+	}
+	expected := []string{
+		"doc.go: warning: @envelope member 'meta' is declared twice [envelope]",
+		"doc.go: warning: @envelope member 'meta' has type '*Paging', which is not a known struct [envelope]",
+		"doc.go: warning: @envelope must have exactly one RESULT member, found 0 [envelope]",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
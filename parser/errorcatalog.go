@@ -0,0 +1,120 @@
+// parser/errorcatalog.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// parseCatalogEntry parses a line of an @errorcatalog block, such as
+// -32004 UserNotFound "The user does not exist.", checking that its code and name are not
+// already in catalog.
+func parseCatalogEntry(line string, catalog []models.CatalogError) (models.CatalogError, error) {
+	parts := strings.Fields(line)
+	if len(parts) < 3 {
+		return models.CatalogError{}, fmt.Errorf("invalid @errorcatalog entry '%s'. Expected format: code name \"description\"", line)
+	}
+	code, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return models.CatalogError{}, fmt.Errorf("invalid @errorcatalog entry '%s': %w", line, ErrInvalidErrorCode)
+	}
+	if !utils.IsValidID(parts[1]) {
+		return models.CatalogError{}, fmt.Errorf("invalid @errorcatalog name '%s': only letters, digits, '.', '_' and '-' are allowed", parts[1])
+	}
+	for _, entry := range catalog {
+		if entry.Code == code {
+			return models.CatalogError{}, fmt.Errorf("error code %d is declared twice in @errorcatalog", code)
+		}
+		if entry.Name == parts[1] {
+			return models.CatalogError{}, fmt.Errorf("error name '%s' is declared twice in @errorcatalog", parts[1])
+		}
+	}
+	return models.CatalogError{Code: code, Name: parts[1], Description: annotationDescription(line, 2)}, nil
+}
+
+// checkErrorCatalog reports the invalid entries of the @errorcatalog blocks of a project doc
+// comment, which parseGlobalTags skips.
+func checkErrorCatalog(cg *ast.CommentGroup, fset *token.FileSet) Diagnostics {
+	if cg == nil {
+		return nil
+	}
+
+	var diagnostics Diagnostics
+	var catalog []models.CatalogError
+	inCatalog := false
+	for _, c := range cg.List {
+		position := fset.Position(c.Pos())
+		text := strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), "/*")
+		text = strings.TrimSuffix(text, "*/")
+		for offset, line := range strings.Split(text, "\n") {
+			line = strings.TrimSpace(line)
+			if !inCatalog || line == "" || strings.HasPrefix(line, "@") {
+				fields := strings.Fields(line)
+				inCatalog = len(fields) > 0 && strings.EqualFold(fields[0], "@errorcatalog")
+				continue
+			}
+			entry, err := parseCatalogEntry(line, catalog)
+			if err != nil {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					File:     position.Filename,
+					Line:     position.Line + offset,
+					Class:    ClassErrorCatalog,
+					Message:  err.Error() + ", entry ignored",
+				})
+				continue
+			}
+			catalog = append(catalog, entry)
+		}
+	}
+	return diagnostics
+}
+
+// resolveErrors names the @Error codes of the commands found in the catalog, and gives the
+// codes written without a description the description of the catalog. A description that
+// differs from the catalog is kept with a warning, and a code with neither a description nor
+// a catalog entry is an error. Codes missing from the catalog are reported by the linter.
+func resolveErrors(apiFunctions []models.APIFunction, catalog []models.CatalogError) Diagnostics {
+	entries := make(map[int]models.CatalogError, len(catalog))
+	for _, entry := range catalog {
+		entries[entry.Code] = entry
+	}
+
+	var diagnostics Diagnostics
+	for i := range apiFunctions {
+		apiFunc := &apiFunctions[i]
+		for j := range apiFunc.Errors {
+			apiError := &apiFunc.Errors[j]
+			report := func(severity Severity, format string, args ...any) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: severity,
+					File:     apiFunc.SourceFile,
+					Line:     apiError.SourceLine,
+					Class:    ClassErrorCatalog,
+					Message:  fmt.Sprintf(format, args...),
+				})
+			}
+
+			entry, found := entries[apiError.Code]
+			switch {
+			case !found && apiError.Description == "":
+				report(SeverityError, "error %d of command '%s' has no description and is not in the @errorcatalog", apiError.Code, apiFunc.Command)
+			case !found:
+			case apiError.Description == "":
+				apiError.Name, apiError.Description = entry.Name, entry.Description
+			default:
+				apiError.Name = entry.Name
+				if apiError.Description != entry.Description {
+					report(SeverityWarning, "error %d of command '%s' overrides the @errorcatalog description of %s", apiError.Code, apiFunc.Command, entry.Name)
+				}
+			}
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,74 @@
+// parser/errorcatalog_test.go
+package parser
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectErrorCatalog(t *testing.T) {
+	result, err := ParseProject("testdata/errorcatalog")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	// Invalid entries are skipped, the rest of the project block is still read
+	wantCatalog := []models.CatalogError{
+		{Code: -32004, Name: "UserNotFound", Description: "The user does not exist."},
+		{Code: -32005, Name: "QuotaExceeded", Description: "The quota of the account is exhausted."},
+	}
+	if !reflect.DeepEqual(result.ProjectInfo.ErrorCatalog, wantCatalog) {
+		t.Errorf("Unexpected catalog:\n%+v\nwant:\n%+v", result.ProjectInfo.ErrorCatalog, wantCatalog)
+	}
+	if result.ProjectInfo.License != "MIT" {
+		t.Errorf("Expected the license after the catalog to be read, got %q", result.ProjectInfo.License)
+	}
+
+	type apiError struct {
+		Code        int
+		Name        string
+		Description string
+	}
+	want := map[string][]apiError{
+		// Bare codes inherit the catalog description, overrides keep their own
+		"users.Get": {
+			{-32004, "UserNotFound", "The user does not exist."},
+			{-32005, "QuotaExceeded", "No requests left this hour."},
+			{400, "", "Bad request."},
+		},
+		"users.Delete": {
+			{-32004, "UserNotFound", "The user does not exist."},
+			{500, "", ""},
+		},
+	}
+	for _, fn := range result.Functions {
+		var got []apiError
+		for _, e := range fn.Errors {
+			got = append(got, apiError{e.Code, e.Name, e.Description})
+		}
+		if !reflect.DeepEqual(got, want[fn.Command]) {
+			t.Errorf("Unexpected errors of %s:\n%+v\nwant:\n%+v", fn.Command, got, want[fn.Command])
+		}
+	}
+
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Class == ClassErrorCatalog {
+			got = append(got, diag.Severity.String()+" "+filepath.Base(diag.File)+":"+strconv.Itoa(diag.Line)+": "+diag.Message)
+		}
+	}
+	wantDiagnostics := []string{
+		"error api.go:8: error code -32004 is declared twice in @errorcatalog, entry ignored",
+		"error api.go:9: invalid @errorcatalog name 'Not/Valid': only letters, digits, '.', '_' and '-' are allowed, entry ignored",
+		"warning api.go:18: error -32005 of command 'users.Get' overrides the @errorcatalog description of QuotaExceeded",
+		"error api.go:26: error 500 of command 'users.Delete' has no description and is not in the @errorcatalog",
+	}
+	if strings.Join(got, "\n") != strings.Join(wantDiagnostics, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(wantDiagnostics, "\n"))
+	}
+}
@@ -0,0 +1,116 @@
+// parser/examples.go
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// jsonBlock collects the JSON document following an annotation of ShapeJSON in a doc
+// comment: either the indented lines following it, as gofmt formats code blocks, or the
+// lines between ``` fences. Empty lines before and inside the document belong to it.
+type jsonBlock struct {
+	// index is the position in the annotation lines of the annotation collecting the block,
+	// -1 outside of a block.
+	index  int
+	fenced bool
+}
+
+// start begins a block when the last annotation line takes a JSON document.
+func (b *jsonBlock) start(lines []annotationLine) {
+	b.index, b.fenced = -1, false
+	fields := strings.Fields(lines[len(lines)-1].Text)
+	annotation, ok := LookupAnnotation(fields[0], ScopeFunction)
+	if ok && len(annotation.Arguments) > 0 && annotation.Arguments[0].Shape == ShapeJSON {
+		b.index = len(lines) - 1
+	}
+}
+
+// collect adds raw, a comment line without its comment markers, to the current block and
+// reports whether it belongs to it. The first line not belonging to the block ends it.
+func (b *jsonBlock) collect(lines []annotationLine, raw string) bool {
+	if b.index < 0 {
+		return false
+	}
+	line := &lines[b.index]
+	trimmed := strings.TrimSpace(raw)
+	switch {
+	case b.fenced && strings.HasPrefix(trimmed, "```"):
+		line.Unterminated = false
+		b.index = -1
+	case b.fenced:
+		line.Block = append(line.Block, raw)
+	case strings.HasPrefix(trimmed, "```") && len(line.Block) == 0:
+		line.Unterminated = true
+		b.fenced = true
+	case trimmed == "":
+		line.Block = append(line.Block, "")
+	case isIndented(raw):
+		line.Block = append(line.Block, raw)
+	default:
+		b.index = -1
+		return false
+	}
+	return true
+}
+
+// isIndented reports whether a comment line is indented past the space following "//".
+func isIndented(raw string) bool {
+	raw = strings.TrimPrefix(raw, " ")
+	return strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")
+}
+
+// exampleJSON returns the JSON document of an @ExampleRequest or @ExampleResponse line: the
+// rest of the line or its block, without the indentation common to its lines.
+func exampleJSON(annotationLine annotationLine) (string, error) {
+	if annotationLine.Unterminated {
+		return "", errors.New("unterminated ``` block")
+	}
+	var lines []string
+	if rest := restOfLine(annotationLine.Text, 1); rest != "" {
+		lines = append(lines, rest)
+	}
+	lines = append(lines, annotationLine.Block...)
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return "", errors.New("missing JSON document, indent it or enclose it in ``` fences on the following lines")
+	}
+
+	indent := leadingSpace(lines[0])
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) != "" {
+			indent = commonPrefix(indent, leadingSpace(line))
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(strings.TrimPrefix(line, indent), " \t")
+	}
+	document := strings.Join(lines, "\n")
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		return "", fmt.Errorf("invalid JSON: %v", err)
+	}
+	return document, nil
+}
+
+// leadingSpace returns the spaces and tabs starting a line.
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
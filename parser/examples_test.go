@@ -0,0 +1,113 @@
+// parser/examples_test.go
+package parser
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestParseFunctionHandWrittenExamples(t *testing.T) {
+	src := "package rpc\n\n" +
+		"// Get is formatted by gofmt, with an indented block.\n" +
+		"//\n" +
+		"// @Command users.Get\n" +
+		"// @Description Get a user.\n" +
+		"// @ExampleRequest\n" +
+		"//\n" +
+		"//\t{\"jsonrpc\": \"2.0\", \"method\": \"users.Get\", \"params\": {\"id\": 7}, \"id\": 1}\n" +
+		"//\n" +
+		"// @ExampleResponse\n" +
+		"//\n" +
+		"//\t{\n" +
+		"//\t  \"jsonrpc\": \"2.0\",\n" +
+		"//\t  \"result\": {\"@type\": \"user\", \"id\": 7},\n" +
+		"//\n" +
+		"//\t  \"id\": 1\n" +
+		"//\t}\n" +
+		"//\n" +
+		"// @Error -32004 \"Not found.\"\n" +
+		"func Get() {}\n\n" +
+		"// @Command users.List\n" +
+		"// @Description List users.\n" +
+		"// @ExampleResponse\n" +
+		"// ```json\n" +
+		"// {\"result\": [\n" +
+		"//   \"@Error 1\"\n" +
+		"// ]}\n" +
+		"// ```\n" +
+		"// @ExampleRequest {\"method\": \"users.List\"}\n" +
+		"func List() {}\n\n" +
+		"// @Command users.Unterminated\n" +
+		"// @Description Unterminated.\n" +
+		"// @ExampleResponse\n" +
+		"// ```\n" +
+		"// {}\n" +
+		"func Unterminated() {}\n\n" +
+		"// @Command users.Invalid\n" +
+		"// @Description Invalid.\n" +
+		"// @ExampleRequest\n" +
+		"//   {\"id\": }\n" +
+		"func Invalid() {}\n\n" +
+		"// @Command users.Empty\n" +
+		"// @Description Empty.\n" +
+		"// @ExampleRequest\n" +
+		"// Some text.\n" +
+		"func Empty() {}\n"
+	fset := token.NewFileSet()
+	fileAst, err := parser.ParseFile(fset, "api.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	functions := map[string]*ast.FuncDecl{}
+	for _, decl := range fileAst.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			functions[fn.Name.Name] = fn
+		}
+	}
+
+	apiFunc, err := parseFunction(functions["Get"], "rpc", nil, "api.go", fset, nil)
+	if err != nil {
+		t.Fatalf("parseFunction returned error: %v", err)
+	}
+	if want := `{"jsonrpc": "2.0", "method": "users.Get", "params": {"id": 7}, "id": 1}`; apiFunc.ExampleRequest != want {
+		t.Errorf("Expected request %q, got %q", want, apiFunc.ExampleRequest)
+	}
+	if want := "{\n  \"jsonrpc\": \"2.0\",\n  \"result\": {\"@type\": \"user\", \"id\": 7},\n\n  \"id\": 1\n}"; apiFunc.ExampleResponse != want {
+		t.Errorf("Expected response %q, got %q", want, apiFunc.ExampleResponse)
+	}
+	// The annotation after the block is still parsed
+	if len(apiFunc.Errors) != 1 {
+		t.Errorf("Expected the @Error after the example, got %v", apiFunc.Errors)
+	}
+
+	apiFunc, err = parseFunction(functions["List"], "rpc", nil, "api.go", fset, nil)
+	if err != nil {
+		t.Fatalf("parseFunction returned error: %v", err)
+	}
+	if want := "{\"result\": [\n  \"@Error 1\"\n]}"; apiFunc.ExampleResponse != want {
+		t.Errorf("Expected fenced response %q, got %q", want, apiFunc.ExampleResponse)
+	}
+	if want := `{"method": "users.List"}`; apiFunc.ExampleRequest != want {
+		t.Errorf("Expected inline request %q, got %q", want, apiFunc.ExampleRequest)
+	}
+
+	tests := []struct {
+		function string
+		line     int
+		message  string
+	}{
+		{"Unterminated", 36, "malformed @ExampleResponse annotation: unterminated ``` block"},
+		{"Invalid", 43, "malformed @ExampleRequest annotation: invalid JSON: invalid character '}' looking for beginning of value"},
+		{"Empty", 49, "malformed @ExampleRequest annotation: missing JSON document, indent it or enclose it in ``` fences on the following lines"},
+	}
+	for _, tt := range tests {
+		_, err := parseFunction(functions[tt.function], "rpc", nil, "api.go", fset, nil)
+		var located *annotationError
+		if !errors.As(err, &located) || located.Line != tt.line || err.Error() != tt.message {
+			t.Errorf("%s: expected %q at line %d, got %v", tt.function, tt.message, tt.line, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// parser/exclude.go
+package parser
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Options controls which files ParseProjects reads and how it handles them.
+type Options struct {
+	// Exclude holds glob patterns of the files and directories left out of the parse, see
+	// matchExclude.
+	Exclude []string
+	// AllowDuplicateCommands keeps the first of the functions declaring the same command,
+	// with a warning, instead of failing.
+	AllowDuplicateCommands bool
+}
+
+// Validate checks the syntax of the exclude patterns, so a typo is reported instead of
+// silently matching nothing.
+func (opts Options) Validate() error {
+	for _, pattern := range opts.Exclude {
+		if strings.Trim(pattern, "/") == "" {
+			return fmt.Errorf("invalid exclude pattern %q: empty pattern", pattern)
+		}
+		for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid exclude pattern %q: %v", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Excludes reports whether a file or directory matches one of the exclude patterns, for
+// tools walking the sources like the parser. relPath is relative to the root being walked.
+func (opts Options) Excludes(relPath string, isDir bool) bool {
+	return excluded(opts.Exclude, filepath.ToSlash(relPath), isDir)
+}
+
+// excluded reports whether a file or directory matches one of the exclude patterns. relPath
+// is slash-separated and relative to the root being walked.
+func excluded(patterns []string, relPath string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if matchExclude(pattern, relPath, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchExclude matches a path relative to the root against an exclude pattern:
+//
+//   - '*', '?' and '[...]' match within a path segment, like path.Match
+//   - "**" as a whole segment matches any number of segments, including none, so
+//     "**/mocks/**" matches the mocks directories at any depth
+//   - a pattern without '/' matches the name of a file or directory at any depth, so
+//     "*_gen.go" matches every generated file and "mocks" every mocks directory
+//   - other patterns match the whole path from the root, such as "third_party/*.go"
+//   - a trailing '/' only matches directories, such as "mocks/"
+//
+// Files under a matching directory are never read, since the directory is skipped.
+func matchExclude(pattern, relPath string, isDir bool) bool {
+	if strings.HasSuffix(pattern, "/") {
+		if !isDir {
+			return false
+		}
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(relPath))
+		return matched
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where "**" matches any
+// number of segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(segments); skip++ {
+			if matchSegments(pattern[1:], segments[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	matched, _ := path.Match(pattern[0], segments[0])
+	return matched && matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,77 @@
+// parser/exclude_test.go
+package parser
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMatchExclude(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		// Patterns without '/' match names at any depth
+		{"*_gen.go", "user_gen.go", false, true},
+		{"*_gen.go", "a/b/user_gen.go", false, true},
+		{"*_gen.go", "user.go", false, false},
+		{"mocks", "mocks", true, true},
+		{"mocks", "internal/mocks", true, true},
+		// "**" matches any number of segments, including none
+		{"**/mocks/**", "mocks", true, true},
+		{"**/mocks/**", "internal/mocks", true, true},
+		{"**/mocks/**", "internal/mocks/mock.go", false, true},
+		{"**/mocks/**", "mockservers", true, false},
+		{"internal/**/*.go", "internal/a/b/c.go", false, true},
+		{"internal/**/*.go", "internal/c.go", false, true},
+		{"internal/**/*.go", "other/c.go", false, false},
+		// Other patterns are anchored at the root
+		{"third_party/*", "third_party/lib", true, true},
+		{"third_party/*", "vendor/third_party/lib", true, false},
+		{"/third_party", "third_party", true, true},
+		// A trailing '/' only matches directories
+		{"mocks/", "mocks", true, true},
+		{"mocks/", "mocks", false, false},
+		{"third_party/", "third_party", true, true},
+	}
+	for _, tt := range tests {
+		if got := matchExclude(tt.pattern, tt.path, tt.isDir); got != tt.want {
+			t.Errorf("matchExclude(%q, %q, %v) = %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestParseProjectsExclude(t *testing.T) {
+	root := filepath.Join("testdata", "exclude")
+	structSources := func(opts Options) string {
+		t.Helper()
+		result, err := ParseProjects([]string{root}, opts)
+		if err != nil {
+			t.Fatalf("ParseProjects returned error: %v", err)
+		}
+		var sources []string
+		for key, def := range result.Structs {
+			rel, _ := filepath.Rel(root, def.SourceFile)
+			sources = append(sources, key.Name+"@"+filepath.ToSlash(rel))
+		}
+		sort.Strings(sources)
+		return strings.Join(sources, ",")
+	}
+
+	if got, want := structSources(Options{}), "DeepMock@internal/mocks/mock.go,MockUser@mocks/mock.go,User@user_gen.go,Vendored@third_party/lib/lib.go"; got != want {
+		t.Errorf("Expected structs %s, got %s", want, got)
+	}
+	opts := Options{Exclude: []string{"*_gen.go", "**/mocks/**", "third_party/"}}
+	if got, want := structSources(opts), "User@api.go"; got != want {
+		t.Errorf("Expected structs %s with %v, got %s", want, opts.Exclude, got)
+	}
+
+	_, err := ParseProjects([]string{root}, Options{Exclude: []string{"[mocks"}})
+	if err == nil || !strings.Contains(err.Error(), `invalid exclude pattern "[mocks"`) {
+		t.Errorf("Expected an invalid pattern error, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+// parser/excluded.go
+package parser
+
+import (
+	"sort"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// ShowExcludedFields puts the fields tagged json:"-" back in their structs, in declaration
+// order, so they are documented with an "excluded from JSON" marker. It returns the number
+// of fields put back. Examples and schemas leave them out all the same.
+func (r *Result) ShowExcludedFields() int {
+	shown := 0
+	for key, structDef := range r.Structs {
+		if len(structDef.ExcludedFields) == 0 {
+			continue
+		}
+		shown += len(structDef.ExcludedFields)
+		fields := append(append([]models.StructField{}, structDef.Fields...), structDef.ExcludedFields...)
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].SourceLine < fields[j].SourceLine
+		})
+		structDef.Fields, structDef.ExcludedFields = fields, nil
+		r.Structs[key] = structDef
+	}
+	return shown
+}
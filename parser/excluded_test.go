@@ -0,0 +1,52 @@
+// parser/excluded_test.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectExcludedFields(t *testing.T) {
+	result, err := ParseProject("testdata/excluded")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	fieldNames := func(fields []models.StructField) []string {
+		var names []string
+		for _, field := range fields {
+			names = append(names, field.Name+"="+field.JSONName)
+		}
+		return names
+	}
+	sessionKey := models.StructKey{Package: "rpc", Name: "Session"}
+	pageKey := models.StructKey{Package: "rpc", Name: "Page[Session]"}
+
+	// Fields tagged json:"-" are set aside, json:"-," names a field "-"
+	session := result.Structs[sessionKey]
+	if got, want := fieldNames(session.Fields), []string{"Token=token", "Dash=-", "Expiry=expiry"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected fields %v, got %v", want, got)
+	}
+	if len(session.ExcludedFields) != 1 || !session.ExcludedFields[0].Excluded || session.ExcludedFields[0].JSONName != "" {
+		t.Errorf("Expected Secret to be excluded, got %+v", session.ExcludedFields)
+	}
+	if session.Fields[2].Excluded || !session.Fields[2].Omitempty {
+		t.Errorf("Expected Expiry to be omitempty and not excluded, got %+v", session.Fields[2])
+	}
+	if page := result.Structs[pageKey]; len(page.ExcludedFields) != 1 || page.ExcludedFields[0].Name != "Cursor" {
+		t.Errorf("Expected the instantiated page to exclude Cursor, got %+v", page)
+	}
+
+	if shown := result.ShowExcludedFields(); shown != 3 {
+		t.Errorf("Expected 3 excluded fields shown, got %d", shown)
+	}
+	session = result.Structs[sessionKey]
+	if got, want := fieldNames(session.Fields), []string{"Token=token", "Secret=", "Dash=-", "Expiry=expiry"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected fields %v in declaration order, got %v", want, got)
+	}
+	if len(session.ExcludedFields) > 0 || session.Fields[1].TypeRef == nil {
+		t.Errorf("Expected the excluded field to be resolved and moved to Fields, got %+v", session)
+	}
+}
@@ -0,0 +1,75 @@
+// parser/features.go
+package parser
+
+import (
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// FeatureFilter selects commands by their @Feature flags. Commands without @Feature are always kept.
+type FeatureFilter struct {
+	// With lists the enabled flags. When set, a command is kept only if all its flags are enabled.
+	With []string
+	// Without lists excluded flags. A command with any of them is removed.
+	Without []string
+}
+
+// FeatureReport counts the commands removed by a FeatureFilter, by the flag that removed them.
+type FeatureReport struct {
+	// Excluded counts commands removed because a flag is listed in FeatureFilter.Without.
+	Excluded map[string]int
+	// NotEnabled counts commands removed because a flag is missing from FeatureFilter.With.
+	NotEnabled map[string]int
+	// Total is the number of commands removed.
+	Total int
+}
+
+// FilterFeatures removes the commands rejected by filter from the result. It runs before
+// generation so every output format documents the same set of commands.
+func (r *Result) FilterFeatures(filter FeatureFilter) FeatureReport {
+	report := FeatureReport{
+		Excluded:   make(map[string]int),
+		NotEnabled: make(map[string]int),
+	}
+	with := toSet(filter.With)
+	without := toSet(filter.Without)
+
+	kept := r.Functions[:0]
+	for _, apiFunc := range r.Functions {
+		if rejectFeatures(apiFunc, with, without, &report) {
+			report.Total++
+			continue
+		}
+		kept = append(kept, apiFunc)
+	}
+	r.Functions = kept
+	return report
+}
+
+// rejectFeatures reports whether apiFunc is rejected, counting it under the first flag responsible.
+// Excluded flags take precedence over flags that are not enabled.
+func rejectFeatures(apiFunc models.APIFunction, with, without map[string]bool, report *FeatureReport) bool {
+	for _, feature := range apiFunc.Features {
+		if without[feature] {
+			report.Excluded[feature]++
+			return true
+		}
+	}
+	if len(with) == 0 {
+		return false
+	}
+	for _, feature := range apiFunc.Features {
+		if !with[feature] {
+			report.NotEnabled[feature]++
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
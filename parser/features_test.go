@@ -0,0 +1,73 @@
+// parser/features_test.go
+package parser
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFilterFeatures(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     FeatureFilter
+		commands   []string
+		excluded   map[string]int
+		notEnabled map[string]int
+	}{
+		{
+			name:       "no filter",
+			commands:   []string{"beta.Preview", "payments.Pay", "payments.Refund", "ping"},
+			excluded:   map[string]int{},
+			notEnabled: map[string]int{},
+		},
+		{
+			name:       "with",
+			filter:     FeatureFilter{With: []string{"payments_v2"}},
+			commands:   []string{"payments.Pay", "ping"},
+			excluded:   map[string]int{},
+			notEnabled: map[string]int{"refunds": 1, "beta": 1},
+		},
+		{
+			name:       "without",
+			filter:     FeatureFilter{Without: []string{"payments_v2"}},
+			commands:   []string{"beta.Preview", "ping"},
+			excluded:   map[string]int{"payments_v2": 2},
+			notEnabled: map[string]int{},
+		},
+		{
+			name:       "without wins over with",
+			filter:     FeatureFilter{With: []string{"payments_v2", "refunds", "beta"}, Without: []string{"refunds"}},
+			commands:   []string{"beta.Preview", "payments.Pay", "ping"},
+			excluded:   map[string]int{"refunds": 1},
+			notEnabled: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseProject("testdata/features")
+			if err != nil {
+				t.Fatalf("ParseProject returned error: %v", err)
+			}
+			parsed := len(result.Functions)
+
+			report := result.FilterFeatures(tt.filter)
+
+			var commands []string
+			for _, apiFunc := range result.Functions {
+				commands = append(commands, apiFunc.Command)
+			}
+			sort.Strings(commands)
+			if !reflect.DeepEqual(commands, tt.commands) {
+				t.Errorf("Kept commands %v, want %v", commands, tt.commands)
+			}
+			if !reflect.DeepEqual(report.Excluded, tt.excluded) || !reflect.DeepEqual(report.NotEnabled, tt.notEnabled) {
+				t.Errorf("Report excluded %v, not enabled %v, want %v and %v", report.Excluded, report.NotEnabled, tt.excluded, tt.notEnabled)
+			}
+			if report.Total != parsed-len(commands) {
+				t.Errorf("Report total %d, want %d", report.Total, parsed-len(commands))
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// parser/globalerrors.go
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// parseGlobalError parses a @globalerror annotation, such as
+// @globalerror 1001 "The request is not authenticated.", checking that its code is not
+// already in globalErrors. The description may be left out for codes of the @errorcatalog.
+func parseGlobalError(line string, globalErrors []models.APIError) (models.APIError, error) {
+	parts, rest := splitFields(line, 2)
+	if len(parts) < 2 {
+		return models.APIError{}, errors.New("invalid @globalerror annotation. Expected format: @globalerror code [\"description\"]")
+	}
+	code, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return models.APIError{}, fmt.Errorf("invalid @globalerror annotation: %w", ErrInvalidErrorCode)
+	}
+	description, err := quotedDescription(rest)
+	if err != nil {
+		return models.APIError{}, fmt.Errorf("malformed @globalerror annotation: %v", err)
+	}
+	for _, apiError := range globalErrors {
+		if apiError.Code == code {
+			return models.APIError{}, fmt.Errorf("error code %d is declared twice with @globalerror", code)
+		}
+	}
+	return models.APIError{Code: code, Description: description}, nil
+}
+
+// nameGlobalErrors names the global errors found in the catalog, and gives the ones written
+// without a description the description of the catalog.
+func nameGlobalErrors(globalErrors []models.APIError, catalog []models.CatalogError) {
+	for i := range globalErrors {
+		for _, entry := range catalog {
+			if entry.Code != globalErrors[i].Code {
+				continue
+			}
+			globalErrors[i].Name = entry.Name
+			if globalErrors[i].Description == "" {
+				globalErrors[i].Description = entry.Description
+			}
+		}
+	}
+}
+
+// checkGlobalErrors reports the invalid @globalerror annotations of a project doc comment,
+// which parseGlobalTags skips, the ones with neither a description nor a catalog entry, and
+// the ones overriding the description of the @errorcatalog.
+func checkGlobalErrors(cg *ast.CommentGroup, fset *token.FileSet) Diagnostics {
+	if cg == nil {
+		return nil
+	}
+
+	type globalError struct {
+		models.APIError
+		line int
+	}
+	var diagnostics Diagnostics
+	var globalErrors []globalError
+	var declared []models.APIError
+	var catalog []models.CatalogError
+	report := func(severity Severity, line int, format string, args ...any) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: severity,
+			File:     fset.Position(cg.Pos()).Filename,
+			Line:     line,
+			Class:    ClassGlobalError,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	inCatalog := false
+	for _, c := range cg.List {
+		position := fset.Position(c.Pos())
+		text := strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), "/*")
+		text = strings.TrimSuffix(text, "*/")
+		for offset, line := range strings.Split(text, "\n") {
+			line = strings.TrimSpace(line)
+			if inCatalog && line != "" && !strings.HasPrefix(line, "@") {
+				// Invalid catalog entries are reported by checkErrorCatalog
+				if entry, err := parseCatalogEntry(line, catalog); err == nil {
+					catalog = append(catalog, entry)
+				}
+				continue
+			}
+			fields := strings.Fields(line)
+			inCatalog = len(fields) > 0 && strings.EqualFold(fields[0], "@errorcatalog")
+			if len(fields) == 0 || !strings.EqualFold(fields[0], "@globalerror") {
+				continue
+			}
+			apiError, err := parseGlobalError(line, declared)
+			if err != nil {
+				report(SeverityError, position.Line+offset, "%s, ignored", err.Error())
+				continue
+			}
+			declared = append(declared, apiError)
+			globalErrors = append(globalErrors, globalError{apiError, position.Line + offset})
+		}
+	}
+
+	entries := make(map[int]models.CatalogError, len(catalog))
+	for _, entry := range catalog {
+		entries[entry.Code] = entry
+	}
+	for _, globalError := range globalErrors {
+		entry, found := entries[globalError.Code]
+		switch {
+		case !found && globalError.Description == "":
+			report(SeverityError, globalError.line, "global error %d has no description and is not in the @errorcatalog", globalError.Code)
+		case found && globalError.Description != "" && globalError.Description != entry.Description:
+			report(SeverityWarning, globalError.line, "global error %d overrides the @errorcatalog description of %s", globalError.Code, entry.Name)
+		}
+	}
+	return diagnostics
+}
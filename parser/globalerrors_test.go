@@ -0,0 +1,54 @@
+// parser/globalerrors_test.go
+package parser
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectGlobalErrors(t *testing.T) {
+	result, err := ParseProject("testdata/globalerrors")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	// Invalid annotations are skipped, catalog codes get their name and description
+	want := []models.APIError{
+		{Code: -32600, Description: "The request is not a valid JSON-RPC request."},
+		{Code: 1001, Description: "The request is not authenticated."},
+		{Code: 1002, Name: "RateLimited", Description: "Too many requests."},
+		{Code: 1003},
+		{Code: 1004, Name: "QuotaExceeded", Description: "No requests left this hour."},
+	}
+	if !reflect.DeepEqual(result.ProjectInfo.GlobalErrors, want) {
+		t.Errorf("Unexpected global errors:\n%+v\nwant:\n%+v", result.ProjectInfo.GlobalErrors, want)
+	}
+	if result.ProjectInfo.License != "MIT" {
+		t.Errorf("Expected the license after the global errors to be read, got %q", result.ProjectInfo.License)
+	}
+	// The errors of the commands are left as declared, the generator merges them
+	if len(result.Functions) != 1 || len(result.Functions[0].Errors) != 1 {
+		t.Fatalf("Expected the single @Error of users.Get, got %+v", result.Functions)
+	}
+
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Class == ClassGlobalError {
+			got = append(got, diag.Severity.String()+" "+filepath.Base(diag.File)+":"+strconv.Itoa(diag.Line)+": "+diag.Message)
+		}
+	}
+	wantDiagnostics := []string{
+		"error api.go:12: error code 1001 is declared twice with @globalerror, ignored",
+		"error api.go:13: invalid @globalerror annotation: @Error code must be a numeric literal, ignored",
+		"error api.go:14: global error 1003 has no description and is not in the @errorcatalog",
+		"warning api.go:15: global error 1004 overrides the @errorcatalog description of QuotaExceeded",
+	}
+	if strings.Join(got, "\n") != strings.Join(wantDiagnostics, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(wantDiagnostics, "\n"))
+	}
+}
@@ -0,0 +1,120 @@
+// parser/ids.go
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// assignIDs fills in the stable identifier of every command and struct that has no
+// explicit @ID, deriving it from the normalized name, and reports identifiers used twice.
+func assignIDs(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition) Diagnostics {
+	var diagnostics Diagnostics
+
+	type owner struct {
+		name string
+		file string
+		line int
+	}
+
+	commandIDs := make(map[string]owner)
+	for i := range apiFunctions {
+		apiFunc := &apiFunctions[i]
+		if apiFunc.ID == "" {
+			apiFunc.ID = utils.NormalizeID(apiFunc.Command)
+		}
+		// A derived ID is located at the command name
+		line := apiFunc.AnnotationLine("@Command")
+		if idLine, explicit := apiFunc.AnnotationLines["@ID"]; explicit {
+			line = idLine
+		}
+		if previous, exists := commandIDs[apiFunc.ID]; exists {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				File:     apiFunc.SourceFile,
+				Line:     line,
+				Class:    ClassDuplicateID,
+				Message:  fmt.Sprintf("command '%s' has ID '%s', already used by command '%s' at %s:%d", apiFunc.Command, apiFunc.ID, previous.name, previous.file, previous.line),
+			})
+			continue
+		}
+		commandIDs[apiFunc.ID] = owner{name: apiFunc.Command, file: apiFunc.SourceFile, line: line}
+	}
+
+	// Visit structs in a fixed order so the reported collision is always the same one
+	keys := make([]models.StructKey, 0, len(structDefinitions))
+	for key := range structDefinitions {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Package != keys[j].Package {
+			return keys[i].Package < keys[j].Package
+		}
+		return keys[i].Name < keys[j].Name
+	})
+
+	structIDs := make(map[string]owner)
+	for _, key := range keys {
+		structDef := structDefinitions[key]
+		if structDef.ID == "" {
+			structDef.ID = utils.NormalizeID(key.Package + "." + key.Name)
+			structDefinitions[key] = structDef
+		}
+		name := key.Package + "." + key.Name
+		if previous, exists := structIDs[structDef.ID]; exists {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				File:     structDef.SourceFile,
+				Line:     structDef.SourceLine,
+				Class:    ClassDuplicateID,
+				Message:  fmt.Sprintf("struct '%s' has ID '%s', already used by struct '%s' at %s:%d", name, structDef.ID, previous.name, previous.file, previous.line),
+			})
+			continue
+		}
+		structIDs[structDef.ID] = owner{name: name, file: structDef.SourceFile, line: structDef.SourceLine}
+	}
+
+	return diagnostics
+}
+
+// checkFormerNames reports former command names that are still in use, either by a live
+// command or as the former name of another command, since both would claim the same anchor.
+func checkFormerNames(apiFunctions []models.APIFunction) Diagnostics {
+	var diagnostics Diagnostics
+
+	live := make(map[string]models.APIFunction)
+	for _, apiFunc := range apiFunctions {
+		live[apiFunc.Command] = apiFunc
+	}
+
+	formerOwners := make(map[string]models.APIFunction)
+	for _, apiFunc := range apiFunctions {
+		for _, former := range apiFunc.FormerNames {
+			if other, exists := live[former]; exists {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					File:     apiFunc.SourceFile,
+					Line:     apiFunc.AnnotationLine("@FormerName " + former),
+					Class:    ClassFormerName,
+					Message:  fmt.Sprintf("command '%s' has former name '%s', which is a live command at %s:%d", apiFunc.Command, former, other.SourceFile, other.AnnotationLine("@Command")),
+				})
+				continue
+			}
+			if other, exists := formerOwners[former]; exists {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					File:     apiFunc.SourceFile,
+					Line:     apiFunc.AnnotationLine("@FormerName " + former),
+					Class:    ClassFormerName,
+					Message:  fmt.Sprintf("command '%s' has former name '%s', already claimed by command '%s' at %s:%d", apiFunc.Command, former, other.Command, other.SourceFile, other.AnnotationLine("@FormerName "+former)),
+				})
+				continue
+			}
+			formerOwners[former] = apiFunc
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,124 @@
+// parser/ignore.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// ignorePragma silences diagnostics of the listed classes, such as
+// "//jdocgen:ignore unresolved-type", on its own line. A pragma alone on its line also
+// silences the next line, so it can be written above a field or an annotation.
+const ignorePragma = "//jdocgen:ignore"
+
+// suppression is a //jdocgen:ignore pragma.
+type suppression struct {
+	File    string
+	Lines   []int
+	Classes []string
+}
+
+// covers reports whether the suppression silences diag.
+func (s suppression) covers(diag Diagnostic) bool {
+	if diag.Severity >= SeverityError || diag.File != s.File || !contains(s.Classes, diag.Class) {
+		return false
+	}
+	for _, line := range s.Lines {
+		if diag.Line == line {
+			return true
+		}
+	}
+	return false
+}
+
+// collectPragmas returns the //jdocgen:ignore pragmas of a file, whose source is src, and
+// reports pragmas naming no class or an unknown one.
+func collectPragmas(fileAst *ast.File, fset *token.FileSet, src []byte) ([]suppression, Diagnostics) {
+	var suppressions []suppression
+	var diagnostics Diagnostics
+	for _, cg := range fileAst.Comments {
+		for _, c := range cg.List {
+			index := strings.Index(c.Text, ignorePragma)
+			if index < 0 || strings.HasPrefix(c.Text, "/*") {
+				continue
+			}
+			position := fset.Position(c.Pos())
+			pragma := suppression{File: position.Filename, Lines: []int{position.Line}}
+			if index == 0 && strings.TrimSpace(string(src[position.Offset-position.Column+1:position.Offset])) == "" {
+				pragma.Lines = append(pragma.Lines, position.Line+1)
+			}
+
+			unknown := false
+			for _, class := range strings.FieldsFunc(c.Text[index+len(ignorePragma):], isClassSeparator) {
+				if !contains(DiagnosticClasses, class) {
+					unknown = true
+					diagnostics = append(diagnostics, Diagnostic{
+						Severity: SeverityWarning,
+						File:     position.Filename,
+						Line:     position.Line,
+						Class:    ClassUnknownIgnore,
+						Message:  fmt.Sprintf("unknown diagnostic class '%s' in %s, expected one of: %s", class, ignorePragma, strings.Join(DiagnosticClasses, ", ")),
+					})
+					continue
+				}
+				pragma.Classes = append(pragma.Classes, class)
+			}
+			if len(pragma.Classes) == 0 {
+				if !unknown {
+					diagnostics = append(diagnostics, Diagnostic{
+						Severity: SeverityWarning,
+						File:     position.Filename,
+						Line:     position.Line,
+						Class:    ClassUnknownIgnore,
+						Message:  fmt.Sprintf("%s names no diagnostic class", ignorePragma),
+					})
+				}
+				continue
+			}
+			suppressions = append(suppressions, pragma)
+		}
+	}
+	return suppressions, diagnostics
+}
+
+// isClassSeparator splits the classes of a pragma, separated by spaces or commas.
+func isClassSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t'
+}
+
+// stripPragma removes a trailing //jdocgen:ignore pragma from a comment line, so it is not
+// taken as part of a description.
+func stripPragma(line string) string {
+	if index := strings.Index(line, ignorePragma); index >= 0 {
+		return strings.TrimSpace(line[:index])
+	}
+	return line
+}
+
+// ApplySuppressions drops the warnings and informational diagnostics silenced by a
+// //jdocgen:ignore pragma, counting them by class in Stats.Suppressed. ParseProject applies
+// them to its own diagnostics; callers adding diagnostics afterwards, such as lint results,
+// apply them again.
+func (r *Result) ApplySuppressions() {
+	kept := r.Diagnostics[:0]
+	for _, diag := range r.Diagnostics {
+		suppressed := false
+		for _, s := range r.suppressions {
+			if s.covers(diag) {
+				suppressed = true
+				break
+			}
+		}
+		if !suppressed {
+			kept = append(kept, diag)
+			continue
+		}
+		if r.Stats.Suppressed == nil {
+			r.Stats.Suppressed = make(map[string]int)
+		}
+		r.Stats.Suppressed[diag.Class]++
+	}
+	r.Diagnostics = kept
+}
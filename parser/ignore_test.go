@@ -0,0 +1,72 @@
+// parser/ignore_test.go
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestIgnorePragmas(t *testing.T) {
+	result, err := ParseProject("testdata/ignore")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	var got []string
+	for _, d := range result.Diagnostics {
+		got = append(got, fmt.Sprintf("%d: %s [%s]", d.Line, d.Message, d.Class))
+	}
+	expected := []string{
+		"33: unknown diagnostic class 'unresolved-typo' in //jdocgen:ignore, expected one of: " + strings.Join(DiagnosticClasses, ", ") + " [unknown-ignore]",
+		"18: type 'rpc.LegacyLedger' of field 'Ledger' of struct 'rpc.Invoice' is not declared in the parsed packages [unresolved-type]",
+		"19: type 'rpc.LegacyOther' of field 'Other' of struct 'rpc.Invoice' is not declared in the parsed packages [unresolved-type]",
+		"31: type 'rpc.LegacyNotes' of an additional struct of command 'invoice.Get' is not declared in the parsed packages [unresolved-type]",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+	if want := map[string]int{ClassUnresolvedType: 3}; !reflect.DeepEqual(result.Stats.Suppressed, want) {
+		t.Errorf("Expected suppressed %v, got %v", want, result.Stats.Suppressed)
+	}
+
+	// Pragmas are not part of descriptions
+	invoice := result.Structs[models.StructKey{Package: "rpc", Name: "Invoice"}]
+	for _, field := range invoice.Fields {
+		if strings.Contains(field.Description, "jdocgen") {
+			t.Errorf("Field %s has the pragma in its description: %q", field.Name, field.Description)
+		}
+	}
+	if len(result.Functions) != 1 || result.Functions[0].Parameters[0].Description != "Legacy filter" {
+		t.Errorf("Expected the parameter description without the pragma, got %+v", result.Functions)
+	}
+
+	// Errors are never silenced, and later diagnostics are silenced when applied again
+	result.Diagnostics = append(Diagnostics{},
+		Diagnostic{Severity: SeverityError, File: invoice.SourceFile, Line: 13, Class: ClassUnresolvedType, Message: "error"},
+		Diagnostic{Severity: SeverityWarning, File: invoice.SourceFile, Line: 19, Class: ClassPlaceholder, Message: "placeholder"},
+	)
+	result.ApplySuppressions()
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Message != "error" {
+		t.Errorf("Expected only the error to be kept, got %v", result.Diagnostics)
+	}
+	if result.Stats.Suppressed[ClassPlaceholder] != 1 {
+		t.Errorf("Expected the placeholder to be counted, got %v", result.Stats.Suppressed)
+	}
+}
+
+func TestStripPragma(t *testing.T) {
+	tests := map[string]string{
+		"// Legacy totals. //jdocgen:ignore unresolved-type": "// Legacy totals.",
+		"//jdocgen:ignore unresolved-type":                   "",
+		"// Plain comment.":                                  "// Plain comment.",
+	}
+	for line, want := range tests {
+		if got := stripPragma(line); got != want {
+			t.Errorf("stripPragma(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// parser/incomplete.go
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// incompleteStub returns the stub of a command that could not be parsed, documenting only
+// its name and the reason.
+func incompleteStub(command, file string, line int, reason string) models.APIFunction {
+	return models.APIFunction{
+		Command:    command,
+		ID:         utils.NormalizeID(command),
+		SourceFile: file,
+		SourceLine: line,
+		Incomplete: reason,
+	}
+}
+
+// commandName returns the name given by the @Command annotation of a function doc comment,
+// or parsed when it is already known.
+func commandName(cg *ast.CommentGroup, fset *token.FileSet, parsed string) string {
+	if parsed != "" {
+		return parsed
+	}
+	lines, _ := functionAnnotations(cg, fset)
+	for _, line := range lines {
+		parts := strings.Fields(line.Text)
+		if annotation, ok := LookupAnnotation(parts[0], ScopeFunction); ok && annotation.Name == "@Command" && len(parts) > 1 {
+			return parts[1]
+		}
+	}
+	return ""
+}
+
+// KeepGoing adds the stubs of the commands that could not be parsed to the documented
+// commands, so they are documented as incomplete instead of being left out, and returns
+// them. Stubs named like a documented command are dropped.
+func (r *Result) KeepGoing() []models.APIFunction {
+	documented := make(map[string]bool, len(r.Functions))
+	for _, apiFunc := range r.Functions {
+		documented[apiFunc.Command] = true
+	}
+	var stubs []models.APIFunction
+	for _, stub := range r.Incomplete {
+		if stub.Command == "" || documented[stub.Command] {
+			continue
+		}
+		documented[stub.Command] = true
+		stubs = append(stubs, stub)
+	}
+	r.Functions = append(r.Functions, stubs...)
+	return stubs
+}
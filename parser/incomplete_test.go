@@ -0,0 +1,59 @@
+// parser/incomplete_test.go
+package parser
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectIncomplete(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want []string
+	}{
+		// Every command of a file failing to parse
+		{"testdata/broken", []string{
+			"user.Delete broken.go:4: its file failed to parse at line 6: missing ',' in parameter list",
+			"user.List broken.go:10: its file failed to parse at line 6: missing ',' in parameter list",
+		}},
+		// A command with an invalid annotation, located at the annotation
+		{"testdata/envelope", []string{
+			"broken.Get api.go:27: invalid @Envelope setting 'version'. Expected key=value",
+		}},
+	}
+	for _, tt := range tests {
+		result, err := ParseProject(tt.dir)
+		if err != nil {
+			t.Fatalf("ParseProject(%s) returned error: %v", tt.dir, err)
+		}
+		var got []string
+		for _, stub := range result.Incomplete {
+			got = append(got, stub.Command+" "+filepath.Base(stub.SourceFile)+":"+strconv.Itoa(stub.SourceLine)+": "+stub.Incomplete)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("Unexpected stubs of %s:\n%s\nwant:\n%s", tt.dir, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestKeepGoing(t *testing.T) {
+	result := &Result{
+		Functions: []models.APIFunction{{Command: "user.Get"}},
+		Incomplete: []models.APIFunction{
+			{Command: "user.Delete", Incomplete: "its file failed to parse"},
+			{Command: "user.Get", Incomplete: "declared twice"},
+			{Command: "", Incomplete: "no @Command"},
+		},
+	}
+	stubs := result.KeepGoing()
+	if len(stubs) != 1 || stubs[0].Command != "user.Delete" {
+		t.Errorf("Expected only the user.Delete stub, got %+v", stubs)
+	}
+	if len(result.Functions) != 2 || result.Functions[1].Incomplete == "" {
+		t.Errorf("Expected the stub to be documented, got %+v", result.Functions)
+	}
+}
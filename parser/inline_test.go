@@ -0,0 +1,69 @@
+// parser/inline_test.go
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/generator"
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectInlineStructs(t *testing.T) {
+	result, err := ParseProject("testdata/inline")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Expected no diagnostics, got %v", result.Diagnostics)
+	}
+
+	tests := map[string][]string{
+		"Order":                {"id", "details", "items"},
+		"Order.Details":        {"code", "msg", "origin"},
+		"Order.Details.Origin": {"source"},
+		"Order.Items":          {"sku"},
+	}
+	for name, want := range tests {
+		structDef, exists := result.Structs[models.StructKey{Package: "rpc", Name: name}]
+		if !exists {
+			t.Errorf("Expected a struct definition for %s", name)
+			continue
+		}
+		var got []string
+		for _, field := range structDef.Fields {
+			got = append(got, field.JSONName)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("Expected the fields %v of %s, got %v", want, name, got)
+		}
+	}
+
+	output := filepath.Join(t.TempDir(), "API.md")
+	if err := generator.GenerateDocumentation(result.Functions, result.Structs, result.ProjectInfo, output, generator.Options{StructSource: true}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| Details | [struct{...}](#rpcorderdetails) | Details of the delivery | details | Yes |\n",
+		"| Items | [[]struct{...}](#rpcorderitems) | Ordered items | items | Yes |\n",
+		"#### rpc.Order.Details\n",
+		"| Code | int | Delivery code | code | Yes |\n",
+		"| Origin | [*struct{...}](#rpcorderdetailsorigin) | Origin of the delivery | origin | No |\n",
+		"#### rpc.Order.Details.Origin\n",
+		"| Source | string | Warehouse the order ships from | source | Yes |\n",
+		"#### rpc.Order.Items\n",
+		"\tDetails Details `json:\"details\"`\n",
+		"// Order.Details, declared inline.\ntype Details struct {\n",
+		`"sku": ""`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, content)
+		}
+	}
+}
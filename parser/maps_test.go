@@ -0,0 +1,52 @@
+// parser/maps_test.go
+package parser
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectMapResultsAndFields(t *testing.T) {
+	result, err := ParseProject("testdata/maps")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Expected no diagnostics, got %v", result.Diagnostics)
+	}
+
+	series := models.StructKey{Package: "rpc", Name: "MetricSeries"}
+	page := models.StructKey{Package: "rpc", Name: "Page[MetricSeries]"}
+	results := make(map[string]models.APIReturn)
+	for _, apiFunc := range result.Functions {
+		results[apiFunc.Command] = apiFunc.Results[0]
+	}
+	for command, want := range map[string]models.StructKey{"metrics.Get": series, "metrics.Pages": page} {
+		ref := results[command].TypeRef
+		if ref.Kind != models.TypeMap || ref.Key.String() != "string" {
+			t.Errorf("Expected the result of %s to be a map keyed by string, got %+v", command, ref)
+		}
+		if held := ref.Held(); held.Kind != models.TypeStruct || held.Struct != want {
+			t.Errorf("Expected the result of %s to hold %v, got %+v", command, want, held)
+		}
+	}
+	// The generic value type is instantiated under its map
+	if _, ok := result.Structs[page]; !ok {
+		t.Errorf("Expected the concrete struct %v to be created", page)
+	}
+	if got := results["metrics.Pages"].Type; got != "map[string]Page[MetricSeries]" {
+		t.Errorf("Expected the result type map[string]Page[MetricSeries], got %q", got)
+	}
+
+	dashboard := result.Structs[models.StructKey{Package: "rpc", Name: "Dashboard"}]
+	for _, field := range dashboard.Fields {
+		held := field.TypeRef.Held()
+		if field.TypeRef.Kind != models.TypeMap {
+			t.Errorf("Expected field %s to be a map, got %+v", field.Name, field.TypeRef)
+		}
+		if isStruct := held.Kind == models.TypeStruct && held.Struct == series; isStruct != (field.Name != "Counts") {
+			t.Errorf("Unexpected value type of field %s: %+v", field.Name, held)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// parser/methods.go
+package parser
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// methodDoc returns the receiver type name and the documentation of an exported, documented
+// method. The documentation is the first paragraph of its doc comment, without annotations.
+func methodDoc(fn *ast.FuncDecl) (string, models.MethodDoc, bool) {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 || fn.Doc == nil || !fn.Name.IsExported() {
+		return "", models.MethodDoc{}, false
+	}
+	receiver := receiverTypeName(fn.Recv.List[0].Type)
+	if receiver == "" {
+		return "", models.MethodDoc{}, false
+	}
+
+	var paragraph []string
+	for _, line := range strings.Split(fn.Doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "@") {
+			paragraph = append(paragraph, line)
+		}
+	}
+	if len(paragraph) == 0 {
+		return "", models.MethodDoc{}, false
+	}
+	return receiver, models.MethodDoc{Name: fn.Name.Name, Doc: normalizeSpace(strings.Join(paragraph, " "))}, true
+}
+
+// receiverTypeName returns the name of the type of a method receiver: "Money" for Money,
+// *Money and Page[T].
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
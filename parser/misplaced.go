@@ -0,0 +1,97 @@
+// parser/misplaced.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// checkDeclAnnotations reports function annotations, such as a @Command block pasted above a
+// var, const or non-struct type declaration, which are never documented. Struct declarations
+// are checked with the struct annotations. One warning is reported per comment, naming
+// @Command when it is present.
+func checkDeclAnnotations(genDecl *ast.GenDecl, fset *token.FileSet) Diagnostics {
+	if genDecl.Tok == token.IMPORT {
+		return nil
+	}
+	kind := genDecl.Tok.String()
+	var diagnostics Diagnostics
+	hasStruct := false
+	for _, spec := range genDecl.Specs {
+		var doc *ast.CommentGroup
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			if _, isStruct := s.Type.(*ast.StructType); isStruct {
+				hasStruct = true
+				continue
+			}
+			doc = s.Doc
+		case *ast.ValueSpec:
+			doc = s.Doc
+		}
+		diagnostics = append(diagnostics, misplacedFunctionAnnotation(doc, fset, fmt.Sprintf("a %s declaration", kind))...)
+	}
+	if !hasStruct {
+		diagnostics = append(diagnostics, misplacedFunctionAnnotation(genDecl.Doc, fset, fmt.Sprintf("a %s declaration", kind))...)
+	}
+	return diagnostics
+}
+
+// checkCommandFunction reports @Command on functions that cannot be called, init and the blank
+// function "_".
+func checkCommandFunction(fn *ast.FuncDecl, fset *token.FileSet) Diagnostics {
+	var what string
+	switch {
+	case fn.Name.Name == "_":
+		what = "the blank function '_'"
+	case fn.Recv == nil && fn.Name.Name == "init":
+		what = "an init function"
+	default:
+		return nil
+	}
+	lines, _ := functionAnnotations(fn.Doc, fset)
+	for _, line := range lines {
+		if annotation, ok := LookupAnnotation(strings.Fields(line.Text)[0], ScopeFunction); ok && annotation.Name == "@Command" {
+			return Diagnostics{{
+				Severity: SeverityWarning,
+				File:     fset.Position(fn.Pos()).Filename,
+				Line:     line.Line,
+				Class:    ClassMisplacedAnnotation,
+				Message:  fmt.Sprintf("@Command found on %s, which is never called by the server; annotate the handler function instead", what),
+			}}
+		}
+	}
+	return nil
+}
+
+// misplacedFunctionAnnotation reports the first annotation of cg that is only valid on
+// functions, or @Command when cg has it, as written on the given declaration.
+func misplacedFunctionAnnotation(cg *ast.CommentGroup, fset *token.FileSet, declaration string) Diagnostics {
+	lines, _ := functionAnnotations(cg, fset)
+	var found *annotationLine
+	for i, line := range lines {
+		annotation, ok := LookupAnnotation(strings.Fields(line.Text)[0], ScopeFunction)
+		if !ok || annotation.allows(ScopeProject) {
+			continue
+		}
+		if found == nil || annotation.Name == "@Command" {
+			found = &lines[i]
+		}
+		if annotation.Name == "@Command" {
+			break
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	annotation, _ := LookupAnnotation(strings.Fields(found.Text)[0], ScopeFunction)
+	return Diagnostics{{
+		Severity: SeverityWarning,
+		File:     fset.Position(cg.Pos()).Filename,
+		Line:     found.Line,
+		Class:    ClassMisplacedAnnotation,
+		Message:  fmt.Sprintf("%s found on %s; only functions are documented", annotation.Name, declaration),
+	}}
+}
@@ -0,0 +1,34 @@
+// parser/misplaced_test.go
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMisplacedCommandAnnotations(t *testing.T) {
+	result, err := ParseProject("testdata/misplaced")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	var got []string
+	for _, d := range result.Diagnostics {
+		if d.Class == ClassMisplacedAnnotation {
+			got = append(got, fmt.Sprintf("%d: %s", d.Line, d.Message))
+		}
+	}
+	expected := []string{
+		"29: annotation '@Command' is not valid on a struct, only on: function",
+		"8: @Command found on a var declaration; only functions are documented",
+		"13: @Parameter found on a const declaration; only functions are documented",
+		"19: @Command found on a var declaration; only functions are documented",
+		"25: @Command found on a type declaration; only functions are documented",
+		"34: @Command found on an init function, which is never called by the server; annotate the handler function instead",
+		"38: @Command found on the blank function '_', which is never called by the server; annotate the handler function instead",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,69 @@
+// parser/multinames_test.go
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/generator"
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectFieldsSharingADeclaration(t *testing.T) {
+	result, err := ParseProject("testdata/multinames")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	type field struct {
+		name, jsonName, description string
+		omitempty                   bool
+	}
+	want := []field{
+		{"Width", "Width", "Size of the box face", false},
+		{"Height", "Height", "Size of the box face", false},
+		{"X", "X", "", false},
+		{"Y", "Y", "", false},
+		{"Z", "Z", "", false},
+		// Every name of the declaration carries its tag
+		{"ScaleX", "scale", "Scale of each axis", true},
+		{"ScaleY", "scale", "Scale of each axis", true},
+		{"ScaleZ", "scale", "Scale of each axis", true},
+		{"Label", "label", "Label printed on the box", false},
+	}
+	var got []field
+	for _, f := range result.Structs[models.StructKey{Package: "rpc", Name: "Box"}].Fields {
+		got = append(got, field{f.Name, f.JSONName, f.Description, f.Omitempty})
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected the fields %+v, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected field %d to be %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	output := filepath.Join(t.TempDir(), "API.md")
+	if err := generator.GenerateDocumentation(result.Functions, result.Structs, result.ProjectInfo, output, generator.Options{}); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range []string{
+		"| Width | int | Size of the box face | Width | Yes |\n",
+		"| Height | int | Size of the box face | Height | Yes |\n",
+		"| Y | float64 | — | Y | Yes |\n",
+		"| Z | float64 | — | Z | Yes |\n",
+		"| ScaleY | float64 | Scale of each axis | scale | No |\n",
+		"| Label | string | Label printed on the box | label | Yes |\n",
+	} {
+		if !strings.Contains(string(content), row) {
+			t.Errorf("Expected the table to contain %q, got:\n%s", row, content)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// parser/only.go
+package parser
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// FilterCommands keeps only the commands matching one of the glob patterns, such as
+// "user.GetProfile" or "user.*". A pattern matching no command is reported as a warning, and
+// it is an error when no command matches at all.
+func (r *Result) FilterCommands(patterns []string) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid -only pattern '%s': %v", pattern, err)
+		}
+	}
+
+	matched := make(map[string]bool)
+	kept := r.Functions[:0]
+	for _, apiFunc := range r.Functions {
+		selected := false
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, apiFunc.Command); ok {
+				matched[pattern] = true
+				selected = true
+			}
+		}
+		if selected {
+			kept = append(kept, apiFunc)
+		}
+	}
+	r.Functions = kept
+
+	if len(kept) == 0 {
+		return fmt.Errorf("no command matches -only %s", strings.Join(patterns, ","))
+	}
+	for _, pattern := range patterns {
+		if !matched[pattern] {
+			r.Diagnostics = append(r.Diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				Class:    ClassOnlyUnmatched,
+				Message:  fmt.Sprintf("-only pattern '%s' matches no command", pattern),
+			})
+		}
+	}
+	return nil
+}
+
+// For returns the errors, the diagnostics without a location, and the diagnostics
+// located in the files declaring the given commands.
+func (d Diagnostics) For(apiFunctions []models.APIFunction) Diagnostics {
+	files := make(map[string]bool)
+	for _, apiFunc := range apiFunctions {
+		files[apiFunc.SourceFile] = true
+	}
+	var kept Diagnostics
+	for _, diag := range d {
+		if diag.Severity == SeverityError || diag.File == "" || files[diag.File] {
+			kept = append(kept, diag)
+		}
+	}
+	return kept
+}
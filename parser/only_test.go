@@ -0,0 +1,107 @@
+// parser/only_test.go
+package parser
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFilterCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		commands []string
+		warnings []string
+		err      string
+	}{
+		{
+			name:     "no patterns",
+			commands: []string{"beta.Preview", "payments.Pay", "payments.Refund", "ping"},
+		},
+		{
+			name:     "exact name",
+			patterns: []string{"ping"},
+			commands: []string{"ping"},
+		},
+		{
+			name:     "glob",
+			patterns: []string{"payments.*", "beta.Preview"},
+			commands: []string{"beta.Preview", "payments.Pay", "payments.Refund"},
+		},
+		{
+			name:     "unmatched pattern",
+			patterns: []string{"ping", "users.*"},
+			commands: []string{"ping"},
+			warnings: []string{"-only pattern 'users.*' matches no command"},
+		},
+		{
+			name:     "nothing matched",
+			patterns: []string{"users.*"},
+			err:      "no command matches -only users.*",
+		},
+		{
+			name:     "invalid pattern",
+			patterns: []string{"payments.[a"},
+			err:      "invalid -only pattern 'payments.[a'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseProject("testdata/features")
+			if err != nil {
+				t.Fatalf("ParseProject returned error: %v", err)
+			}
+			before := len(result.Diagnostics)
+
+			err = result.FilterCommands(tt.patterns)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FilterCommands returned error: %v", err)
+			}
+
+			var commands []string
+			for _, apiFunc := range result.Functions {
+				commands = append(commands, apiFunc.Command)
+			}
+			sort.Strings(commands)
+			if !reflect.DeepEqual(commands, tt.commands) {
+				t.Errorf("Kept commands %v, want %v", commands, tt.commands)
+			}
+			var warnings []string
+			for _, d := range result.Diagnostics[before:] {
+				warnings = append(warnings, d.Message)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("Warnings %v, want %v", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsFor(t *testing.T) {
+	result, err := ParseProject("testdata/directives")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	diagnostics := append(result.Diagnostics,
+		Diagnostic{Severity: SeverityWarning, File: "other.go", Message: "elsewhere"},
+		Diagnostic{Severity: SeverityError, File: "other.go", Message: "always reported"},
+		Diagnostic{Severity: SeverityWarning, Message: "no location"},
+	)
+
+	var messages []string
+	for _, d := range diagnostics.For(result.Functions[:1]) {
+		messages = append(messages, d.Message)
+	}
+	if len(messages) != len(diagnostics)-1 || strings.Contains(strings.Join(messages, "\n"), "elsewhere") {
+		t.Errorf("Unexpected diagnostics %v", messages)
+	}
+}
@@ -0,0 +1,187 @@
+// parser/packages.go
+package parser
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// packageIndex tells apart the packages of a root that share their name, such as
+// internal/v1/models and internal/v2/models. Structs are keyed by package, so the structs of
+// such packages are keyed by the shortest suffix of their import path telling them apart,
+// such as "v1/models", instead of their name. Other packages keep their name, and packages
+// of the same name in different roots are the same package, see ParseProjects.
+type packageIndex struct {
+	// ids maps the directories of the packages sharing their name to their package ID.
+	ids map[string]string
+	// imports maps the import paths of those directories to their package.
+	imports map[string]indexedPackage
+}
+
+// indexedPackage is a package sharing its name with another package of its root.
+type indexedPackage struct {
+	id   string
+	name string
+}
+
+// newPackageIndex returns the index of the packages of the parsed files, whose roots are
+// given by fileRoots.
+func newPackageIndex(parsedFiles []parsedFile, fileRoots map[string]string) packageIndex {
+	index := packageIndex{ids: make(map[string]string), imports: make(map[string]indexedPackage)}
+
+	// The directories of each package name, per root
+	type rootPackage struct{ root, name string }
+	dirs := make(map[rootPackage][]string)
+	seen := make(map[string]bool)
+	for _, file := range parsedFiles {
+		dir := filepath.Dir(file.path)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		key := rootPackage{root: fileRoots[file.path], name: file.ast.Name.Name}
+		dirs[key] = append(dirs[key], dir)
+	}
+
+	for key, packageDirs := range dirs {
+		if len(packageDirs) < 2 {
+			continue
+		}
+		importPaths := make([]string, len(packageDirs))
+		for i, dir := range packageDirs {
+			importPaths[i] = importPath(key.root, dir)
+		}
+		for i, dir := range packageDirs {
+			id := uniqueSuffix(importPaths[i], importPaths)
+			index.ids[dir] = id
+			index.imports[importPaths[i]] = indexedPackage{id: id, name: key.name}
+		}
+	}
+	return index
+}
+
+// packageOf returns the package ID of a parsed file: the name of its package, or the import
+// path suffix of a package sharing its name.
+func (index packageIndex) packageOf(file parsedFile) string {
+	if id, exists := index.ids[filepath.Dir(file.path)]; exists {
+		return id
+	}
+	return file.ast.Name.Name
+}
+
+// importAliases maps the names under which a parsed file refers to its imports to their
+// package IDs. Imports are known by the last element of their path, or the name of the
+// package for the packages sharing their name.
+func (index packageIndex) importAliases(file parsedFile) map[string]string {
+	importAliases := make(map[string]string)
+	for _, imp := range file.ast.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		pkg, indexed := index.lookup(importPath)
+		if !indexed {
+			pkg = indexedPackage{id: path.Base(importPath), name: path.Base(importPath)}
+		}
+		alias := pkg.name
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		importAliases[alias] = pkg.id
+	}
+	return importAliases
+}
+
+// lookup returns the indexed package imported as importPath. Without a go.mod, the import
+// paths of the index are relative to their root and match the end of importPath; the
+// longest match wins.
+func (index packageIndex) lookup(importPath string) (indexedPackage, bool) {
+	if pkg, exists := index.imports[importPath]; exists {
+		return pkg, true
+	}
+	var match indexedPackage
+	matched := ""
+	for indexed, pkg := range index.imports {
+		if strings.HasSuffix(importPath, "/"+indexed) && len(indexed) > len(matched) {
+			match, matched = pkg, indexed
+		}
+	}
+	return match, matched != ""
+}
+
+// importPath returns the import path of dir: the module path of the nearest go.mod followed
+// by the directory, or the directory relative to root when there is no go.mod.
+func importPath(root string, dir string) string {
+	if absDir, err := filepath.Abs(dir); err == nil {
+		dir = absDir
+	}
+	if absRoot, err := filepath.Abs(root); err == nil {
+		root = absRoot
+	}
+	if modulePath, moduleDir, found := findModule(dir); found {
+		if rel, err := filepath.Rel(moduleDir, dir); err == nil && rel != "." {
+			return modulePath + "/" + filepath.ToSlash(rel)
+		}
+		return modulePath
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return filepath.Base(root)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// findModule returns the module path declared by the go.mod of dir or of its nearest parent.
+func findModule(dir string) (modulePath string, moduleDir string, found bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", false
+	}
+	for {
+		if modulePath, ok := readModulePath(filepath.Join(dir, "go.mod")); ok {
+			return modulePath, dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", false
+		}
+		dir = parent
+	}
+}
+
+// readModulePath returns the path of the module directive of a go.mod file.
+func readModulePath(goMod string) (string, bool) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), true
+		}
+	}
+	return "", false
+}
+
+// uniqueSuffix returns the shortest suffix of importPath, of at least two elements, that is
+// not a suffix of the other import paths, or importPath itself.
+func uniqueSuffix(importPath string, importPaths []string) string {
+	elements := strings.Split(importPath, "/")
+	for n := 2; n < len(elements); n++ {
+		suffix := strings.Join(elements[len(elements)-n:], "/")
+		unique := true
+		for _, other := range importPaths {
+			if other != importPath && (other == suffix || strings.HasSuffix(other, "/"+suffix)) {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return suffix
+		}
+	}
+	return importPath
+}
@@ -0,0 +1,66 @@
+// parser/packages_test.go
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/generator"
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectPackagesSharingTheirName(t *testing.T) {
+	result, err := ParseProject("testdata/versions")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Expected no diagnostics, got %v", result.Diagnostics)
+	}
+
+	v1 := models.StructKey{Package: "v1/models", Name: "User"}
+	v2 := models.StructKey{Package: "v2/models", Name: "User"}
+	if got := result.Structs[v1].Fields; len(got) != 1 || got[0].Name != "Name" {
+		t.Errorf("Expected %v to have the field Name, got %+v", v1, got)
+	}
+	if got := result.Structs[v2].Fields; len(got) != 2 || got[0].Name != "FirstName" {
+		t.Errorf("Expected %v to have the fields FirstName and LastName, got %+v", v2, got)
+	}
+
+	// Aliased and unaliased imports, in annotations and in struct fields
+	results := make(map[string]models.StructKey)
+	for _, apiFunc := range result.Functions {
+		results[apiFunc.Command] = apiFunc.Results[0].TypeRef.Struct
+	}
+	if results["users.GetV1"] != v1 || results["users.GetV2"] != v2 {
+		t.Errorf("Expected the results to resolve to %v and %v, got %v", v1, v2, results)
+	}
+	migration := result.Structs[models.StructKey{Package: "api", Name: "Migration"}]
+	if from, to := migration.Fields[0].TypeRef.Struct, migration.Fields[1].TypeRef.Struct; from != v1 || to != v2 {
+		t.Errorf("Expected the fields of Migration to resolve to %v and %v, got %v and %v", v1, v2, from, to)
+	}
+
+	var doc bytes.Buffer
+	if err := generator.WriteDocumentation(&doc, result.Functions, result.Structs, result.ProjectInfo, generator.Options{}); err != nil {
+		t.Fatalf("WriteDocumentation returned error: %v", err)
+	}
+	for _, want := range []string{
+		"#### v1/models.User\n\nUser is a user of the first version.",
+		"#### v2/models.User\n\nUser is a user of the second version.",
+	} {
+		if !strings.Contains(doc.String(), want) {
+			t.Errorf("Expected the documentation to contain %q, got:\n%s", want, doc.String())
+		}
+	}
+}
+
+func TestUniqueSuffix(t *testing.T) {
+	importPaths := []string{"example.com/shop/internal/v1/models", "example.com/shop/internal/v2/models", "example.com/shop/legacy/v1/models"}
+	want := []string{"internal/v1/models", "v2/models", "legacy/v1/models"}
+	for i, importPath := range importPaths {
+		if got := uniqueSuffix(importPath, importPaths); got != want[i] {
+			t.Errorf("uniqueSuffix(%q) = %q, want %q", importPath, got, want[i])
+		}
+	}
+}
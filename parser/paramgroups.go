@@ -0,0 +1,170 @@
+// parser/paramgroups.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// paramGroupKey identifies a @ParamGroup by its package and name.
+type paramGroupKey struct {
+	Package string
+	Name    string
+}
+
+// paramGroup is a named list of parameters shared by several commands.
+type paramGroup struct {
+	Parameters []models.APIParameter
+	SourceFile string
+	SourceLine int
+}
+
+// collectParamGroups adds the @ParamGroup blocks of a file to groups. A group is a comment
+// block of its own, not the doc comment of a declaration, starting with "@ParamGroup name" and
+// followed by the @Parameter lines of the group. A block may declare several groups.
+func collectParamGroups(fileAst *ast.File, pkg string, fset *token.FileSet, groups map[paramGroupKey]paramGroup) Diagnostics {
+	docs := map[*ast.CommentGroup]bool{fileAst.Doc: true}
+	for _, decl := range fileAst.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			docs[decl.Doc] = true
+		case *ast.GenDecl:
+			docs[decl.Doc] = true
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					docs[spec.Doc] = true
+				case *ast.ValueSpec:
+					docs[spec.Doc] = true
+				}
+			}
+		}
+	}
+
+	var diagnostics Diagnostics
+	report := func(severity Severity, file string, line int, format string, args ...any) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: severity,
+			File:     file,
+			Line:     line,
+			Class:    ClassParamGroup,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, cg := range fileAst.Comments {
+		if docs[cg] {
+			continue
+		}
+		lines, _ := functionAnnotations(cg, fset)
+		if len(lines) == 0 || strings.Fields(lines[0].Text)[0] != "@ParamGroup" {
+			continue
+		}
+		file := fset.Position(cg.Pos()).Filename
+
+		var key paramGroupKey
+		var group paramGroup
+		names := make(map[string]bool)
+		flush := func() {
+			if key.Name == "" {
+				return
+			}
+			if len(group.Parameters) == 0 {
+				report(SeverityWarning, file, group.SourceLine, "@ParamGroup '%s' has no @Parameter lines", key.Name)
+			}
+			if existing, exists := groups[key]; exists {
+				report(SeverityError, file, group.SourceLine, "@ParamGroup '%s' is declared twice, also at %s:%d", key.Name, existing.SourceFile, existing.SourceLine)
+				return
+			}
+			groups[key] = group
+		}
+
+		for _, line := range lines {
+			parts := strings.Fields(line.Text)
+			switch parts[0] {
+			case "@ParamGroup":
+				flush()
+				key, group, names = paramGroupKey{Package: pkg}, paramGroup{SourceFile: file, SourceLine: line.Line}, make(map[string]bool)
+				if len(parts) < 2 {
+					report(SeverityError, file, line.Line, "invalid @ParamGroup annotation. Expected format: @ParamGroup name")
+					continue
+				}
+				key.Name = parts[1]
+			case "@Parameter":
+				if key.Name == "" {
+					continue
+				}
+				param, err := parseParameter(line)
+				if err != nil {
+					report(SeverityError, file, line.Line, "%v", err)
+					continue
+				}
+				if names[param.Name] {
+					report(SeverityWarning, file, line.Line, "parameter '%s' is declared twice in @ParamGroup '%s', using the first one", param.Name, key.Name)
+					continue
+				}
+				names[param.Name] = true
+				group.Parameters = append(group.Parameters, param)
+			}
+		}
+		flush()
+	}
+	return diagnostics
+}
+
+// expandParamGroups appends the parameters of the groups named by @Params to the parameters of
+// each command, group after group. A parameter the command already has, declared by itself or
+// by an earlier group, is kept and the one of the group is dropped with a warning. Naming a
+// group the package does not declare is an error.
+func expandParamGroups(apiFunctions []models.APIFunction, groups map[paramGroupKey]paramGroup) Diagnostics {
+	var diagnostics Diagnostics
+	for i := range apiFunctions {
+		apiFunc := &apiFunctions[i]
+		report := func(severity Severity, line int, format string, args ...any) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: severity,
+				File:     apiFunc.SourceFile,
+				Line:     line,
+				Class:    ClassParamGroup,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		declared := make(map[string]string)
+		for _, param := range apiFunc.Parameters {
+			declared[param.Name] = ""
+		}
+		merged := make(map[string]bool)
+		for _, name := range apiFunc.ParamGroups {
+			line := apiFunc.AnnotationLine("@Params " + name)
+			if merged[name] {
+				continue
+			}
+			merged[name] = true
+			group, exists := groups[paramGroupKey{Package: apiFunc.PackageName, Name: name}]
+			if !exists {
+				report(SeverityError, line, "command '%s' uses undefined @ParamGroup '%s'", apiFunc.Command, name)
+				continue
+			}
+			for _, param := range group.Parameters {
+				if from, exists := declared[param.Name]; exists {
+					if from == "" {
+						report(SeverityWarning, line, "parameter '%s' of @ParamGroup '%s' is also declared by command '%s', using the command's own", param.Name, name, apiFunc.Command)
+					} else {
+						report(SeverityWarning, line, "parameter '%s' of @ParamGroup '%s' is also in @ParamGroup '%s' of command '%s', using the first one", param.Name, name, from, apiFunc.Command)
+					}
+					continue
+				}
+				declared[param.Name] = name
+				param.SourceLine = line
+				param.Group = name
+				apiFunc.Parameters = append(apiFunc.Parameters, param)
+			}
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,67 @@
+// parser/paramgroups_test.go
+package parser
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseProjectParamGroups(t *testing.T) {
+	result, err := ParseProject("testdata/paramgroups")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	type param struct {
+		Name     string
+		Required bool
+		Group    string
+		Line     int
+	}
+	want := map[string][]param{
+		// Group parameters follow the command's own, in @Params order
+		"items.List": {
+			{"filter", true, "", 18},
+			{"page_size", false, "PagingParams", 19},
+			{"page_token", false, "PagingParams", 19},
+			{"tenant", true, "Tenant", 20},
+		},
+		// The command's own page_size wins over the group's
+		"items.Search": {
+			{"page_size", true, "", 26},
+			{"page_token", false, "PagingParams", 28},
+		},
+		"items.Broken": nil,
+	}
+	for _, fn := range result.Functions {
+		var got []param
+		for _, p := range fn.Parameters {
+			got = append(got, param{p.Name, p.Required, p.Group, p.SourceLine})
+		}
+		if !reflect.DeepEqual(got, want[fn.Command]) {
+			t.Errorf("Unexpected parameters of %s:\n%+v\nwant:\n%+v", fn.Command, got, want[fn.Command])
+		}
+	}
+	if len(result.Functions) != len(want) {
+		t.Errorf("Expected %d commands, got %d", len(want), len(result.Functions))
+	}
+
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Class == ClassParamGroup {
+			got = append(got, diag.Severity.String()+" "+filepath.Base(diag.File)+":"+strconv.Itoa(diag.Line)+": "+diag.Message)
+		}
+	}
+	wantDiagnostics := []string{
+		"error more.go:3: @ParamGroup 'Tenant' is declared twice, also at testdata/paramgroups/api.go:11",
+		"warning api.go:20: parameter 'page_size' of @ParamGroup 'Tenant' is also in @ParamGroup 'PagingParams' of command 'items.List', using the first one",
+		"warning api.go:28: parameter 'page_size' of @ParamGroup 'PagingParams' is also declared by command 'items.Search', using the command's own",
+		"error api.go:34: command 'items.Broken' uses undefined @ParamGroup 'Missing'",
+	}
+	if strings.Join(got, "\n") != strings.Join(wantDiagnostics, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(wantDiagnostics, "\n"))
+	}
+}
@@ -0,0 +1,56 @@
+// parser/paramrules.go
+package parser
+
+import (
+	"fmt"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// checkParamRules reports @Requires and @ConflictsWith rules naming parameters the command does
+// not declare or relating a parameter to itself, and conflicts between two required
+// parameters, which no request could satisfy.
+func checkParamRules(apiFunctions []models.APIFunction) Diagnostics {
+	var diagnostics Diagnostics
+	for _, apiFunc := range apiFunctions {
+		params := make(map[string]models.APIParameter)
+		for _, param := range apiFunc.Parameters {
+			params[param.Name] = param
+		}
+		report := func(rule models.ParamRule, format string, args ...any) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				File:     apiFunc.SourceFile,
+				Line:     rule.SourceLine,
+				Class:    ClassParamRule,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		check := func(annotation string, rules []models.ParamRule) {
+			for _, rule := range rules {
+				for _, name := range append([]string{rule.Param}, rule.Others...) {
+					if _, exists := params[name]; !exists {
+						report(rule, "%s of command '%s' names unknown parameter '%s'", annotation, apiFunc.Command, name)
+					}
+				}
+				for _, other := range rule.Others {
+					if other == rule.Param {
+						report(rule, "%s of command '%s' relates parameter '%s' to itself", annotation, apiFunc.Command, other)
+					}
+				}
+			}
+		}
+		check("@Requires", apiFunc.Requires)
+		check("@ConflictsWith", apiFunc.ConflictsWith)
+
+		for _, rule := range apiFunc.ConflictsWith {
+			for _, other := range rule.Others {
+				if other != rule.Param && params[rule.Param].Required && params[other].Required {
+					report(rule, "@ConflictsWith of command '%s' forbids sending '%s' with '%s', but both are required", apiFunc.Command, rule.Param, other)
+				}
+			}
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,45 @@
+// parser/paramrules_test.go
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectParamRules(t *testing.T) {
+	result, err := ParseProject("testdata/paramrules")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	var search models.APIFunction
+	for _, fn := range result.Functions {
+		if fn.Command == "search.Find" {
+			search = fn
+		}
+	}
+	if want := []models.ParamRule{{Param: "page_token", Others: []string{"page_size"}, SourceLine: 14}}; !reflect.DeepEqual(search.Requires, want) {
+		t.Errorf("Expected @Requires %+v, got %+v", want, search.Requires)
+	}
+	if want := []models.ParamRule{{Param: "query", Others: []string{"ids"}, SourceLine: 15}}; !reflect.DeepEqual(search.ConflictsWith, want) {
+		t.Errorf("Expected @ConflictsWith %+v, got %+v", want, search.ConflictsWith)
+	}
+
+	var got []string
+	for _, d := range result.Diagnostics {
+		got = append(got, fmt.Sprintf("%d: %s: %s", d.Line, d.Severity, d.Message))
+	}
+	expected := []string{
+		"23: error: @Requires of command 'search.Broken' names unknown parameter 'cursor'",
+		"23: error: @Requires of command 'search.Broken' names unknown parameter 'limit'",
+		"24: error: @ConflictsWith of command 'search.Broken' relates parameter 'query' to itself",
+		"24: error: @ConflictsWith of command 'search.Broken' forbids sending 'query' with 'ids', but both are required",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"go/ast"
 	goparser "go/parser"
+	"go/scanner"
 	"go/token"
-	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -27,45 +29,169 @@ var (
 	ErrMalformedResult    = errors.New("malformed @Result annotation. Expected format: @Result type \"description\"")
 )
 
-func ParseProject(rootDir string) ([]models.APIFunction, map[models.StructKey]models.StructDefinition, models.ProjectInfo, error) {
+// defaultNotificationMethod is the method of the notifications of a @Subscription without one.
+const defaultNotificationMethod = "subscription"
+
+// Result holds everything collected by ParseProject.
+type Result struct {
+	Functions   []models.APIFunction
+	Structs     map[models.StructKey]models.StructDefinition
+	ProjectInfo models.ProjectInfo
+	Diagnostics Diagnostics
+	Stats       Stats
+	// Incomplete holds the stubs of the commands left out because their file or their
+	// annotations could not be parsed, see KeepGoing.
+	Incomplete []models.APIFunction
+	// TypeAliases are the types declared with a type other than a struct, whose underlying
+	// type replaces them in the parsed types.
+	TypeAliases map[models.StructKey]models.TypeAlias
+
+	// suppressions are the //jdocgen:ignore pragmas of the parsed files.
+	suppressions []suppression
+}
+
+// ParseProject parses the Go files under rootDir, see ParseProjects.
+func ParseProject(rootDir string) (*Result, error) {
+	return ParseProjects([]string{rootDir}, Options{})
+}
+
+// ParseProjects parses the Go files under several root directories as a single project, so
+// commands of one root may use the structs of another. A struct declared in two roots must be
+// declared the same way in both. The project information comes from the first root declaring
+// it, and the titles of other roots declaring a different one are reported as warnings. The
+// files and directories matching opts.Exclude are left out.
+func ParseProjects(rootDirs []string, opts Options) (*Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	var apiFunctions []models.APIFunction
 	structDefinitions := make(map[models.StructKey]models.StructDefinition)
 	var projectInfo models.ProjectInfo
 	projectInfoSet := false
+	var diagnostics Diagnostics
+	var stats Stats
 
 	fset := token.NewFileSet()
 	processedStructs := make(map[models.StructKey]bool)
-
-	// First pass: Collect all struct definitions
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+	methodDocs := make(map[models.StructKey][]models.MethodDoc)
+	// Every declared type and package, to tell unresolved types from non-struct ones
+	declaredTypes := make(map[models.StructKey]bool)
+	packages := make(map[string]bool)
+	// Types declared with another type, and the imports of their files
+	typeAliases := make(map[models.StructKey]models.TypeAlias)
+	aliasImports := make(map[models.StructKey]map[string]string)
+	var suppressions []suppression
+	// Stubs of the commands that could not be parsed, documented with -keep-going
+	var incomplete []models.APIFunction
+	paramGroups := make(map[paramGroupKey]paramGroup)
+	constants := make(map[constKey]constant)
+	enums := make(map[models.StructKey]*models.EnumDefinition)
+
+	// Roots are parsed in order and their files in path order, whatever order they are listed
+	// in, so the output does not depend on the filesystem. Files under several roots are
+	// parsed once, as part of the first.
+	var files []string
+	fileRoots := make(map[string]string)
+	for _, rootDir := range rootDirs {
+		rootFiles, err := listSourceFiles(rootDir, opts.Exclude)
 		if err != nil {
-			return err
+			return nil, err
 		}
-
-		if info.IsDir() {
-			if info.Name() == "vendor" || strings.HasPrefix(info.Name(), ".") {
-				return filepath.SkipDir
+		sort.Strings(rootFiles)
+		for _, path := range rootFiles {
+			if _, exists := fileRoots[path]; !exists {
+				fileRoots[path] = rootDir
+				files = append(files, path)
 			}
-			return nil
 		}
+	}
 
-		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
-			return nil
+	// The first root with project annotations declares the project
+	projectRoot := ""
+	conflictingRoots := make(map[string]bool)
+	collectProjectInfo := func(doc *ast.CommentGroup, file parsedFile) {
+		path := file.path
+		globalInfo, err := parseGlobalTags(doc)
+		if err != nil {
+			return
+		}
+		if !projectInfoSet {
+			projectInfo = locateEnvelope(globalInfo, path, file.pkg, file.importAliases)
+			projectInfoSet, projectRoot = true, fileRoots[path]
+			return
+		}
+		root := fileRoots[path]
+		if root == projectRoot || globalInfo.Title == projectInfo.Title || conflictingRoots[root] {
+			return
 		}
+		conflictingRoots[root] = true
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			File:     path,
+			Line:     fset.Position(doc.Pos()).Line,
+			Class:    ClassProjectInfo,
+			Message:  fmt.Sprintf("root '%s' declares the project title '%s', using '%s' of root '%s'", root, globalInfo.Title, projectInfo.Title, projectRoot),
+		})
+	}
 
-		fileAst, err := goparser.ParseFile(fset, path, nil, goparser.ParseComments)
+	// Each file is parsed once, and its syntax tree, without function bodies, is kept for both
+	// passes. The trees are released once the second pass is done.
+	var parsedFiles []parsedFile
+	for _, path := range files {
+		src, err := os.ReadFile(path)
 		if err != nil {
-			return nil
+			return nil, err
+		}
+		fileAst, err := goparser.ParseFile(fset, path, src, goparser.ParseComments)
+		if err != nil {
+			stats.FilesSkipped++
+			failureDiagnostics, stubs := parseFailure(path, err)
+			diagnostics = append(diagnostics, failureDiagnostics...)
+			incomplete = append(incomplete, stubs...)
+			continue
 		}
+		stats.FilesParsed++
+		parsedFiles = append(parsedFiles, parsedFile{path: path, ast: withoutBodies(fileAst), src: src})
+	}
 
-		currentPackage := fileAst.Name.Name
+	// Packages sharing their name are told apart before their structs are keyed
+	index := newPackageIndex(parsedFiles, fileRoots)
+	for i := range parsedFiles {
+		parsedFiles[i].pkg = index.packageOf(parsedFiles[i])
+		parsedFiles[i].importAliases = index.importAliases(parsedFiles[i])
+	}
+	// The fields of each struct resolve with the imports of its file
+	structAliases := make(map[models.StructKey]map[string]string)
+
+	// First pass: Collect all struct definitions, which the second pass needs from every file
+	err := forEachFile(parsedFiles, func(file parsedFile) error {
+		path, fileAst := file.path, file.ast
+		currentPackage := file.pkg
+		packages[currentPackage] = true
+		pragmas, pragmaDiagnostics := collectPragmas(fileAst, fset, file.src)
+		suppressions = append(suppressions, pragmas...)
+		diagnostics = append(diagnostics, pragmaDiagnostics...)
+		diagnostics = append(diagnostics, collectParamGroups(fileAst, currentPackage, fset, paramGroups)...)
+		collectConstants(fileAst, currentPackage, constants)
+		collectEnums(fileAst, currentPackage, enums)
 
 		// Extract global tags
-		if fileAst.Doc != nil && !projectInfoSet {
-			globalInfo, err := parseGlobalTags(fileAst.Doc)
-			if err == nil {
-				projectInfo = globalInfo
-				projectInfoSet = true
+		if fileAst.Doc != nil {
+			collectProjectInfo(fileAst.Doc, file)
+		}
+		diagnostics = append(diagnostics, checkAnnotations(fileAst.Doc, fset, ScopeProject)...)
+		diagnostics = append(diagnostics, checkSizes(fileAst.Doc, fset)...)
+		diagnostics = append(diagnostics, checkErrorCatalog(fileAst.Doc, fset)...)
+		diagnostics = append(diagnostics, checkGlobalErrors(fileAst.Doc, fset)...)
+
+		// Collect method docs, attached to the structs annotated with @IncludeMethodDocs below
+		for _, decl := range fileAst.Decls {
+			if fn, isFn := decl.(*ast.FuncDecl); isFn {
+				if receiver, method, ok := methodDoc(fn); ok {
+					key := models.StructKey{Package: currentPackage, Name: receiver}
+					methodDocs[key] = append(methodDocs[key], method)
+				}
 			}
 		}
 
@@ -80,8 +206,14 @@ func ParseProject(rootDir string) ([]models.APIFunction, map[models.StructKey]mo
 				if !isType {
 					continue
 				}
+				declaredTypes[models.StructKey{Package: currentPackage, Name: typeSpec.Name.Name}] = true
 				structType, isStruct := typeSpec.Type.(*ast.StructType)
 				if !isStruct {
+					if alias, ok := typeAlias(typeSpec, fset.Position(typeSpec.Pos())); ok {
+						aliasKey := models.StructKey{Package: currentPackage, Name: alias.Name}
+						typeAliases[aliasKey] = alias
+						aliasImports[aliasKey] = file.importAliases
+					}
 					continue
 				}
 
@@ -89,6 +221,27 @@ func ParseProject(rootDir string) ([]models.APIFunction, map[models.StructKey]mo
 					Name: typeSpec.Name.Name,
 				}
 				structDef.Description = extractStructDescription(genDecl.Doc)
+				structDef.OnlyTagged = hasMarker(genDecl.Doc, "@OnlyTagged")
+				structDef.NoTruncate = hasMarker(genDecl.Doc, "@NoTruncate")
+				structDef.IncludeMethodDocs = hasMarker(genDecl.Doc, "@IncludeMethodDocs")
+				diagnostics = append(diagnostics, checkAnnotations(genDecl.Doc, fset, ScopeStruct)...)
+				position := fset.Position(typeSpec.Pos())
+				structDef.SourceFile = position.Filename
+				structDef.SourceLine = position.Line
+				if id, ok := markerValue(genDecl.Doc, "@ID"); ok {
+					if utils.IsValidID(id) {
+						structDef.ID = id
+					} else {
+						diagnostics = append(diagnostics, Diagnostic{
+							Severity: SeverityError,
+							File:     position.Filename,
+							Line:     position.Line,
+							Class:    ClassInvalidID,
+							Message:  fmt.Sprintf("invalid @ID '%s' on struct '%s': only letters, digits, '.', '_' and '-' are allowed", id, structDef.Name),
+						})
+					}
+				}
+				var hiddenFields, untaggedFields []string
 
 				// Capture type parameters if generic
 				if typeSpec.TypeParams != nil {
@@ -105,66 +258,180 @@ func ParseProject(rootDir string) ([]models.APIFunction, map[models.StructKey]mo
 					}
 				}
 
-				// Process fields
-				for _, field := range structType.Fields.List {
-					fieldName := ""
-					if len(field.Names) > 0 {
-						fieldName = field.Names[0].Name
-					} else {
-						fieldName = utils.ExprToString(field.Type)
-					}
+				// Process fields. Anonymous struct types of fields are documented as a struct
+				// of their own, named after the field, such as "Order.Details".
+				var inlineStructs []models.StructDefinition
+				var processFields func(structDef *models.StructDefinition, fieldList *ast.FieldList)
+				processFields = func(structDef *models.StructDefinition, fieldList *ast.FieldList) {
+					for _, field := range fieldList.List {
+						diagnostics = append(diagnostics, checkAnnotations(field.Doc, fset, ScopeField)...)
+						diagnostics = append(diagnostics, checkAnnotations(field.Comment, fset, ScopeField)...)
+						for _, fieldName := range fieldNames(field) {
+							// @Hidden always wins and sets the field aside for the internal audience. With
+							// @OnlyTagged only fields carrying a json tag are kept, and unexported fields are
+							// dropped even when tagged since encoding/json ignores them.
+							hidden := hasMarker(field.Doc, "@Hidden") || hasMarker(field.Comment, "@Hidden")
+							untagged := structDef.OnlyTagged && (!hasJSONTag(field) || !ast.IsExported(fieldName))
+							switch {
+							case hidden:
+								hiddenFields = append(hiddenFields, fieldName)
+								if untagged {
+									continue
+								}
+							case untagged:
+								untaggedFields = append(untaggedFields, fieldName)
+								continue
+							}
 
-					jsonName := fieldName
-					if field.Tag != nil {
-						tag := field.Tag.Value
-						jsonName = utils.ExtractJSONTag(tag, fieldName)
-					}
+							jsonName := fieldName
+							omitempty := false
+							var units, format string
+							if field.Tag != nil {
+								tag := field.Tag.Value
+								jsonName = utils.ExtractJSONTag(tag, fieldName)
+								omitempty = utils.HasJSONOption(tag, "omitempty")
+								units = utils.TagValue(tag, "units")
+								format = utils.TagValue(tag, "format")
+							}
 
-					fieldType := utils.ExprToString(field.Type)
-					fieldDesc := extractFieldDescription(field.Doc, field.Comment)
+							fieldType := utils.ExprToString(field.Type)
+							fieldDesc := extractFieldDescription(field.Doc, field.Comment)
+
+							var inlineRef *models.TypeRef
+							if prefix, elem := utils.SplitElementType(fieldType); elem == utils.InlineStructType {
+								inline := models.StructDefinition{
+									Name:       structDef.Name + "." + fieldName,
+									OnlyTagged: structDef.OnlyTagged,
+									SourceFile: position.Filename,
+									SourceLine: fset.Position(field.Pos()).Line,
+								}
+								processFields(&inline, inlineStructType(field.Type).Fields)
+								inlineStructs = append(inlineStructs, inline)
+								inlineKey := models.StructKey{Package: currentPackage, Name: inline.Name}
+								inlineRef = utils.WrapElementType(prefix, &models.TypeRef{Kind: models.TypeStruct, Name: inline.Name, Package: currentPackage, Struct: inlineKey})
+							}
 
-					structField := models.StructField{
-						Name:        fieldName,
-						Type:        fieldType,
-						Description: fieldDesc,
-						JSONName:    jsonName,
-					}
-					structDef.Fields = append(structDef.Fields, structField)
+							// An explicit format tag wins over the format implied by the type
+							if implied := utils.ImpliedFormat(fieldType); format == "" {
+								format = implied
+							} else if implied != "" && format != implied {
+								diagnostics = append(diagnostics, Diagnostic{
+									Severity: SeverityInfo,
+									File:     path,
+									Line:     fset.Position(field.Pos()).Line,
+									Class:    ClassFormatOverride,
+									Message:  fmt.Sprintf("field '%s' of struct '%s' has format '%s', overriding the format '%s' of its type '%s'", fieldName, structDef.Name, format, implied, fieldType),
+								})
+							}
 
-					// Note nested structs for processing if needed
-					baseType, pkg := utils.ResolveType(fieldType)
-					if baseType == "" {
-						continue
-					}
-					if utils.IsBasicType(baseType) {
-						continue
-					}
+							structField := models.StructField{
+								Name:        fieldName,
+								Type:        fieldType,
+								TypeRef:     inlineRef,
+								Description: fieldDesc,
+								JSONName:    jsonName,
+								Omitempty:   omitempty,
+								Embedded:    len(field.Names) == 0 && jsonName != "" && (field.Tag == nil || utils.ExtractJSONTag(field.Tag.Value, "") == ""),
+								SourceLine:  fset.Position(field.Pos()).Line,
+								Units:       units,
+								Format:      format,
+							}
+							keys, hasKeys := markerText(field.Doc, "@keys")
+							if !hasKeys {
+								keys, hasKeys = markerText(field.Comment, "@keys")
+							}
+							if hasKeys {
+								structField.DynamicKeys = splitKeys(keys)
+								if utils.ParseType(fieldType).Deref().Kind != models.TypeMap {
+									diagnostics = append(diagnostics, Diagnostic{
+										Severity: SeverityWarning,
+										File:     position.Filename,
+										Line:     structField.SourceLine,
+										Class:    ClassDynamicKeys,
+										Message:  fmt.Sprintf("field '%s' of struct '%s' has @keys, but its type '%s' is not a map", fieldName, structDef.Name, fieldType),
+									})
+								}
+							}
+							switch {
+							case structField.JSONName == "":
+								// Fields tagged json:"-" never appear in payloads
+								structField.Excluded = true
+								structDef.ExcludedFields = append(structDef.ExcludedFields, structField)
+								continue
+							case hidden:
+								structDef.HiddenFields = append(structDef.HiddenFields, structField)
+								continue
+							}
+							structDef.Fields = append(structDef.Fields, structField)
 
-					var structKey models.StructKey
-					if pkg != "" {
-						structKey = models.StructKey{
-							Package: pkg,
-							Name:    baseType,
-						}
-					} else {
-						structKey = models.StructKey{
-							Package: currentPackage,
-							Name:    baseType,
+							// Note nested structs for processing if needed
+							baseType, pkg := utils.ResolveType(fieldType)
+							if baseType == "" || inlineRef != nil {
+								continue
+							}
+							if _, wellKnown := utils.WellKnownType(utils.ParseType(fieldType), nil); utils.IsBasicType(baseType) || wellKnown {
+								continue
+							}
+
+							var structKey models.StructKey
+							if pkg != "" {
+								structKey = models.StructKey{
+									Package: pkg,
+									Name:    baseType,
+								}
+							} else {
+								structKey = models.StructKey{
+									Package: currentPackage,
+									Name:    baseType,
+								}
+							}
+							if _, exists := structDefinitions[structKey]; exists || processedStructs[structKey] {
+								continue
+							}
+							processedStructs[structKey] = true
 						}
 					}
-					if _, exists := structDefinitions[structKey]; exists || processedStructs[structKey] {
-						continue
-					}
-					processedStructs[structKey] = true
 				}
+				processFields(&structDef, structType.Fields)
 
 				key := models.StructKey{
 					Package: currentPackage,
 					Name:    structDef.Name,
 				}
+				if existing, exists := structDefinitions[key]; exists && fileRoots[existing.SourceFile] != fileRoots[path] {
+					// Roots may share a copy of a struct, such as a vendored package
+					if !sameStructDefinition(existing, structDef) {
+						return fmt.Errorf("struct '%s.%s' is declared differently in %s:%d and %s:%d", key.Package, key.Name,
+							existing.SourceFile, existing.SourceLine, structDef.SourceFile, structDef.SourceLine)
+					}
+					continue
+				}
 				structDefinitions[key] = structDef
+				structAliases[key] = file.importAliases
+				for _, inline := range inlineStructs {
+					inlineKey := models.StructKey{Package: currentPackage, Name: inline.Name}
+					structDefinitions[inlineKey] = inline
+					structAliases[inlineKey] = file.importAliases
+				}
 
-				log.Printf("Collected struct: Package='%s', Name='%s'", key.Package, key.Name)
+				if len(hiddenFields) > 0 {
+					diagnostics = append(diagnostics, Diagnostic{
+						Severity: SeverityInfo,
+						File:     position.Filename,
+						Line:     position.Line,
+						Class:    ClassHiddenFields,
+						Message:  fmt.Sprintf("struct '%s.%s': %d fields hidden by @Hidden (%s)", key.Package, key.Name, len(hiddenFields), strings.Join(hiddenFields, ", ")),
+					})
+				}
+				if len(untaggedFields) > 0 {
+					diagnostics = append(diagnostics, Diagnostic{
+						Severity: SeverityInfo,
+						File:     position.Filename,
+						Line:     position.Line,
+						Class:    ClassHiddenFields,
+						Message:  fmt.Sprintf("struct '%s.%s': %d fields omitted by @OnlyTagged (%s)", key.Package, key.Name, len(untaggedFields), strings.Join(untaggedFields, ", ")),
+					})
+				}
 			}
 		}
 
@@ -172,196 +439,606 @@ func ParseProject(rootDir string) ([]models.APIFunction, map[models.StructKey]mo
 	})
 
 	if err != nil {
-		return nil, nil, projectInfo, err
+		return nil, err
 	}
 
-	log.Println("Collected structs:")
-	for key := range structDefinitions {
-		log.Printf(" - Package: %s, Struct: %s", key.Package, key.Name)
+	// The content of the files is only needed by the first pass
+	for i := range parsedFiles {
+		parsedFiles[i].src = nil
 	}
+	diagnostics = append(diagnostics, unresolvedFieldTypes(structDefinitions, structAliases, declaredTypes, packages)...)
 
-	// Second pass: process functions
-	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if info.IsDir() {
-			if info.Name() == "vendor" || strings.HasPrefix(info.Name(), ".") {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
-			return nil
-		}
-
-		fileAst, err := goparser.ParseFile(fset, path, nil, goparser.ParseComments)
-		if err != nil {
-			return nil
+	for key, structDef := range structDefinitions {
+		if structDef.IncludeMethodDocs {
+			structDef.Methods = methodDocs[key]
+			structDefinitions[key] = structDef
 		}
+	}
 
-		currentPackage := fileAst.Name.Name
-		importAliases := extractImportAliases(fileAst)
+	// Second pass: process functions
+	for _, file := range parsedFiles {
+		path, fileAst := file.path, file.ast
+		currentPackage, importAliases := file.pkg, file.importAliases
+		sourcePath := repositoryPath(fileRoots[path], path)
 
 		// Extract global tags from file-level comments if not set
 		if fileAst.Doc != nil && !projectInfoSet {
-			globalInfo, err := parseGlobalTags(fileAst.Doc)
-			if err == nil {
-				projectInfo = globalInfo
-				projectInfoSet = true
-			}
+			collectProjectInfo(fileAst.Doc, file)
 		}
 
 		for _, decl := range fileAst.Decls {
+			if genDecl, isGen := decl.(*ast.GenDecl); isGen {
+				diagnostics = append(diagnostics, checkDeclAnnotations(genDecl, fset)...)
+				continue
+			}
 			fn, isFn := decl.(*ast.FuncDecl)
 			if !isFn || fn.Doc == nil {
 				continue
 			}
+			// Project annotations may also be written on functions
+			diagnostics = append(diagnostics, checkAnnotations(fn.Doc, fset, ScopeFunction, ScopeProject)...)
+			diagnostics = append(diagnostics, checkCommandFunction(fn, fset)...)
+			diagnostics = append(diagnostics, checkSizes(fn.Doc, fset)...)
+			_, directiveDiagnostics := functionAnnotations(fn.Doc, fset)
+			diagnostics = append(diagnostics, directiveDiagnostics...)
 
 			apiFunc, err := parseFunction(fn, currentPackage, importAliases, path, fset, structDefinitions)
 			if err == nil {
+				apiFunc.SourcePath = sourcePath
 				apiFunctions = append(apiFunctions, apiFunc)
-			} else {
-				if !errors.Is(err, ErrMissingCommand) {
-					position := fset.Position(fn.Pos())
-					log.Printf("Error in file %s at line %d: Function '%s' skipped due to error: %v", position.Filename, position.Line, fn.Name.Name, err)
+				diagnostics = append(diagnostics, unresolvedAnnotationTypes(apiFunc, fn.Doc, fset, declaredTypes, packages)...)
+			} else if !errors.Is(err, ErrMissingCommand) {
+				position := fset.Position(fn.Pos())
+				var located *annotationError
+				if errors.As(err, &located) {
+					position.Line = located.Line
 				}
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityWarning,
+					File:     position.Filename,
+					Line:     position.Line,
+					Class:    ClassParseFailure,
+					Message:  fmt.Sprintf("function '%s' skipped: %v", fn.Name.Name, err),
+				})
+				stub := incompleteStub(commandName(fn.Doc, fset, apiFunc.Command), position.Filename, position.Line, err.Error())
+				stub.PackageName = currentPackage
+				incomplete = append(incomplete, stub)
 			}
 
 			if !projectInfoSet {
-				globalInfo, err := parseGlobalTags(fn.Doc)
-				if err == nil {
-					projectInfo = globalInfo
-					projectInfoSet = true
-				}
+				collectProjectInfo(fn.Doc, file)
 			}
 		}
+	}
 
+	if !projectInfoSet {
+		return nil, errors.New("no global tags found in any Go file. Please include global tags in at least one file")
+	}
+
+	apiFunctions, duplicateDiagnostics, err := checkDuplicateCommands(apiFunctions, opts.AllowDuplicateCommands)
+	if err != nil {
+		return nil, err
+	}
+	diagnostics = append(diagnostics, duplicateDiagnostics...)
+	diagnostics = append(diagnostics, expandParamGroups(apiFunctions, paramGroups)...)
+	diagnostics = append(diagnostics, resolveDefaults(apiFunctions, constants)...)
+	diagnostics = append(diagnostics, resolveErrors(apiFunctions, projectInfo.ErrorCatalog)...)
+	resolveTypeRefs(apiFunctions, structDefinitions, structAliases)
+	diagnostics = append(diagnostics, resolveEnvelope(&projectInfo.ResultEnvelope, structDefinitions)...)
+	resolveTypeAliases(apiFunctions, structDefinitions, &projectInfo.ResultEnvelope, typeAliases, aliasImports)
+	attachEnums(apiFunctions, structDefinitions, enums)
+	diagnostics = append(diagnostics, assignIDs(apiFunctions, structDefinitions)...)
+	diagnostics = append(diagnostics, checkFormerNames(apiFunctions)...)
+	diagnostics = append(diagnostics, checkEnvelopes(apiFunctions)...)
+	diagnostics = append(diagnostics, checkDynamicKeys(apiFunctions)...)
+	diagnostics = append(diagnostics, checkParamRules(apiFunctions)...)
+
+	stats.Commands = len(apiFunctions)
+	stats.Structs = len(structDefinitions)
+
+	result := &Result{
+		Functions:    apiFunctions,
+		Structs:      structDefinitions,
+		TypeAliases:  typeAliases,
+		ProjectInfo:  projectInfo,
+		Diagnostics:  diagnostics,
+		Stats:        stats,
+		Incomplete:   incomplete,
+		suppressions: suppressions,
+	}
+	result.ApplySuppressions()
+	return result, nil
+}
+
+// listSourceFiles lists the Go files parsed by ParseProject. Tests replace it to check that
+// the result does not depend on the order files are listed in.
+var listSourceFiles = sourceFiles
+
+// sourceFiles returns the Go files under rootDir, leaving out tests, vendor directories,
+// hidden directories and the paths matching the exclude patterns.
+func sourceFiles(rootDir string, exclude []string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if relPath, err := filepath.Rel(rootDir, path); err == nil && relPath != "." && excluded(exclude, filepath.ToSlash(relPath), info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if info.Name() == "vendor" || strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".go" && !strings.HasSuffix(path, "_test.go") {
+			files = append(files, path)
+		}
 		return nil
 	})
+	return files, err
+}
 
-	if err != nil {
-		return nil, nil, projectInfo, err
+// parsedFile is a parsed Go file kept between the two passes of ParseProjects.
+type parsedFile struct {
+	path string
+	ast  *ast.File
+	// src is the content of the file, only kept for the first pass.
+	src []byte
+	// pkg is the package ID of the file and importAliases the package IDs of its imports,
+	// see packageIndex.
+	pkg           string
+	importAliases map[string]string
+}
+
+// withoutBodies drops the bodies of the functions of a parsed file, which documenting never
+// reads, so the files kept between the passes take less memory.
+func withoutBodies(fileAst *ast.File) *ast.File {
+	for _, decl := range fileAst.Decls {
+		if fn, isFn := decl.(*ast.FuncDecl); isFn {
+			fn.Body = nil
+		}
 	}
+	return fileAst
+}
 
-	if !projectInfoSet {
-		return nil, nil, projectInfo, errors.New("no global tags found in any Go file. Please include global tags in at least one file")
+// forEachFile calls fn for each parsed file, stopping at the first error.
+func forEachFile(files []parsedFile, fn func(file parsedFile) error) error {
+	for _, file := range files {
+		if err := fn(file); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	log.Println("Final structDefinitions:")
-	for key := range structDefinitions {
-		log.Printf(" - Package: %s, Struct: %s", key.Package, key.Name)
+// checkDynamicKeys reports @DynamicKeys on results that are not maps.
+func checkDynamicKeys(apiFunctions []models.APIFunction) Diagnostics {
+	var diagnostics Diagnostics
+	for _, apiFunc := range apiFunctions {
+		for _, result := range apiFunc.Results {
+			if len(result.DynamicKeys) > 0 && result.TypeRef.Deref().Kind != models.TypeMap {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityWarning,
+					File:     apiFunc.SourceFile,
+					Line:     apiFunc.AnnotationLine("@DynamicKeys"),
+					Class:    ClassDynamicKeys,
+					Message:  fmt.Sprintf("command '%s' has @DynamicKeys, but its result type '%s' is not a map", apiFunc.Command, result.Type),
+				})
+			}
+		}
 	}
+	return diagnostics
+}
 
-	return apiFunctions, structDefinitions, projectInfo, nil
+// fieldNames returns the names declared by a struct field, such as Width and Height for
+// "Width, Height int", or the name of the type of an embedded field.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		return []string{embeddedFieldName(utils.ExprToString(field.Type))}
+	}
+	names := make([]string, len(field.Names))
+	for i, name := range field.Names {
+		names[i] = name.Name
+	}
+	return names
+}
+
+// inlineStructType returns the anonymous struct type held by expr, directly or through
+// pointers, slices and maps, such as struct{ Code int } for "[]struct{ Code int }".
+func inlineStructType(expr ast.Expr) *ast.StructType {
+	for {
+		switch e := expr.(type) {
+		case *ast.StructType:
+			return e
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			expr = e.Elt
+		case *ast.MapType:
+			expr = e.Value
+		default:
+			return nil
+		}
+	}
+}
+
+// embeddedFieldName returns the name of an embedded field of type typ, which is the name of
+// its type without pointer, package or type arguments, such as "Meta" for "*common.Meta[T]".
+func embeddedFieldName(typ string) string {
+	name := strings.TrimLeft(typ, "*")
+	if start := strings.Index(name, "["); start != -1 {
+		name = name[:start]
+	}
+	if dot := strings.LastIndex(name, "."); dot != -1 {
+		name = name[dot+1:]
+	}
+	return name
+}
+
+// resolveTypeRefs sets the parsed type of every parameter, result, notification payload and
+// struct field that does not have one yet. Struct fields are resolved in the package of their
+// struct, with the imports of its file given by structAliases.
+func resolveTypeRefs(apiFunctions []models.APIFunction, structDefinitions map[models.StructKey]models.StructDefinition, structAliases map[models.StructKey]map[string]string) {
+	for i := range apiFunctions {
+		apiFunc := &apiFunctions[i]
+		for j, param := range apiFunc.Parameters {
+			if param.TypeRef == nil {
+				apiFunc.Parameters[j].TypeRef = utils.ResolveTypeRef(utils.ParseType(param.Type), apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
+			}
+		}
+		for j, result := range apiFunc.Results {
+			if result.TypeRef == nil {
+				apiFunc.Results[j].TypeRef = utils.ResolveTypeRef(utils.ParseType(result.Type), apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
+			}
+		}
+		if apiFunc.Subscription != nil && apiFunc.Subscription.Payload.TypeRef == nil {
+			payload := &apiFunc.Subscription.Payload
+			payload.TypeRef = utils.ResolveTypeRef(utils.ParseType(payload.Type), apiFunc.PackageName, apiFunc.ImportAliases, structDefinitions)
+		}
+	}
+
+	for key, structDef := range structDefinitions {
+		for _, fields := range [][]models.StructField{structDef.Fields, structDef.HiddenFields, structDef.ExcludedFields} {
+			for j, field := range fields {
+				if field.TypeRef == nil {
+					fields[j].TypeRef = utils.ResolveTypeRef(utils.ParseType(field.Type), key.Package, structAliases[key], structDefinitions)
+				}
+			}
+		}
+	}
+}
+
+// parseFailure reports a file that could not be parsed, together with every @Command
+// annotation it contains, since those commands are missing from the output. It returns the
+// stubs of these commands.
+func parseFailure(path string, err error) (Diagnostics, []models.APIFunction) {
+	var diagnostics Diagnostics
+	var stubs []models.APIFunction
+
+	failure := Diagnostic{
+		Severity: SeverityWarning,
+		File:     path,
+		Class:    ClassParseFailure,
+		Message:  fmt.Sprintf("file skipped, failed to parse: %v", err),
+	}
+	reason := fmt.Sprintf("its file failed to parse: %v", err)
+	var errList scanner.ErrorList
+	if errors.As(err, &errList) && len(errList) > 0 {
+		failure.Line = errList[0].Pos.Line
+		failure.Column = errList[0].Pos.Column
+		failure.Message = fmt.Sprintf("file skipped, failed to parse: %s", errList[0].Msg)
+		if len(errList) > 1 {
+			failure.Message += fmt.Sprintf(" (and %d more errors)", len(errList)-1)
+		}
+		reason = fmt.Sprintf("its file failed to parse at line %d: %s", errList[0].Pos.Line, errList[0].Msg)
+	}
+	diagnostics = append(diagnostics, failure)
+
+	content, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return diagnostics, stubs
+	}
+	lines := bufio.NewScanner(strings.NewReader(string(content)))
+	lineNumber := 0
+	for lines.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(lines.Text())
+		if !strings.HasPrefix(line, "//") {
+			continue
+		}
+		parts := strings.Fields(strings.TrimPrefix(line, "//"))
+		if len(parts) < 2 || !strings.EqualFold(parts[0], "@Command") && parts[0] != "jdocgen:command" {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			File:     path,
+			Line:     lineNumber,
+			Class:    ClassParseFailure,
+			Message:  fmt.Sprintf("command '%s' is not documented because its file failed to parse", parts[1]),
+		})
+		stubs = append(stubs, incompleteStub(parts[1], path, lineNumber, reason))
+	}
+
+	return diagnostics, stubs
+}
+
+// annotationError is an error in a function annotation, located at the line of the annotation.
+type annotationError struct {
+	Line int
+	err  error
+}
+
+func (e *annotationError) Error() string { return e.err.Error() }
+
+func (e *annotationError) Unwrap() error { return e.err }
+
+// atLine locates err at the line of the annotation it was found in.
+func atLine(line int, err error) error {
+	return &annotationError{Line: line, err: err}
 }
 
 func parseFunction(fn *ast.FuncDecl, currentPackage string, importAliases map[string]string, fileName string, fset *token.FileSet, structDefinitions map[models.StructKey]models.StructDefinition) (models.APIFunction, error) {
+	position := fset.Position(fn.Pos())
 	apiFunc := models.APIFunction{
 		ImportAliases: importAliases,
 		PackageName:   currentPackage,
+		SourceFile:    position.Filename,
+		SourceLine:    position.Line,
 	}
 
-	var resultAnnotations []*ast.Comment
-	scanner := bufio.NewScanner(strings.NewReader(fn.Doc.Text()))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if !strings.HasPrefix(line, "@") {
-			continue
-		}
+	var resultAnnotations []annotationLine
+	var dynamicKeys []string
+	var dynamicKeysLine, contentTypeLine int
+	var contentTypes []models.ContentType
+	var payload *models.NotificationPayload
+	lines, _ := functionAnnotations(fn.Doc, fset)
+	apiFunc.AnnotationLines = make(map[string]int)
+	for _, annotationLine := range lines {
+		line := annotationLine.Text
 		parts := strings.Fields(line)
 		if len(parts) < 1 {
 			continue
 		}
-		switch parts[0] {
+		annotation, ok := LookupAnnotation(parts[0], ScopeFunction)
+		if !ok {
+			continue
+		}
+		switch annotation.Name {
 		case "@Command":
 			if len(parts) < 2 {
-				return apiFunc, errors.New("missing command name in @Command annotation")
+				return apiFunc, atLine(annotationLine.Line, errors.New("missing command name in @Command annotation"))
 			}
 			apiFunc.Command = parts[1]
+			apiFunc.AnnotationLines["@Command"] = annotationLine.Line
 		case "@Description":
-			description := strings.TrimPrefix(line, "@Description")
-			apiFunc.Description = strings.TrimSpace(description)
+			apiFunc.Description = normalizeSpace(restOfLine(line, 1))
+			apiFunc.AnnotationLines["@Description"] = annotationLine.Line
 		case "@Parameter":
-			if len(parts) < 4 {
-				return apiFunc, errors.New("invalid @Parameter annotation. Expected format: @Parameter name type \"description\"")
-			}
-			paramName := parts[1]
-			paramType := parts[2]
-			paramDesc := strings.Join(parts[3:], " ")
-			paramDesc = strings.Trim(paramDesc, "\"")
-			param := models.APIParameter{
-				Name:        paramName,
-				Type:        paramType,
-				Description: paramDesc,
-				Required:    true,
-			}
-			if strings.HasPrefix(paramDesc, "optional") {
-				param.Required = false
-				param.Description = strings.TrimPrefix(param.Description, "optional")
-				param.Description = strings.TrimSpace(param.Description)
+			param, err := parseParameter(annotationLine)
+			if err != nil {
+				return apiFunc, err
 			}
 			apiFunc.Parameters = append(apiFunc.Parameters, param)
+		case "@Params":
+			if len(parts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @Params annotation. Expected format: @Params group"))
+			}
+			apiFunc.ParamGroups = append(apiFunc.ParamGroups, parts[1])
+			apiFunc.AnnotationLines["@Params "+parts[1]] = annotationLine.Line
 		case "@Result":
-			resultAnnotations = append(resultAnnotations, &ast.Comment{Text: line})
+			resultAnnotations = append(resultAnnotations, annotationLine)
 		case "@Error":
-			if len(parts) < 3 {
-				return apiFunc, errors.New("invalid @Error annotation. Expected format: @Error code \"description\"")
+			// The description may be left out for codes of the @errorcatalog
+			errorParts, rest := splitFields(line, 2)
+			if len(errorParts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @Error annotation. Expected format: @Error code [\"description\"]"))
 			}
-			errorCodeStr := parts[1]
-			errorDesc := strings.Join(parts[2:], " ")
-			errorDesc = strings.Trim(errorDesc, "\"")
-			errorCode, err := strconv.Atoi(errorCodeStr)
+			errorCode, err := strconv.Atoi(errorParts[1])
 			if err != nil {
-				return apiFunc, ErrInvalidErrorCode
+				return apiFunc, atLine(annotationLine.Line, ErrInvalidErrorCode)
+			}
+			errorDesc, err := quotedDescription(rest)
+			if err != nil {
+				return apiFunc, atLine(annotationLine.Line, fmt.Errorf("malformed @Error annotation: %v", err))
 			}
 			apiError := models.APIError{
 				Code:        errorCode,
 				Description: errorDesc,
+				SourceLine:  annotationLine.Line,
 			}
 			apiFunc.Errors = append(apiFunc.Errors, apiError)
 		case "@Additional":
 			if len(parts) < 2 {
-				return apiFunc, errors.New("invalid @Additional annotation. Expected format: @Additional [package.]structname")
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @Additional annotation. Expected format: @Additional [package.]structname"))
 			}
 			additionalType := parts[1]
 			apiFunc.AdditionalStructs = append(apiFunc.AdditionalStructs, additionalType)
+			apiFunc.AnnotationLines["@Additional "+additionalType] = annotationLine.Line
+		case "@ID":
+			if len(parts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @ID annotation. Expected format: @ID identifier"))
+			}
+			if !utils.IsValidID(parts[1]) {
+				return apiFunc, atLine(annotationLine.Line, fmt.Errorf("invalid @ID '%s': only letters, digits, '.', '_' and '-' are allowed", parts[1]))
+			}
+			apiFunc.ID = parts[1]
+			apiFunc.AnnotationLines["@ID"] = annotationLine.Line
+		case "@FlattenParams":
+			apiFunc.FlattenParams = true
+		case "@NoEnvelope":
+			apiFunc.NoEnvelope = true
+		case "@Internal":
+			apiFunc.Internal = true
+		case "@Deprecated":
+			apiFunc.Deprecated = true
+			apiFunc.DeprecationReason = annotationDescription(line, 1)
+		case "@DynamicKeys":
+			if len(parts) < 3 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @DynamicKeys annotation. Expected format: @DynamicKeys result \"key description\""))
+			}
+			if parts[1] != "result" {
+				return apiFunc, atLine(annotationLine.Line, fmt.Errorf("invalid @DynamicKeys target '%s'. Only result is supported", parts[1]))
+			}
+			dynamicKeys = splitKeys(strings.Join(parts[2:], " "))
+			dynamicKeysLine = annotationLine.Line
+			apiFunc.AnnotationLines["@DynamicKeys"] = annotationLine.Line
+		case "@Requires", "@ConflictsWith":
+			if len(parts) < 3 {
+				return apiFunc, atLine(annotationLine.Line, fmt.Errorf("invalid %s annotation. Expected format: %s param other [other...]", parts[0], parts[0]))
+			}
+			rule := models.ParamRule{
+				Param:      parts[1],
+				Others:     strings.FieldsFunc(strings.Join(parts[2:], " "), isClassSeparator),
+				SourceLine: annotationLine.Line,
+			}
+			if annotation.Name == "@Requires" {
+				apiFunc.Requires = append(apiFunc.Requires, rule)
+			} else {
+				apiFunc.ConflictsWith = append(apiFunc.ConflictsWith, rule)
+			}
+		case "@ContentType":
+			if len(parts) < 2 || len(parts) > 3 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @ContentType annotation. Expected format: @ContentType media/type [encoding]"))
+			}
+			if !strings.Contains(parts[1], "/") {
+				return apiFunc, atLine(annotationLine.Line, fmt.Errorf("invalid @ContentType media type '%s'. Expected type/subtype, such as application/pdf", parts[1]))
+			}
+			contentType := models.ContentType{MediaType: parts[1]}
+			if len(parts) == 3 {
+				contentType.Encoding = parts[2]
+			}
+			contentTypes = append(contentTypes, contentType)
+			contentTypeLine = annotationLine.Line
+		case "@Subscription":
+			apiFunc.Subscription = &models.Subscription{Method: defaultNotificationMethod}
+			if len(parts) > 1 {
+				apiFunc.Subscription.Method = parts[1]
+			}
+			apiFunc.AnnotationLines["@Subscription"] = annotationLine.Line
+		case "@NotificationPayload":
+			if len(parts) < 3 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @NotificationPayload annotation. Expected format: @NotificationPayload type \"description\""))
+			}
+			payload = &models.NotificationPayload{
+				Type:        parts[1],
+				Description: annotationDescription(line, 2),
+				SourceLine:  annotationLine.Line,
+			}
+		case "@MaxRequestSize":
+			apiFunc.Sizes.MaxRequest = sizeValue(line)
+		case "@TypicalResponseSize":
+			apiFunc.Sizes.TypicalResponse = sizeValue(line)
+		case "@Auth":
+			if len(parts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @Auth annotation. Expected format: @Auth scheme"))
+			}
+			apiFunc.Auth = parts[1]
+		case "@Feature":
+			if len(parts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @Feature annotation. Expected format: @Feature flag"))
+			}
+			apiFunc.Features = append(apiFunc.Features, parts[1])
+		case "@ExampleRequest", "@ExampleResponse":
+			example, err := exampleJSON(annotationLine)
+			if err != nil {
+				return apiFunc, atLine(annotationLine.Line, fmt.Errorf("malformed %s annotation: %v", annotation.Name, err))
+			}
+			if annotation.Name == "@ExampleRequest" {
+				apiFunc.ExampleRequest = example
+			} else {
+				apiFunc.ExampleResponse = example
+			}
+		case "@Tag":
+			if len(parts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @Tag annotation. Expected format: @Tag name"))
+			}
+			apiFunc.Tags = append(apiFunc.Tags, parts[1])
+		case "@FormerName":
+			if len(parts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @FormerName annotation. Expected format: @FormerName command"))
+			}
+			apiFunc.FormerNames = append(apiFunc.FormerNames, parts[1])
+			apiFunc.AnnotationLines["@FormerName "+parts[1]] = annotationLine.Line
+		case "@Envelope":
+			apiFunc.AnnotationLines["@Envelope"] = annotationLine.Line
+			if len(parts) < 2 {
+				return apiFunc, atLine(annotationLine.Line, errors.New("invalid @Envelope annotation. Expected format: @Envelope jsonrpc=version id=type"))
+			}
+			for _, pair := range parts[1:] {
+				key, value, found := strings.Cut(pair, "=")
+				switch {
+				case !found:
+					return apiFunc, atLine(annotationLine.Line, fmt.Errorf("invalid @Envelope setting '%s'. Expected key=value", pair))
+				case key == "jsonrpc":
+					apiFunc.Envelope.JSONRPC = value
+				case key == "id":
+					apiFunc.Envelope.IDType = value
+				default:
+					return apiFunc, atLine(annotationLine.Line, fmt.Errorf("unknown @Envelope setting '%s'. Expected jsonrpc or id", key))
+				}
+			}
+		}
+	}
+
+	if dynamicKeys != nil && len(resultAnnotations) == 0 {
+		return apiFunc, atLine(dynamicKeysLine, errors.New("@DynamicKeys result requires a @Result annotation"))
+	}
+	if contentTypes != nil && len(resultAnnotations) == 0 {
+		return apiFunc, atLine(contentTypeLine, errors.New("@ContentType requires a @Result annotation"))
+	}
+
+	if payload != nil && apiFunc.Subscription == nil {
+		return apiFunc, atLine(payload.SourceLine, errors.New("@NotificationPayload requires a @Subscription annotation"))
+	}
+	if apiFunc.Subscription != nil {
+		if payload == nil {
+			return apiFunc, atLine(apiFunc.AnnotationLines["@Subscription"], errors.New("@Subscription requires a @NotificationPayload annotation"))
+		}
+		if len(resultAnnotations) == 0 {
+			return apiFunc, atLine(apiFunc.AnnotationLines["@Subscription"], errors.New("@Subscription requires a @Result annotation describing the subscription id"))
 		}
+		apiFunc.Subscription.Payload = *payload
 	}
 
 	if len(resultAnnotations) > 1 {
-		return apiFunc, fmt.Errorf("%w. JSON-RPC specification enforces a single @Result annotation per function.", ErrMultipleResults)
+		return apiFunc, atLine(resultAnnotations[1].Line, fmt.Errorf("%w. JSON-RPC specification enforces a single @Result annotation per function.", ErrMultipleResults))
 	}
 
 	if len(resultAnnotations) == 1 {
-		line := strings.TrimSpace(resultAnnotations[0].Text)
-		parts := strings.Fields(line)
-		if len(parts) < 3 {
-			return apiFunc, ErrMalformedResult
+		parts, rest := splitFields(resultAnnotations[0].Text, 2)
+		if len(parts) < 2 || rest == "" {
+			return apiFunc, atLine(resultAnnotations[0].Line, ErrMalformedResult)
 		}
 		resultType := parts[1]
-		resultDescParts := parts[2:]
-		resultDesc := strings.Join(resultDescParts, " ")
-		resultDesc = strings.Trim(resultDesc, "\"")
+		resultDesc, err := quotedDescription(rest)
+		if err != nil {
+			return apiFunc, atLine(resultAnnotations[0].Line, fmt.Errorf("malformed @Result annotation: %v", err))
+		}
 		result := models.APIReturn{
-			Name:        "result",
-			Type:        resultType,
-			Description: resultDesc,
-			Required:    true,
+			Name:         "result",
+			Type:         resultType,
+			Description:  resultDesc,
+			Required:     true,
+			DynamicKeys:  dynamicKeys,
+			ContentTypes: contentTypes,
+			SourceLine:   resultAnnotations[0].Line,
 		}
 		apiFunc.Results = append(apiFunc.Results, result)
 
-		baseType, typeArgs := utils.ParseGenericType(resultType)
-		// Resolve base type to a package and name
-		basePkg, baseName := resolvePackageAndType(baseType, currentPackage, importAliases, structDefinitions)
-
-		if baseName != "" {
-			log.Printf("Resolved type '%s' to package '%s' and type '%s'", baseType, basePkg, baseName)
-		} else {
-			log.Printf("Failed to resolve type '%s'", baseType)
+		// Slices, arrays, maps and pointers of a generic type instantiate their element type
+		prefix, elemType := utils.SplitElementType(resultType)
+		baseType, typeArgs := utils.ParseGenericType(elemType)
+		if ref := utils.ParseType(elemType); ref.Kind != models.TypeNamed || len(ref.TypeArgs) == 0 {
+			baseType, typeArgs = elemType, nil
 		}
+		// Resolve base type to a package and name
+		// Unresolved types are reported by unresolvedAnnotationTypes
+		basePkg, baseName := utils.ResolvePackageAndType(baseType, currentPackage, importAliases, structDefinitions)
 
 		if len(typeArgs) > 0 {
 			// Handle generic instantiation
@@ -370,13 +1047,12 @@ func parseFunction(fn *ast.FuncDecl, currentPackage string, importAliases map[st
 				Package: genBaseTypePkg,
 				Name:    genBaseTypeName,
 			}
-			genericStructDef, exists := structDefinitions[structKey]
-			if !exists {
-				log.Printf("Warning: Generic struct '%s' not found for result 'result'.", genBaseTypeName)
-			} else {
+			if genericStructDef, exists := structDefinitions[structKey]; exists {
 				processedGenArgs := []string{}
+				argRefs := []*models.TypeRef{}
 				for _, arg := range typeArgs {
-					argBasePkg, argBaseName := resolvePackageAndType(arg, currentPackage, importAliases, structDefinitions)
+					argRefs = append(argRefs, utils.ResolveTypeRef(utils.ParseType(arg), currentPackage, importAliases, structDefinitions))
+					argBasePkg, argBaseName := utils.ResolvePackageAndType(arg, currentPackage, importAliases, structDefinitions)
 					if argBaseName == "" {
 						argBaseName = arg
 					}
@@ -396,26 +1072,44 @@ func parseFunction(fn *ast.FuncDecl, currentPackage string, importAliases map[st
 					Name:    concreteTypeName,
 				}
 
+				apiFunc.Results[len(apiFunc.Results)-1].TypeRef = utils.WrapElementType(prefix, &models.TypeRef{
+					Kind:     models.TypeStruct,
+					Name:     genBaseTypeName,
+					Package:  genBaseTypePkg,
+					TypeArgs: argRefs,
+					Struct:   concreteKey,
+				})
+
 				if _, exists := structDefinitions[concreteKey]; !exists {
 					concreteStructDef := models.StructDefinition{
 						Name:        concreteTypeName,
 						Description: genericStructDef.Description,
+						NoTruncate:  genericStructDef.NoTruncate,
+						Methods:     genericStructDef.Methods,
 					}
 
+					concreteField := func(field models.StructField) models.StructField {
+						fieldRef := utils.ResolveTypeRef(utils.ParseType(field.Type), genBaseTypePkg, map[string]string{}, structDefinitions)
+						field.Type = utils.ReplaceTypeParams(field.Type, genericStructDef.TypeParams, processedGenArgs)
+						field.TypeRef = utils.SubstituteTypeParams(fieldRef, genBaseTypePkg, genericStructDef.TypeParams, argRefs)
+						return field
+					}
 					for _, field := range genericStructDef.Fields {
-						concreteField := field
-						concreteField.Type = utils.ReplaceTypeParams(field.Type, genericStructDef.TypeParams, processedGenArgs)
-						concreteStructDef.Fields = append(concreteStructDef.Fields, concreteField)
+						concreteStructDef.Fields = append(concreteStructDef.Fields, concreteField(field))
+					}
+					for _, field := range genericStructDef.HiddenFields {
+						concreteStructDef.HiddenFields = append(concreteStructDef.HiddenFields, concreteField(field))
+					}
+					for _, field := range genericStructDef.ExcludedFields {
+						concreteStructDef.ExcludedFields = append(concreteStructDef.ExcludedFields, concreteField(field))
 					}
 
 					structDefinitions[concreteKey] = concreteStructDef
-					log.Printf("Created concrete struct '%s' for generic type instantiation.", concreteTypeName)
 
 					// Update the result type to the concrete type
-					apiFunc.Results[len(apiFunc.Results)-1].Type = concreteTypeName
+					apiFunc.Results[len(apiFunc.Results)-1].Type = prefix + concreteTypeName
 				} else {
-					log.Printf("Concrete struct '%s' already exists.", concreteTypeName)
-					apiFunc.Results[len(apiFunc.Results)-1].Type = concreteTypeName
+					apiFunc.Results[len(apiFunc.Results)-1].Type = prefix + concreteTypeName
 				}
 			}
 		} else {
@@ -437,8 +1131,44 @@ func parseFunction(fn *ast.FuncDecl, currentPackage string, importAliases map[st
 	return apiFunc, nil
 }
 
+// parseParameter parses a @Parameter line. A description starting with "optional" makes the
+// parameter optional.
+func parseParameter(line annotationLine) (models.APIParameter, error) {
+	parts, rest := splitFields(line.Text, 3)
+	if len(parts) < 3 || rest == "" {
+		return models.APIParameter{}, atLine(line.Line, errors.New("invalid @Parameter annotation. Expected format: @Parameter name type \"description\""))
+	}
+	param := models.APIParameter{
+		Name:       parts[1],
+		Type:       parts[2],
+		Required:   true,
+		SourceLine: line.Line,
+	}
+	if strings.HasPrefix(rest, "default=") {
+		fields, description := splitFields(rest, 1)
+		value := strings.TrimPrefix(fields[0], "default=")
+		if value == "" || description == "" {
+			return models.APIParameter{}, atLine(line.Line, errors.New("invalid @Parameter annotation. Expected format: @Parameter name type default=value \"description\""))
+		}
+		param.Default = parseDefault(value)
+		rest = description
+	}
+	description, err := quotedDescription(rest)
+	if err != nil {
+		return models.APIParameter{}, atLine(line.Line, fmt.Errorf("malformed @Parameter annotation: %v", err))
+	}
+	param.Description = description
+	if strings.HasPrefix(param.Description, "optional") {
+		param.Required = false
+		param.Description = strings.TrimPrefix(param.Description, "optional")
+		param.Description = strings.TrimSpace(param.Description)
+	}
+	return param, nil
+}
+
 func parseGlobalTags(cg *ast.CommentGroup) (models.ProjectInfo, error) {
 	projectInfo := models.ProjectInfo{}
+	inCatalog := false
 	scanner := bufio.NewScanner(strings.NewReader(cg.Text()))
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -448,6 +1178,16 @@ func parseGlobalTags(cg *ast.CommentGroup) (models.ProjectInfo, error) {
 		line = strings.TrimSuffix(line, "*/")
 		line = strings.TrimSpace(line)
 
+		// The entries of an @errorcatalog block run up to an empty line or the next
+		// annotation. Invalid entries are skipped, and reported by checkErrorCatalog.
+		if inCatalog && line != "" && !strings.HasPrefix(line, "@") {
+			if entry, err := parseCatalogEntry(line, projectInfo.ErrorCatalog); err == nil {
+				projectInfo.ErrorCatalog = append(projectInfo.ErrorCatalog, entry)
+			}
+			continue
+		}
+		inCatalog = false
+
 		if !strings.HasPrefix(line, "@") {
 			continue
 		}
@@ -455,41 +1195,43 @@ func parseGlobalTags(cg *ast.CommentGroup) (models.ProjectInfo, error) {
 		if len(parts) == 0 {
 			continue
 		}
-		annotation := strings.ToLower(parts[0])
-		switch annotation {
+		annotation, ok := LookupAnnotation(parts[0], ScopeProject)
+		if !ok {
+			continue
+		}
+		switch annotation.Name {
 		case "@title":
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @title annotation")
 			}
-			projectInfo.Title = strings.Join(parts[1:], " ")
+			projectInfo.Title = normalizeSpace(restOfLine(line, 1))
 		case "@version":
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @version annotation")
 			}
 			projectInfo.Version = strings.Join(parts[1:], " ")
 		case "@description":
-			description := strings.TrimPrefix(line, "@description")
-			projectInfo.Description = strings.TrimSpace(description)
+			projectInfo.Description = normalizeSpace(restOfLine(line, 1))
 		case "@author":
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @author annotation")
 			}
-			projectInfo.Author = strings.Join(parts[1:], " ")
+			projectInfo.Author = normalizeSpace(restOfLine(line, 1))
 		case "@license":
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @license annotation")
 			}
-			projectInfo.License = strings.Join(parts[1:], " ")
+			projectInfo.License = normalizeSpace(restOfLine(line, 1))
 		case "@contact":
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @contact annotation")
 			}
-			projectInfo.Contact = strings.Join(parts[1:], " ")
+			projectInfo.Contact = normalizeSpace(restOfLine(line, 1))
 		case "@terms":
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @terms annotation")
 			}
-			projectInfo.Terms = strings.Join(parts[1:], " ")
+			projectInfo.Terms = normalizeSpace(restOfLine(line, 1))
 		case "@repository":
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @repository annotation")
@@ -505,10 +1247,39 @@ func parseGlobalTags(cg *ast.CommentGroup) (models.ProjectInfo, error) {
 			if len(parts) < 2 {
 				return projectInfo, errors.New("missing value in @copyright annotation")
 			}
-			projectInfo.Copyright = strings.Join(parts[1:], " ")
+			projectInfo.Copyright = normalizeSpace(restOfLine(line, 1))
+		case "@server":
+			if len(parts) < 2 {
+				return projectInfo, errors.New("missing value in @server annotation")
+			}
+			projectInfo.Servers = append(projectInfo.Servers, parts[1])
+		case "@envelope":
+			if len(parts) < 2 {
+				return projectInfo, errors.New("missing value in @envelope annotation")
+			}
+			for _, pair := range parts[1:] {
+				name, typ, found := strings.Cut(pair, "=")
+				if !found || name == "" || typ == "" {
+					return projectInfo, fmt.Errorf("invalid @envelope member '%s'. Expected name=type", pair)
+				}
+				projectInfo.ResultEnvelope.Members = append(projectInfo.ResultEnvelope.Members, models.EnvelopeMember{Name: name, Type: typ})
+			}
+		case "@errorcatalog":
+			inCatalog = true
+		case "@globalerror":
+			// Invalid entries are skipped, and reported by checkGlobalErrors
+			if apiError, err := parseGlobalError(line, projectInfo.GlobalErrors); err == nil {
+				projectInfo.GlobalErrors = append(projectInfo.GlobalErrors, apiError)
+			}
+		case "@maxrequestsize":
+			projectInfo.Sizes.MaxRequest = sizeValue(line)
+		case "@typicalresponsesize":
+			projectInfo.Sizes.TypicalResponse = sizeValue(line)
 		}
 	}
 
+	nameGlobalErrors(projectInfo.GlobalErrors, projectInfo.ErrorCatalog)
+
 	if projectInfo.Title == "" {
 		return projectInfo, errors.New("missing @title annotation")
 	}
@@ -522,26 +1293,6 @@ func parseGlobalTags(cg *ast.CommentGroup) (models.ProjectInfo, error) {
 	return projectInfo, nil
 }
 
-func extractImportAliases(fileAst *ast.File) map[string]string {
-	importAliases := make(map[string]string)
-	for _, imp := range fileAst.Imports {
-		var alias string
-		var pkgName string
-		if imp.Name != nil {
-			alias = imp.Name.Name
-		} else {
-			path := strings.Trim(imp.Path.Value, `"`)
-			parts := strings.Split(path, "/")
-			alias = parts[len(parts)-1]
-		}
-		path := strings.Trim(imp.Path.Value, `"`)
-		parts := strings.Split(path, "/")
-		pkgName = parts[len(parts)-1]
-		importAliases[alias] = pkgName
-	}
-	return importAliases
-}
-
 func extractStructDescription(cg *ast.CommentGroup) string {
 	if cg == nil {
 		return ""
@@ -549,14 +1300,14 @@ func extractStructDescription(cg *ast.CommentGroup) string {
 	var desc []string
 	scanner := bufio.NewScanner(strings.NewReader(cg.Text()))
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		line := strings.TrimSpace(stripPragma(scanner.Text()))
 		line = strings.TrimPrefix(line, "//")
 		line = strings.TrimSpace(line)
-		if line != "" {
+		if line != "" && !isMarker(line) {
 			desc = append(desc, line)
 		}
 	}
-	return strings.Join(desc, " ")
+	return normalizeSpace(strings.Join(desc, " "))
 }
 
 func extractFieldDescription(doc *ast.CommentGroup, comment *ast.CommentGroup) string {
@@ -564,10 +1315,10 @@ func extractFieldDescription(doc *ast.CommentGroup, comment *ast.CommentGroup) s
 
 	if doc != nil {
 		for _, c := range doc.List {
-			line := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+			line := strings.TrimSpace(strings.TrimPrefix(stripPragma(c.Text), "//"))
 			line = strings.TrimSpace(strings.TrimPrefix(line, "/*"))
 			line = strings.TrimSpace(strings.TrimSuffix(line, "*/"))
-			if line != "" {
+			if line != "" && !isMarker(line) {
 				comments = append(comments, line)
 			}
 		}
@@ -575,48 +1326,98 @@ func extractFieldDescription(doc *ast.CommentGroup, comment *ast.CommentGroup) s
 
 	if comment != nil {
 		for _, c := range comment.List {
-			line := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+			line := strings.TrimSpace(strings.TrimPrefix(stripPragma(c.Text), "//"))
 			line = strings.TrimSpace(strings.TrimPrefix(line, "/*"))
 			line = strings.TrimSpace(strings.TrimSuffix(line, "*/"))
-			if line != "" {
+			if line != "" && !isMarker(line) {
 				comments = append(comments, line)
 			}
 		}
 	}
 
-	return strings.Join(comments, " ")
+	return normalizeSpace(strings.Join(comments, " "))
 }
 
-// resolvePackageAndType returns a package and name for any type.
-// If it's fully qualified (package.struct), it splits it.
-// If not, it tries to find it in the current package or import aliases.
-// For generics, we do not attempt to resolve package per argument here; it's done later.
-func resolvePackageAndType(typ string, currentPackage string, importAliases map[string]string, structDefinitions map[models.StructKey]models.StructDefinition) (pkg string, typeName string) {
-	if strings.Contains(typ, ".") {
-		// Possibly fully qualified or alias
-		p, n := utils.SplitQualifiedName(typ)
-		if p != "" && n != "" {
-			// Check if p is an alias
-			if actualPkg, exists := importAliases[p]; exists {
-				return actualPkg, n
-			}
-			// p is actually the package name
-			return p, n
+// isMarker reports whether a comment line is a struct or field annotation, which is not part of a description.
+func isMarker(line string) bool {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return false
+	}
+	_, isStruct := LookupAnnotation(parts[0], ScopeStruct)
+	_, isField := LookupAnnotation(parts[0], ScopeField)
+	return isStruct || isField
+}
+
+// hasMarker reports whether the comment group contains the given marker annotation on its own line.
+func hasMarker(cg *ast.CommentGroup, marker string) bool {
+	if cg == nil {
+		return false
+	}
+	scanner := bufio.NewScanner(strings.NewReader(cg.Text()))
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) > 0 && parts[0] == marker {
+			return true
 		}
-		// If we can't split properly, just return empty
-		return "", typ
 	}
+	return false
+}
 
-	// Unqualified name: assume current package if it exists
-	key := models.StructKey{
-		Package: currentPackage,
-		Name:    typ,
+// markerText returns the text following the given marker annotation in the comment group.
+func markerText(cg *ast.CommentGroup, marker string) (string, bool) {
+	if cg == nil {
+		return "", false
 	}
-	if _, exists := structDefinitions[key]; exists {
-		return currentPackage, typ
+	scanner := bufio.NewScanner(strings.NewReader(cg.Text()))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripPragma(scanner.Text()))
+		if parts := strings.Fields(line); len(parts) > 0 && parts[0] == marker {
+			return strings.TrimSpace(strings.TrimPrefix(line, marker)), true
+		}
 	}
+	return "", false
+}
+
+// splitKeys splits the key descriptions of @DynamicKeys and @keys, one per nesting level
+// separated by commas, removing surrounding quotes.
+func splitKeys(text string) []string {
+	var keys []string
+	for _, key := range strings.Split(strings.Trim(text, "\""), ",") {
+		if key = normalizeSpace(strings.Trim(strings.TrimSpace(key), "\"")); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// markerValue returns the first argument of the given marker annotation in the comment group.
+func markerValue(cg *ast.CommentGroup, marker string) (string, bool) {
+	if cg == nil {
+		return "", false
+	}
+	scanner := bufio.NewScanner(strings.NewReader(cg.Text()))
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) > 0 && parts[0] == marker {
+			if len(parts) < 2 {
+				return "", true
+			}
+			return parts[1], true
+		}
+	}
+	return "", false
+}
 
-	// Not found
-	log.Printf("Type '%s' not found in package '%s'. Ensure it is imported or fully qualified.", typ, currentPackage)
-	return "", ""
+// hasJSONTag reports whether a struct field carries an explicit json key in its tag.
+func hasJSONTag(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return false
+	}
+	_, ok := reflect.StructTag(tag).Lookup("json")
+	return ok
 }
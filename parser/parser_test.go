@@ -2,8 +2,16 @@
 package parser
 
 import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/pablolagos/jdocgen/models"
 	"github.com/pablolagos/jdocgen/utils"
 )
 
@@ -32,3 +40,482 @@ func TestParseGenericType(t *testing.T) {
 		t.Errorf("Expected typeArgs [], got %v", typeArgs)
 	}
 }
+
+func TestParseGenericTypeEdgeCases(t *testing.T) {
+	tests := []struct {
+		typ      string
+		baseType string
+		typeArgs []string
+	}{
+		{"ReportItem", "ReportItem", nil},
+		{"[]ReportItem", "[]ReportItem", nil},
+		{"Page[]", "Page[]", nil},
+		{"Page[ ]", "Page", []string{""}},
+		{"Page[a, ]", "Page", []string{"a", ""}},
+		{"Page[a,]", "Page", []string{"a"}},
+		{" Page [ reports.Item ]", "Page", []string{"reports.Item"}},
+		{"Pair[Details, Pair[Info, map[string][]int]]", "Pair", []string{"Details", "Pair[Info, map[string][]int]"}},
+		{"map[string]Item", "map", []string{"string"}},
+	}
+	// Twice, so the second round is answered by the cache
+	for round := 0; round < 2; round++ {
+		for _, tt := range tests {
+			baseType, typeArgs := utils.ParseGenericType(tt.typ)
+			if baseType != tt.baseType || fmt.Sprintf("%q", typeArgs) != fmt.Sprintf("%q", tt.typeArgs) {
+				t.Errorf("round %d: ParseGenericType(%q) = %q, %q, want %q, %q", round, tt.typ, baseType, typeArgs, tt.baseType, tt.typeArgs)
+			}
+		}
+	}
+
+	// Appending to the returned arguments must not change what later calls return
+	_, typeArgs := utils.ParseGenericType("Map[string, int]")
+	_ = append(typeArgs, "bool")
+	if _, again := utils.ParseGenericType("Map[string, int]"); len(again) != 2 || again[1] != "int" {
+		t.Errorf("cached arguments changed to %q", again)
+	}
+}
+
+func TestParseProjectReportsBrokenFiles(t *testing.T) {
+	result, err := ParseProject("testdata/broken")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	if len(result.Functions) != 1 || result.Functions[0].Command != "user.Get" {
+		t.Errorf("Expected only 'user.Get' to be documented, got %v", result.Functions)
+	}
+	if result.Stats.FilesParsed != 1 || result.Stats.FilesSkipped != 1 {
+		t.Errorf("Expected 1 parsed and 1 skipped file, got %+v", result.Stats)
+	}
+
+	var failure *Diagnostic
+	var lostCommands []string
+	for i, diag := range result.Diagnostics {
+		if diag.Severity != SeverityWarning || filepath.Base(diag.File) != "broken.go" {
+			continue
+		}
+		if strings.Contains(diag.Message, "failed to parse:") {
+			failure = &result.Diagnostics[i]
+		} else {
+			lostCommands = append(lostCommands, fmt.Sprintf("%d:%s", diag.Line, diag.Message))
+		}
+	}
+
+	if failure == nil {
+		t.Fatalf("Expected a parse failure warning for broken.go, got %v", result.Diagnostics)
+	}
+	if failure.Line != 6 {
+		t.Errorf("Expected parse failure at line 6, got %d", failure.Line)
+	}
+	want := []string{
+		"4:command 'user.Delete' is not documented because its file failed to parse",
+		"10:command 'user.List' is not documented because its file failed to parse",
+	}
+	if strings.Join(lostCommands, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected lost command warnings:\n%s", strings.Join(lostCommands, "\n"))
+	}
+}
+
+func TestParseProjectOnlyTaggedAndHidden(t *testing.T) {
+	result, err := ParseProject("testdata/tagged")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	fieldNames := func(name string) []string {
+		def, ok := result.Structs[models.StructKey{Package: "rpc", Name: name}]
+		if !ok {
+			t.Fatalf("Struct %s not collected", name)
+		}
+		var names []string
+		for _, field := range def.Fields {
+			names = append(names, field.Name)
+		}
+		return names
+	}
+
+	// @OnlyTagged keeps exported fields with a json tag, @Hidden wins over the tag
+	if got := strings.Join(fieldNames("Account"), ","); got != "ID,Name" {
+		t.Errorf("Expected Account fields 'ID,Name', got '%s'", got)
+	}
+	// Without @OnlyTagged, untagged and unexported fields are still documented
+	if got := strings.Join(fieldNames("Profile"), ","); got != "Email,note" {
+		t.Errorf("Expected Profile fields 'Email,note', got '%s'", got)
+	}
+
+	account := result.Structs[models.StructKey{Package: "rpc", Name: "Account"}]
+	if !account.OnlyTagged {
+		t.Error("Expected Account.OnlyTagged to be set")
+	}
+	if account.Description != "Account mixes wire fields with internal bookkeeping." {
+		t.Errorf("Marker leaked into description: '%s'", account.Description)
+	}
+	if account.NoTruncate {
+		t.Error("Expected Account.NoTruncate to be unset")
+	}
+
+	profile := result.Structs[models.StructKey{Package: "rpc", Name: "Profile"}]
+	if !profile.NoTruncate {
+		t.Error("Expected Profile.NoTruncate to be set")
+	}
+	if profile.Description != "Profile is a regular struct." {
+		t.Errorf("Marker leaked into description: '%s'", profile.Description)
+	}
+
+	var infos []string
+	for _, diag := range result.Diagnostics {
+		if diag.Severity == SeverityInfo {
+			infos = append(infos, diag.Message)
+		}
+	}
+	want := []string{
+		"struct 'rpc.Account': 1 fields hidden by @Hidden (Secret)",
+		"struct 'rpc.Account': 2 fields omitted by @OnlyTagged (Revision, cache)",
+		"struct 'rpc.Profile': 1 fields hidden by @Hidden (Password)",
+	}
+	if strings.Join(infos, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected info diagnostics:\n%s", strings.Join(infos, "\n"))
+	}
+}
+
+func TestNormalizeID(t *testing.T) {
+	tests := map[string]string{
+		"user.GetProfile":              "user-getprofile",
+		"rpc.Pagination[reports.Item]": "rpc-pagination-reports-item",
+		"  Admin/User.Reset!  ":        "admin-user-reset",
+		"stats.GetAllMetrics_v2":       "stats-getallmetrics-v2",
+	}
+	for name, want := range tests {
+		if got := utils.NormalizeID(name); got != want {
+			t.Errorf("NormalizeID(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+		kind models.TypeKind
+		held string
+	}{
+		{"int", "int", models.TypeBasic, "int"},
+		{"ReportItem", "ReportItem", models.TypeNamed, "ReportItem"},
+		{"*ReportItem", "*ReportItem", models.TypePointer, "ReportItem"},
+		{"[]ReportItem", "[]ReportItem", models.TypeSlice, "ReportItem"},
+		{"[4]*reports.ReportItem", "[]*reports.ReportItem", models.TypeSlice, "reports.ReportItem"},
+		{"...string", "[]string", models.TypeSlice, "string"},
+		{"map[string][]*reports.ReportItem", "map[string][]*reports.ReportItem", models.TypeMap, "reports.ReportItem"},
+		{"map[[2]int]Pagination[ReportItem]", "map[[]int]Pagination[ReportItem]", models.TypeMap, "Pagination[ReportItem]"},
+		{"Map[string, []int]", "Map[string, []int]", models.TypeNamed, "Map[string, []int]"},
+		{"cm.Page[map[string]User]", "cm.Page[map[string]User]", models.TypeNamed, "cm.Page[map[string]User]"},
+		{"interface{}", "interface{}", models.TypeAny, "interface{}"},
+		{"any", "any", models.TypeAny, "any"},
+		{"", "", models.TypeAny, ""},
+		{"map[string", "map[string", models.TypeAny, "map[string"},
+		{"Page[User", "Page[User", models.TypeAny, "Page[User"},
+	}
+	for _, tt := range tests {
+		ref := utils.ParseType(tt.typ)
+		if got := ref.String(); got != tt.want {
+			t.Errorf("ParseType(%q).String() = %q, want %q", tt.typ, got, tt.want)
+		}
+		if ref.Kind != tt.kind {
+			t.Errorf("ParseType(%q).Kind = %q, want %q", tt.typ, ref.Kind, tt.kind)
+		}
+		if got := ref.Held().String(); got != tt.held {
+			t.Errorf("ParseType(%q).Held() = %q, want %q", tt.typ, got, tt.held)
+		}
+	}
+
+	ref := utils.ParseType("cm.Page[map[string]User, int]")
+	if ref.Package != "cm" || ref.Name != "Page" || len(ref.TypeArgs) != 2 {
+		t.Fatalf("ParseType returned %+v, want Page of package cm with 2 type arguments", ref)
+	}
+	if ref.TypeArgs[0].Kind != models.TypeMap || ref.TypeArgs[0].Key.Name != "string" || ref.TypeArgs[0].Elem.Name != "User" {
+		t.Errorf("first type argument = %s, want map[string]User", ref.TypeArgs[0])
+	}
+}
+
+func TestResolveTypeRef(t *testing.T) {
+	structs := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "User"}:                 {Name: "User"},
+		{Package: "common", Name: "Cursor"}:            {Name: "Cursor"},
+		{Package: "common", Name: "Page"}:              {Name: "Page"},
+		{Package: "common", Name: "Page[User]"}:        {Name: "Page[User]"},
+		{Package: "common", Name: "Page[rpc.Admin]"}:   {Name: "Page[rpc.Admin]"},
+		{Package: "reports", Name: "Item"}:             {Name: "Item"},
+		{Package: "reports", Name: "Pagination[Item]"}: {Name: "Pagination[Item]"},
+	}
+	aliases := map[string]string{"cm": "common"}
+
+	tests := []struct {
+		typ  string
+		pkg  string
+		want string
+		held models.StructKey
+	}{
+		{"User", "rpc", "rpc.User", models.StructKey{Package: "rpc", Name: "User"}},
+		{"*User", "rpc", "*rpc.User", models.StructKey{Package: "rpc", Name: "User"}},
+		{"map[int][]*User", "rpc", "map[int][]*rpc.User", models.StructKey{Package: "rpc", Name: "User"}},
+		{"*cm.Cursor", "rpc", "*common.Cursor", models.StructKey{Package: "common", Name: "Cursor"}},
+		{"cm.Page[User]", "rpc", "common.Page[rpc.User]", models.StructKey{Package: "common", Name: "Page[User]"}},
+		{"[]cm.Page[Admin]", "rpc", "[]common.Page[rpc.Admin]", models.StructKey{}},
+		{"Pagination[Item]", "rpc", "reports.Pagination[rpc.Item]", models.StructKey{Package: "reports", Name: "Pagination[Item]"}},
+		{"Item", "rpc", "rpc.Item", models.StructKey{}},
+		{"reports.Item", "rpc", "reports.Item", models.StructKey{Package: "reports", Name: "Item"}},
+		{"[]string", "rpc", "[]string", models.StructKey{}},
+	}
+	for _, tt := range tests {
+		ref := utils.ResolveTypeRef(utils.ParseType(tt.typ), tt.pkg, aliases, structs)
+		if got := ref.String(); got != tt.want {
+			t.Errorf("ResolveTypeRef(%q).String() = %q, want %q", tt.typ, got, tt.want)
+		}
+		var held models.StructKey
+		if h := ref.Held(); h.Kind == models.TypeStruct {
+			held = h.Struct
+		}
+		if held != tt.held {
+			t.Errorf("ResolveTypeRef(%q) holds struct %v, want %v", tt.typ, held, tt.held)
+		}
+	}
+}
+
+func TestParseProjectTypeRefs(t *testing.T) {
+	result, err := ParseProject("testdata/types")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	types := map[string]string{}
+	for _, fn := range result.Functions {
+		for _, param := range fn.Parameters {
+			types[fn.Command+" "+param.Name] = param.TypeRef.String()
+		}
+		for _, res := range fn.Results {
+			types[fn.Command+" result"] = res.TypeRef.String()
+			if held := res.TypeRef.Held(); held.Kind != models.TypeStruct {
+				t.Errorf("%s: result %s does not hold a struct", fn.Command, res.Type)
+			}
+		}
+	}
+	want := map[string]string{
+		"users.List ids":      "[]int",
+		"users.List labels":   "map[string]string",
+		"users.List after":    "*common.Cursor",
+		"users.List result":   "common.Page[rpc.User]",
+		"users.Lookup ids":    "[]int",
+		"users.Lookup result": "map[int]*rpc.User",
+	}
+	for name, typ := range want {
+		if types[name] != typ {
+			t.Errorf("%s has type %q, want %q", name, types[name], typ)
+		}
+	}
+
+	// The fields of the concrete struct refer to the type argument, resolved where it was written
+	page, exists := result.Structs[models.StructKey{Package: "common", Name: "Page[User]"}]
+	if !exists {
+		t.Fatalf("concrete struct common.Page[User] not created, structs: %v", result.Structs)
+	}
+	fields := map[string]string{}
+	for _, field := range page.Fields {
+		fields[field.Name] = field.TypeRef.String()
+		if field.TypeRef.Held().Kind != models.TypeStruct {
+			t.Errorf("field %s of %s does not hold a struct", field.Name, page.Name)
+		}
+	}
+	wantFields := map[string]string{
+		"Items": "[]rpc.User",
+		"Next":  "*common.Cursor",
+		"Index": "map[string]rpc.User",
+	}
+	for name, typ := range wantFields {
+		if fields[name] != typ {
+			t.Errorf("field %s has type %q, want %q", name, fields[name], typ)
+		}
+	}
+}
+
+func TestParseProjectIDs(t *testing.T) {
+	result, err := ParseProject("testdata/ids")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	ids := make(map[string]string)
+	for _, apiFunc := range result.Functions {
+		ids[apiFunc.Command] = apiFunc.ID
+	}
+	if ids["user.Get"] != "usr-get-01" {
+		t.Errorf("Expected explicit ID 'usr-get-01', got '%s'", ids["user.Get"])
+	}
+	if ids["user.List"] != "user-list" {
+		t.Errorf("Expected derived ID 'user-list', got '%s'", ids["user.List"])
+	}
+	if team := result.Structs[models.StructKey{Package: "rpc", Name: "Team"}]; team.ID != "rpc-team" {
+		t.Errorf("Expected derived struct ID 'rpc-team', got '%s'", team.ID)
+	}
+	if user := result.Structs[models.StructKey{Package: "rpc", Name: "User"}]; user.Description != "User is a user account." {
+		t.Errorf("@ID leaked into description: '%s'", user.Description)
+	}
+
+	var errs []string
+	for _, diag := range result.Diagnostics {
+		if diag.Severity == SeverityError {
+			errs = append(errs, fmt.Sprintf("%s:%d: %s", filepath.Base(diag.File), diag.Line, strings.ReplaceAll(diag.Message, filepath.Dir(diag.File)+string(filepath.Separator), "")))
+		}
+	}
+	want := []string{
+		"api.go:37: command 'user.Renamed' has ID 'user-list', already used by command 'user.List' at api.go:31",
+		"api.go:9: struct 'rpc.User' has ID 'usr', already used by struct 'rpc.Group' at api.go:15",
+	}
+	if strings.Join(errs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected ID collision errors:\n%s", strings.Join(errs, "\n"))
+	}
+}
+
+func TestParseProjectFormerNames(t *testing.T) {
+	result, err := ParseProject("testdata/renames")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	for _, apiFunc := range result.Functions {
+		if apiFunc.Command == "account.Get" && strings.Join(apiFunc.FormerNames, ",") != "account.GetUser,user.Get" {
+			t.Errorf("Expected former names 'account.GetUser,user.Get', got %v", apiFunc.FormerNames)
+		}
+	}
+
+	file := filepath.Join("testdata", "renames", "api.go")
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Severity == SeverityError {
+			got = append(got, diag.String())
+		}
+	}
+	want := []string{
+		fmt.Sprintf("%s:11: error: command 'account.Get' has former name 'user.Get', which is a live command at %s:15", file, file),
+		fmt.Sprintf("%s:22: error: command 'account.Fetch' has former name 'account.GetUser', already claimed by command 'account.Get' at %s:10", file, file),
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestParseProjectMethodDocs(t *testing.T) {
+	result, err := ParseProject("testdata/methods")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	money := result.Structs[models.StructKey{Package: "rpc", Name: "Money"}]
+	want := []models.MethodDoc{
+		{Name: "Validate", Doc: "Validate checks that the amount is not negative and that the currency is an ISO 4217 code."},
+		{Name: "Cents", Doc: "Cents returns the amount in cents."},
+	}
+	if !reflect.DeepEqual(money.Methods, want) {
+		t.Errorf("Expected methods %+v, got %+v", want, money.Methods)
+	}
+	if money.Description != "Money is an amount in a currency." {
+		t.Errorf("Expected the annotation to be left out of the description, got %q", money.Description)
+	}
+
+	if invoice := result.Structs[models.StructKey{Package: "rpc", Name: "Invoice"}]; len(invoice.Methods) > 0 {
+		t.Errorf("Expected no methods without @IncludeMethodDocs, got %+v", invoice.Methods)
+	}
+}
+
+func TestParseProjectContentTypes(t *testing.T) {
+	result, err := ParseProject("testdata/contenttype")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Expected no diagnostics, got %v", result.Diagnostics)
+	}
+
+	contentTypes := map[string][]models.ContentType{}
+	for _, fn := range result.Functions {
+		contentTypes[fn.Command] = fn.Results[0].ContentTypes
+	}
+	want := map[string][]models.ContentType{
+		"invoice.Pdf":    {{MediaType: "application/pdf", Encoding: "base64"}},
+		"invoice.Render": {{MediaType: "application/pdf", Encoding: "base64"}, {MediaType: "text/html"}},
+		"invoice.Total":  nil,
+	}
+	if !reflect.DeepEqual(contentTypes, want) {
+		t.Errorf("Expected content types %v, got %v", want, contentTypes)
+	}
+}
+
+func TestParseProjectDoesNotLog(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	entries, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		// Fixtures failing to parse are expected, only the log matters here
+		ParseProject(filepath.Join("testdata", entry.Name()))
+	}
+	if logged.Len() > 0 {
+		t.Errorf("Expected the parser not to log, got:\n%s", logged.String())
+	}
+}
+
+func TestParseProjectResolvesStructsOfLaterFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Commands come before the file declaring their structs in path order
+		"a.go": `// @title Order API
+// @version 1.0.0
+// @description Structs declared after their commands.
+package rpc
+
+// Get returns a report.
+// @Command report.Get
+// @Description Get a report.
+// @Result Page[Row] "The rows"
+func Get() error {
+	rows := []Row{}
+	_ = rows
+	return nil
+}
+`,
+		"z.go": `package rpc
+
+// Page is a page of items.
+type Page[T any] struct {
+	Items []T ` + "`json:\"items\"`" + ` // Items of the page.
+}
+
+// Row is a report row.
+type Row struct {
+	Value int ` + "`json:\"value\"`" + ` // Value of the row.
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := ParseProject(dir)
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Functions) != 1 || result.Functions[0].Results[0].Type != "Page[Row]" {
+		t.Fatalf("Expected report.Get to return Page[Row], got %+v", result.Functions)
+	}
+	if _, exists := result.Structs[models.StructKey{Package: "rpc", Name: "Page[Row]"}]; !exists {
+		t.Error("Expected the instantiation Page[Row] of a struct of a later file")
+	}
+	if result.Stats.FilesParsed != 2 {
+		t.Errorf("Expected 2 parsed files, got %d", result.Stats.FilesParsed)
+	}
+}
@@ -0,0 +1,92 @@
+// parser/reproducible_test.go
+package parser
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/generator"
+)
+
+// renderFixture parses dir and returns its diagnostics and every file generated from it, in
+// single-file, split and JSON form, by name. Duplicate commands are allowed, so the fixture
+// checks that the same declaration is kept whatever the file order.
+func renderFixture(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	result, err := ParseProjects([]string{dir}, Options{AllowDuplicateCommands: true})
+	if err != nil {
+		t.Fatalf("ParseProjects returned error: %v", err)
+	}
+	var diagnostics []string
+	for _, d := range result.Diagnostics {
+		diagnostics = append(diagnostics, d.String())
+	}
+	files := map[string]string{"diagnostics": strings.Join(diagnostics, "\n")}
+
+	out := t.TempDir()
+	opts := generator.Options{MaxFields: 1, StructSource: true}
+	if err := generator.GenerateDocumentation(result.Functions, result.Structs, result.ProjectInfo, filepath.Join(out, "API.md"), opts); err != nil {
+		t.Fatalf("GenerateDocumentation returned error: %v", err)
+	}
+	if err := generator.GenerateJSON(result.Functions, result.Structs, result.ProjectInfo, filepath.Join(out, "api.json"), opts); err != nil {
+		t.Fatalf("GenerateJSON returned error: %v", err)
+	}
+	opts.AppendixSplit = generator.AppendixSplitPackage
+	if err := generator.GenerateSplitDocumentation(result.Functions, result.Structs, result.ProjectInfo, filepath.Join(out, "split"), opts); err != nil {
+		t.Fatalf("GenerateSplitDocumentation returned error: %v", err)
+	}
+
+	err = filepath.Walk(out, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		files[strings.TrimPrefix(path, out)] = string(content)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestParseProjectReproducible(t *testing.T) {
+	defer func(original func(string, []string) ([]string, error)) { listSourceFiles = original }(listSourceFiles)
+
+	environments := []struct {
+		tz, lang string
+	}{
+		{"UTC", "C"},
+		{"Asia/Tokyo", "ja_JP.UTF-8"},
+		{"America/St_Johns", "tr_TR.UTF-8"},
+	}
+	var want map[string]string
+	for seed, env := range environments {
+		t.Setenv("TZ", env.tz)
+		t.Setenv("LANG", env.lang)
+		listSourceFiles = func(rootDir string, exclude []string) ([]string, error) {
+			files, err := sourceFiles(rootDir, exclude)
+			rand.New(rand.NewSource(int64(seed))).Shuffle(len(files), func(i, j int) {
+				files[i], files[j] = files[j], files[i]
+			})
+			return files, err
+		}
+
+		got := renderFixture(t, filepath.Join("testdata", "reproducible"))
+		if want == nil {
+			want = got
+			continue
+		}
+		if len(got) != len(want) {
+			t.Errorf("TZ=%s LANG=%s: expected %d files, got %d", env.tz, env.lang, len(want), len(got))
+		}
+		for name, content := range want {
+			if got[name] != content {
+				t.Errorf("TZ=%s LANG=%s: %s differs:\n%s\nexpected:\n%s", env.tz, env.lang, name, got[name], content)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// parser/required_test.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectFieldPresence(t *testing.T) {
+	result, err := ParseProject("testdata/required")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	result.ShowExcludedFields()
+
+	account := result.Structs[models.StructKey{Package: "rpc", Name: "Account"}]
+	type presence struct {
+		Name      string
+		Omitempty bool
+		Pointer   bool
+		Embedded  bool
+		Excluded  bool
+	}
+	var got []presence
+	for _, field := range account.Fields {
+		got = append(got, presence{field.Name, field.Omitempty, field.TypeRef.Kind == models.TypePointer, field.Embedded, field.Excluded})
+	}
+	want := []presence{
+		{Name: "Audit", Embedded: true},
+		{Name: "ID"},
+		{Name: "Nickname", Omitempty: true},
+		{Name: "Manager", Pointer: true},
+		{Name: "Parent", Omitempty: true, Pointer: true},
+		{Name: "Password", Excluded: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected fields\n%+v\ngot\n%+v", want, got)
+	}
+}
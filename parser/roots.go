@@ -0,0 +1,28 @@
+// parser/roots.go
+package parser
+
+import (
+	"reflect"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// sameStructDefinition reports whether two declarations of a struct in different roots
+// document the same struct, whatever their location.
+func sameStructDefinition(a, b models.StructDefinition) bool {
+	return reflect.DeepEqual(withoutLocation(a), withoutLocation(b))
+}
+
+// withoutLocation returns a copy of a struct definition without its source locations.
+func withoutLocation(structDef models.StructDefinition) models.StructDefinition {
+	structDef.SourceFile, structDef.SourceLine = "", 0
+	for _, fields := range []*[]models.StructField{&structDef.Fields, &structDef.HiddenFields, &structDef.ExcludedFields} {
+		located := *fields
+		*fields = make([]models.StructField, len(located))
+		for i, field := range located {
+			field.SourceLine = 0
+			(*fields)[i] = field
+		}
+	}
+	return structDef
+}
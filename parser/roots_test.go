@@ -0,0 +1,59 @@
+// parser/roots_test.go
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectsSeveralRoots(t *testing.T) {
+	root := func(name string) string { return "testdata/roots/" + name }
+
+	result, err := ParseProjects([]string{root("rpc"), root("types"), root("copy")}, Options{})
+	if err != nil {
+		t.Fatalf("ParseProjects returned error: %v", err)
+	}
+	if len(result.Functions) != 1 || result.ProjectInfo.Title != "Roots API" {
+		t.Fatalf("Expected users.Get of Roots API, got %d commands of %q", len(result.Functions), result.ProjectInfo.Title)
+	}
+	// The result uses the struct of the other root, and the identical copy is accepted
+	userKey := models.StructKey{Package: "types", Name: "User"}
+	if ref := result.Functions[0].Results[0].TypeRef; ref == nil || ref.Struct != userKey {
+		t.Errorf("Expected the result to resolve to types.User, got %+v", ref)
+	}
+	if user := result.Structs[userKey]; user.SourceFile != root("types")+"/user.go" {
+		t.Errorf("Expected types.User of the first root declaring it, got %s", user.SourceFile)
+	}
+	for _, diag := range result.Diagnostics {
+		if diag.Severity != SeverityInfo {
+			t.Errorf("Unexpected diagnostic: %v", diag)
+		}
+	}
+
+	// A struct declared differently in two roots is an error
+	_, err = ParseProjects([]string{root("rpc"), root("types"), root("conflict")}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "struct 'types.User' is declared differently in "+root("types")+"/user.go:4 and "+root("conflict")+"/user.go:4") {
+		t.Errorf("Expected a conflicting struct error, got %v", err)
+	}
+
+	// The first root declaring the project wins, other titles are reported
+	result, err = ParseProjects([]string{root("rpc"), root("types"), root("other")}, Options{})
+	if err != nil {
+		t.Fatalf("ParseProjects returned error: %v", err)
+	}
+	if result.ProjectInfo.Title != "Roots API" {
+		t.Errorf("Expected the title of the first root, got %q", result.ProjectInfo.Title)
+	}
+	var warnings []string
+	for _, diag := range result.Diagnostics {
+		if diag.Class == ClassProjectInfo {
+			warnings = append(warnings, diag.Message)
+		}
+	}
+	want := "root '" + root("other") + "' declares the project title 'Other API', using 'Roots API' of root '" + root("rpc") + "'"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("Expected warning %q, got %q", want, warnings)
+	}
+}
@@ -0,0 +1,81 @@
+// parser/sizes.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are the suffixes accepted by parseSize, longest first so "KB" is not read as "B".
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"KB", 1 << 10},
+	{"MB", 1 << 20},
+	{"B", 1},
+}
+
+// parseSize returns the number of bytes of a size such as "512", "512B", "64KB", "1.5MB" or
+// "1 MB". Units are case-insensitive and binary, so "1KB" is 1024 bytes.
+func parseSize(value string) (int64, error) {
+	number, unit := strings.ToUpper(strings.ReplaceAll(strings.Trim(value, "\""), " ", "")), int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(number, u.suffix) {
+			number, unit = strings.TrimSuffix(number, u.suffix), u.bytes
+			break
+		}
+	}
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil || size <= 0 || size*float64(unit) > 1<<53 {
+		return 0, fmt.Errorf("invalid size '%s'. Expected a positive number of bytes with an optional B, KB or MB suffix, such as 1MB", value)
+	}
+	return int64(size * float64(unit)), nil
+}
+
+// sizeValue returns the bytes of a size annotation line, or 0 when it has no valid size.
+// Invalid sizes are reported by checkSizes.
+func sizeValue(line string) int64 {
+	size, _ := parseSize(restOfLine(line, 1))
+	return size
+}
+
+// checkSizes reports the size annotations of a comment group whose value is not a size, in
+// their function or project form. Those annotations are ignored.
+func checkSizes(cg *ast.CommentGroup, fset *token.FileSet) Diagnostics {
+	lines, _ := functionAnnotations(cg, fset)
+	var diagnostics Diagnostics
+	for _, annotationLine := range lines {
+		parts := strings.Fields(annotationLine.Text)
+		if len(parts) == 0 || !isSizeAnnotation(parts[0]) {
+			continue
+		}
+		if _, err := parseSize(restOfLine(annotationLine.Text, 1)); err != nil {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				File:     fset.Position(cg.Pos()).Filename,
+				Line:     annotationLine.Line,
+				Class:    ClassInvalidSize,
+				Message:  fmt.Sprintf("%s annotation ignored: %v", parts[0], err),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// isSizeAnnotation reports whether name is one of the size annotations, in function or
+// project scope.
+func isSizeAnnotation(name string) bool {
+	for _, scope := range []Scope{ScopeFunction, ScopeProject} {
+		if annotation, ok := LookupAnnotation(name, scope); ok {
+			switch annotation.Name {
+			case "@MaxRequestSize", "@TypicalResponseSize", "@maxrequestsize", "@typicalresponsesize":
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// parser/sizes_test.go
+package parser
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"512", 512},
+		{"512B", 512},
+		{"64KB", 64 << 10},
+		{"64kb", 64 << 10},
+		{"1MB", 1 << 20},
+		{"1.5MB", 3 << 19},
+		{"1 MB", 1 << 20},
+		{`"5MB"`, 5 << 20},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v, want %d", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "huge", "MB", "-1KB", "0", "1GB", "1e30MB"} {
+		if got, err := parseSize(value); err == nil {
+			t.Errorf("parseSize(%q) = %d, want an error", value, got)
+		}
+	}
+}
+
+func TestParseProjectSizes(t *testing.T) {
+	result, err := ParseProject("testdata/sizes")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	if want := (models.Sizes{MaxRequest: 1 << 20, TypicalResponse: 64 << 10}); result.ProjectInfo.Sizes != want {
+		t.Errorf("Expected project sizes %+v, got %+v", want, result.ProjectInfo.Sizes)
+	}
+
+	want := map[string]models.Sizes{
+		"items.Get":    {},
+		"items.List":   {TypicalResponse: 5 << 20},
+		"items.Upload": {MaxRequest: 3 << 19},
+		"items.Ping":   {},
+	}
+	for _, fn := range result.Functions {
+		if fn.Sizes != want[fn.Command] {
+			t.Errorf("Expected %s to have sizes %+v, got %+v", fn.Command, want[fn.Command], fn.Sizes)
+		}
+	}
+	if len(result.Functions) != len(want) {
+		t.Errorf("Expected %d commands, got %d", len(want), len(result.Functions))
+	}
+
+	var warnings []Diagnostic
+	for _, diag := range result.Diagnostics {
+		if diag.Class == ClassInvalidSize {
+			warnings = append(warnings, diag)
+		}
+	}
+	if len(warnings) != 1 || warnings[0].Line != 33 || warnings[0].Severity != SeverityWarning {
+		t.Errorf("Expected one invalid-size warning at line 33, got %v", warnings)
+	}
+}
@@ -0,0 +1,51 @@
+// parser/slices_test.go
+package parser
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectSliceResults(t *testing.T) {
+	result, err := ParseProject("testdata/slices")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("Expected no diagnostics, got %v", result.Diagnostics)
+	}
+
+	item := models.StructKey{Package: "rpc", Name: "ReportItem"}
+	page := models.StructKey{Package: "rpc", Name: "Page[ReportItem]"}
+	want := map[string]models.StructKey{
+		"reports.List":     item,
+		"reports.Pointers": item,
+		"reports.Grid":     item,
+		"reports.Top":      item,
+		"reports.Pages":    page,
+	}
+	for _, apiFunc := range result.Functions {
+		result := apiFunc.Results[0]
+		held := result.TypeRef.Held()
+		if held == nil || held.Kind != models.TypeStruct || held.Struct != want[apiFunc.Command] {
+			t.Errorf("Expected the result of %s to hold %v, got %+v", apiFunc.Command, want[apiFunc.Command], held)
+		}
+		if result.TypeRef.Kind != models.TypeSlice {
+			t.Errorf("Expected the result of %s to be a slice, got %+v", apiFunc.Command, result.TypeRef)
+		}
+	}
+	if len(result.Functions) != len(want) {
+		t.Errorf("Expected %d commands, got %d", len(want), len(result.Functions))
+	}
+
+	// The generic element type is instantiated under its slice
+	if _, ok := result.Structs[page]; !ok {
+		t.Fatalf("Expected the concrete struct %v to be created", page)
+	}
+	for _, apiFunc := range result.Functions {
+		if apiFunc.Command == "reports.Pages" && apiFunc.Results[0].Type != "[]Page[ReportItem]" {
+			t.Errorf("Expected the result type []Page[ReportItem], got %q", apiFunc.Results[0].Type)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// parser/sourcepath.go
+package parser
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// repositoryPath returns the slash-separated path of file relative to the root of its
+// repository, the nearest directory above it holding a .git entry, so source links point at
+// the right file whatever -dir was given. Files outside a repository are located relative
+// to root, the parsed directory they were found in.
+func repositoryPath(root string, file string) string {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	for dir := filepath.Dir(absFile); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			if rel, err := filepath.Rel(dir, absFile); err == nil {
+				return filepath.ToSlash(rel)
+			}
+			break
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	if absRoot, err := filepath.Abs(root); err == nil {
+		if rel, err := filepath.Rel(absRoot, absFile); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(file)
+}
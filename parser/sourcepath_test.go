@@ -0,0 +1,29 @@
+// parser/sourcepath_test.go
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepositoryPath(t *testing.T) {
+	repo := t.TempDir()
+	root := filepath.Join(repo, "services", "api")
+	file := filepath.Join(root, "handlers", "users.go")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Outside a repository, files are located relative to the parsed directory
+	if got, want := repositoryPath(root, file), "handlers/users.go"; got != want {
+		t.Errorf("Expected %q without a repository, got %q", want, got)
+	}
+
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := repositoryPath(root, file), "services/api/handlers/users.go"; got != want {
+		t.Errorf("Expected %q in a repository, got %q", want, got)
+	}
+}
@@ -0,0 +1,66 @@
+// parser/spans_test.go
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseProjectAnnotationLines(t *testing.T) {
+	result, err := ParseProject("testdata/spans")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	var got []string
+	for _, d := range result.Diagnostics {
+		got = append(got, fmt.Sprintf("%d: %s [%s]", d.Line, d.Message, d.Class))
+	}
+	expected := []string{
+		"20: type 'rpc.Filter' of parameter 'filter' of command 'stats.Report' is not declared in the parsed packages [unresolved-type]",
+		"34: function 'Broken' skipped: @Error code must be a numeric literal [parse-failure]",
+		"27: command 'stats.Report' has ID 'user-list', already used by command 'user.List' at " + filepath.Join("testdata", "spans", "api.go") + ":13 [duplicate-id]",
+		"25: command 'stats.Report' has former name 'user.List', which is a live command at " + filepath.Join("testdata", "spans", "api.go") + ":13 [former-name]",
+		"26: command 'stats.Report' has unknown @Envelope jsonrpc version '3.0', expected one of: 1.0, 2.0 [envelope]",
+		"24: command 'stats.Report' has @DynamicKeys, but its result type 'Stats' is not a map [dynamic-keys]",
+		"22: @Requires of command 'stats.Report' names unknown parameter 'until' [param-rule]",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	for _, apiFunc := range result.Functions {
+		if apiFunc.Command != "stats.Report" {
+			continue
+		}
+		if apiFunc.Parameters[1].SourceLine != 21 || apiFunc.Results[0].SourceLine != 23 || apiFunc.AnnotationLine("@Description") != 19 {
+			t.Errorf("Unexpected annotation lines: parameter %d, result %d, description %d",
+				apiFunc.Parameters[1].SourceLine, apiFunc.Results[0].SourceLine, apiFunc.AnnotationLine("@Description"))
+		}
+	}
+}
+
+func TestParseFunctionErrorLine(t *testing.T) {
+	fset := token.NewFileSet()
+	fileAst, err := parser.ParseFile(fset, filepath.Join("testdata", "spans", "api.go"), nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decl := range fileAst.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "Broken" {
+			continue
+		}
+		_, err := parseFunction(fn, "rpc", nil, "api.go", fset, nil)
+		var located *annotationError
+		if !errors.Is(err, ErrInvalidErrorCode) || !errors.As(err, &located) || located.Line != 34 {
+			t.Errorf("Expected an invalid error code at line 34, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// parser/subscription_test.go
+package parser
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectSubscriptions(t *testing.T) {
+	result, err := ParseProject("testdata/subscriptions")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	subscriptions := make(map[string]*models.Subscription)
+	var commands []string
+	for _, fn := range result.Functions {
+		commands = append(commands, fn.Command)
+		subscriptions[fn.Command] = fn.Subscription
+	}
+	sort.Strings(commands)
+	// events.Orphan and events.NoPayload are skipped, each missing half of the pair
+	if want := []string{"clock.Subscribe", "events.Subscribe"}; !reflect.DeepEqual(commands, want) {
+		t.Fatalf("Expected commands %v, got %v", want, commands)
+	}
+
+	events := subscriptions["events.Subscribe"]
+	if events == nil || events.Method != "events.push" {
+		t.Fatalf("Expected events.Subscribe to push events.push notifications, got %+v", events)
+	}
+	payload := events.Payload
+	if payload.Type != "EventPayload" || payload.Description != "Pushed for each event" || payload.SourceLine != 19 {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+	if want := (models.StructKey{Package: "rpc", Name: "EventPayload"}); payload.TypeRef == nil || payload.TypeRef.Struct != want {
+		t.Errorf("Expected the payload to resolve to %v, got %+v", want, payload.TypeRef)
+	}
+
+	clock := subscriptions["clock.Subscribe"]
+	if clock == nil || clock.Method != defaultNotificationMethod || clock.Payload.Type != "int64" {
+		t.Errorf("Expected clock.Subscribe to push %s notifications with an int64, got %+v", defaultNotificationMethod, clock)
+	}
+}
@@ -0,0 +1,28 @@
+// parser/tags_test.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProjectTags(t *testing.T) {
+	result, err := ParseProject("testdata/tags")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, apiFunc := range result.Functions {
+		got[apiFunc.Command] = apiFunc.Tags
+	}
+	want := map[string][]string{
+		"ping":           nil,
+		"users.Get":      {"users"},
+		"billing.Charge": {"billing", "users"},
+		"billing.Refund": {"billing"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected tags %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,51 @@
+// Package rpc
+// @title Aliases Fixture API
+// @version 1.0.0
+// @description Fixture tree for types declared with another type.
+package rpc
+
+// UserID identifies a user.
+type UserID string
+
+// Status is the status of a user.
+type Status = string
+
+// State is another name of Status.
+type State = Status
+
+// IDList is a list of user ids.
+type IDList []int64
+
+// Labels are free-form labels.
+type Labels map[string]string
+
+// Members is a list of members.
+type Members []Member
+
+// Member is a member of a team.
+type Member struct {
+	ID      UserID  `json:"id"`                // Identifier of the member
+	State   State   `json:"state"`             // State of the member
+	Friends IDList  `json:"friends"`           // Friends of the member
+	Labels  Labels  `json:"labels"`            // Labels of the member
+	Manager *UserID `json:"manager,omitempty"` // Manager of the member
+}
+
+// GetMember returns a member.
+// @Command members.Get
+// @Description Returns a member.
+// @Parameter id UserID "Identifier of the member"
+// @Result Member "The member"
+func GetMember() {}
+
+// ListMembers returns the members of a team.
+// @Command members.List
+// @Description Returns the members of a team.
+// @Result Members "The members"
+func ListMembers() {}
+
+// ListIDs returns the ids of the members of a team.
+// @Command members.IDs
+// @Description Returns the ids of the members of a team.
+// @Result IDList "The ids"
+func ListIDs() {}
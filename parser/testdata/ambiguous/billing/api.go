@@ -0,0 +1,12 @@
+package billing
+
+// Result is the outcome of a charge.
+type Result struct {
+	Charged bool `json:"charged"` // Whether the card was charged.
+}
+
+// Charge charges a card.
+// @Command billing.Charge
+// @Description Charge a card.
+// @Result Result "The outcome of the charge"
+func Charge() (Result, error) { return Result{}, nil }
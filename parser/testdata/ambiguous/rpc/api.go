@@ -0,0 +1,19 @@
+// Package rpc
+// @title Ambiguous Fixture API
+// @version 1.0.0
+// @description Fixture tree with two packages declaring a Result struct.
+package rpc
+
+import accounts "example.com/app/users"
+
+// Status returns the status of the service.
+// @Command service.Status
+// @Description Get the status of the service.
+// @Result Result "The status"
+func Status() error { return nil }
+
+// Lookup returns a user.
+// @Command users.Lookup
+// @Description Look up a user.
+// @Result accounts.Result "The user"
+func Lookup() (accounts.Result, error) { return accounts.Result{}, nil }
@@ -0,0 +1,6 @@
+package users
+
+// Result is a user found by a lookup.
+type Result struct {
+	Name string `json:"name"` // Name of the user.
+}
@@ -0,0 +1,28 @@
+// Package rpc
+// @title Audience Fixture API
+// @version 1.0.0
+// @description Fixture tree for @Internal commands and @Hidden fields.
+package rpc
+
+// User is returned by both commands.
+type User struct {
+	ID int `json:"id"` // User id.
+	// PasswordHash is only documented for the internal audience.
+	// @Hidden
+	PasswordHash string `json:"password_hash"`
+	Name         string `json:"name"` // Display name.
+}
+
+// Get is public.
+// @Command user.Get
+// @Description Returns a user.
+// @Parameter id int "User id"
+// @Result User "The user"
+func Get() error { return nil }
+
+// Reindex is for operators only.
+// @Command admin.Reindex
+// @Description Rebuilds the user index.
+// @Result bool "Whether the index was rebuilt"
+// @Internal
+func Reindex() error { return nil }
@@ -0,0 +1,11 @@
+// Package rpc
+// @title Broken Fixture API
+// @version 1.0.0
+// @description Fixture tree with one file that does not compile.
+package rpc
+
+// GetUser returns a user.
+// @Command user.Get
+// @Description Get a user by id.
+// @Parameter id int "User id."
+func GetUser(id int) {}
@@ -0,0 +1,12 @@
+package rpc
+
+// DeleteUser removes a user.
+// @Command user.Delete
+// @Description Delete a user.
+func DeleteUser(id int {
+}
+
+// ListUsers lists users.
+// @Command user.List
+// @Description List users.
+func ListUsers() {}
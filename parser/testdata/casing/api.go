@@ -0,0 +1,20 @@
+// Package rpc
+// @title Casing Fixture API
+// @version 1.0.0
+// @description Fixture tree for annotations written in another case.
+package rpc
+
+// Account is an account.
+type Account struct {
+	ID int `json:"id"` // Account id.
+}
+
+// GetAccount returns an account.
+// @command account.Get
+// @DESCRIPTION	Returns an account.
+// @Parmeter name string "Account name."
+// @PARAMETER  	id   int	"Account id."
+// @result Account "The account."
+func GetAccount(id int) (Account, error) {
+	return Account{}, nil
+}
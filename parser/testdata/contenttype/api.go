@@ -0,0 +1,27 @@
+// Package rpc
+// @title Content Type Fixture API
+// @version 1.0.0
+// @description Fixture tree for @ContentType.
+package rpc
+
+// GetPdf returns an invoice as a PDF.
+// @Command invoice.Pdf
+// @Description Returns an invoice as a PDF.
+// @Result string "The document"
+// @ContentType application/pdf base64
+func GetPdf() error { return nil }
+
+// Render renders an invoice in the accepted format.
+// @Command invoice.Render
+// @Description Renders an invoice in the accepted format.
+// @Result string "The rendered invoice"
+// @ContentType application/pdf base64
+//
+//jdocgen:contenttype text/html
+func Render() error { return nil }
+
+// Total returns the invoice total.
+// @Command invoice.Total
+// @Description Returns the invoice total.
+// @Result int64 "Total in cents"
+func Total() error { return nil }
@@ -0,0 +1,33 @@
+// Package rpc
+// @title Defaults Fixture API
+// @version 1.0.0
+// @description Fixture tree for parameter defaults.
+package rpc
+
+const (
+	// DefaultPageSize is the page size of List when none is given.
+	DefaultPageSize = 50
+	DefaultOrder    = "asc"
+	DefaultRatio    = -0.5
+	DefaultDeleted  = false
+	maxRetries      = iota
+)
+
+// List documents literal and constant defaults.
+// @Command items.List
+// @Description Lists items.
+// @Parameter page_size int default=@DefaultPageSize "optional Items per page"
+// @Parameter order string default=@DefaultOrder "optional Sort order"
+// @Parameter ratio float64 default=@DefaultRatio "optional Sampling ratio"
+// @Parameter deleted bool default=false "optional Include deleted items"
+// @Parameter cursor string default=@paging.DefaultCursor "optional Page cursor"
+func List() error { return nil }
+
+// Broken has defaults that cannot be resolved.
+// @Command items.Broken
+// @Description Has broken defaults.
+// @Parameter page_size string default=@DefaultPageSize "optional Items per page"
+// @Parameter order string default=@DefaultSort "optional Sort order"
+// @Parameter retries int default=@maxRetries "optional Retries"
+// @Parameter limit int default=@paging.MaxLimit "optional Limit"
+func Broken() error { return nil }
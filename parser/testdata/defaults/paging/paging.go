@@ -0,0 +1,4 @@
+package paging
+
+// DefaultCursor is the cursor of the first page.
+const DefaultCursor = "start"
@@ -0,0 +1,24 @@
+// Package rpc
+// @title Deprecated Fixture API
+// @version 1.0.0
+// @description Fixture tree for @Deprecated.
+package rpc
+
+// GetProfile is replaced by GetProfileV2.
+// @Command users.GetProfile
+// @Description Returns a profile.
+// @Result string "Profile"
+// @Deprecated use users.GetProfileV2 instead
+func GetProfile() error { return nil }
+
+// Legacy is deprecated without a reason.
+// @Command users.Legacy
+// @Description Old endpoint.
+// @Deprecated
+func Legacy() error { return nil }
+
+// GetProfileV2 is current.
+// @Command users.GetProfileV2
+// @Description Returns a profile.
+// @Result string "Profile"
+func GetProfileV2() error { return nil }
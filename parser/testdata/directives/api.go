@@ -0,0 +1,38 @@
+// Package rpc
+// @title Directives Fixture API
+// @version 1.0.0
+// @description Fixture tree for //jdocgen: directives.
+package rpc
+
+// User is returned by the user commands.
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetUser is written entirely with directives, as a code generator would.
+//
+//jdocgen:command user.Get
+//jdocgen:description Returns a user.
+//jdocgen:param id int "User identifier"
+//jdocgen:result User "The user"
+//jdocgen:error 404 "User not found"
+func GetUser() error { return nil }
+
+// RenameUser mixes both styles. The directive wins where both set the same key.
+// @Command user.Old
+// @Description Renames a user.
+// @Parameter id int "User identifier"
+// @Parameter name string "Old description"
+// @Result User "The renamed user"
+//
+//jdocgen:command user.Rename
+//jdocgen:param name string "New name"
+func RenameUser() error { return nil }
+
+// Misspelled uses a directive that does not exist.
+//
+//jdocgen:command user.Delete
+//jdocgen:description Deletes a user.
+//jdocgen:parameter id int "User identifier"
+func Misspelled() error { return nil }
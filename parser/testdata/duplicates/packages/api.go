@@ -0,0 +1,10 @@
+// Package rpc
+// @title Duplicates Fixture API
+// @version 1.0.0
+// @description Fixture tree declaring a command in two packages.
+package rpc
+
+// GetInvoice returns an invoice.
+// @Command invoices.Get
+// @Description Returns an invoice.
+func GetInvoice() error { return nil }
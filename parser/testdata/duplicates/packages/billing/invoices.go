@@ -0,0 +1,11 @@
+package billing
+
+// FetchInvoice declares the command of rpc.GetInvoice again.
+// @Command invoices.Get
+// @Description Fetches an invoice.
+func FetchInvoice() error { return nil }
+
+// ListInvoices lists invoices.
+// @Command invoices.List
+// @Description Lists invoices.
+func ListInvoices() error { return nil }
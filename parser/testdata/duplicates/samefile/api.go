@@ -0,0 +1,15 @@
+// Package rpc
+// @title Duplicates Fixture API
+// @version 1.0.0
+// @description Fixture tree declaring a command twice in one file.
+package rpc
+
+// GetUser returns a user.
+// @Command users.Get
+// @Description Returns a user.
+func GetUser() error { return nil }
+
+// GetUserV2 declares the same command again.
+// @Command users.Get
+// @Description Returns a user, with its groups.
+func GetUserV2() error { return nil }
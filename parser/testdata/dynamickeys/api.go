@@ -0,0 +1,38 @@
+// Package rpc
+// @title Dynamic Keys Fixture API
+// @version 1.0.0
+// @description Fixture tree for @DynamicKeys and @keys.
+package rpc
+
+// HostStats holds the traffic of a host.
+type HostStats struct {
+	Requests int64 `json:"requests"`
+	// Requests by day
+	// @keys "date (YYYY-MM-DD)"
+	Daily map[string]int64 `json:"daily"`
+	// Peak request rate
+	// @keys "hour"
+	Peak int64 `json:"peak"`
+}
+
+// GetStats returns traffic by host.
+// @Command stats.Get
+// @Description Returns traffic by host.
+// @Result map[string]HostStats "Traffic by host"
+// @DynamicKeys result "host name"
+func GetStats() error { return nil }
+
+// GetHistory returns traffic by host and day.
+// @Command stats.History
+// @Description Returns traffic by host and day.
+// @Result map[string]map[string]HostStats "Traffic by host and day"
+//
+//jdocgen:dynamickeys result "host name, date"
+func GetHistory() error { return nil }
+
+// GetTotal returns the total traffic.
+// @Command stats.Total
+// @Description Returns the total traffic.
+// @Result HostStats "Total traffic"
+// @DynamicKeys result "host name"
+func GetTotal() error { return nil }
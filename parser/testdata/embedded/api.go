@@ -0,0 +1,40 @@
+// Package rpc
+// @title Embedded Fixture API
+// @version 1.0.0
+// @description Fixture tree for embedded struct fields.
+package rpc
+
+import "example.com/app/common"
+
+// Meta holds the metadata of a record.
+type Meta struct {
+	ID      int    `json:"id"`      // Identifier of the record
+	Created string `json:"created"` // Creation time of the record
+}
+
+// UserResponse is a user with its metadata.
+type UserResponse struct {
+	Meta
+	*common.Audit
+	Name string `json:"name"` // Name of the user
+	// Created shadows the creation time of Meta
+	Created int64 `json:"created"` // Creation time as a Unix timestamp
+}
+
+// TaggedResponse keeps its metadata under a key.
+type TaggedResponse struct {
+	Meta `json:"meta"`
+	Note string `json:"note"` // Free text
+}
+
+// GetUser returns a user.
+// @Command users.Get
+// @Description Returns a user.
+// @Result UserResponse "The user"
+func GetUser() {}
+
+// GetTagged returns a tagged record.
+// @Command records.Get
+// @Description Returns a record.
+// @Result TaggedResponse "The record"
+func GetTagged() {}
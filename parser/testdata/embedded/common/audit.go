@@ -0,0 +1,6 @@
+package common
+
+// Audit records who changed a record.
+type Audit struct {
+	UpdatedBy string `json:"updated_by"` // User who last changed the record
+}
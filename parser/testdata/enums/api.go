@@ -0,0 +1,54 @@
+// Package rpc
+// @title Enums Fixture API
+// @version 1.0.0
+// @description Fixture tree for constants declared with a named type.
+package rpc
+
+// Status is the status of an account.
+type Status string
+
+const (
+	Active    Status = "active"    // Can sign in
+	Suspended Status = "suspended" // Locked by an administrator
+	Deleted   Status = "deleted"
+)
+
+// Priority is the priority of a task.
+type Priority int
+
+const (
+	Low Priority = iota + 1
+	Normal
+	_
+	High
+)
+
+// Permission is a set of permission bits.
+type Permission uint16
+
+const (
+	Read Permission = 1 << iota
+	Write
+	Execute
+)
+
+// Untyped constants are no enum
+const (
+	DefaultLimit = 50
+	MaxLimit     = DefaultLimit * 2
+)
+
+// Account is a user account.
+type Account struct {
+	Status      Status       `json:"status"`      // Status of the account
+	Priority    Priority     `json:"priority"`    // Priority of its tasks
+	Permissions []Permission `json:"permissions"` // Permissions of the account
+	Limit       int          `json:"limit"`       // Page size
+}
+
+// FindAccounts returns the accounts with a status.
+// @Command accounts.Find
+// @Description Returns the accounts with a status.
+// @Parameter status Status "Status of the accounts"
+// @Result Account "An account"
+func FindAccounts() {}
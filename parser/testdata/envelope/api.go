@@ -0,0 +1,28 @@
+// Package rpc
+// @title Envelope Fixture API
+// @version 1.0.0
+// @description Fixture tree for @Envelope overrides.
+package rpc
+
+// Legacy is served through the JSON-RPC 1.0 compatibility layer.
+// @Command legacy.Get
+// @Description legacy.Get command.
+// @Envelope jsonrpc=1.0 id=string
+func Legacy() error { return nil }
+
+// Current uses the project-wide envelope.
+// @Command current.Get
+// @Description current.Get command.
+func Current() error { return nil }
+
+// Future asks for a version that does not exist.
+// @Command future.Get
+// @Description future.Get command.
+// @Envelope jsonrpc=3.0 id=uuid
+func Future() error { return nil }
+
+// Broken has a malformed setting.
+// @Command broken.Get
+// @Description broken.Get command.
+// @Envelope version
+func Broken() error { return nil }
@@ -0,0 +1,27 @@
+// Package rpc
+// @title Error Catalog Fixture API
+// @version 1.0.0
+// @description Fixture tree for @errorcatalog.
+// @errorcatalog
+// -32004 UserNotFound "The user does not exist."
+// -32005 QuotaExceeded "The quota of the account is exhausted."
+// -32004 UserMissing "Declared a second time."
+// -32006 Not/Valid "Invalid name."
+//
+// @license MIT
+package rpc
+
+// Get returns a user.
+// @Command users.Get
+// @Description Returns a user.
+// @Error -32004
+// @Error -32005 "No requests left this hour."
+// @Error 400 "Bad request."
+func Get() error { return nil }
+
+// Delete deletes a user.
+// @Command users.Delete
+// @Description Deletes a user.
+// @Error -32004
+// @Error 500
+func Delete() error { return nil }
@@ -0,0 +1,16 @@
+// Package rpc
+// @title Exclude Fixture API
+// @version 1.0.0
+// @description Fixture tree for -exclude.
+package rpc
+
+// User is the real user struct.
+type User struct {
+	ID int `json:"id"`
+}
+
+// GetUser returns a user.
+// @Command users.Get
+// @Description Get a user.
+// @Result User "The user."
+func GetUser() error { return nil }
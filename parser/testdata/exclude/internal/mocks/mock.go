@@ -0,0 +1,4 @@
+package mocks
+
+// DeepMock is a nested test double.
+type DeepMock struct{}
@@ -0,0 +1,4 @@
+package mocks
+
+// MockUser is a test double.
+type MockUser struct{}
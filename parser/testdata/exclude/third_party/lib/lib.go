@@ -0,0 +1,4 @@
+package lib
+
+// Vendored is third-party code.
+type Vendored struct{}
@@ -0,0 +1,6 @@
+package rpc
+
+// User is generated and collides with the real one.
+type User struct {
+	Generated bool `json:"generated"`
+}
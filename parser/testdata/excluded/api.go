@@ -0,0 +1,32 @@
+// Package rpc
+// @title Excluded Fields Fixture API
+// @version 1.0.0
+// @description Fixture tree for fields tagged json:"-".
+package rpc
+
+// Session is returned by Login.
+type Session struct {
+	Token  string `json:"token"`            // Session token.
+	Secret string `json:"-"`                // Signing secret, never sent.
+	Dash   string `json:"-,"`               // Field named "-".
+	Expiry int64  `json:"expiry,omitempty"` // Expiry in seconds.
+}
+
+// Page is a generic page.
+type Page[T any] struct {
+	Items  []T    `json:"items"`
+	Cursor string `json:"-"`
+}
+
+// Login opens a session.
+// @Command auth.Login
+// @Description Opens a session.
+// @Parameter user string "User name"
+// @Result Session "The session"
+func Login() error { return nil }
+
+// List lists sessions.
+// @Command auth.List
+// @Description Lists sessions.
+// @Result Page[Session] "A page of sessions"
+func List() error { return nil }
@@ -0,0 +1,29 @@
+// Package rpc
+// @title Features Fixture API
+// @version 1.0.0
+// @description Fixture tree for @Feature filtering.
+package rpc
+
+// Ping is always available.
+// @Command ping
+// @Description ping command.
+func Ping() error { return nil }
+
+// Pay uses the new payments backend.
+// @Command payments.Pay
+// @Description payments.Pay command.
+// @Feature payments_v2
+func Pay() error { return nil }
+
+// Refund needs both payments and refunds.
+// @Command payments.Refund
+// @Description payments.Refund command.
+// @Feature payments_v2
+// @Feature refunds
+func Refund() error { return nil }
+
+// Preview is only built for beta customers.
+// @Command beta.Preview
+// @Description beta.Preview command.
+// @Feature beta
+func Preview() error { return nil }
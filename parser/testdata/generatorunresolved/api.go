@@ -0,0 +1,11 @@
+// Package rpc
+// @title Generator Unresolved Fixture API
+// @version 1.0.0
+// @description Fixture tree for a result struct only the generator finds missing.
+package rpc
+
+// Owner returns the owner of an account.
+// @Command account.Owner
+// @Description Get the owner of an account.
+// @Result users.Owner "The owner"
+func Owner() error { return nil }
@@ -0,0 +1,23 @@
+// Package rpc
+// @title Global Errors Fixture API
+// @version 1.0.0
+// @description Fixture tree for @globalerror.
+// @errorcatalog
+// 1002 RateLimited "Too many requests."
+// 1004 QuotaExceeded "The quota of the account is exhausted."
+//
+// @globalerror -32600 "The request is not a valid JSON-RPC request."
+// @globalerror 1001 "The request is not authenticated."
+// @globalerror 1002
+// @globalerror 1001 "Declared a second time."
+// @globalerror unauthorized "Not a code."
+// @globalerror 1003
+// @globalerror 1004 "No requests left this hour."
+// @license MIT
+package rpc
+
+// Get returns a user.
+// @Command users.Get
+// @Description Returns a user.
+// @Error 1001 "The session of the user expired."
+func Get() error { return nil }
@@ -0,0 +1,39 @@
+// Package rpc
+// @title IDs Fixture API
+// @version 1.0.0
+// @description Fixture tree for stable identifiers.
+package rpc
+
+// User is a user account.
+// @ID usr
+type User struct {
+	Name string `json:"name"`
+}
+
+// Group is a user group.
+// @ID usr
+type Group struct {
+	Name string `json:"name"`
+}
+
+// Team has no explicit identifier.
+type Team struct {
+	Name string `json:"name"`
+}
+
+// GetUser returns a user.
+// @Command user.Get
+// @ID usr-get-01
+// @Description Get a user.
+func GetUser() {}
+
+// ListUsers lists users.
+// @Command user.List
+// @Description List users.
+func ListUsers() {}
+
+// RenamedListUsers collides with the derived ID of user.List.
+// @Command user.Renamed
+// @ID user-list
+// @Description List users under a new name.
+func RenamedListUsers() {}
@@ -0,0 +1,34 @@
+// Package rpc
+// @title Ignore Fixture API
+// @version 1.0.0
+// @description Fixture tree for ignore pragmas.
+package rpc
+
+import "time"
+
+// Invoice has fields of legacy types documented elsewhere.
+type Invoice struct {
+	// Total amount.
+	Total   Amount       `json:"total"`
+	Legacy  LegacyTotals `json:"legacy"` // Legacy totals. //jdocgen:ignore unresolved-type
+	Created time.Time    `json:"created"`
+	//jdocgen:ignore unresolved-type
+	Archive LegacyArchive `json:"archive"`
+	// Not silenced.
+	Ledger LegacyLedger `json:"ledger"`
+	Other  LegacyOther  `json:"other"` //jdocgen:ignore placeholder
+}
+
+// Amount is an amount in cents.
+type Amount int64
+
+// GetInvoice returns an invoice.
+// @Command invoice.Get
+// @Description Returns an invoice.
+// @Parameter filter LegacyFilter "Legacy filter" //jdocgen:ignore unresolved-type
+// @Parameter id int "Invoice id"
+// @Result Invoice "The invoice"
+// @Additional LegacyNotes
+//
+//jdocgen:ignore unresolved-typo
+func GetInvoice() error { return nil }
@@ -0,0 +1,28 @@
+// Package rpc
+// @title Inline Struct Fixture API
+// @version 1.0.0
+// @description Fixture tree for fields of anonymous struct types.
+package rpc
+
+// Order is an order placed by a customer.
+type Order struct {
+	ID int `json:"id"` // Identifier of the order
+	// Details of the delivery
+	Details struct {
+		Code int    `json:"code"` // Delivery code
+		Msg  string `json:"msg"`  // Message for the courier
+		// Origin of the delivery
+		Origin *struct {
+			Source string `json:"source"` // Warehouse the order ships from
+		} `json:"origin,omitempty"`
+	} `json:"details"`
+	Items []struct {
+		SKU string `json:"sku"` // Stock keeping unit
+	} `json:"items"` // Ordered items
+}
+
+// GetOrder returns an order.
+// @Command orders.Get
+// @Description Returns an order.
+// @Result Order "The order"
+func GetOrder() {}
@@ -0,0 +1,40 @@
+// Package rpc
+// @title Maps Fixture API
+// @version 1.0.0
+// @description Fixture tree for results and fields holding maps of structs.
+package rpc
+
+// MetricSeries is a series of measurements.
+type MetricSeries struct {
+	Points []float64 `json:"points"` // Measurements of the series
+}
+
+// Page is a page of rows.
+type Page[T any] struct {
+	Rows []T `json:"rows"` // Rows of the page
+}
+
+// Dashboard groups series.
+type Dashboard struct {
+	Series map[string]*MetricSeries  `json:"series"` // Series by name
+	Groups map[string][]MetricSeries `json:"groups"` // Series by group
+	Counts map[string]int            `json:"counts"` // Points by series name
+}
+
+// GetMetrics returns the series by name.
+// @Command metrics.Get
+// @Description Returns the series by name.
+// @Result map[string]MetricSeries "metrics keyed by name"
+func GetMetrics() {}
+
+// PageMetrics returns the series by name, a page at a time.
+// @Command metrics.Pages
+// @Description Returns the series by name, a page at a time.
+// @Result map[string]Page[MetricSeries] "pages keyed by name"
+func PageMetrics() {}
+
+// GetDashboard returns the dashboard.
+// @Command metrics.Dashboard
+// @Description Returns the dashboard.
+// @Result Dashboard "the dashboard"
+func GetDashboard() {}
@@ -0,0 +1,26 @@
+// Package rpc
+// @title Method Docs Fixture API
+// @version 1.0.0
+// @description Fixture tree for @IncludeMethodDocs.
+package rpc
+
+// Money is an amount in a currency.
+// @IncludeMethodDocs
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Invoice has documented methods, but is not annotated.
+type Invoice struct {
+	Total Money `json:"total"`
+}
+
+// Validate is not collected, Invoice has no @IncludeMethodDocs.
+func (i Invoice) Validate() error { return nil }
+
+// GetInvoice returns an invoice.
+// @Command invoice.Get
+// @Description Returns an invoice.
+// @Result Invoice "The invoice"
+func GetInvoice() error { return nil }
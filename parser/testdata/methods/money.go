@@ -0,0 +1,15 @@
+package rpc
+
+// Validate checks that the amount is not negative
+// and that the currency is an ISO 4217 code.
+//
+// It is called before every write.
+func (m Money) Validate() error { return nil }
+
+// Cents returns the amount in cents.
+func (m *Money) Cents() int64 { return m.Amount }
+
+func (m Money) Undocumented() {}
+
+// normalize is not exported.
+func (m *Money) normalize() {}
@@ -0,0 +1,46 @@
+// Package rpc
+// @title Misplaced Annotations Fixture API
+// @version 1.0.0
+// @description Fixture tree for command annotations on declarations that are not documented.
+package rpc
+
+// GetUserHandler was meant to be documented.
+// @Command user.Get
+// @Description Returns a user.
+var GetUserHandler = func() error { return nil }
+
+// DefaultLimit has a copied parameter.
+// @Parameter limit int "Page size"
+const DefaultLimit = 10
+
+var (
+	// Retries is inside a group.
+	// @Description Number of retries.
+	// @Command retries.Get
+	Retries = 3
+)
+
+// Handler is not a function declaration.
+//
+//jdocgen:command handler.Run
+type Handler func() error
+
+// User is a struct, checked as a struct.
+// @Command user.List
+type User struct {
+	Name string `json:"name"`
+}
+
+// @Command app.Init
+// @Description Runs at startup.
+func init() {}
+
+// @Command blank.Do
+// @Description Never called.
+func _() {}
+
+// Ping is documented.
+// @Command ping
+// @Description Checks the server.
+// @Result string "pong"
+func Ping() error { return nil }
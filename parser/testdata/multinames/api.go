@@ -0,0 +1,20 @@
+// Package rpc
+// @title Multiple Names Fixture API
+// @version 1.0.0
+// @description Fixture tree for struct fields declaring several names.
+package rpc
+
+// Box is a box in three dimensions.
+type Box struct {
+	Width, Height int // Size of the box face
+	X, Y, Z       float64
+	// Scale of each axis
+	ScaleX, ScaleY, ScaleZ float64 `json:"scale,omitempty"`
+	Label                  string  `json:"label"` // Label printed on the box
+}
+
+// GetBox returns a box.
+// @Command boxes.Get
+// @Description Returns a box.
+// @Result Box "The box"
+func GetBox() {}
@@ -0,0 +1,35 @@
+// Package rpc
+// @title Parameter Groups Fixture API
+// @version 1.0.0
+// @description Fixture tree for @ParamGroup and @Params.
+package rpc
+
+// @ParamGroup PagingParams
+// @Parameter page_size int "optional Items per page"
+// @Parameter page_token string "optional Token of the page to return"
+
+// @ParamGroup Tenant
+// @Parameter tenant string "Tenant owning the items"
+// @Parameter page_size int "Shadowed by PagingParams"
+
+// List lists the items of a tenant.
+// @Command items.List
+// @Description Lists items.
+// @Parameter filter string "Filter expression"
+// @Params PagingParams
+// @Params Tenant
+func List() error { return nil }
+
+// Search overrides page_size.
+// @Command items.Search
+// @Description Searches items.
+// @Parameter page_size int "Results per page, at most 20"
+//
+//jdocgen:params PagingParams
+func Search() error { return nil }
+
+// Broken names an undefined group.
+// @Command items.Broken
+// @Description Uses a group that does not exist.
+// @Params Missing
+func Broken() error { return nil }
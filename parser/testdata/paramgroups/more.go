@@ -0,0 +1,4 @@
+package rpc
+
+// @ParamGroup Tenant
+// @Parameter tenant string "Declared a second time"
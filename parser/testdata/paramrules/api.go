@@ -0,0 +1,25 @@
+// Package rpc
+// @title Parameter Rules Fixture API
+// @version 1.0.0
+// @description Fixture tree for @Requires and @ConflictsWith.
+package rpc
+
+// Search finds documents.
+// @Command search.Find
+// @Description Finds documents by query or by ids.
+// @Parameter query string "optional Full-text query"
+// @Parameter ids []int "optional Document ids"
+// @Parameter page_size int "optional Results per page"
+// @Parameter page_token string "optional Token of the next page"
+// @Requires page_token page_size
+// @ConflictsWith query ids
+func Search() error { return nil }
+
+// Broken has rules that cannot hold.
+// @Command search.Broken
+// @Description Rules naming unknown or required parameters.
+// @Parameter query string "Full-text query"
+// @Parameter ids []int "Document ids"
+// @Requires cursor limit
+// @ConflictsWith query ids, query
+func Broken() error { return nil }
@@ -0,0 +1,23 @@
+// Package rpc
+// @title Renames Fixture API
+// @version 1.0.0
+// @description Fixture tree for @FormerName.
+package rpc
+
+// GetAccount was renamed from account.GetUser.
+// @Command account.Get
+// @Description Get an account.
+// @FormerName account.GetUser
+// @FormerName user.Get
+func GetAccount() error { return nil }
+
+// GetUser is still live.
+// @Command user.Get
+// @Description Get a user.
+func GetUser() error { return nil }
+
+// FetchAccount claims a former name already taken.
+// @Command account.Fetch
+// @Description Fetch an account.
+// @FormerName account.GetUser
+func FetchAccount() error { return nil }
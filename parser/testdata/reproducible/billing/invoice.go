@@ -0,0 +1,27 @@
+package billing
+
+// User is the billing contact, named like rpc.User.
+type User struct {
+	Email string `json:"email"`
+}
+
+// Invoice is a bill sent to a user.
+type Invoice struct {
+	Number  string       `json:"number"`
+	Contact User         `json:"contact"`
+	Lines   []Line       `json:"lines"`
+	Totals  LegacyTotals `json:"totals"`
+}
+
+// Line is one item of an invoice.
+type Line struct {
+	Label  string `json:"label"`
+	Amount int64  `json:"amount"`
+}
+
+// GetInvoice returns an invoice.
+// @Command billing.Get
+// @Description Get an invoice.
+// @Parameter number string "Invoice number"
+// @Result Invoice "The invoice"
+func GetInvoice() error { return nil }
@@ -0,0 +1,5 @@
+// Package reproducible
+// @title Reproducible Fixture API
+// @version 1.0.0
+// @description Fixture tree whose output must not depend on file order or environment.
+package reproducible
@@ -0,0 +1,13 @@
+package rpc
+
+// Profile is the legacy view of a user.
+type Profile struct {
+	User    User   `json:"user"`
+	Comment string `json:"comment"`
+}
+
+// GetProfile declares the same command name as GetUser.
+// @Command user.Get
+// @Description Get the legacy profile of a user.
+// @Result Profile "The profile"
+func GetProfile() error { return nil }
@@ -0,0 +1,36 @@
+package rpc
+
+// User is an account.
+type User struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Address *Address `json:"address,omitempty"`
+	Tags    []string `json:"tags"`
+}
+
+// Address is where a user lives.
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+// Page holds one page of items.
+type Page[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+}
+
+// GetUser returns a user.
+// @Command user.Get
+// @Description Get a user by id.
+// @Parameter id int "User id"
+// @Result User "The user"
+// @Error 404 "User not found"
+func GetUser() error { return nil }
+
+// ListUsers lists users.
+// @Command user.List
+// @Description List users page by page.
+// @Parameter page_token string "optional Token of the page"
+// @Result Page[User] "A page of users"
+func ListUsers() error { return nil }
@@ -0,0 +1,27 @@
+// Package rpc
+// @title Required Fields Fixture API
+// @version 1.0.0
+// @description Fixture tree for required and optional fields.
+package rpc
+
+// Audit is embedded in Account.
+type Audit struct {
+	Created string `json:"created"` // Creation time.
+}
+
+// Account mixes required, omitempty, pointer and excluded fields.
+type Account struct {
+	Audit
+	ID       int     `json:"id"`                 // Account id.
+	Nickname string  `json:"nickname,omitempty"` // Nickname, when set.
+	Manager  *int    `json:"manager"`            // Manager id, null without one.
+	Parent   *string `json:"parent,omitempty"`   // Parent account, absent without one.
+	Password string  `json:"-"`                  // Never sent.
+}
+
+// Get returns an account.
+// @Command account.Get
+// @Description Returns an account.
+// @Parameter id int "Account id"
+// @Result Account "The account"
+func Get() error { return nil }
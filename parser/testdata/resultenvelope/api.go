@@ -0,0 +1,29 @@
+// Package rpc
+// @title Result Envelope Fixture API
+// @version 1.0.0
+// @description Fixture tree for the project @envelope.
+// @envelope data=RESULT meta=ResponseMeta
+package rpc
+
+// ResponseMeta is added by the server to every response.
+type ResponseMeta struct {
+	RequestID string `json:"request_id"` // Identifier of the request, for support.
+}
+
+// User is a user account.
+type User struct {
+	ID int `json:"id"` // User id.
+}
+
+// GetUser returns a user.
+// @Command user.Get
+// @Description Get a user.
+// @Result User "The user"
+func GetUser() error { return nil }
+
+// Health is answered by the load balancer, without the envelope.
+// @Command health
+// @Description Health check.
+// @Result bool "Always true"
+// @NoEnvelope
+func Health() error { return nil }
@@ -0,0 +1,9 @@
+package types
+
+// A copy of types.User, such as a vendored package, at other lines.
+
+// User is shared by the handlers.
+type User struct {
+	ID   int    `json:"id"`   // User id.
+	Name string `json:"name"` // Display name.
+}
@@ -0,0 +1,5 @@
+// Package other
+// @title Other API
+// @version 2.0.0
+// @description Another project.
+package other
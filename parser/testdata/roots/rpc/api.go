@@ -0,0 +1,11 @@
+// Package rpc
+// @title Roots API
+// @version 1.0.0
+// @description Fixture tree for projects spread over several roots.
+package rpc
+
+// GetUser uses a struct of another root.
+// @Command users.Get
+// @Description Get a user.
+// @Result types.User "The user."
+func GetUser() error { return nil }
@@ -0,0 +1,7 @@
+package types
+
+// User is shared by the handlers.
+type User struct {
+	ID   int    `json:"id"`   // User id.
+	Name string `json:"name"` // Display name.
+}
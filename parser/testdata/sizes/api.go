@@ -0,0 +1,34 @@
+// Package rpc
+// @title Sizes Fixture API
+// @version 1.0.0
+// @description Fixture tree for @MaxRequestSize and @TypicalResponseSize.
+// @maxrequestsize 1MB
+// @typicalresponsesize 64KB
+package rpc
+
+// Get uses the project defaults.
+// @Command items.Get
+// @Description Returns an item.
+// @Result string "Item"
+func Get() error { return nil }
+
+// List overrides the typical response size.
+// @Command items.List
+// @Description Lists every item.
+// @Result []string "Items"
+// @TypicalResponseSize 5MB
+func List() error { return nil }
+
+// Upload overrides the request size with a directive.
+// @Command items.Upload
+// @Description Uploads an item.
+// @Parameter data string "Base64 content"
+//
+//jdocgen:maxrequestsize 1.5 mb
+func Upload() error { return nil }
+
+// Ping has an invalid size, which is ignored.
+// @Command items.Ping
+// @Description Checks the service.
+// @MaxRequestSize huge
+func Ping() error { return nil }
@@ -0,0 +1,46 @@
+// Package rpc
+// @title Slices Fixture API
+// @version 1.0.0
+// @description Fixture tree for results holding slices, arrays and pointers of structs.
+package rpc
+
+// ReportItem is a line of a report.
+type ReportItem struct {
+	Name  string `json:"name"`  // Name of the line
+	Total int    `json:"total"` // Total of the line
+}
+
+// Page is a page of rows.
+type Page[T any] struct {
+	Rows []T `json:"rows"` // Rows of the page
+}
+
+// ListReports lists the report items.
+// @Command reports.List
+// @Description Lists the report items.
+// @Result []ReportItem "list of report items"
+func ListReports() {}
+
+// ListPointers lists the report items by reference.
+// @Command reports.Pointers
+// @Description Lists the report items by reference.
+// @Result []*ReportItem "list of report items"
+func ListPointers() {}
+
+// ListGrid lists the report items by row and column.
+// @Command reports.Grid
+// @Description Lists the report items by row and column.
+// @Result [][]ReportItem "grid of report items"
+func ListGrid() {}
+
+// ListTop lists the three largest report items.
+// @Command reports.Top
+// @Description Lists the three largest report items.
+// @Result [3]ReportItem "largest report items"
+func ListTop() {}
+
+// ListPages lists the report items by page.
+// @Command reports.Pages
+// @Description Lists the report items by page.
+// @Result []Page[ReportItem] "pages of report items"
+func ListPages() {}
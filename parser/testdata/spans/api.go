@@ -0,0 +1,35 @@
+// Package rpc
+// @title Annotation Spans Fixture API
+// @version 1.0.0
+// @description Fixture tree locating diagnostics at their annotations.
+package rpc
+
+// Stats is not a map.
+type Stats struct {
+	Total int `json:"total"`
+}
+
+// ListUsers is live.
+// @Command user.List
+// @Description List users.
+func ListUsers() error { return nil }
+
+// Report has several bad annotations in one comment block.
+// @Command stats.Report
+// @Description Report statistics.
+// @Parameter filter Filter "Filter of the report"
+// @Parameter from int "optional First day"
+// @Requires from until
+// @Result Stats "The statistics"
+// @DynamicKeys result "day"
+// @FormerName user.List
+// @Envelope jsonrpc=3.0
+// @ID user-list
+func Report() error { return nil }
+
+// Broken has a malformed @Error after valid annotations.
+// @Command stats.Broken
+// @Description Broken error code.
+// @Error 400 "Bad request"
+// @Error code "Not a number"
+func Broken() error { return nil }
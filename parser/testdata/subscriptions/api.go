@@ -0,0 +1,43 @@
+// Package rpc
+// @title Subscriptions Fixture API
+// @version 1.0.0
+// @description Fixture tree for @Subscription.
+package rpc
+
+// EventPayload is pushed for each event.
+type EventPayload struct {
+	Kind string `json:"kind"` // Kind of event.
+	At   int64  `json:"at"`   // Unix time of the event.
+}
+
+// Watch subscribes to events.
+// @Command events.Subscribe
+// @Description Subscribes to account events.
+// @Parameter account string "Account to watch"
+// @Result string "Subscription id"
+// @Subscription events.push
+// @NotificationPayload EventPayload "Pushed for each event"
+func Watch() error { return nil }
+
+// Ticks subscribes to clock ticks.
+// @Command clock.Subscribe
+// @Description Subscribes to clock ticks.
+// @Result int "Subscription id"
+//
+//jdocgen:subscription
+//jdocgen:notificationpayload int64 "Unix time of the tick"
+func Ticks() error { return nil }
+
+// Orphan declares a payload without a subscription.
+// @Command events.Orphan
+// @Description Has a payload but no subscription.
+// @Result string "Nothing"
+// @NotificationPayload EventPayload "Never pushed"
+func Orphan() error { return nil }
+
+// NoPayload declares a subscription without a payload.
+// @Command events.NoPayload
+// @Description Has a subscription but no payload.
+// @Result string "Subscription id"
+// @Subscription
+func NoPayload() error { return nil }
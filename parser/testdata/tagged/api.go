@@ -0,0 +1,25 @@
+// Package rpc
+// @title Tagged Fixture API
+// @version 1.0.0
+// @description Fixture tree for @OnlyTagged and @Hidden.
+package rpc
+
+// Account mixes wire fields with internal bookkeeping.
+// @OnlyTagged
+type Account struct {
+	ID       int    `json:"id"`   // Account id.
+	Name     string `json:"name"` // Display name.
+	Revision int    // Internal revision counter.
+	cache    string `json:"cache"`
+	// Secret is tagged but only documented for the internal audience.
+	// @Hidden
+	Secret string `json:"secret"`
+}
+
+// Profile is a regular struct.
+// @NoTruncate
+type Profile struct {
+	Email    string `json:"email"`
+	Password string `json:"password"` // @Hidden
+	note     string
+}
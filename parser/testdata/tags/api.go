@@ -0,0 +1,30 @@
+// Package rpc
+// @title Tags Fixture API
+// @version 1.0.0
+// @description Fixture tree for @Tag.
+package rpc
+
+// Ping has no tag.
+// @Command ping
+// @Description ping command.
+func Ping() error { return nil }
+
+// GetUser is in a single section.
+// @Command users.Get
+// @Description users.Get command.
+// @Tag users
+func GetUser() error { return nil }
+
+// Charge belongs to two sections.
+// @Command billing.Charge
+// @Description billing.Charge command.
+// @Tag billing
+// @Tag users
+func Charge() error { return nil }
+
+// Refund is tagged with a directive.
+//
+//jdocgen:command billing.Refund
+//jdocgen:description billing.Refund command.
+//jdocgen:tag billing
+func Refund() error { return nil }
@@ -0,0 +1,32 @@
+// Package rpc
+// @title Types Fixture API
+// @version 1.0.0
+// @description Fixture tree for parsed parameter and result types.
+package rpc
+
+import (
+	cm "example.com/types/common"
+)
+
+// User is a user account.
+type User struct {
+	ID      int               `json:"id"`
+	Friends []*User           `json:"friends"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// List lists users.
+// @Command users.List
+// @Description List users.
+// @Parameter ids []int "User ids"
+// @Parameter labels map[string]string "optional Label filter"
+// @Parameter after *cm.Cursor "optional Cursor of the page"
+// @Result cm.Page[User] "Page of users"
+func List() error { return nil }
+
+// Lookup returns users by id.
+// @Command users.Lookup
+// @Description Lookup users.
+// @Parameter ids []int "User ids"
+// @Result map[int]*User "Users by id"
+func Lookup() error { return nil }
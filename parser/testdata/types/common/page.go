@@ -0,0 +1,13 @@
+package common
+
+// Page is one page of results.
+type Page[T any] struct {
+	Items []T          `json:"items"`
+	Next  *Cursor      `json:"next,omitempty"`
+	Index map[string]T `json:"index"`
+}
+
+// Cursor points to the next page.
+type Cursor struct {
+	Token string `json:"token"`
+}
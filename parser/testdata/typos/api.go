@@ -0,0 +1,23 @@
+// Package rpc
+// @title Typos Fixture API
+// @version 1.0.0
+// @description Fixture tree for annotation typo detection.
+// @licence MIT
+package rpc
+
+// Account has a misspelled marker.
+// @OnlyTaged
+type Account struct {
+	ID   int    `json:"id"`   // @Hiden
+	Name string `json:"name"` // Display name.
+}
+
+// GetAccount returns an account.
+// @Command account.Get
+// @Paramter id int "Account id."
+// @Hidden
+// @see account.List for listing.
+// @Result Account "The account."
+func GetAccount(id int) (Account, error) {
+	return Account{}, nil
+}
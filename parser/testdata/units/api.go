@@ -0,0 +1,28 @@
+// Package rpc
+// @title Units Fixture API
+// @version 1.0.0
+// @description Fixture tree for the units and format tags.
+package rpc
+
+import (
+	"net/url"
+	"time"
+)
+
+// Job is a scheduled job.
+type Job struct {
+	Timeout   int64      `json:"timeout_ms" units:"milliseconds"`       // Time the job may run.
+	Owner     string     `json:"owner" format:"email"`                  // Email of the owner.
+	Created   time.Time  `json:"created"`                               // Creation time.
+	Day       time.Time  `json:"day" format:"date"`                     // Day the job runs.
+	Callback  *url.URL   `json:"callback,omitempty"`                    // Called when the job ends.
+	Deadline  *time.Time `json:"deadline,omitempty" format:"date-time"` // Latest end time.
+	Size      int64      `json:"size" units:"bytes" format:"int64"`     // Size of the output.
+	Untouched string     `json:"untouched"`                             // No hints.
+}
+
+// Get returns a job.
+// @Command jobs.Get
+// @Description Returns a job.
+// @Result Job "The job"
+func Get() error { return nil }
@@ -0,0 +1,44 @@
+// Package rpc
+// @title Unresolved Fixture API
+// @version 1.0.0
+// @description Fixture tree for types without a struct definition.
+package rpc
+
+// Account is a documented struct.
+type Account struct {
+	ID int `json:"id"` // Account id.
+}
+
+// Status is a named type that is not a struct.
+type Status string
+
+// Get returns an account.
+// @Command account.Get
+// @Description Get an account.
+// @Result Account "The account"
+func Get() error { return nil }
+
+// Audit returns the audit log of an account.
+// @Command account.Audit
+// @Description Audit an account.
+// @Result AuditLog "The audit log"
+// @Additional AuditEntry
+func Audit() error { return nil }
+
+// State returns the status of an account.
+// @Command account.State
+// @Description Get the status of an account.
+// @Result Status "The status"
+func State() error { return nil }
+
+// Since returns the creation time of an account.
+// @Command account.Since
+// @Description Get the creation time of an account.
+// @Result time.Time "The creation time"
+func Since() error { return nil }
+
+// Owner returns the owner of an account.
+// @Command account.Owner
+// @Description Get the owner of an account.
+// @Result users.Owner "The owner"
+func Owner() error { return nil }
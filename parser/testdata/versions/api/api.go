@@ -0,0 +1,35 @@
+// Package api
+// @title Versions Fixture API
+// @version 1.0.0
+// @description Fixture tree with two packages named models.
+package api
+
+import (
+	"example.com/shop/internal/v2/models"
+
+	v1 "example.com/shop/internal/v1/models"
+)
+
+// Migration moves a user from the first to the second version of the models.
+type Migration struct {
+	From v1.User     `json:"from"` // User in the first version.
+	To   models.User `json:"to"`   // User in the second version.
+}
+
+// GetV1 returns a user of the first version.
+// @Command users.GetV1
+// @Description Get a user of the first version.
+// @Result v1.User "The user"
+func GetV1() (v1.User, error) { return v1.User{}, nil }
+
+// GetV2 returns a user of the second version.
+// @Command users.GetV2
+// @Description Get a user of the second version.
+// @Result models.User "The user"
+func GetV2() (models.User, error) { return models.User{}, nil }
+
+// Migrate migrates a user.
+// @Command users.Migrate
+// @Description Migrate a user to the second version.
+// @Result Migration "The migration"
+func Migrate() (Migration, error) { return Migration{}, nil }
@@ -0,0 +1,6 @@
+package models
+
+// User is a user of the first version.
+type User struct {
+	Name string `json:"name"` // Full name.
+}
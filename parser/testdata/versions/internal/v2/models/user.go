@@ -0,0 +1,7 @@
+package models
+
+// User is a user of the second version.
+type User struct {
+	FirstName string `json:"firstName"` // First name.
+	LastName  string `json:"lastName"`  // Last name.
+}
@@ -0,0 +1,29 @@
+// Package rpc
+// @title   Whitespace   Fixture API
+// @version 1.0.0
+// @description Fixture tree for  whitespace	normalization.
+package rpc
+
+// Narrow is an account,
+// see   `a  code   span`.
+type Narrow struct {
+	// Name of the account,
+	// as shown to users.
+	Name string `json:"name"`
+	Note string `json:"note"` // Free text,	set by support.
+}
+
+// Wide is an account, see `a  code   span`.
+type Wide struct {
+	// Name of the account, as shown to users.
+	Name string `json:"name"`
+	Note string `json:"note"` // Free text, set by support.
+}
+
+// Get returns an account.
+// @Command account.Get
+// @Description Returns  an	account by   id.
+// @Parameter id int "The	account   id, see `id  format`."
+// @Result Narrow "The  account."
+// @Error 404 "Account	not found."
+func Get() error { return nil }
@@ -0,0 +1,88 @@
+// parser/tokenize.go
+package parser
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// splitFields reads up to n leading fields of an annotation line and returns them with the
+// rest of the line. Fields are separated by white space, except inside brackets, so
+// "map[string, int]" is one field, and inside double quotes, so `default="a b"` is one field.
+// A field starting with a double quote opens the description: it is left in the rest.
+func splitFields(text string, n int) ([]string, string) {
+	var fields []string
+	rest := strings.TrimLeftFunc(text, unicode.IsSpace)
+	for len(fields) < n && rest != "" && rest[0] != '"' {
+		end := fieldEnd(rest)
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+	return fields, rest
+}
+
+// fieldEnd returns the offset just after the field at the start of text.
+func fieldEnd(text string) int {
+	depth := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '"':
+			if end, err := quoteEnd(text, i); err == nil {
+				i = end
+				continue
+			}
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case unicode.IsSpace(r) && depth == 0:
+			return i
+		}
+		i += size
+	}
+	return len(text)
+}
+
+// quoteEnd returns the offset just after the double quote closing the one at start, skipping
+// quotes escaped with a backslash.
+func quoteEnd(text string, start int) (int, error) {
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, errors.New("unterminated quoted description")
+}
+
+// quotedDescription returns the description at the end of an annotation line, normalized.
+// A quoted description ends at its closing quote, and \" and \\ stand for a quote and a
+// backslash in it; other backslashes are kept. A description without quotes is the rest of
+// the line.
+func quotedDescription(rest string) (string, error) {
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "\"") {
+		return normalizeSpace(strings.Trim(rest, "\"")), nil
+	}
+	end, err := quoteEnd(rest, 0)
+	if err != nil {
+		return "", err
+	}
+	if trailing := strings.TrimSpace(rest[end:]); trailing != "" {
+		return "", errors.New("unexpected text '" + trailing + "' after the quoted description, escape quotes inside it as \\\"")
+	}
+
+	var b strings.Builder
+	for i := 1; i < end-1; i++ {
+		if rest[i] == '\\' && i+1 < end-1 && (rest[i+1] == '"' || rest[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(rest[i])
+	}
+	return normalizeSpace(b.String()), nil
+}
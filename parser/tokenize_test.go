@@ -0,0 +1,119 @@
+// parser/tokenize_test.go
+package parser
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		text   string
+		n      int
+		fields []string
+		rest   string
+	}{
+		{`@Parameter id int "The id."`, 3, []string{"@Parameter", "id", "int"}, `"The id."`},
+		{`@Parameter counts map[string, int] "Counts by name."`, 3, []string{"@Parameter", "counts", "map[string, int]"}, `"Counts by name."`},
+		{`@Result Page[User, Group] "A page."`, 2, []string{"@Result", "Page[User, Group]"}, `"A page."`},
+		{`default="a b" "Sort order."`, 1, []string{`default="a b"`}, `"Sort order."`},
+		{`@Error -32004 "Not found."`, 3, []string{"@Error", "-32004"}, `"Not found."`},
+		{`@Error -32004`, 2, []string{"@Error", "-32004"}, ""},
+		{`@Result User The user.`, 2, []string{"@Result", "User"}, "The user."},
+	}
+	for _, tt := range tests {
+		fields, rest := splitFields(tt.text, tt.n)
+		if strings.Join(fields, "|") != strings.Join(tt.fields, "|") || rest != tt.rest {
+			t.Errorf("splitFields(%q, %d) = %q, %q, want %q, %q", tt.text, tt.n, fields, rest, tt.fields, tt.rest)
+		}
+	}
+}
+
+func TestQuotedDescription(t *testing.T) {
+	tests := []struct {
+		rest string
+		want string
+		err  string
+	}{
+		{`"The id."`, "The id.", ""},
+		{`"Use \"asc\" or \"desc\"."`, `Use "asc" or "desc".`, ""},
+		{`"A path such as C:\\Users, matched by \d+."`, `A path such as C:\Users, matched by \d+.`, ""},
+		{`"  Spread   over  spaces. "  `, "Spread over spaces.", ""},
+		{"The user.", "The user.", ""},
+		{"", "", ""},
+		{`"Not closed.`, "", "unterminated quoted description"},
+		{`"The "id" of the user."`, "", `unexpected text 'id" of the user."' after the quoted description`},
+	}
+	for _, tt := range tests {
+		got, err := quotedDescription(tt.rest)
+		switch {
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("quotedDescription(%q) error = %v, want %q", tt.rest, err, tt.err)
+		case tt.err == "" && (err != nil || got != tt.want):
+			t.Errorf("quotedDescription(%q) = %q, %v, want %q", tt.rest, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseFunctionQuotedDescriptions(t *testing.T) {
+	src := `package rpc
+
+// @Command users.Search
+// @Description Search users.
+// @Parameter counts map[string, int] "Counts by \"name\"."
+// @Parameter order string default="a b" "optional Sort \\ order."
+// @Result Page[User, Group] "A page of \"users\"."
+// @Error -32004 "Not \"found\"."
+func Search() {}
+
+// @Command users.Broken
+// @Description Broken.
+// @Parameter id int "The id."
+// @Error -32004 "Not found.
+func Broken() {}
+`
+	fset := token.NewFileSet()
+	fileAst, err := parser.ParseFile(fset, "api.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	functions := map[string]*ast.FuncDecl{}
+	for _, decl := range fileAst.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			functions[fn.Name.Name] = fn
+		}
+	}
+
+	apiFunc, err := parseFunction(functions["Search"], "rpc", nil, "api.go", fset, nil)
+	if err != nil {
+		t.Fatalf("parseFunction returned error: %v", err)
+	}
+	got := []string{
+		apiFunc.Parameters[0].Type, apiFunc.Parameters[0].Description,
+		apiFunc.Parameters[1].Default.Value, apiFunc.Parameters[1].Description,
+		apiFunc.Results[0].Type, apiFunc.Results[0].Description,
+		apiFunc.Errors[0].Description,
+	}
+	want := []string{
+		"map[string, int]", `Counts by "name".`,
+		`"a b"`, `Sort \ order.`,
+		"Page[User, Group]", `A page of "users".`,
+		`Not "found".`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if apiFunc.Parameters[1].Required {
+		t.Errorf("Expected the optional parameter not to be required")
+	}
+
+	_, err = parseFunction(functions["Broken"], "rpc", nil, "api.go", fset, nil)
+	var located *annotationError
+	if !errors.As(err, &located) || located.Line != 14 || err.Error() != "malformed @Error annotation: unterminated quoted description" {
+		t.Errorf("Expected a malformed @Error at line 12, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// parser/units_test.go
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestParseProjectUnitsAndFormats(t *testing.T) {
+	result, err := ParseProject("testdata/units")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	job, ok := result.Structs[models.StructKey{Package: "rpc", Name: "Job"}]
+	if !ok {
+		t.Fatal("Expected struct Job to be collected")
+	}
+	type hints struct{ units, format string }
+	want := map[string]hints{
+		"Timeout":   {units: "milliseconds"},
+		"Owner":     {format: "email"},
+		"Created":   {format: "date-time"},
+		"Day":       {format: "date"},
+		"Callback":  {format: "uri"},
+		"Deadline":  {format: "date-time"},
+		"Size":      {units: "bytes", format: "int64"},
+		"Untouched": {},
+	}
+	for _, field := range job.Fields {
+		if got := (hints{field.Units, field.Format}); got != want[field.Name] {
+			t.Errorf("Expected field %s to have %+v, got %+v", field.Name, want[field.Name], got)
+		}
+	}
+	if len(job.Fields) != len(want) {
+		t.Errorf("Expected %d fields, got %d", len(want), len(job.Fields))
+	}
+}
+
+func TestParseProjectReportsFormatOverrides(t *testing.T) {
+	result, err := ParseProject("testdata/units")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	var got []string
+	for _, diag := range result.Diagnostics {
+		if diag.Class == ClassFormatOverride {
+			got = append(got, diag.String())
+		}
+	}
+	want := filepath.Join("testdata", "units", "api.go") + ":17: info: field 'Day' of struct 'Job' has format 'date', overriding the format 'date-time' of its type 'time.Time' [format-override]"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Expected the diagnostic %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,134 @@
+// parser/unresolved.go
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+	"github.com/pablolagos/jdocgen/utils"
+)
+
+// unresolvedType returns the type held by typ when it names a type of a parsed package, or of
+// no package, that is not declared, followed by the types of the same name declared in other
+// packages. Types of other packages, such as time.Time, are expected to be missing and are not
+// reported.
+func unresolvedType(typ string, pkg string, importAliases map[string]string, declaredTypes map[models.StructKey]bool, packages map[string]bool) (string, string, bool) {
+	held := utils.ResolveTypeRef(utils.ParseType(typ), pkg, importAliases, nil).Held()
+	if held.Kind != models.TypeNamed || !packages[held.Package] {
+		return "", "", false
+	}
+	if declaredTypes[models.StructKey{Package: held.Package, Name: held.Name}] {
+		return "", "", false
+	}
+	return held.String(), declaredElsewhere(held.Name, declaredTypes), true
+}
+
+// declaredElsewhere returns a hint naming the types called name in any package, in package
+// order, or an empty string when there are none. Unresolved types are never guessed from
+// their name, since the choice between several packages would be arbitrary.
+func declaredElsewhere(name string, declaredTypes map[models.StructKey]bool) string {
+	var candidates []string
+	for key := range declaredTypes {
+		if key.Name == name {
+			candidates = append(candidates, "'"+key.Package+"."+key.Name+"'")
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+	return ", did you mean " + strings.Join(candidates, " or ") + "?"
+}
+
+// unresolvedAnnotationTypes reports the @Parameter, @Result and @Additional types of apiFunc
+// that are not declared, at the line of their annotation.
+func unresolvedAnnotationTypes(apiFunc models.APIFunction, doc *ast.CommentGroup, fset *token.FileSet, declaredTypes map[models.StructKey]bool, packages map[string]bool) Diagnostics {
+	lines, _ := functionAnnotations(doc, fset)
+
+	var diagnostics Diagnostics
+	for _, line := range lines {
+		// Types may hold spaces, such as "map[string, int]"
+		parts, _ := splitFields(line.Text, 3)
+		annotation, ok := LookupAnnotation(parts[0], ScopeFunction)
+		if !ok {
+			continue
+		}
+		var typ, what string
+		switch {
+		case annotation.Name == "@Parameter" && len(parts) > 2:
+			typ, what = parts[2], fmt.Sprintf("parameter '%s'", parts[1])
+		case annotation.Name == "@Result" && len(parts) > 1:
+			typ, what = parts[1], "the result"
+		case annotation.Name == "@Additional" && len(parts) > 1:
+			typ, what = parts[1], "an additional struct"
+		case annotation.Name == "@NotificationPayload" && len(parts) > 1:
+			typ, what = parts[1], "the notification payload"
+		default:
+			continue
+		}
+		if name, hint, unresolved := unresolvedType(typ, apiFunc.PackageName, apiFunc.ImportAliases, declaredTypes, packages); unresolved {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				File:     apiFunc.SourceFile,
+				Line:     line.Line,
+				Class:    ClassUnresolvedType,
+				Message:  fmt.Sprintf("type '%s' of %s of command '%s' is not declared in the parsed packages%s", name, what, apiFunc.Command, hint),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// unresolvedFieldTypes reports the struct fields whose type is not declared, at the line of
+// the field. Fields are resolved in the package of their struct, with the imports of its file
+// given by structAliases.
+func unresolvedFieldTypes(structDefinitions map[models.StructKey]models.StructDefinition, structAliases map[models.StructKey]map[string]string, declaredTypes map[models.StructKey]bool, packages map[string]bool) Diagnostics {
+	keys := make([]models.StructKey, 0, len(structDefinitions))
+	for key := range structDefinitions {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Package != keys[j].Package {
+			return keys[i].Package < keys[j].Package
+		}
+		return keys[i].Name < keys[j].Name
+	})
+
+	var diagnostics Diagnostics
+	for _, key := range keys {
+		structDef := structDefinitions[key]
+		if structDef.SourceFile == "" {
+			continue
+		}
+		for _, field := range structDef.Fields {
+			if isTypeParam(field.Type, structDef.TypeParams) {
+				continue
+			}
+			if name, hint, unresolved := unresolvedType(field.Type, key.Package, structAliases[key], declaredTypes, packages); unresolved {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityWarning,
+					File:     structDef.SourceFile,
+					Line:     field.SourceLine,
+					Class:    ClassUnresolvedType,
+					Message:  fmt.Sprintf("type '%s' of field '%s' of struct '%s.%s' is not declared in the parsed packages%s", name, field.Name, key.Package, key.Name, hint),
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+// isTypeParam reports whether the type held by typ is one of the type parameters.
+func isTypeParam(typ string, typeParams []models.TypeParam) bool {
+	held := utils.ParseType(typ).Held()
+	for _, param := range typeParams {
+		if held.Kind == models.TypeNamed && held.Package == "" && held.Name == param.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// parser/whitespace.go
+package parser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// normalizeSpace trims text and collapses every run of whitespace, tabs and no-break spaces
+// included, into a single space, so re-wrapping a comment does not change the text extracted
+// from it. Code spans are kept as written, up to their closing backticks.
+func normalizeSpace(text string) string {
+	var b strings.Builder
+	pendingSpace := false
+	for i := 0; i < len(text); {
+		if text[i] == '`' {
+			// An unclosed run of backticks is literal text
+			end := codeSpanEnd(text, i)
+			if end == 0 {
+				end = i + backtickRun(text, i)
+			}
+			if pendingSpace && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			pendingSpace = false
+			b.WriteString(text[i:end])
+			i = end
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			pendingSpace = true
+		} else {
+			if pendingSpace && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			pendingSpace = false
+			b.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// codeSpanEnd returns the offset just after the code span opened by the run of backticks
+// at start, or 0 when the run is never closed by a run of the same length.
+func codeSpanEnd(text string, start int) int {
+	opening := backtickRun(text, start)
+	for i := start + opening; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		closing := backtickRun(text, i)
+		if closing == opening {
+			return i + closing
+		}
+		i += closing
+	}
+	return 0
+}
+
+// backtickRun returns the number of consecutive backticks at start.
+func backtickRun(text string, start int) int {
+	n := 0
+	for start+n < len(text) && text[start+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// restOfLine returns the text of an annotation line after its first n fields, as written.
+func restOfLine(line string, n int) string {
+	rest := strings.TrimLeftFunc(line, unicode.IsSpace)
+	for ; n > 0; n-- {
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			return ""
+		}
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+	return rest
+}
+
+// annotationDescription returns the description of an annotation line after its first n
+// fields, normalized and without its enclosing double quotes.
+func annotationDescription(line string, n int) string {
+	return normalizeSpace(strings.Trim(restOfLine(line, n), "\""))
+}
@@ -0,0 +1,75 @@
+// parser/whitespace_test.go
+package parser
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestNormalizeSpace(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"trimmed", "  Name of the user. \t", "Name of the user."},
+		{"tabs", "Name\tof\t\tthe user.", "Name of the user."},
+		{"no-break spaces", "Name of the  user.", "Name of the user."},
+		{"re-wrapped", "Name of the\n   user,  as shown.", "Name of the user, as shown."},
+		{"code span", "Set `a  b\tc`  twice.", "Set `a  b\tc` twice."},
+		{"double backticks", "Use ``a ` b``  here.", "Use ``a ` b`` here."},
+		{"adjacent code span", "Call`f(x,  y)`now.", "Call`f(x,  y)`now."},
+		{"unclosed backtick", "A `b  c.", "A `b c."},
+		{"unclosed double backticks", "A ``b  ` c.", "A ``b ` c."},
+		{"empty", " \t ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeSpace(tt.in); got != tt.want {
+				t.Errorf("normalizeSpace(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProjectNormalizesWhitespace(t *testing.T) {
+	result, err := ParseProject("testdata/whitespace")
+	if err != nil {
+		t.Fatalf("ParseProject returned error: %v", err)
+	}
+
+	if want := "Whitespace Fixture API"; result.ProjectInfo.Title != want {
+		t.Errorf("Expected title %q, got %q", want, result.ProjectInfo.Title)
+	}
+	if want := "Fixture tree for whitespace normalization."; result.ProjectInfo.Description != want {
+		t.Errorf("Expected description %q, got %q", want, result.ProjectInfo.Description)
+	}
+
+	// Narrow and Wide only differ in how their comments are wrapped
+	narrow := result.Structs[models.StructKey{Package: "rpc", Name: "Narrow"}]
+	wide := result.Structs[models.StructKey{Package: "rpc", Name: "Wide"}]
+	if want := "Narrow is an account, see `a  code   span`."; narrow.Description != want {
+		t.Errorf("Expected description %q, got %q", want, narrow.Description)
+	}
+	if narrow.Description[len("Narrow"):] != wide.Description[len("Wide"):] {
+		t.Errorf("Expected the same description, got %q and %q", narrow.Description, wide.Description)
+	}
+	for i := range narrow.Fields {
+		if narrow.Fields[i].Description != wide.Fields[i].Description {
+			t.Errorf("Expected field %s to have the same description, got %q and %q", narrow.Fields[i].Name, narrow.Fields[i].Description, wide.Fields[i].Description)
+		}
+	}
+
+	if len(result.Functions) != 1 {
+		t.Fatalf("Expected 1 command, got %d", len(result.Functions))
+	}
+	fn := result.Functions[0]
+	got := []string{fn.Description, fn.Parameters[0].Description, fn.Results[0].Description, fn.Errors[0].Description}
+	want := []string{"Returns an account by id.", "The account id, see `id  format`.", "The account.", "Account not found."}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], got[i])
+		}
+	}
+}
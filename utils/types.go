@@ -0,0 +1,229 @@
+// utils/types.go
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+// ParseType parses a type string as written in annotations and struct fields.
+// Package qualifiers are kept as written, ResolveTypeRef maps them to packages and structs.
+// For example, "map[string][]*reports.Item" returns a map of string to a slice of pointers
+// to the named type Item of package reports.
+func ParseType(typ string) *models.TypeRef {
+	typ = strings.TrimSpace(typ)
+	switch {
+	case typ == "" || typ == "any" || typ == "error" || strings.HasPrefix(typ, "interface") || typ == InlineStructType ||
+		strings.HasPrefix(typ, "func") || strings.HasPrefix(typ, "chan "):
+		return &models.TypeRef{Kind: models.TypeAny, Name: typ}
+	case strings.HasPrefix(typ, "*"):
+		return &models.TypeRef{Kind: models.TypePointer, Elem: ParseType(typ[1:])}
+	case strings.HasPrefix(typ, "..."):
+		return &models.TypeRef{Kind: models.TypeSlice, Elem: ParseType(typ[3:])}
+	case strings.HasPrefix(typ, "["):
+		end := strings.Index(typ, "]")
+		if end == -1 {
+			return &models.TypeRef{Kind: models.TypeAny, Name: typ}
+		}
+		return &models.TypeRef{Kind: models.TypeSlice, Elem: ParseType(typ[end+1:])}
+	case strings.HasPrefix(typ, "map["):
+		end := matchingBracket(typ, 3)
+		if end == -1 {
+			return &models.TypeRef{Kind: models.TypeAny, Name: typ}
+		}
+		return &models.TypeRef{Kind: models.TypeMap, Key: ParseType(typ[4:end]), Elem: ParseType(typ[end+1:])}
+	case IsBasicType(typ):
+		return &models.TypeRef{Kind: models.TypeBasic, Name: typ}
+	}
+
+	ref := &models.TypeRef{Kind: models.TypeNamed, Name: typ}
+	if start := strings.Index(typ, "["); start != -1 {
+		end := matchingBracket(typ, start)
+		if end != len(typ)-1 {
+			return &models.TypeRef{Kind: models.TypeAny, Name: typ}
+		}
+		ref.Name = typ[:start]
+		for _, arg := range splitTypeArguments(typ[start+1 : end]) {
+			ref.TypeArgs = append(ref.TypeArgs, ParseType(arg))
+		}
+	}
+	if dot := strings.LastIndex(ref.Name, "."); dot != -1 {
+		ref.Package, ref.Name = ref.Name[:dot], ref.Name[dot+1:]
+	}
+	return ref
+}
+
+// matchingBracket returns the index of the ']' closing the '[' at start, or -1.
+func matchingBracket(typ string, start int) int {
+	depth := 0
+	for i := start; i < len(typ); i++ {
+		switch typ[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// SplitElementType splits the slice, array, map and pointer prefixes off typ. For example,
+// "[]*Item" returns ("[]*", "Item") and "map[string][]Page[Row]" returns ("map[string][]",
+// "Page[Row]").
+func SplitElementType(typ string) (prefix string, elem string) {
+	elem = strings.TrimSpace(typ)
+	for {
+		end := -1
+		switch {
+		case strings.HasPrefix(elem, "*"):
+			end = 0
+		case strings.HasPrefix(elem, "map["):
+			end = matchingBracket(elem, 3)
+		case strings.HasPrefix(elem, "["):
+			end = strings.Index(elem, "]")
+		}
+		if end == -1 {
+			return prefix, elem
+		}
+		prefix, elem = prefix+elem[:end+1], elem[end+1:]
+	}
+}
+
+// WrapElementType returns the type holding elem under prefix, as split by SplitElementType.
+func WrapElementType(prefix string, elem *models.TypeRef) *models.TypeRef {
+	switch {
+	case strings.HasPrefix(prefix, "*"):
+		return &models.TypeRef{Kind: models.TypePointer, Elem: WrapElementType(prefix[1:], elem)}
+	case strings.HasPrefix(prefix, "map["):
+		end := matchingBracket(prefix, 3)
+		return &models.TypeRef{Kind: models.TypeMap, Key: ParseType(prefix[4:end]), Elem: WrapElementType(prefix[end+1:], elem)}
+	case strings.HasPrefix(prefix, "["):
+		end := strings.Index(prefix, "]")
+		return &models.TypeRef{Kind: models.TypeSlice, Elem: WrapElementType(prefix[end+1:], elem)}
+	}
+	return elem
+}
+
+// ResolveTypeRef resolves the named types of ref, as written in package pkg, in place: import
+// aliases are replaced by package names, unqualified names belong to pkg, and named types with
+// a struct definition become TypeStruct with their key. Generic instantiations resolve to the
+// concrete struct created by the parser. It returns ref.
+func ResolveTypeRef(ref *models.TypeRef, pkg string, importAliases map[string]string, structDefinitions map[models.StructKey]models.StructDefinition) *models.TypeRef {
+	if ref == nil {
+		return nil
+	}
+	switch ref.Kind {
+	case models.TypePointer, models.TypeSlice:
+		ResolveTypeRef(ref.Elem, pkg, importAliases, structDefinitions)
+	case models.TypeMap:
+		ResolveTypeRef(ref.Key, pkg, importAliases, structDefinitions)
+		ResolveTypeRef(ref.Elem, pkg, importAliases, structDefinitions)
+	case models.TypeNamed, models.TypeStruct:
+		qualified := ref.Package != ""
+		if !qualified {
+			ref.Package = pkg
+		} else if aliased, exists := importAliases[ref.Package]; exists {
+			ref.Package = aliased
+		}
+		for _, arg := range ref.TypeArgs {
+			ResolveTypeRef(arg, pkg, importAliases, structDefinitions)
+		}
+
+		name := ref.Name
+		if len(ref.TypeArgs) > 0 {
+			name = concreteName(ref, pkg)
+		}
+		if _, exists := structDefinitions[models.StructKey{Package: ref.Package, Name: name}]; exists {
+			ref.Kind = models.TypeStruct
+			ref.Struct = models.StructKey{Package: ref.Package, Name: name}
+		} else if key, found := findStructByName(name, structDefinitions); found && !qualified && len(ref.TypeArgs) > 0 {
+			// Concrete generic structs live in the package of their base type
+			ref.Kind = models.TypeStruct
+			ref.Package = key.Package
+			ref.Struct = key
+		}
+	}
+	return ref
+}
+
+// concreteName returns the name the parser gives to the concrete struct of a generic
+// instantiation: type arguments from pkg are unqualified, others keep their package.
+func concreteName(ref *models.TypeRef, pkg string) string {
+	args := make([]string, len(ref.TypeArgs))
+	for i, arg := range ref.TypeArgs {
+		switch {
+		case (arg.Kind == models.TypeNamed || arg.Kind == models.TypeStruct) && len(arg.TypeArgs) == 0 && arg.Package == pkg:
+			args[i] = arg.Name
+		default:
+			args[i] = arg.String()
+		}
+	}
+	return ref.Name + "[" + strings.Join(args, ", ") + "]"
+}
+
+// ResolvePackageAndType returns the package and name of the base type typ, as written in
+// package pkg. A qualified name keeps its name, with its import alias replaced by the package
+// it names. An unqualified name belongs to pkg when pkg declares such a struct; otherwise both
+// results are empty. Type arguments of generics are not resolved, see ResolveTypeRef.
+func ResolvePackageAndType(typ string, pkg string, importAliases map[string]string, structDefinitions map[models.StructKey]models.StructDefinition) (string, string) {
+	if strings.Contains(typ, ".") {
+		qualifier, name := SplitQualifiedName(typ)
+		if qualifier == "" || name == "" {
+			return "", typ
+		}
+		if aliased, exists := importAliases[qualifier]; exists {
+			return aliased, name
+		}
+		return qualifier, name
+	}
+	if _, exists := structDefinitions[models.StructKey{Package: pkg, Name: typ}]; exists {
+		return pkg, typ
+	}
+	return "", ""
+}
+
+// findStructByName returns the struct named name in any package, choosing the first package
+// in alphabetical order when several define it.
+func findStructByName(name string, structDefinitions map[models.StructKey]models.StructDefinition) (models.StructKey, bool) {
+	var matches []models.StructKey
+	for key := range structDefinitions {
+		if key.Name == name {
+			matches = append(matches, key)
+		}
+	}
+	if len(matches) == 0 {
+		return models.StructKey{}, false
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Package < matches[j].Package
+	})
+	return matches[0], true
+}
+
+// SubstituteTypeParams replaces, in place, the type parameters of a generic struct of package
+// pkg by the type arguments of an instantiation. ref must already be resolved in pkg, and
+// args in the package of the instantiation. It returns the substituted ref.
+func SubstituteTypeParams(ref *models.TypeRef, pkg string, typeParams []models.TypeParam, args []*models.TypeRef) *models.TypeRef {
+	if ref == nil {
+		return nil
+	}
+	if ref.Kind == models.TypeNamed && ref.Package == pkg && len(ref.TypeArgs) == 0 {
+		for i, param := range typeParams {
+			if param.Name == ref.Name && i < len(args) {
+				substituted := *args[i]
+				return &substituted
+			}
+		}
+	}
+	ref.Elem = SubstituteTypeParams(ref.Elem, pkg, typeParams, args)
+	ref.Key = SubstituteTypeParams(ref.Key, pkg, typeParams, args)
+	for i, arg := range ref.TypeArgs {
+		ref.TypeArgs[i] = SubstituteTypeParams(arg, pkg, typeParams, args)
+	}
+	return ref
+}
@@ -0,0 +1,110 @@
+// utils/types_test.go
+package utils
+
+import (
+	"testing"
+
+	"github.com/pablolagos/jdocgen/models"
+)
+
+func TestResolvePackageAndType(t *testing.T) {
+	structDefinitions := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "User"}:     {Name: "User"},
+		{Package: "billing", Name: "Plan"}: {Name: "Plan"},
+	}
+	importAliases := map[string]string{"bill": "billing", "models": "v2/models"}
+
+	tests := []struct {
+		typ      string
+		wantPkg  string
+		wantName string
+	}{
+		{"bill.Plan", "billing", "Plan"},     // aliased import
+		{"models.User", "v2/models", "User"}, // import of a package sharing its name
+		{"billing.Plan", "billing", "Plan"},  // qualified by package name
+		{"time.Time", "time", "Time"},        // qualified, without a struct definition
+		{"User", "rpc", "User"},              // unqualified, in the package
+		{"Plan", "", ""},                     // unqualified, in another package
+		{"a.b.C", "", "a.b.C"},               // not a qualified name
+	}
+	for _, test := range tests {
+		pkg, name := ResolvePackageAndType(test.typ, "rpc", importAliases, structDefinitions)
+		if pkg != test.wantPkg || name != test.wantName {
+			t.Errorf("ResolvePackageAndType(%q) = (%q, %q), want (%q, %q)", test.typ, pkg, name, test.wantPkg, test.wantName)
+		}
+	}
+}
+
+func TestResolveTypeRef(t *testing.T) {
+	structDefinitions := map[models.StructKey]models.StructDefinition{
+		{Package: "rpc", Name: "User"}:     {Name: "User"},
+		{Package: "billing", Name: "Plan"}: {Name: "Plan"},
+	}
+	importAliases := map[string]string{"bill": "billing"}
+
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{"[]*bill.Plan", "billing.Plan"},
+		{"map[string]billing.Plan", "billing.Plan"},
+		{"User", "rpc.User"},
+	}
+	for _, test := range tests {
+		held := ResolveTypeRef(ParseType(test.typ), "rpc", importAliases, structDefinitions).Held()
+		if held.Kind != models.TypeStruct || held.Struct.Package+"."+held.Struct.Name != test.want {
+			t.Errorf("ResolveTypeRef(%q) holds %+v, want the struct %s", test.typ, held, test.want)
+		}
+	}
+	if ref := ResolveTypeRef(ParseType("Plan"), "rpc", importAliases, structDefinitions); ref.Kind == models.TypeStruct {
+		t.Errorf("Expected an unqualified Plan not to resolve outside its package, got %+v", ref)
+	}
+}
+
+func TestSplitElementType(t *testing.T) {
+	tests := []struct {
+		typ, prefix, elem string
+	}{
+		{"Item", "", "Item"},
+		{"[]*Item", "[]*", "Item"},
+		{"[3][]Item", "[3][]", "Item"},
+		{"map[string][]Page[Row]", "map[string][]", "Page[Row]"},
+		{"map[Key[int]]*Item", "map[Key[int]]*", "Item"},
+	}
+	for _, test := range tests {
+		prefix, elem := SplitElementType(test.typ)
+		if prefix != test.prefix || elem != test.elem {
+			t.Errorf("SplitElementType(%q) = (%q, %q), want (%q, %q)", test.typ, prefix, elem, test.prefix, test.elem)
+		}
+		if got := WrapElementType(prefix, ParseType(elem)).String(); got != ParseType(test.typ).String() {
+			t.Errorf("WrapElementType(%q) = %q, want %q", prefix, got, ParseType(test.typ).String())
+		}
+	}
+}
+
+func TestWellKnownType(t *testing.T) {
+	terminalTypes := map[string]string{"decimal.Decimal": "string (decimal number)", "uuid.UUID": "string (RFC 4122 UUID)"}
+	tests := []struct {
+		typ         string
+		description string
+		wellKnown   bool
+	}{
+		{"time.Time", "string (RFC 3339 timestamp)", true},
+		{"*time.Time", "string (RFC 3339 timestamp)", true},
+		{"time.Duration", "string/number duration", true},
+		{"json.RawMessage", "arbitrary JSON", true},
+		{"decimal.Decimal", "string (decimal number)", true},
+		// Terminal types replace the description of well-known types
+		{"uuid.UUID", "string (RFC 4122 UUID)", true},
+		// Types holding a well-known type are not terminal themselves
+		{"[]time.Time", "", false},
+		{"Time", "", false},
+		{"string", "", false},
+	}
+	for _, test := range tests {
+		description, wellKnown := WellKnownType(ParseType(test.typ), terminalTypes)
+		if description != test.description || wellKnown != test.wellKnown {
+			t.Errorf("WellKnownType(%q) = (%q, %v), want (%q, %v)", test.typ, description, wellKnown, test.description, test.wellKnown)
+		}
+	}
+}
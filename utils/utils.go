@@ -3,11 +3,18 @@ package utils
 
 import (
 	"go/ast"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/pablolagos/jdocgen/models"
 )
 
+// InlineStructType is the string representation of an anonymous struct type, whose fields are
+// documented by a struct definition of their own.
+const InlineStructType = "struct{...}"
+
 // ExprToString converts an AST expression to its string representation.
 func ExprToString(expr ast.Expr) string {
 	switch e := expr.(type) {
@@ -25,6 +32,8 @@ func ExprToString(expr ast.Expr) string {
 		return "func" // Simplified
 	case *ast.InterfaceType:
 		return "interface{}" // Simplified
+	case *ast.StructType:
+		return InlineStructType
 	case *ast.ChanType:
 		return "chan " + ExprToString(e.Value)
 	case *ast.Ellipsis:
@@ -39,7 +48,8 @@ func ExprToString(expr ast.Expr) string {
 }
 
 // ExtractJSONTag extracts the JSON tag from a struct field tag.
-// If no JSON tag is found, it defaults to the field name.
+// If no JSON tag is found, it defaults to the field name. Fields excluded from JSON with
+// json:"-" return "", while json:"-," names the field "-" like encoding/json does.
 func ExtractJSONTag(tag string, fieldName string) string {
 	// Remove backticks
 	tag = strings.Trim(tag, "`")
@@ -51,6 +61,9 @@ func ExtractJSONTag(tag string, fieldName string) string {
 			jsonTag := strings.TrimPrefix(t, "json:")
 			jsonTag = strings.Trim(jsonTag, `"`)
 			// Handle omitempty and other options
+			if jsonTag == "-" {
+				return ""
+			}
 			jsonParts := strings.Split(jsonTag, ",")
 			if len(jsonParts) > 0 && jsonParts[0] != "" {
 				return jsonParts[0]
@@ -61,6 +74,47 @@ func ExtractJSONTag(tag string, fieldName string) string {
 	return fieldName
 }
 
+// HasJSONOption reports whether the JSON tag of a struct field tag carries the given option.
+// For example, HasJSONOption(`json:"name,omitempty"`, "omitempty") returns true
+func HasJSONOption(tag string, option string) bool {
+	tag = strings.Trim(tag, "`")
+	for _, t := range strings.Split(tag, " ") {
+		if strings.HasPrefix(t, "json:") {
+			jsonTag := strings.Trim(strings.TrimPrefix(t, "json:"), `"`)
+			jsonParts := strings.Split(jsonTag, ",")
+			for _, part := range jsonParts[1:] {
+				if part == option {
+					return true
+				}
+			}
+			break
+		}
+	}
+	return false
+}
+
+// TagValue returns the value of key in a struct field tag as written in the source, such as
+// "milliseconds" for key "units" in `json:"timeout" units:"milliseconds"`.
+func TagValue(tag string, key string) string {
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		unquoted = strings.Trim(tag, "`")
+	}
+	return reflect.StructTag(unquoted).Get(key)
+}
+
+// impliedFormats are the formats well-known types are encoded in by encoding/json.
+var impliedFormats = map[string]string{
+	"time.Time": "date-time",
+	"url.URL":   "uri",
+}
+
+// ImpliedFormat returns the format a type is encoded in, such as "date-time" for time.Time
+// or *time.Time, or "" when the type implies none.
+func ImpliedFormat(typ string) string {
+	return impliedFormats[strings.TrimLeft(typ, "*")]
+}
+
 // IsBasicType checks if a given type is a basic Go type.
 func IsBasicType(typ string) bool {
 	basicTypes := []string{
@@ -92,47 +146,92 @@ func ResolveType(typ string) (baseType string, pkg string) {
 	return typ, ""
 }
 
+// genericTypes memoizes ParseGenericType, which is called for the same type strings
+// once per field during both parsing and generation.
+var genericTypes = typeCache{entries: make(map[string]genericType)}
+
+// maxCachedTypes bounds the memoized type strings. The cache is emptied when it is full.
+const maxCachedTypes = 4096
+
+type genericType struct {
+	baseType string
+	typeArgs []string
+}
+
+// typeCache is a size-bounded map guarded for concurrent use.
+type typeCache struct {
+	mu      sync.Mutex
+	entries map[string]genericType
+}
+
+func (c *typeCache) get(typ string) (genericType, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	parsed, exists := c.entries[typ]
+	return parsed, exists
+}
+
+func (c *typeCache) put(typ string, parsed genericType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedTypes {
+		c.entries = make(map[string]genericType)
+	}
+	c.entries[typ] = parsed
+}
+
 // ParseGenericType parses a generic type string and returns the base type and type arguments.
 // For example, "Pagination[ReportItem]" returns ("Pagination", ["ReportItem"])
+// The returned arguments are shared between calls and must not be modified.
 func ParseGenericType(typ string) (string, []string) {
 	start := strings.Index(typ, "[")
+	if start == -1 {
+		return typ, nil
+	}
 	end := strings.LastIndex(typ, "]")
-	if start == -1 || end == -1 || end <= start+1 {
+	if end == -1 || end <= start+1 {
 		return typ, nil
 	}
+	if parsed, exists := genericTypes.get(typ); exists {
+		return parsed.baseType, parsed.typeArgs
+	}
 	baseType := strings.TrimSpace(typ[:start])
 	argsStr := typ[start+1 : end]
 	typeArgs := splitTypeArguments(argsStr)
+	// Appending to the shared slice must not write into the cached backing array
+	typeArgs = typeArgs[:len(typeArgs):len(typeArgs)]
+	genericTypes.put(typ, genericType{baseType: baseType, typeArgs: typeArgs})
 	return baseType, typeArgs
 }
 
 // splitTypeArguments splits type arguments considering nested generics.
 // For example, "ReportItem, Pair[Details, Info]" returns ["ReportItem", "Pair[Details, Info]"]
 func splitTypeArguments(argsStr string) []string {
+	if strings.IndexByte(argsStr, ',') == -1 {
+		// A single argument, the common case
+		if argsStr == "" {
+			return nil
+		}
+		return []string{strings.TrimSpace(argsStr)}
+	}
+
 	var args []string
-	var current strings.Builder
-	depth := 0
-	for _, r := range argsStr {
-		switch r {
+	depth, start := 0, 0
+	for i := 0; i < len(argsStr); i++ {
+		switch argsStr[i] {
 		case '[':
 			depth++
-			current.WriteRune(r)
 		case ']':
 			depth--
-			current.WriteRune(r)
 		case ',':
 			if depth == 0 {
-				args = append(args, strings.TrimSpace(current.String()))
-				current.Reset()
-			} else {
-				current.WriteRune(r)
+				args = append(args, strings.TrimSpace(argsStr[start:i]))
+				start = i + 1
 			}
-		default:
-			current.WriteRune(r)
 		}
 	}
-	if current.Len() > 0 {
-		args = append(args, strings.TrimSpace(current.String()))
+	if start < len(argsStr) {
+		args = append(args, strings.TrimSpace(argsStr[start:]))
 	}
 	return args
 }
@@ -144,6 +243,8 @@ func ReplaceTypeParams(typ string, typeParams []models.TypeParam, concreteTypes
 		// Mismatch in type parameters and concrete types
 		return typ
 	}
+	// strings.ReplaceAll returns typ itself when a parameter does not occur, so only
+	// substitutions allocate
 	for i, param := range typeParams {
 		typ = strings.ReplaceAll(typ, param.Name, concreteTypes[i])
 	}
@@ -159,3 +260,36 @@ func SplitQualifiedName(qualifiedName string) (pkg string, structName string) {
 	}
 	return "", qualifiedName
 }
+
+// IsValidID reports whether id is usable as a stable identifier: letters, digits, '.', '_' and '-'.
+func IsValidID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeID derives a stable identifier from a name by lower-casing it and
+// replacing every run of other characters with a single '-'.
+// For example, "Pagination[reports.ReportItem]" returns "pagination-reports-reportitem"
+func NormalizeID(name string) string {
+	var id strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if pendingDash && id.Len() > 0 {
+				id.WriteRune('-')
+			}
+			pendingDash = false
+			id.WriteRune(r)
+		} else {
+			pendingDash = true
+		}
+	}
+	return id.String()
+}
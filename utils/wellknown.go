@@ -0,0 +1,36 @@
+// utils/wellknown.go
+package utils
+
+import "github.com/pablolagos/jdocgen/models"
+
+// wellKnownTypes are the types of other packages that encoding/json writes as a single JSON
+// value rather than as an object of their fields, with the description they are documented by.
+var wellKnownTypes = map[string]string{
+	"time.Time":       "string (RFC 3339 timestamp)",
+	"time.Duration":   "string/number duration",
+	"json.RawMessage": "arbitrary JSON",
+	"uuid.UUID":       "string (UUID)",
+}
+
+// WellKnownType returns the description of ref, looking through pointers, when it names a
+// terminal type: a well-known type such as time.Time, or one of terminalTypes, which map
+// qualified names such as "decimal.Decimal" to their description and take precedence.
+// Terminal types are documented by their description and never as a struct. A type declared
+// with another type, such as "type Status string", is looked up by its own name first.
+func WellKnownType(ref *models.TypeRef, terminalTypes map[string]string) (string, bool) {
+	ref = ref.Deref()
+	if ref != nil && ref.Alias != "" {
+		if description, exists := terminalTypes[ref.Alias]; exists {
+			return description, true
+		}
+	}
+	if ref == nil || (ref.Kind != models.TypeNamed && ref.Kind != models.TypeStruct) || ref.Package == "" || len(ref.TypeArgs) > 0 {
+		return "", false
+	}
+	name := ref.Package + "." + ref.Name
+	if description, exists := terminalTypes[name]; exists {
+		return description, true
+	}
+	description, exists := wellKnownTypes[name]
+	return description, exists
+}
@@ -0,0 +1,220 @@
+// validate/markdown.go
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pablolagos/jdocgen/parser"
+)
+
+var (
+	// heading matches an ATX heading and captures its marker and text.
+	heading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	// fence matches the opening or closing line of a fenced code block.
+	fence = regexp.MustCompile("^\\s{0,3}(`{3,}|~{3,})(.*)$")
+	// htmlAnchor matches explicit anchors such as <a id="old-name"></a>.
+	htmlAnchor = regexp.MustCompile(`<a\s+(?:id|name)="([^"]+)"`)
+	// link matches the destination of inline links and images.
+	link = regexp.MustCompile(`\]\(([^)\s]*)\)`)
+	// codeSpan matches inline code, whose content is never a link.
+	codeSpan = regexp.MustCompile("`[^`]*`")
+	// delimiterRow matches the row separating the header of a table from its body.
+	delimiterRow = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// Markdown validates the structure of a single generated document. name is used in the
+// reported diagnostics and to resolve links to the document itself.
+func Markdown(name string, content string) parser.Diagnostics {
+	return Documents(map[string]string{name: content}, 0)
+}
+
+// Documents validates the structure of a set of generated documents, keyed by file name,
+// such as the files written in split mode. Links between the documents are checked too.
+// headingOffset is the level the headings of the documents start below, as set by the
+// -heading-offset option of the generator, 0 for documents starting with a level 1 heading.
+// Every problem is reported as an error with the line it was found on:
+//   - table rows whose column count differs from the header
+//   - internal links to anchors or files that do not exist
+//   - fenced code blocks that are never closed
+//   - headings skipping more than one level below the previous heading, such as a level 5
+//     heading after a level 2 one; skipping a single level, as from the heading of a section
+//     to the level 4 headings of its structs, is allowed
+func Documents(files map[string]string, headingOffset int) parser.Diagnostics {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	documents := make(map[string]*document, len(files))
+	for _, name := range names {
+		documents[name] = scan(name, files[name], headingOffset)
+	}
+
+	var diagnostics parser.Diagnostics
+	for _, name := range names {
+		doc := documents[name]
+		diagnostics = append(diagnostics, doc.problems...)
+		for _, l := range doc.links {
+			if problem := resolveLink(doc, l.target, documents); problem != "" {
+				diagnostics = append(diagnostics, diagnostic(name, l.line, problem))
+			}
+		}
+	}
+	return diagnostics
+}
+
+// document is the structure found in one file.
+type document struct {
+	name     string
+	anchors  map[string]bool
+	links    []linkRef
+	problems parser.Diagnostics
+}
+
+type linkRef struct {
+	line   int
+	target string
+}
+
+// scan reads the headings, anchors, links and tables of a document, reporting the problems
+// that do not depend on other documents. Its headings start below level headingOffset.
+func scan(name string, content string, headingOffset int) *document {
+	doc := &document{name: name, anchors: make(map[string]bool)}
+	slugs := make(map[string]int)
+
+	var (
+		fenceMarker  string
+		fenceLine    int
+		level        int
+		tableColumns int
+		lines        = strings.Split(content, "\n")
+	)
+	level = headingOffset
+	for i, line := range lines {
+		number := i + 1
+
+		// Fenced code blocks hide everything else
+		if m := fence.FindStringSubmatch(line); m != nil {
+			switch {
+			case fenceMarker == "":
+				fenceMarker, fenceLine = m[1], number
+				continue
+			case m[1][0] == fenceMarker[0] && len(m[1]) >= len(fenceMarker) && strings.TrimSpace(m[2]) == "":
+				fenceMarker = ""
+				continue
+			}
+		}
+		if fenceMarker != "" {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if tableColumns > 0 {
+			if strings.HasPrefix(trimmed, "|") {
+				if columns := countCells(trimmed); columns != tableColumns {
+					doc.problems = append(doc.problems, diagnostic(name, number, fmt.Sprintf("table row has %d columns, the header has %d", columns, tableColumns)))
+				}
+			} else {
+				tableColumns = 0
+			}
+		} else if strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && delimiterRow.MatchString(strings.TrimSpace(lines[i+1])) {
+			// The delimiter row is checked like the body rows
+			tableColumns = countCells(trimmed)
+		}
+
+		if m := heading.FindStringSubmatch(line); m != nil {
+			// One skipped level is allowed, see Documents
+			if next := len(m[1]); next > level+2 {
+				doc.problems = append(doc.problems, diagnostic(name, number, fmt.Sprintf("heading level jumps from %d to %d", level, next)))
+			}
+			level = len(m[1])
+			slug := slugify(m[2])
+			anchor := slug
+			if n := slugs[slug]; n > 0 {
+				anchor = fmt.Sprintf("%s-%d", slug, n)
+			}
+			slugs[slug]++
+			doc.anchors[anchor] = true
+		}
+		for _, m := range htmlAnchor.FindAllStringSubmatch(line, -1) {
+			doc.anchors[m[1]] = true
+		}
+		for _, m := range link.FindAllStringSubmatch(codeSpan.ReplaceAllString(line, ""), -1) {
+			if isInternal(m[1]) {
+				doc.links = append(doc.links, linkRef{line: number, target: m[1]})
+			}
+		}
+	}
+	if fenceMarker != "" {
+		doc.problems = append(doc.problems, diagnostic(name, fenceLine, "fenced code block is never closed"))
+	}
+	return doc
+}
+
+// resolveLink describes why an internal link of doc does not resolve, or returns "".
+func resolveLink(doc *document, target string, documents map[string]*document) string {
+	file, anchor, _ := strings.Cut(target, "#")
+	targetDoc := doc
+	if file != "" {
+		var exists bool
+		if targetDoc, exists = documents[file]; !exists {
+			return fmt.Sprintf("link to '%s': file '%s' was not generated", target, file)
+		}
+	}
+	if anchor != "" && !targetDoc.anchors[anchor] {
+		return fmt.Sprintf("link to '%s': anchor '%s' does not exist in %s", target, anchor, targetDoc.name)
+	}
+	return ""
+}
+
+// isInternal reports whether a link destination points into the generated documentation:
+// an anchor, or a relative Markdown file.
+func isInternal(target string) bool {
+	if strings.HasPrefix(target, "#") {
+		return true
+	}
+	file, _, _ := strings.Cut(target, "#")
+	return !strings.Contains(file, ":") && !strings.HasPrefix(file, "/") && strings.HasSuffix(file, ".md")
+}
+
+// countCells returns the number of cells of a table row, splitting on unescaped pipes.
+func countCells(row string) int {
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, "\\|") {
+		row = row[:len(row)-1]
+	}
+	cells := 1
+	for i := 0; i < len(row); i++ {
+		switch row[i] {
+		case '\\':
+			i++
+		case '|':
+			cells++
+		}
+	}
+	return cells
+}
+
+// slugify converts heading text to the anchor GitHub assigns to it: lower-case, spaces
+// become hyphens and punctuation other than '-' and '_' is removed.
+func slugify(text string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			slug.WriteRune(r)
+		case r == ' ':
+			slug.WriteRune('-')
+		}
+	}
+	return slug.String()
+}
+
+func diagnostic(name string, line int, message string) parser.Diagnostic {
+	return parser.Diagnostic{Severity: parser.SeverityError, File: name, Line: line, Class: parser.ClassMarkdown, Message: message}
+}
@@ -0,0 +1,154 @@
+// validate/markdown_test.go
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// messages returns the diagnostics formatted one per line.
+func messages(t *testing.T, files map[string]string) string {
+	t.Helper()
+	var lines []string
+	for _, d := range Documents(files, 0) {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// knownProblems lists the problems the generator still produces in its golden files.
+var knownProblems = map[string]string{}
+
+func TestGoldenFilesAreValid(t *testing.T) {
+	goldens, err := filepath.Glob(filepath.Join("..", "generator", "testdata", "*.golden"))
+	if err != nil || len(goldens) == 0 {
+		t.Fatalf("No golden files found: %v", err)
+	}
+	for _, golden := range goldens {
+		content, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", golden, err)
+		}
+		name := filepath.Base(golden)
+		if got := messages(t, map[string]string{name: string(content)}); got != knownProblems[name] {
+			t.Errorf("%s: expected problems:\n%s\ngot:\n%s", golden, knownProblems[name], got)
+		}
+	}
+}
+
+func TestTables(t *testing.T) {
+	doc := strings.Join([]string{
+		"| Name | Type | Description |",
+		"|------|------|-------------|",
+		"| id | int | User id. |",
+		"| tz | string | Time zone \\| region. |",
+		"| `a|b` | string | Pipes in code spans split cells. |",
+		"| name | string |",
+		"",
+		"| A | B |",
+		"|---|---|---|",
+		"| 1 | 2 |",
+		"",
+		"| Not a table |",
+		"Because no delimiter row follows.",
+	}, "\n")
+	expected := strings.Join([]string{
+		"api.md:5: error: table row has 4 columns, the header has 3",
+		"api.md:6: error: table row has 2 columns, the header has 3",
+		"api.md:9: error: table row has 3 columns, the header has 2",
+	}, "\n")
+	if got := messages(t, map[string]string{"api.md": doc}); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	files := map[string]string{
+		"index.md": strings.Join([]string{
+			"# API",
+			"## Commands",
+			"- [user.Get](user.get.md)",
+			"- [user.Get result](user.get.md#rpcuser)",
+			"- [missing file](user.list.md)",
+			"- [missing anchor](user.get.md#rpcaccount)",
+			"- [spec](https://www.jsonrpc.org/specification#request_object)",
+			"- [top](#api) and [commands](#commands) and [nowhere](#nowhere)",
+			"- `[not](#a-link)`",
+		}, "\n"),
+		"user.get.md": strings.Join([]string{
+			"## user.Get",
+			"<a id=\"account.getuser\"></a>",
+			"#### rpc.User",
+			"#### rpc.User",
+			"See [rpc.User](#rpcuser-1), [old name](#account.getuser) and [index](index.md#api).",
+		}, "\n"),
+	}
+	expected := strings.Join([]string{
+		"index.md:5: error: link to 'user.list.md': file 'user.list.md' was not generated",
+		"index.md:6: error: link to 'user.get.md#rpcaccount': anchor 'rpcaccount' does not exist in user.get.md",
+		"index.md:8: error: link to '#nowhere': anchor 'nowhere' does not exist in index.md",
+	}, "\n")
+	if got := messages(t, files); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestFencesAndHeadings(t *testing.T) {
+	doc := strings.Join([]string{
+		"# API",
+		"#### Too deep",
+		"## Command",
+		"```json",
+		"| not | a | table |",
+		"|---|",
+		"# not a heading",
+		"```",
+		"### Results",
+		"~~~",
+		"```",
+		"~~~",
+		"#### Example",
+		"````bash",
+		"```",
+		"curl ...",
+	}, "\n")
+	expected := strings.Join([]string{
+		"api.md:2: error: heading level jumps from 1 to 4",
+		"api.md:14: error: fenced code block is never closed",
+	}, "\n")
+	if got := messages(t, map[string]string{"api.md": doc}); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestHeadingLevelBoundary(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"one level deeper", "# API\n## Command\n### Results", ""},
+		{"one skipped level", "# API\n## Command\n#### rpc.User", ""},
+		{"two skipped levels", "# API\n## Command\n##### rpc.User", "api.md:3: error: heading level jumps from 2 to 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messages(t, map[string]string{"api.md": tt.doc}); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHeadingOffset(t *testing.T) {
+	// Documentation generated with -heading-offset 2 starts at level 3
+	files := map[string]string{"api.md": "### API\n#### Command\n###### rpc.User"}
+	if got := Documents(files, 2); len(got) > 0 {
+		t.Errorf("Expected no problems, got %v", got)
+	}
+	if got := Documents(files, 0); len(got) != 1 {
+		t.Errorf("Expected the first heading to be reported without the offset, got %v", got)
+	}
+}